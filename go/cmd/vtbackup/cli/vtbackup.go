@@ -19,6 +19,7 @@ package cli
 import (
 	"context"
 	"crypto/rand"
+	"encoding/json"
 	"fmt"
 	"math"
 	"math/big"
@@ -75,6 +76,8 @@ var (
 	allowFirstBackup    bool
 	restartBeforeBackup bool
 	upgradeSafe         bool
+	verifyBackup        bool
+	verifyBackupName    string
 
 	// vttablet-like flags
 	initDbNameOverride string
@@ -176,7 +179,11 @@ to the handling of the query path.
 The command-line parameters to vtbackup specify a policy for when a new backup
 is needed, and when old backups should be removed. If the existing backups
 already satisfy the policy, then vtbackup will do nothing and return success
-immediately.`,
+immediately.
+
+With --verify-backup, vtbackup instead restores a backup of the shard into a
+temporary mysqld, checks its files, tables and GTID position, and fails if the
+backup can't be verified. No backup is taken, and no backup is removed.`,
 		Version: servenv.AppVersion.String(),
 		Args:    cobra.NoArgs,
 		PreRunE: servenv.CobraPreRunE,
@@ -198,6 +205,8 @@ func init() {
 	Main.Flags().BoolVar(&allowFirstBackup, "allow_first_backup", allowFirstBackup, "Allow this job to take the first backup of an existing shard.")
 	Main.Flags().BoolVar(&restartBeforeBackup, "restart_before_backup", restartBeforeBackup, "Perform a mysqld clean/full restart after applying binlogs, but before taking the backup. Only makes sense to work around xtrabackup bugs.")
 	Main.Flags().BoolVar(&upgradeSafe, "upgrade-safe", upgradeSafe, "Whether to use innodb_fast_shutdown=0 for the backup so it is safe to use for MySQL upgrades.")
+	Main.Flags().BoolVar(&verifyBackup, "verify-backup", verifyBackup, "Instead of taking a new backup, restore a backup of the shard into a temporary mysqld, and check its files, tables and GTID position.")
+	Main.Flags().StringVar(&verifyBackupName, "verify-backup-name", verifyBackupName, "Name of the backup to check with --verify-backup. Default: the most recent complete backup.")
 
 	// vttablet-like flags
 	Main.Flags().StringVar(&initDbNameOverride, "init_db_name_override", initDbNameOverride, "(init parameter) override the name of the db used by vttablet")
@@ -273,6 +282,14 @@ func run(_ *cobra.Command, args []string) error {
 		}
 	}
 
+	if verifyBackup {
+		if err := verifyLastBackup(ctx); err != nil {
+			return fmt.Errorf("Failed to verify backup: %w", err)
+		}
+		log.Info("Exiting.")
+		return nil
+	}
+
 	// Try to take a backup, if it's been long enough since the last one.
 	// Skip pruning if backup wasn't fully successful. We don't want to be
 	// deleting things if the backup process is not healthy.
@@ -291,6 +308,12 @@ func run(_ *cobra.Command, args []string) error {
 	if err := pruneBackups(ctx, backupStorage, backupDir); err != nil {
 		return fmt.Errorf("Couldn't prune old backups: %w", err)
 	}
+	// Remove the chunks of deduplicated backups that are no longer referenced.
+	// This runs even if pruning of old backups is disabled, so that the chunks
+	// of abandoned backups, and of backups removed by other means, are collected.
+	if err := mysqlctl.PruneBackupChunks(ctx, backupStorage, backupDir, logutil.NewConsoleLogger()); err != nil {
+		return fmt.Errorf("Couldn't prune unreferenced backup chunks: %w", err)
+	}
 
 	if keepAliveTimeout > 0 {
 		log.Infof("Backup was successful, waiting %s before exiting (or until context expires).", keepAliveTimeout)
@@ -304,6 +327,57 @@ func run(_ *cobra.Command, args []string) error {
 	return nil
 }
 
+// verifyLastBackup restores a backup into a temporary mysqld, and checks it.
+func verifyLastBackup(ctx context.Context) error {
+	// As in takeBackup, use a random tablet UID for the temporary data dir.
+	bigN, err := rand.Int(rand.Reader, big.NewInt(math.MaxUint32))
+	if err != nil {
+		return fmt.Errorf("can't generate random tablet UID: %v", err)
+	}
+	tabletUID := uint32(bigN.Uint64())
+	tabletDir := mysqlctl.TabletDir(tabletUID)
+	defer func() {
+		log.Infof("Removing temporary tablet directory: %v", tabletDir)
+		if err := os.RemoveAll(tabletDir); err != nil {
+			log.Warningf("Failed to remove temporary tablet directory: %v", err)
+		}
+	}()
+
+	mysqld, mycnf, err := mysqlctl.CreateMysqldAndMycnf(tabletUID, mysqlSocket, mysqlPort, collationEnv)
+	if err != nil {
+		return fmt.Errorf("failed to initialize mysql config: %v", err)
+	}
+	defer mysqld.Close()
+	if err := mysqld.InitConfig(mycnf); err != nil {
+		return fmt.Errorf("failed to initialize mysql config: %v", err)
+	}
+
+	verification, err := mysqlctl.VerifyBackup(ctx, mysqlctl.VerifyBackupParams{
+		Cnf:                  mycnf,
+		Mysqld:               mysqld,
+		Logger:               logutil.NewConsoleLogger(),
+		Concurrency:          concurrency,
+		Keyspace:             initKeyspace,
+		Shard:                initShard,
+		BackupName:           verifyBackupName,
+		Stats:                backupstats.RestoreStats(),
+		MysqlShutdownTimeout: mysqlShutdownTimeout,
+	})
+	if err != nil {
+		return err
+	}
+	result, err := json.MarshalIndent(verification, "", "  ")
+	if err != nil {
+		return err
+	}
+	log.Infof("Backup verification result: %s", result)
+	if !verification.Verified() {
+		return fmt.Errorf("backup %v did not pass verification", verification.BackupName)
+	}
+	log.Infof("Backup %v was verified", verification.BackupName)
+	return nil
+}
+
 func takeBackup(ctx context.Context, topoServer *topo.Server, backupStorage backupstorage.BackupStorage) error {
 	// This is an imaginary tablet alias. The value doesn't matter for anything,
 	// except that we generate a random UID to ensure the target backup
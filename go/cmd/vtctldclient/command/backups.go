@@ -76,6 +76,20 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandRestoreFromBackup,
 	}
+	// VerifyBackup makes a VerifyBackup gRPC call to a vtctld.
+	VerifyBackup = &cobra.Command{
+		Use:   "VerifyBackup [--backup-name <name>] [--concurrency <concurrency>] <keyspace/shard>",
+		Short: "Restores a backup into a scratch MySQL instance on the vtctld host, and checks the restored files, tables and GTID position.",
+		Long: `Restores a backup into a scratch MySQL instance on the vtctld host, and checks it:
+the restored files are checked against the hashes of the backup MANIFEST, CHECK TABLE and CHECKSUM TABLE are run
+on all the tables, and the restored GTID position is compared with the one of the MANIFEST.
+
+The vtctld host must have the MySQL binaries of the backup. The result is printed in JSON format, and the command
+fails if the backup does not pass the checks.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandVerifyBackup,
+	}
 )
 
 var backupOptions = struct {
@@ -278,6 +292,41 @@ func commandRestoreFromBackup(cmd *cobra.Command, args []string) error {
 	}
 }
 
+var verifyBackupOptions = struct {
+	BackupName  string
+	Concurrency int32
+}{}
+
+func commandVerifyBackup(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.VerifyBackup(commandCtx, &vtctldatapb.VerifyBackupRequest{
+		Keyspace:    keyspace,
+		Shard:       shard,
+		BackupName:  verifyBackupOptions.BackupName,
+		Concurrency: verifyBackupOptions.Concurrency,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", data)
+
+	if !resp.Verified {
+		return fmt.Errorf("backup %s/%s/%s failed verification", keyspace, shard, resp.BackupName)
+	}
+	return nil
+}
+
 func init() {
 	Backup.Flags().BoolVar(&backupOptions.AllowPrimary, "allow-primary", false, "Allow the primary of a shard to be used for the backup. WARNING: If using the builtin backup engine, this will shutdown mysqld on the primary and stop writes for the duration of the backup.")
 	Backup.Flags().Int32Var(&backupOptions.Concurrency, "concurrency", 4, "Specifies the number of compression/checksum jobs to run simultaneously.")
@@ -303,4 +352,8 @@ func init() {
 	RestoreFromBackup.Flags().StringVar(&restoreFromBackupOptions.RestoreToTimestamp, "restore-to-timestamp", "", "Run a point in time recovery that restores up to, and excluding, given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`). This will attempt to use one full backup followed by zero or more incremental backups")
 	RestoreFromBackup.Flags().BoolVar(&restoreFromBackupOptions.DryRun, "dry-run", false, "Only validate restore steps, do not actually restore data")
 	Root.AddCommand(RestoreFromBackup)
+
+	VerifyBackup.Flags().StringVar(&verifyBackupOptions.BackupName, "backup-name", "", "Name of the backup to verify. Omit to verify the latest complete backup.")
+	VerifyBackup.Flags().Int32Var(&verifyBackupOptions.Concurrency, "concurrency", 4, "Specifies the number of files to restore simultaneously.")
+	Root.AddCommand(VerifyBackup)
 }
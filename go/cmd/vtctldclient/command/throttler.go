@@ -33,7 +33,7 @@ import (
 var (
 	// UpdateThrottlerConfig makes a UpdateThrottlerConfig gRPC call to a vtctld.
 	UpdateThrottlerConfig = &cobra.Command{
-		Use:                   "UpdateThrottlerConfig [--enable|--disable] [--threshold=<float64>] [--custom-query=<query>] [--check-as-check-self|--check-as-check-shard] [--throttle-app|unthrottle-app=<name>] [--throttle-app-ratio=<float, range [0..1]>] [--throttle-app-duration=<duration>] <keyspace>",
+		Use:                   "UpdateThrottlerConfig [--enable|--disable] [--threshold=<float64>] [--custom-query=<query>] [--custom-metric-url=<url>] [--check-as-check-self|--check-as-check-shard] [--throttle-app|unthrottle-app=<name>] [--throttle-app-ratio=<float, range [0..1]>] [--throttle-app-duration=<duration>] [--throttle-app-exempt] [--throttle-app-dry-run] [--metric-name=<name>] [--app-name=<name> --app-metrics=<metrics>] <keyspace>",
 		Short:                 "Update the tablet throttler configuration for all tablets in the given keyspace (across all cells)",
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
@@ -55,8 +55,13 @@ func commandUpdateThrottlerConfig(cmd *cobra.Command, args []string) error {
 	if throttledAppRule.Name != "" && unthrottledAppRule.Name != "" {
 		return fmt.Errorf("throttle-app and unthrottle-app are mutually exclusive")
 	}
+	if throttledAppRule.DryRun && !cmd.Flags().Changed("throttle-app-ratio") {
+		// A dry-run rule only records how the app would be throttled, unless explicitly given a ratio
+		throttledAppRule.Ratio = 0
+	}
 
 	updateThrottlerConfigOptions.CustomQuerySet = cmd.Flags().Changed("custom-query")
+	updateThrottlerConfigOptions.CustomMetricUrlSet = cmd.Flags().Changed("custom-metric-url")
 	updateThrottlerConfigOptions.Keyspace = keyspace
 
 	if throttledAppRule.Name != "" {
@@ -79,14 +84,19 @@ func init() {
 	UpdateThrottlerConfig.Flags().BoolVar(&updateThrottlerConfigOptions.Disable, "disable", false, "Disable the throttler")
 	UpdateThrottlerConfig.Flags().Float64Var(&updateThrottlerConfigOptions.Threshold, "threshold", 0, "threshold for the either default check (replication lag seconds) or custom check")
 	UpdateThrottlerConfig.Flags().StringVar(&updateThrottlerConfigOptions.CustomQuery, "custom-query", "", "custom throttler check query")
+	UpdateThrottlerConfig.Flags().StringVar(&updateThrottlerConfigOptions.CustomMetricUrl, "custom-metric-url", "", "URL of a local HTTP endpoint the custom metric is read from instead of the custom query, e.g. http://localhost:9100/metrics#node_load1 to read a metric from a Prometheus exposition. The host must be a loopback address")
+	UpdateThrottlerConfig.Flags().StringVar(&updateThrottlerConfigOptions.MetricName, "metric-name", "", "name of the metric the --threshold applies to, e.g. lag, threads_running, history_list_length, loadavg, custom")
+	UpdateThrottlerConfig.Flags().StringVar(&updateThrottlerConfigOptions.AppName, "app-name", "", "name of the app whose checked metrics are set by --app-metrics. Use 'all' for apps with no assigned metrics")
+	UpdateThrottlerConfig.Flags().StringSliceVar(&updateThrottlerConfigOptions.AppCheckedMetrics, "app-metrics", nil, "comma separated list of metrics checked by the app given in --app-name. An empty list reverts the app to the default metrics")
 	UpdateThrottlerConfig.Flags().BoolVar(&updateThrottlerConfigOptions.CheckAsCheckSelf, "check-as-check-self", false, "/throttler/check requests behave as is /throttler/check-self was called")
 	UpdateThrottlerConfig.Flags().BoolVar(&updateThrottlerConfigOptions.CheckAsCheckShard, "check-as-check-shard", false, "use standard behavior for /throttler/check requests")
 
 	UpdateThrottlerConfig.Flags().StringVar(&unthrottledAppRule.Name, "unthrottle-app", "", "an app name to unthrottle")
 	UpdateThrottlerConfig.Flags().StringVar(&throttledAppRule.Name, "throttle-app", "", "an app name to throttle")
-	UpdateThrottlerConfig.Flags().Float64Var(&throttledAppRule.Ratio, "throttle-app-ratio", throttle.DefaultThrottleRatio, "ratio to throttle app (app specififed in --throttled-app)")
+	UpdateThrottlerConfig.Flags().Float64Var(&throttledAppRule.Ratio, "throttle-app-ratio", throttle.DefaultThrottleRatio, "ratio to throttle app (app specififed in --throttled-app). Defaults to 0 with --throttle-app-dry-run")
 	UpdateThrottlerConfig.Flags().DurationVar(&throttledAppDuration, "throttle-app-duration", throttle.DefaultAppThrottleDuration, "duration after which throttled app rule expires (app specififed in --throttled-app)")
 	UpdateThrottlerConfig.Flags().BoolVar(&throttledAppRule.Exempt, "throttle-app-exempt", throttledAppRule.Exempt, "exempt this app from being at all throttled. WARNING: use with extreme care, as this is likely to push metrics beyond the throttler's threshold, and starve other apps")
+	UpdateThrottlerConfig.Flags().BoolVar(&throttledAppRule.DryRun, "throttle-app-dry-run", throttledAppRule.DryRun, "evaluate and record the checks of this app, but always respond to them with OK. Use with --throttle-app=all to put all apps in dry-run mode. The app is not throttled by ratio unless --throttle-app-ratio is also given")
 
 	Root.AddCommand(UpdateThrottlerConfig)
 }
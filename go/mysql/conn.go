@@ -151,6 +151,11 @@ type Conn struct {
 	// PrepareData is the map to use a prepared statement.
 	PrepareData map[uint32]*PrepareData
 
+	// QueryAttributes are the query attributes sent by the client with
+	// the COM_QUERY or COM_STMT_EXECUTE being handled, if it negotiated
+	// CapabilityClientQueryAttributes. It is only used by the server.
+	QueryAttributes []QueryAttribute
+
 	// protects the bufferedWriter and bufferedReader
 	bufMu sync.Mutex
 
@@ -194,6 +199,15 @@ type Conn struct {
 	// Packet encoding variables.
 	sequence uint8
 
+	// compression is set once protocol compression was negotiated
+	// and the handshake is done. All packets are then sent and
+	// received as part of compressed packets.
+	compression *compressedIO
+
+	// zstdCompressionLevel is the compression level used with zstd
+	// protocol compression. It is sent by the client in its handshake.
+	zstdCompressionLevel int
+
 	// ExpectSemiSyncIndicator is applicable when the connection is used for replication (ComBinlogDump).
 	// When 'true', events are assumed to be padded with 2-byte semi-sync information
 	// See https://dev.mysql.com/doc/internals/en/semi-sync-binlog-event.html
@@ -229,6 +243,12 @@ type PrepareData struct {
 	ParamsCount uint16
 }
 
+// QueryAttribute is a query attribute, as sent by MySQL 8 clients along with a query.
+type QueryAttribute struct {
+	Name  string
+	Value sqltypes.Value
+}
+
 // execResult is an enum signifying the result of executing a query
 type execResult byte
 
@@ -323,7 +343,7 @@ func (c *Conn) startWriterBuffering() {
 	defer c.bufMu.Unlock()
 
 	c.bufferedWriter = writersPool.Get().(*bufio.Writer)
-	c.bufferedWriter.Reset(c.conn)
+	c.bufferedWriter.Reset(c.getWriter())
 }
 
 // endWriterBuffering must be called to terminate startWriteBuffering.
@@ -371,14 +391,35 @@ func (c *Conn) startFlushTimer() {
 }
 
 // getReader returns reader for connection. It can be *bufio.Reader or net.Conn
-// depending on which buffer size was passed to newServerConn.
+// depending on which buffer size was passed to newServerConn, or the reader
+// of the compressed packets if compression is used.
 func (c *Conn) getReader() io.Reader {
+	if c.compression != nil {
+		return c.compression
+	}
 	if c.bufferedReader != nil {
 		return c.bufferedReader
 	}
 	return c.conn
 }
 
+// getWriter returns the writer for unbuffered writes on the connection. It is
+// net.Conn, or the writer of the compressed packets if compression is used.
+func (c *Conn) getWriter() io.Writer {
+	if c.compression != nil {
+		return c.compression
+	}
+	return c.conn
+}
+
+// resetSequence resets the packet sequence at the start of a new command.
+func (c *Conn) resetSequence() {
+	c.sequence = 0
+	if c.compression != nil {
+		c.compression.sequence = 0
+	}
+}
+
 func (c *Conn) readHeaderFrom(r io.Reader) (int, error) {
 	// Note io.ReadFull will return two different types of errors:
 	// 1. if the socket is already closed, and the go runtime knows it,
@@ -400,7 +441,12 @@ func (c *Conn) readHeaderFrom(r io.Reader) (int, error) {
 	}
 
 	sequence := uint8(c.header[3])
-	if sequence != c.sequence {
+	if c.compression != nil {
+		// Like MySQL, we don't check the sequence of the packets
+		// within compressed packets, only the sequence of the
+		// compressed packets themselves.
+		c.sequence = sequence
+	} else if sequence != c.sequence {
 		return 0, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid sequence, expected %v got %v", c.sequence, sequence)
 	}
 
@@ -611,7 +657,7 @@ func (c *Conn) writePacket(data []byte) error {
 		}()
 	} else {
 		c.bufMu.Unlock()
-		w = c.conn
+		w = c.getWriter()
 	}
 
 	var header [packetHeaderSize]byte
@@ -715,7 +761,7 @@ func (c *Conn) recycleWritePacket() {
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) writeComQuit() error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 
 	data, pos := c.startEphemeralPacketWithHeader(1)
 	data[pos] = ComQuit
@@ -921,7 +967,7 @@ func (c *Conn) writeEOFPacket(flags uint16, warnings uint16) error {
 // handleNextCommand is called in the server loop to process
 // incoming packets.
 func (c *Conn) handleNextCommand(handler Handler) bool {
-	c.sequence = 0
+	c.resetSequence()
 	data, err := c.readEphemeralPacket()
 	if err != nil {
 		// Don't log EOF errors. They cause too much spam.
@@ -970,11 +1016,10 @@ func (c *Conn) handleNextCommand(handler Handler) bool {
 	case ComResetConnection:
 		c.handleComResetConnection(handler)
 		return true
+	case ComChangeUser:
+		return c.handleComChangeUser(handler, data)
 	case ComFieldList:
-		c.recycleReadPacket()
-		if !c.writeErrorAndLog(sqlerror.ERUnknownComError, sqlerror.SSNetError, "command handling not implemented yet: %v", data[0]) {
-			return false
-		}
+		return c.handleComFieldList(handler, data)
 	case ComBinlogDump:
 		return c.handleComBinlogDump(handler, data)
 	case ComBinlogDumpGTID:
@@ -1070,6 +1115,84 @@ func (c *Conn) handleComResetConnection(handler Handler) {
 	}
 }
 
+// handleComChangeUser authenticates the connection as another user. As
+// for MySQL, the user and the session are left untouched if authentication
+// fails. Otherwise, the session is reset, as if the user just connected.
+func (c *Conn) handleComChangeUser(handler Handler, data []byte) bool {
+	user, authMethod, authResponse, schemaName, characterSet, err := c.parseComChangeUser(data)
+	c.recycleReadPacket()
+	if err != nil {
+		log.Errorf("Cannot parse COM_CHANGE_USER from %s: %v", c, err)
+		return c.writeErrorAndLog(sqlerror.ERUnknownComError, sqlerror.SSNetError, "error handling packet: %v", err)
+	}
+	if c.listener == nil {
+		return c.writeErrorAndLog(sqlerror.ERUnknownComError, sqlerror.SSNetError, "command handling not implemented yet: %v", ComChangeUser)
+	}
+
+	userData, ok := c.listener.authenticate(c, user, authMethod, authResponse, c.salt)
+	if !ok {
+		return true
+	}
+
+	if c.User != "" {
+		connCountPerUser.Add(c.User, -1)
+	}
+	c.User = user
+	c.UserData = userData
+	if c.User != "" {
+		connCountPerUser.Add(c.User, 1)
+	}
+	if characterSet != 0 {
+		c.CharacterSet = characterSet
+	}
+	c.schemaName = schemaName
+	c.PrepareData = make(map[uint32]*PrepareData)
+	handler.ComChangeUser(c)
+
+	if c.schemaName != "" {
+		err = handler.ComQuery(c, "use "+sqlescape.EscapeID(c.schemaName), func(result *sqltypes.Result) error {
+			return nil
+		})
+		if err != nil {
+			return c.writeErrorPacketFromErrorAndLog(err)
+		}
+	}
+
+	if err := c.writeOKPacket(&PacketOK{statusFlags: c.StatusFlags}); err != nil {
+		log.Errorf("Error writing COM_CHANGE_USER OK packet to %s: %v", c, err)
+		return false
+	}
+	return true
+}
+
+func (c *Conn) handleComFieldList(handler Handler, data []byte) (kontinue bool) {
+	c.startWriterBuffering()
+	defer func() {
+		if err := c.endWriterBuffering(); err != nil {
+			log.Errorf("conn %v: flush() failed: %v", c.ID(), err)
+			kontinue = false
+		}
+	}()
+
+	table, wildcard, ok := c.parseComFieldList(data)
+	c.recycleReadPacket()
+	if !ok {
+		log.Errorf("Got unhandled packet from client %v, returning error: %v", c.ConnectionID, data)
+		return c.writeErrorAndLog(sqlerror.ERUnknownComError, sqlerror.SSNetError, "error handling packet: %v", data)
+	}
+
+	fields, err := handler.ComFieldList(c, table, wildcard)
+	if err != nil {
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
+
+	if err := c.writeFieldList(fields); err != nil {
+		log.Errorf("Error writing ComFieldList result to %s: %v", c, err)
+		return false
+	}
+	return true
+}
+
 func (c *Conn) handleComStmtReset(data []byte) bool {
 	stmtID, ok := c.parseComStmtReset(data)
 	c.recycleReadPacket()
@@ -1142,6 +1265,9 @@ func (c *Conn) handleComStmtExecute(handler Handler, data []byte) (kontinue bool
 	queryStart := time.Now()
 	stmtID, _, err := c.parseComStmtExecute(c.PrepareData, data)
 	c.recycleReadPacket()
+	defer func() {
+		c.QueryAttributes = nil
+	}()
 
 	if stmtID != uint32(0) {
 		defer func() {
@@ -1358,11 +1484,16 @@ func (c *Conn) handleComQuery(handler Handler, data []byte) (kontinue bool) {
 	}()
 
 	queryStart := time.Now()
-	query := c.parseComQuery(data)
+	query, err := c.parseComQuery(data)
 	c.recycleReadPacket()
+	defer func() {
+		c.QueryAttributes = nil
+	}()
+	if err != nil {
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
 
 	var queries []string
-	var err error
 	if c.Capabilities&CapabilityClientMultiStatements != 0 {
 		queries, err = handler.Env().Parser().SplitStatementToPieces(query)
 		if err != nil {
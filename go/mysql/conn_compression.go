@@ -0,0 +1,316 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// This file contains the protocol compression, negotiated with
+// CapabilityClientCompress (zlib) or CapabilityClientZstdCompressionAlgorithm (zstd).
+// See: https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_basic_compression.html
+//
+// Once the handshake is done, the regular packets are not sent on the wire
+// anymore, but are framed into compressed packets. A compressed packet has
+// its own header and sequence, and its payload can hold any number of regular
+// packets, or parts of them. The regular packet reading and writing code is
+// unaware of this, as the compressed packets are read and written by an
+// io.Reader / io.Writer that sits between it and the network connection.
+
+const (
+	// compressedPacketHeaderSize is the 7 bytes of header per compressed
+	// packet: the length of the compressed payload, the sequence, and
+	// the length of the uncompressed payload.
+	compressedPacketHeaderSize = 7
+
+	// minCompressLength is the smallest payload we compress. Smaller payloads
+	// are sent as is, with an uncompressed length of 0, as MySQL does.
+	minCompressLength = 50
+
+	// defaultZstdCompressionLevel is the zstd level used by MySQL
+	// clients when none is specified.
+	defaultZstdCompressionLevel = 3
+)
+
+// compressionAlgorithm compresses and decompresses the payload of
+// compressed packets.
+type compressionAlgorithm interface {
+	// compress appends the compressed data to dst.
+	compress(dst, data []byte) ([]byte, error)
+	// decompress fills dst with the decompressed data.
+	decompress(dst, data []byte) error
+}
+
+// newCompressionAlgorithm returns the compression to use for the negotiated capabilities.
+// zstd is preferred when both sides support both algorithms.
+func newCompressionAlgorithm(capabilities uint32, zstdLevel int) compressionAlgorithm {
+	switch {
+	case capabilities&CapabilityClientZstdCompressionAlgorithm != 0:
+		return newZstdCompression(zstdLevel)
+	case capabilities&CapabilityClientCompress != 0:
+		return &zlibCompression{}
+	default:
+		return nil
+	}
+}
+
+// zlibCompression is the CapabilityClientCompress algorithm.
+// The zlib writer and reader are kept for the lifetime of the connection.
+type zlibCompression struct {
+	w *zlib.Writer
+	r io.ReadCloser
+}
+
+func (z *zlibCompression) compress(dst, data []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(dst)
+	if z.w == nil {
+		z.w = zlib.NewWriter(buf)
+	} else {
+		z.w.Reset(buf)
+	}
+	if _, err := z.w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := z.w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (z *zlibCompression) decompress(dst, data []byte) error {
+	var err error
+	if z.r == nil {
+		z.r, err = zlib.NewReader(bytes.NewReader(data))
+	} else {
+		err = z.r.(zlib.Resetter).Reset(bytes.NewReader(data), nil)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = io.ReadFull(z.r, dst)
+	return err
+}
+
+// zstdCompression is the CapabilityClientZstdCompressionAlgorithm algorithm.
+// The encoders and the decoder are safe for concurrent use, and are shared
+// by all the connections.
+type zstdCompression struct {
+	encoder *zstd.Encoder
+}
+
+var (
+	zstdEncodersMu sync.Mutex
+	zstdEncoders   = map[zstd.EncoderLevel]*zstd.Encoder{}
+
+	// zstdPacketDecoder is used to decompress the compressed packets.
+	// They can never hold more than MaxPacketSize bytes of data.
+	zstdPacketDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(MaxPacketSize))
+)
+
+// zstdCompressionLevel returns the zstd level to use, 0 meaning the default level.
+func zstdCompressionLevel(level int) int {
+	if level == 0 {
+		return defaultZstdCompressionLevel
+	}
+	return level
+}
+
+func newZstdCompression(level int) *zstdCompression {
+	encoderLevel := zstd.EncoderLevelFromZstd(zstdCompressionLevel(level))
+
+	zstdEncodersMu.Lock()
+	defer zstdEncodersMu.Unlock()
+	encoder, ok := zstdEncoders[encoderLevel]
+	if !ok {
+		// This can only fail with invalid options.
+		encoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(encoderLevel), zstd.WithEncoderConcurrency(1))
+		zstdEncoders[encoderLevel] = encoder
+	}
+	return &zstdCompression{encoder: encoder}
+}
+
+func (z *zstdCompression) compress(dst, data []byte) ([]byte, error) {
+	return z.encoder.EncodeAll(data, dst), nil
+}
+
+func (z *zstdCompression) decompress(dst, data []byte) error {
+	out, err := zstdPacketDecoder.DecodeAll(data, dst[:0])
+	if err != nil {
+		return err
+	}
+	if len(out) != len(dst) {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "decompressed packet has length %v, expected %v", len(out), len(dst))
+	}
+	return nil
+}
+
+// compressedIO reads and writes compressed packets on behalf of a Conn.
+type compressedIO struct {
+	algorithm compressionAlgorithm
+
+	// r is the underlying reader, and w the underlying writer.
+	r io.Reader
+	w io.Writer
+
+	// sequence is the sequence of the compressed packets. It is reset
+	// with the sequence of the regular packets at the start of each command.
+	sequence uint8
+
+	header [compressedPacketHeaderSize]byte
+
+	// readBuffer holds the uncompressed payload of the last compressed packet
+	// we read, and readPos is how much of it was already consumed.
+	readBuffer *[]byte
+	readPos    int
+}
+
+// enableCompression switches the connection to compressed packets,
+// if compression was negotiated during the handshake.
+func (c *Conn) enableCompression() {
+	algorithm := newCompressionAlgorithm(c.Capabilities, c.zstdCompressionLevel)
+	if algorithm == nil {
+		return
+	}
+	c.compression = &compressedIO{
+		algorithm: algorithm,
+		r:         c.getReader(),
+		w:         c.conn,
+	}
+}
+
+// Read implements io.Reader. It returns the uncompressed data
+// of the compressed packets read from the underlying reader.
+func (cio *compressedIO) Read(p []byte) (int, error) {
+	for cio.readBuffer == nil || cio.readPos == len(*cio.readBuffer) {
+		if err := cio.readCompressedPacket(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, (*cio.readBuffer)[cio.readPos:])
+	cio.readPos += n
+	return n, nil
+}
+
+func (cio *compressedIO) readCompressedPacket() error {
+	if cio.readBuffer != nil {
+		bufPool.Put(cio.readBuffer)
+		cio.readBuffer = nil
+	}
+
+	// io.EOF is returned as is, so the server can
+	// recognize a client that just disconnected.
+	if _, err := io.ReadFull(cio.r, cio.header[:]); err != nil {
+		if err == io.EOF {
+			return err
+		}
+		return vterrors.Wrapf(err, "io.ReadFull(compressed header size) failed")
+	}
+
+	sequence := cio.header[3]
+	if sequence != cio.sequence {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid compressed sequence, expected %v got %v", cio.sequence, sequence)
+	}
+	cio.sequence++
+
+	length := int(uint32(cio.header[0]) | uint32(cio.header[1])<<8 | uint32(cio.header[2])<<16)
+	uncompressedLength := int(uint32(cio.header[4]) | uint32(cio.header[5])<<8 | uint32(cio.header[6])<<16)
+
+	payload := bufPool.Get(length)
+	if _, err := io.ReadFull(cio.r, *payload); err != nil {
+		bufPool.Put(payload)
+		return vterrors.Wrapf(err, "io.ReadFull(compressed packet body of length %v) failed", length)
+	}
+
+	// An uncompressed length of 0 means the payload was sent as is.
+	if uncompressedLength == 0 {
+		cio.readBuffer = payload
+		cio.readPos = 0
+		return nil
+	}
+
+	defer bufPool.Put(payload)
+	uncompressed := bufPool.Get(uncompressedLength)
+	if err := cio.algorithm.decompress(*uncompressed, *payload); err != nil {
+		bufPool.Put(uncompressed)
+		return vterrors.Wrapf(err, "cannot decompress packet of length %v", length)
+	}
+	cio.readBuffer = uncompressed
+	cio.readPos = 0
+	return nil
+}
+
+// Write implements io.Writer. It sends the data as one or more
+// compressed packets to the underlying writer.
+func (cio *compressedIO) Write(data []byte) (int, error) {
+	written := 0
+	for len(data) > 0 {
+		toBeSent := min(len(data), MaxPacketSize)
+		if err := cio.writeCompressedPacket(data[:toBeSent]); err != nil {
+			return written, err
+		}
+		written += toBeSent
+		data = data[toBeSent:]
+	}
+	return written, nil
+}
+
+func (cio *compressedIO) writeCompressedPacket(data []byte) error {
+	buf := bufPool.Get(compressedPacketHeaderSize + len(data))
+	defer bufPool.Put(buf)
+
+	packet := (*buf)[:compressedPacketHeaderSize]
+	uncompressedLength := 0
+	if len(data) >= minCompressLength {
+		compressed, err := cio.algorithm.compress(packet, data)
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot compress packet of length %v", len(data))
+		}
+		// Only use the compressed data if it is actually smaller.
+		if len(compressed)-compressedPacketHeaderSize < len(data) {
+			packet = compressed
+			uncompressedLength = len(data)
+		}
+	}
+	if uncompressedLength == 0 {
+		packet = append(packet, data...)
+	}
+
+	length := len(packet) - compressedPacketHeaderSize
+	packet[0] = byte(length)
+	packet[1] = byte(length >> 8)
+	packet[2] = byte(length >> 16)
+	packet[3] = cio.sequence
+	packet[4] = byte(uncompressedLength)
+	packet[5] = byte(uncompressedLength >> 8)
+	packet[6] = byte(uncompressedLength >> 16)
+
+	if n, err := cio.w.Write(packet); err != nil {
+		return vterrors.Wrapf(err, "Write(compressed packet) failed")
+	} else if n != len(packet) {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "Write(compressed packet) returned a short write: %v < %v", n, len(packet))
+	}
+	cio.sequence++
+	return nil
+}
@@ -64,6 +64,11 @@ type ConnParams struct {
 	// FlushDelay is the delay after which buffered response will be flushed to the client.
 	FlushDelay time.Duration
 
+	// ZstdCompressionLevel is the compression level sent to the server when
+	// zstd protocol compression is requested by setting
+	// CapabilityClientZstdCompressionAlgorithm in Flags. 0 means the default level.
+	ZstdCompressionLevel int
+
 	TruncateErrLen int
 }
 
@@ -222,6 +222,52 @@ func TestPackets(t *testing.T) {
 	verifyPacketComms(t, cConn, sConn, data)
 }
 
+func TestCompressedPackets(t *testing.T) {
+	for _, capability := range []uint32{CapabilityClientCompress, CapabilityClientZstdCompressionAlgorithm} {
+		t.Run(fmt.Sprintf("%x", capability), func(t *testing.T) {
+			listener, sConn, cConn := createSocketPair(t)
+			defer func() {
+				listener.Close()
+				sConn.Close()
+				cConn.Close()
+			}()
+			cConn.Capabilities |= capability
+			cConn.enableCompression()
+			sConn.Capabilities |= capability
+			sConn.enableCompression()
+
+			verify := func(data []byte) {
+				for _, write := range []func(t *testing.T, cConn *Conn, data []byte){useWritePacket, useWriteEphemeralPacketBuffered, useWriteEphemeralPacketDirect} {
+					verifyPacketCommsSpecific(t, cConn, data, write, sConn.ReadPacket)
+					verifyPacketCommsSpecific(t, cConn, data, write, sConn.readEphemeralPacket)
+					sConn.recycleReadPacket()
+				}
+			}
+
+			// Small one, sent uncompressed.
+			verify([]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+
+			// 0 length packet
+			verify([]byte{})
+
+			// Large enough to be compressed.
+			verify(bytes.Repeat([]byte("compressed "), 1000))
+
+			// Not compressible, sent uncompressed.
+			data := make([]byte, 1000)
+			_, err := crypto_rand.Read(data)
+			require.NoError(t, err)
+			verify(data)
+
+			// Over the limit, two packets, and two compressed packets.
+			data = make([]byte, MaxPacketSize+1000)
+			data[0] = 0xab
+			data[MaxPacketSize+999] = 0xef
+			verify(data)
+		})
+	}
+}
+
 func TestBasicPackets(t *testing.T) {
 	require := require.New(t)
 	assert := assert.New(t)
@@ -926,6 +972,54 @@ func TestInitDbAgainstWrongDbDoesNotDropConnection(t *testing.T) {
 	require.EqualValues(t, data[0], ErrPacket) // we should see the error here
 }
 
+func TestComFieldList(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	writeComFieldList := func(table, wildcard string) {
+		cConn.resetSequence()
+		data, pos := cConn.startEphemeralPacketWithHeader(1 + len(table) + 1 + len(wildcard))
+		pos = writeByte(data, pos, ComFieldList)
+		pos = writeNullString(data, pos, table)
+		_ = writeEOFString(data, pos, wildcard)
+		require.NoError(t, cConn.writeEphemeralPacket())
+	}
+
+	writeComFieldList("t1", "na%")
+	data, err := sConn.ReadPacket()
+	require.NoError(t, err)
+	table, wildcard, ok := sConn.parseComFieldList(data)
+	require.True(t, ok)
+	assert.Equal(t, "t1", table)
+	assert.Equal(t, "na%", wildcard)
+
+	// The column definitions are followed by an EOF packet.
+	writeComFieldList("t1", "")
+	handler := &testRun{t: t}
+	require.True(t, sConn.handleNextCommand(handler))
+	for i, want := range selectRowsResult.Fields {
+		field := &querypb.Field{}
+		require.NoError(t, cConn.readColumnDefinition(field, i))
+		assert.Equal(t, want.Name, field.Name)
+		assert.Equal(t, want.Type, field.Type)
+	}
+	data, err = cConn.ReadPacket()
+	require.NoError(t, err)
+	require.True(t, cConn.isEOFPacket(data), "expected an EOF packet, got %v", data)
+
+	// An error does not close the connection.
+	writeComFieldList("t1", "")
+	handler = &testRun{t: t, err: fmt.Errorf("execution failed")}
+	require.True(t, sConn.handleNextCommand(handler))
+	data, err = cConn.ReadPacket()
+	require.NoError(t, err)
+	require.EqualValues(t, ErrPacket, data[0])
+}
+
 func TestConnectionErrorWhileWritingComQuery(t *testing.T) {
 	// Set the conn for the server connection to the simulated connection which always returns an error on writing
 	sConn := newConn(testConn{
@@ -1137,6 +1231,13 @@ func (t testRun) ComPrepare(c *Conn, query string, bv map[string]*querypb.BindVa
 	return nil, nil
 }
 
+func (t testRun) ComFieldList(c *Conn, table, wildcard string) ([]*querypb.Field, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	return selectRowsResult.Fields, nil
+}
+
 func (t testRun) WarningCount(c *Conn) uint16 {
 	return 0
 }
@@ -76,15 +76,19 @@ const (
 	// CLIENT_NO_SCHEMA 1 << 4
 	// Do not permit database.table.column. We do permit it.
 
-	// CLIENT_COMPRESS 1 << 5
-	// We do not support compression. CPU is usually our bottleneck.
+	// CapabilityClientCompress is CLIENT_COMPRESS.
+	// Use zlib protocol compression once the handshake is done.
+	// Only enabled when both sides ask for it, as CPU is usually
+	// our bottleneck.
+	CapabilityClientCompress = 1 << 5
 
 	// CLIENT_ODBC 1 << 6
 	// No special behavior since 3.22.
 
-	// CLIENT_LOCAL_FILES 1 << 7
+	// CapabilityClientLocalFiles is CLIENT_LOCAL_FILES.
 	// Client can use LOCAL INFILE request of LOAD DATA|XML.
-	// We do not set it.
+	// We only set it if the listener enables LOCAL INFILE.
+	CapabilityClientLocalFiles = 1 << 7
 
 	// CLIENT_IGNORE_SPACE 1 << 8
 	// Parser can ignore spaces before '('.
@@ -148,6 +152,18 @@ const (
 	// CapabilityClientDeprecateEOF is CLIENT_DEPRECATE_EOF
 	// Expects an OK (instead of EOF) after the resultset rows of a Text Resultset.
 	CapabilityClientDeprecateEOF = 1 << 24
+
+	// CLIENT_OPTIONAL_RESULTSET_METADATA 1 << 25
+	// Not supported.
+
+	// CapabilityClientZstdCompressionAlgorithm is CLIENT_ZSTD_COMPRESSION_ALGORITHM
+	// Use zstd protocol compression once the handshake is done. The
+	// compression level is sent at the end of Protocol::HandshakeResponse41.
+	CapabilityClientZstdCompressionAlgorithm = 1 << 26
+
+	// CapabilityClientQueryAttributes is CLIENT_QUERY_ATTRIBUTES
+	// Can send query attributes with COM_QUERY and COM_STMT_EXECUTE.
+	CapabilityClientQueryAttributes = 1 << 27
 )
 
 // Status flags. They are returned by the server in a few cases.
@@ -209,6 +225,9 @@ const (
 	// ComPing is COM_PING.
 	ComPing = 0x0e
 
+	// ComChangeUser is COM_CHANGE_USER.
+	ComChangeUser = 0x11
+
 	// ComBinlogDump is COM_BINLOG_DUMP.
 	ComBinlogDump = 0x12
 
@@ -257,6 +276,16 @@ const (
 
 	// NullValue is the encoded value of NULL.
 	NullValue = 0xfb
+
+	// LocalInfilePacket is the header of the LOCAL INFILE request packet.
+	LocalInfilePacket = 0xfb
+)
+
+// Flags sent with the cursor type of COM_STMT_EXECUTE.
+const (
+	// ParameterCountAvailable is PARAMETER_COUNT_AVAILABLE.
+	// The parameter count is sent, as it includes the query attributes.
+	ParameterCountAvailable = 0x08
 )
 
 // Auth packet types
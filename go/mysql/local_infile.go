@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"io"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+)
+
+// RequestLocalInfile asks the client for the content of the given file, as part of
+// a LOAD DATA LOCAL INFILE statement being handled by a ComQuery. The client sends
+// the content in packets that are read by the returned reader. The reader must be
+// closed, which drains what the client has not sent yet, before the result of the
+// statement is written.
+// Server -> Client.
+func (c *Conn) RequestLocalInfile(fileName string) (io.ReadCloser, error) {
+	if c.Capabilities&CapabilityClientLocalFiles == 0 {
+		return nil, sqlerror.NewSQLError(sqlerror.ERNotAllowedCommand, sqlerror.SSClientError, "Loading local data is disabled; this must be enabled on both the client and server sides")
+	}
+
+	data, pos := c.startEphemeralPacketWithHeader(1 + len(fileName))
+	pos = writeByte(data, pos, LocalInfilePacket)
+	writeEOFString(data, pos, fileName)
+	if err := c.writeEphemeralPacket(); err != nil {
+		return nil, sqlerror.NewSQLError(sqlerror.CRServerGone, sqlerror.SSUnknownSQLState, "%v", err)
+	}
+	// The client only answers once it received the request.
+	if err := c.flushBufferedWriter(); err != nil {
+		return nil, sqlerror.NewSQLError(sqlerror.CRServerGone, sqlerror.SSUnknownSQLState, "%v", err)
+	}
+	return &localInfileReader{c: c}, nil
+}
+
+// flushBufferedWriter flushes the buffered writer of the connection, if writes are buffered.
+func (c *Conn) flushBufferedWriter() error {
+	c.bufMu.Lock()
+	defer c.bufMu.Unlock()
+
+	if c.bufferedWriter == nil {
+		return nil
+	}
+	return c.bufferedWriter.Flush()
+}
+
+// localInfileReader reads the content of a file sent by the client in response to
+// a LOCAL INFILE request. The content ends with an empty packet.
+type localInfileReader struct {
+	c    *Conn
+	data []byte
+	done bool
+	err  error
+}
+
+// Read implements io.Reader.
+func (r *localInfileReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		r.next()
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// Close drains the packets the client has not sent yet, so that the connection is
+// ready for the result of the statement.
+func (r *localInfileReader) Close() error {
+	for !r.done && r.err == nil {
+		r.next()
+	}
+	r.data = nil
+	return r.err
+}
+
+func (r *localInfileReader) next() {
+	data, err := r.c.readPacket()
+	if err != nil {
+		r.err = sqlerror.NewSQLError(sqlerror.CRServerLost, sqlerror.SSUnknownSQLState, "%v", err)
+		return
+	}
+	if len(data) == 0 {
+		r.done = true
+		return
+	}
+	r.data = data
+}
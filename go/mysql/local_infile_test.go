@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+)
+
+func TestRequestLocalInfile(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	// The client did not enable LOCAL INFILE.
+	_, err := sConn.RequestLocalInfile("/tmp/data.csv")
+	require.Error(t, err)
+	assert.Equal(t, sqlerror.ERNotAllowedCommand, err.(*sqlerror.SQLError).Number())
+
+	sConn.Capabilities |= CapabilityClientLocalFiles
+	sConn.startWriterBuffering()
+	defer sConn.endWriterBuffering()
+
+	// The client reads the request and sends the file in two packets.
+	clientErr := make(chan error, 1)
+	go func() {
+		data, err := cConn.ReadPacket()
+		if err != nil {
+			clientErr <- err
+			return
+		}
+		if !assert.Equal(t, append([]byte{LocalInfilePacket}, "/tmp/data.csv"...), data) {
+			clientErr <- nil
+			return
+		}
+		useWritePacket(t, cConn, []byte("1,a\n2,"))
+		useWritePacket(t, cConn, []byte("b\n"))
+		useWritePacket(t, cConn, nil)
+		clientErr <- nil
+	}()
+
+	reader, err := sConn.RequestLocalInfile("/tmp/data.csv")
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "1,a\n2,b\n", string(content))
+	require.NoError(t, reader.Close())
+	require.NoError(t, <-clientErr)
+
+	// Closing the reader drains the content that was not read.
+	go func() {
+		if _, err := cConn.ReadPacket(); err != nil {
+			clientErr <- err
+			return
+		}
+		useWritePacket(t, cConn, []byte("3,c\n"))
+		useWritePacket(t, cConn, nil)
+		useWritePacket(t, cConn, []byte{OKPacket})
+		clientErr <- nil
+	}()
+
+	reader, err = sConn.RequestLocalInfile("/tmp/data.csv")
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.NoError(t, <-clientErr)
+	data, err := sConn.ReadPacket()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{OKPacket}, data)
+}
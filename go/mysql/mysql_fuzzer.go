@@ -288,6 +288,14 @@ func (th *fuzzTestHandler) ComResetConnection(c *Conn) {
 
 }
 
+func (th *fuzzTestHandler) ComChangeUser(c *Conn) {
+
+}
+
+func (th *fuzzTestHandler) ComFieldList(c *Conn, table, wildcard string) ([]*querypb.Field, error) {
+	return nil, nil
+}
+
 func (th *fuzzTestHandler) WarningCount(c *Conn) uint16 {
 	th.mu.Lock()
 	defer th.mu.Unlock()
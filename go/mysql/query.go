@@ -55,7 +55,7 @@ const (
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) WriteComQuery(query string) error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 
 	data, pos := c.startEphemeralPacketWithHeader(len(query) + 1)
 	data[pos] = ComQuery
@@ -574,8 +574,83 @@ func (c *Conn) readComQueryResponse(packetOk *PacketOK) (int, error) {
 // Server side methods.
 //
 
-func (c *Conn) parseComQuery(data []byte) string {
-	return string(data[1:])
+// parseComQuery parses a COM_QUERY packet, and returns the query. The query
+// attributes sent before the query, if any, are stored in c.QueryAttributes.
+func (c *Conn) parseComQuery(data []byte) (string, error) {
+	c.QueryAttributes = nil
+	if c.Capabilities&CapabilityClientQueryAttributes == 0 {
+		return string(data[1:]), nil
+	}
+
+	count, pos, ok := readLenEncInt(data, 1)
+	if !ok || count > uint64(len(data)) {
+		return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attributes count failed")
+	}
+	// The parameter set count is always 1.
+	_, pos, ok = readLenEncInt(data, pos)
+	if !ok {
+		return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attributes set count failed")
+	}
+	if count == 0 {
+		return string(data[pos:]), nil
+	}
+
+	nullBitmap, pos, ok := readBytes(data, pos, (int(count)+7)/8)
+	if !ok {
+		return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attributes NULL-bitmap failed")
+	}
+	newParamsBoundFlag, pos, ok := readByte(data, pos)
+	if !ok || newParamsBoundFlag != 0x01 {
+		return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attributes types failed")
+	}
+
+	attributes := make([]QueryAttribute, count)
+	types := make([]querypb.Type, count)
+	for i := range attributes {
+		var err error
+		types[i], pos, err = readParameterType(data, pos)
+		if err != nil {
+			return "", err
+		}
+		attributes[i].Name, pos, ok = readLenEncString(data, pos)
+		if !ok {
+			return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attribute name failed")
+		}
+	}
+	for i := range attributes {
+		if nullBitmap[i/8]&(1<<uint(i%8)) > 0 {
+			attributes[i].Value = sqltypes.NULL
+			continue
+		}
+		attributes[i].Value, pos, ok = c.parseStmtArgs(data, types[i], pos)
+		if !ok {
+			return "", sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "decoding query attribute value failed: %v", types[i])
+		}
+	}
+
+	c.QueryAttributes = attributes
+	return string(data[pos:]), nil
+}
+
+// readParameterType reads the type and the flags of a parameter of
+// COM_STMT_EXECUTE, or of a query attribute.
+func readParameterType(data []byte, pos int) (querypb.Type, int, error) {
+	mysqlType, pos, ok := readByte(data, pos)
+	if !ok {
+		return 0, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter type failed")
+	}
+
+	flags, pos, ok := readByte(data, pos)
+	if !ok {
+		return 0, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter flags failed")
+	}
+
+	// convert MySQL type to internal type.
+	valType, err := sqltypes.MySQLToType(mysqlType, int64(flags))
+	if err != nil {
+		return 0, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "MySQLToType(%v,%v) failed: %v", mysqlType, flags, err)
+	}
+	return valType, pos, nil
 }
 
 func (c *Conn) parseComSetOption(data []byte) (uint16, bool) {
@@ -587,7 +662,11 @@ func (c *Conn) parseComPrepare(data []byte) string {
 	return string(data[1:])
 }
 
+// parseComStmtExecute parses a COM_STMT_EXECUTE packet, and stores the values of the parameters
+// in the PrepareData of the statement. The query attributes, if any, are stored in c.QueryAttributes.
 func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []byte) (uint32, byte, error) {
+	c.QueryAttributes = nil
+
 	pos := 0
 	payload := data[1:]
 	bitMap := make([]byte, 0)
@@ -617,8 +696,22 @@ func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []b
 		return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "iteration count is not equal to 1")
 	}
 
-	if prepare.ParamsCount > 0 {
-		bitMap, pos, ok = readBytes(payload, pos, (int(prepare.ParamsCount)+7)/8)
+	// With query attributes, the parameter count can be sent. The
+	// parameters after the ones of the statement are the query attributes.
+	paramsCount := int(prepare.ParamsCount)
+	if c.Capabilities&CapabilityClientQueryAttributes != 0 && cursorType&ParameterCountAvailable != 0 {
+		count, newPos, ok := readLenEncInt(payload, pos)
+		if !ok || count < uint64(prepare.ParamsCount) || count > uint64(len(payload)) {
+			return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter count failed")
+		}
+		paramsCount = int(count)
+		pos = newPos
+	}
+	attributes := make([]QueryAttribute, paramsCount-int(prepare.ParamsCount))
+	attributeTypes := make([]querypb.Type, len(attributes))
+
+	if paramsCount > 0 {
+		bitMap, pos, ok = readBytes(payload, pos, (paramsCount+7)/8)
 		if !ok {
 			return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading NULL-bitmap failed")
 		}
@@ -626,26 +719,32 @@ func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []b
 
 	newParamsBoundFlag, pos, ok := readByte(payload, pos)
 	if ok && newParamsBoundFlag == 0x01 {
-		var mysqlType, flags byte
-		for i := uint16(0); i < prepare.ParamsCount; i++ {
-			mysqlType, pos, ok = readByte(payload, pos)
-			if !ok {
-				return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter type failed")
+		for i := 0; i < paramsCount; i++ {
+			valType, newPos, err := readParameterType(payload, pos)
+			if err != nil {
+				return stmtID, 0, err
 			}
+			pos = newPos
 
-			flags, pos, ok = readByte(payload, pos)
-			if !ok {
-				return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter flags failed")
+			// With query attributes, every parameter has a name,
+			// which is only meaningful for the query attributes.
+			var name string
+			if c.Capabilities&CapabilityClientQueryAttributes != 0 {
+				name, pos, ok = readLenEncString(payload, pos)
+				if !ok {
+					return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter name failed")
+				}
 			}
 
-			// convert MySQL type to internal type.
-			valType, err := sqltypes.MySQLToType(mysqlType, int64(flags))
-			if err != nil {
-				return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "MySQLToType(%v,%v) failed: %v", mysqlType, flags, err)
+			if i < int(prepare.ParamsCount) {
+				prepare.ParamsType[i] = int32(valType)
+			} else {
+				attributes[i-int(prepare.ParamsCount)].Name = name
+				attributeTypes[i-int(prepare.ParamsCount)] = valType
 			}
-
-			prepare.ParamsType[i] = int32(valType)
 		}
+	} else if len(attributes) > 0 {
+		return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attributes types failed")
 	}
 
 	for i := 0; i < len(prepare.ParamsType); i++ {
@@ -669,6 +768,21 @@ func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []b
 		prepare.BindVars[parameterID] = sqltypes.ValueBindVariable(val)
 	}
 
+	for i := range attributes {
+		bit := int(prepare.ParamsCount) + i
+		if (bitMap[bit/8] & (1 << uint(bit%8))) > 0 {
+			attributes[i].Value = sqltypes.NULL
+			continue
+		}
+		attributes[i].Value, pos, ok = c.parseStmtArgs(payload, attributeTypes[i], pos)
+		if !ok {
+			return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "decoding query attribute value failed: %v", attributeTypes[i])
+		}
+	}
+	if len(attributes) > 0 {
+		c.QueryAttributes = attributes
+	}
+
 	return stmtID, cursorType, nil
 }
 
@@ -932,6 +1046,68 @@ func (c *Conn) parseComInitDB(data []byte) string {
 	return string(data[1:])
 }
 
+// parseComFieldList parses a COM_FIELD_LIST packet. It returns the table name,
+// and the wildcard that the column names have to match, possibly empty.
+func (c *Conn) parseComFieldList(data []byte) (string, string, bool) {
+	table, pos, ok := readNullString(data, 1)
+	if !ok {
+		return "", "", false
+	}
+	return table, string(data[pos:]), true
+}
+
+// parseComChangeUser parses a COM_CHANGE_USER packet. It returns the user, the auth method,
+// a copy of the auth response, the schema name and the character set (0 if not sent).
+func (c *Conn) parseComChangeUser(data []byte) (string, AuthMethodDescription, []byte, string, collations.ID, error) {
+	pos := 1
+
+	user, pos, ok := readNullString(data, pos)
+	if !ok {
+		return "", "", nil, "", 0, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read user")
+	}
+
+	// We only support protocol 4.1 clients, that always send a length-prefixed auth response.
+	authResponseLen, pos, ok := readByte(data, pos)
+	if !ok {
+		return "", "", nil, "", 0, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read auth-response length")
+	}
+	authResponse, pos, ok := readBytesCopy(data, pos, int(authResponseLen))
+	if !ok {
+		return "", "", nil, "", 0, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read auth-response")
+	}
+
+	schemaName, pos, ok := readNullString(data, pos)
+	if !ok {
+		return "", "", nil, "", 0, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read schema name")
+	}
+
+	// The packet can end here.
+	authMethod := MysqlNativePassword
+	if pos == len(data) {
+		return user, authMethod, authResponse, schemaName, 0, nil
+	}
+
+	characterSet, pos, ok := readUint16(data, pos)
+	if !ok {
+		return "", "", nil, "", 0, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read character set")
+	}
+
+	// The auth method is only sent with CapabilityClientPluginAuth,
+	// and can be followed by the connection attributes, that we ignore.
+	if pos < len(data) {
+		var authMethodStr string
+		authMethodStr, _, ok = readNullString(data, pos)
+		if !ok {
+			return "", "", nil, "", 0, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseComChangeUser: can't read auth method")
+		}
+		if authMethodStr != "" {
+			authMethod = AuthMethodDescription(authMethodStr)
+		}
+	}
+
+	return user, authMethod, authResponse, schemaName, collations.ID(characterSet), nil
+}
+
 func (c *Conn) sendColumnCount(count uint64) error {
 	length := lenEncIntSize(count)
 	data, pos := c.startEphemeralPacketWithHeader(length)
@@ -940,6 +1116,12 @@ func (c *Conn) sendColumnCount(count uint64) error {
 }
 
 func (c *Conn) writeColumnDefinition(field *querypb.Field) error {
+	return c.writeColumnDefinitionPacket(field, false)
+}
+
+// writeColumnDefinitionPacket writes a column definition. For COM_FIELD_LIST,
+// it is followed by the default value of the column, that we always send as NULL.
+func (c *Conn) writeColumnDefinitionPacket(field *querypb.Field, fieldList bool) error {
 	length := 4 + // lenEncStringSize("def")
 		lenEncStringSize(field.Database) +
 		lenEncStringSize(field.Table) +
@@ -953,6 +1135,9 @@ func (c *Conn) writeColumnDefinition(field *querypb.Field) error {
 		2 + // flags
 		1 + // decimals
 		2 // filler
+	if fieldList {
+		length++ // default value
+	}
 
 	// Get the type and the flags back. If the Field contains
 	// non-zero flags, we use them. Otherwise use the flags we
@@ -977,6 +1162,9 @@ func (c *Conn) writeColumnDefinition(field *querypb.Field) error {
 	pos = writeUint16(data, pos, uint16(flags))
 	pos = writeByte(data, pos, byte(field.Decimals))
 	pos = writeUint16(data, pos, uint16(0x0000))
+	if fieldList {
+		pos = writeByte(data, pos, NullValue)
+	}
 
 	if pos != len(data) {
 		return vterrors.Errorf(vtrpc.Code_INTERNAL, "packing of column definition used %v bytes instead of %v", pos, len(data))
@@ -1045,6 +1233,17 @@ func (c *Conn) writeRows(result *sqltypes.Result) error {
 	return nil
 }
 
+// writeFieldList sends the response of a COM_FIELD_LIST: the column
+// definitions, not preceded by a column count, and an EOF packet.
+func (c *Conn) writeFieldList(fields []*querypb.Field) error {
+	for _, field := range fields {
+		if err := c.writeColumnDefinitionPacket(field, true); err != nil {
+			return err
+		}
+	}
+	return c.writeEndResult(false, 0, 0, 0)
+}
+
 // writeEndResult concludes the sending of a Result.
 // if more is set to true, then it means there are more results afterwords
 func (c *Conn) writeEndResult(more bool, affectedRows, lastInsertID uint64, warnings uint16) error {
@@ -101,6 +101,57 @@ func TestComInitDB(t *testing.T) {
 
 }
 
+func TestComQueryAttributes(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	// Without the capability, the whole payload is the query.
+	query, err := sConn.parseComQuery(append([]byte{ComQuery}, "select 1"...))
+	require.NoError(t, err)
+	assert.Equal(t, "select 1", query)
+	assert.Nil(t, sConn.QueryAttributes)
+
+	sConn.Capabilities |= CapabilityClientQueryAttributes
+
+	// No query attributes.
+	query, err = sConn.parseComQuery(append([]byte{ComQuery, 0x00, 0x01}, "select 1"...))
+	require.NoError(t, err)
+	assert.Equal(t, "select 1", query)
+	assert.Nil(t, sConn.QueryAttributes)
+
+	data := []byte{
+		ComQuery,
+		0x03,       // parameter count
+		0x01,       // parameter set count
+		0x04,       // NULL-bitmap
+		0x01,       // new params bound flag
+		0xfd, 0x00, // VAR_STRING
+		0x05, 't', 'r', 'a', 'c', 'e',
+		0x08, 0x00, // LONGLONG
+		0x01, 'n',
+		0x06, 0x00, // NULL
+		0x07, 'n', 'o', 't', 'h', 'i', 'n', 'g',
+		0x03, 'a', 'b', 'c',
+		0x2a, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+	query, err = sConn.parseComQuery(append(data, "select 1"...))
+	require.NoError(t, err)
+	assert.Equal(t, "select 1", query)
+	assert.Equal(t, []QueryAttribute{
+		{Name: "trace", Value: sqltypes.NewVarBinary("abc")},
+		{Name: "n", Value: sqltypes.NewInt64(42)},
+		{Name: "nothing", Value: sqltypes.NULL},
+	}, sConn.QueryAttributes)
+
+	// Truncated packet.
+	_, err = sConn.parseComQuery(data[:10])
+	assert.ErrorContains(t, err, "reading query attribute name failed")
+}
+
 func TestComSetOption(t *testing.T) {
 	listener, sConn, cConn := createSocketPair(t)
 	defer func() {
@@ -331,6 +382,66 @@ func TestComStmtExecuteUpdStmt(t *testing.T) {
 	assert.EqualValues(t, querypb.Type_CHAR, prepData.ParamsType[28], "got: %s", querypb.Type(prepData.ParamsType[28]))
 }
 
+func TestComStmtExecuteQueryAttributes(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+	sConn.Capabilities |= CapabilityClientQueryAttributes
+
+	prepareDataMap := map[uint32]*PrepareData{
+		1: {
+			StatementID: 1,
+			ParamsCount: 1,
+			ParamsType:  make([]int32, 1),
+			BindVars:    map[string]*querypb.BindVariable{},
+		}}
+
+	// `select * from t where id = ?` with id = 5, and the query attributes
+	// `trace` = 'abc' and `nothing` = NULL.
+	data := []byte{
+		ComStmtExecute, 0x01, 0x00, 0x00, 0x00, ParameterCountAvailable, 0x01, 0x00, 0x00, 0x00,
+		0x03,       // parameter count
+		0x04,       // NULL-bitmap
+		0x01,       // new params bound flag
+		0x08, 0x00, // LONGLONG
+		0x00,       // no name
+		0xfd, 0x00, // VAR_STRING
+		0x05, 't', 'r', 'a', 'c', 'e',
+		0x06, 0x00, // NULL
+		0x07, 'n', 'o', 't', 'h', 'i', 'n', 'g',
+		0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x03, 'a', 'b', 'c',
+	}
+
+	stmtID, _, err := sConn.parseComStmtExecute(prepareDataMap, data)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, stmtID)
+	assert.Equal(t, sqltypes.Int64BindVariable(5), prepareDataMap[1].BindVars["v1"])
+	assert.Equal(t, []QueryAttribute{
+		{Name: "trace", Value: sqltypes.NewVarBinary("abc")},
+		{Name: "nothing", Value: sqltypes.NULL},
+	}, sConn.QueryAttributes)
+
+	// Without PARAMETER_COUNT_AVAILABLE, only the parameters are sent.
+	prepareDataMap[1].BindVars = map[string]*querypb.BindVariable{}
+	data = []byte{
+		ComStmtExecute, 0x01, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
+		0x00,       // NULL-bitmap
+		0x01,       // new params bound flag
+		0x08, 0x00, // LONGLONG
+		0x00, // no name
+		0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+
+	_, _, err = sConn.parseComStmtExecute(prepareDataMap, data)
+	require.NoError(t, err)
+	assert.Equal(t, sqltypes.Int64BindVariable(7), prepareDataMap[1].BindVars["v1"])
+	assert.Nil(t, sConn.QueryAttributes)
+}
+
 func TestComStmtClose(t *testing.T) {
 	listener, sConn, cConn := createSocketPair(t)
 	defer func() {
@@ -51,8 +51,10 @@ type ReplicationStatus struct {
 	RelayLogFilePosition  Position
 	SourceServerID        uint32
 	IOState               ReplicationState
+	LastIOErrno           uint32
 	LastIOError           string
 	SQLState              ReplicationState
+	LastSQLErrno          uint32
 	LastSQLError          string
 	ReplicationLagSeconds uint32
 	ReplicationLagUnknown bool
@@ -110,8 +112,10 @@ func ReplicationStatusToProto(s ReplicationStatus) *replicationdatapb.Status {
 		ConnectRetry:                           s.ConnectRetry,
 		SourceUuid:                             s.SourceUUID.String(),
 		IoState:                                int32(s.IOState),
+		LastIoErrno:                            s.LastIOErrno,
 		LastIoError:                            s.LastIOError,
 		SqlState:                               int32(s.SQLState),
+		LastSqlErrno:                           s.LastSQLErrno,
 		LastSqlError:                           s.LastSQLError,
 		SslAllowed:                             s.SSLAllowed,
 		HasReplicationFilters:                  s.HasReplicationFilters,
@@ -166,8 +170,10 @@ func ProtoToReplicationStatus(s *replicationdatapb.Status) ReplicationStatus {
 		ConnectRetry:                           s.ConnectRetry,
 		SourceUUID:                             sid,
 		IOState:                                ReplicationState(s.IoState),
+		LastIOErrno:                            s.LastIoErrno,
 		LastIOError:                            s.LastIoError,
 		SQLState:                               ReplicationState(s.SqlState),
+		LastSQLErrno:                           s.LastSqlErrno,
 		LastSQLError:                           s.LastSqlError,
 		SSLAllowed:                             s.SslAllowed,
 		HasReplicationFilters:                  s.HasReplicationFilters,
@@ -313,6 +319,10 @@ func ParseReplicationStatus(fields map[string]string) ReplicationStatus {
 	status.SourceServerID = uint32(parseUint)
 	parseUint, _ = strconv.ParseUint(fields["SQL_Delay"], 10, 32)
 	status.SQLDelay = uint32(parseUint)
+	parseUint, _ = strconv.ParseUint(fields["Last_IO_Errno"], 10, 32)
+	status.LastIOErrno = uint32(parseUint)
+	parseUint, _ = strconv.ParseUint(fields["Last_SQL_Errno"], 10, 32)
+	status.LastSQLErrno = uint32(parseUint)
 
 	executedPosStr := fields["Exec_Master_Log_Pos"]
 	file := fields["Relay_Master_Log_File"]
@@ -145,6 +145,23 @@ func TestMysqlRetrieveSourceServerId(t *testing.T) {
 	assert.Equalf(t, got.SourceServerID, want.SourceServerID, "got SourceServerID: %v; want SourceServerID: %v", got.SourceServerID, want.SourceServerID)
 }
 
+func TestMysqlRetrieveReplicationErrors(t *testing.T) {
+	resultMap := map[string]string{
+		"Slave_IO_Running":  "Yes",
+		"Slave_SQL_Running": "No",
+		"Last_IO_Errno":     "0",
+		"Last_SQL_Errno":    "1062",
+		"Last_SQL_Error":    "Could not execute Write_rows event on table ks.t1; Duplicate entry '1' for key 't1.PRIMARY'",
+	}
+
+	got, err := ParseMysqlReplicationStatus(resultMap)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, got.LastIOErrno)
+	assert.EqualValues(t, 1062, got.LastSQLErrno)
+	assert.Equal(t, resultMap["Last_SQL_Error"], got.LastSQLError)
+	assert.Equal(t, got, ProtoToReplicationStatus(ReplicationStatusToProto(got)))
+}
+
 func TestMysqlRetrieveFileBasedPositions(t *testing.T) {
 	resultMap := map[string]string{
 		"Exec_Master_Log_Pos":   "1307",
@@ -111,6 +111,11 @@ type Handler interface {
 	// statement query.
 	ComPrepare(c *Conn, query string, bindVars map[string]*querypb.BindVariable) ([]*querypb.Field, error)
 
+	// ComFieldList is called when a connection receives a COM_FIELD_LIST
+	// request. It returns the columns of the table whose names match
+	// the wildcard, a LIKE pattern that can be empty.
+	ComFieldList(c *Conn, table, wildcard string) ([]*querypb.Field, error)
+
 	// ComStmtExecute is called when a connection receives a statement
 	// execute query.
 	ComStmtExecute(c *Conn, prepare *PrepareData, callback func(*sqltypes.Result) error) error
@@ -133,6 +138,11 @@ type Handler interface {
 
 	ComResetConnection(c *Conn)
 
+	// ComChangeUser is called when a connection was authenticated as
+	// another user with COM_CHANGE_USER. The User and UserData of the
+	// connection are already updated.
+	ComChangeUser(c *Conn)
+
 	Env() *vtenv.Environment
 }
 
@@ -146,6 +156,11 @@ func (UnimplementedHandler) NewConnection(*Conn)      {}
 func (UnimplementedHandler) ConnectionReady(*Conn)    {}
 func (UnimplementedHandler) ConnectionClosed(*Conn)   {}
 func (UnimplementedHandler) ComResetConnection(*Conn) {}
+func (UnimplementedHandler) ComChangeUser(*Conn)      {}
+
+func (UnimplementedHandler) ComFieldList(*Conn, string, string) ([]*querypb.Field, error) {
+	return nil, sqlerror.NewSQLError(sqlerror.ERUnknownComError, sqlerror.SSNetError, "command handling not implemented yet: %v", ComFieldList)
+}
 
 // Listener is the MySQL server protocol listener.
 type Listener struct {
@@ -207,6 +222,15 @@ type Listener struct {
 	// RequireSecureTransport configures the server to reject connections from insecure clients
 	RequireSecureTransport bool
 
+	// EnableCompression configures the server to advertise zlib and zstd
+	// protocol compression. It is only used with clients that ask for it.
+	EnableCompression bool
+
+	// EnableLocalInfile configures the server to advertise LOCAL INFILE, so that
+	// handlers can read files from the clients that enable it, as part of
+	// LOAD DATA LOCAL INFILE statements.
+	EnableLocalInfile bool
+
 	// PreHandleFunc is called for each incoming connection, immediately after
 	// accepting a new connection. By default it's no-op. Useful for custom
 	// connection inspection or TLS termination. The returned connection is
@@ -391,13 +415,15 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 	defer connCount.Add(-1)
 
 	// First build and send the server handshake packet.
-	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, uint8(l.charset), l.TLSConfig.Load() != nil)
+	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, uint8(l.charset), l.TLSConfig.Load() != nil, l.EnableCompression, l.EnableLocalInfile)
 	if err != nil {
 		if err != io.EOF {
 			log.Errorf("Cannot send HandshakeV10 packet to %s: %v", c, err)
 		}
 		return
 	}
+	// The salt is used again if the client sends a COM_CHANGE_USER.
+	c.salt = serverAuthPluginData
 
 	// Wait for the client response. This has to be a direct read,
 	// so we don't buffer the TLS negotiation packets.
@@ -450,6 +476,73 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 		defer connCountByTLSVer.Add(versionNoTLS, -1)
 	}
 
+	userData, ok := l.authenticate(c, user, clientAuthMethod, clientAuthResponse, serverAuthPluginData)
+	if !ok {
+		return
+	}
+
+	c.User = user
+	c.UserData = userData
+
+	// The user can be changed later on with COM_CHANGE_USER,
+	// which then moves the connection to the new user.
+	if c.User != "" {
+		connCountPerUser.Add(c.User, 1)
+	}
+	defer func() {
+		if c.User != "" {
+			connCountPerUser.Add(c.User, -1)
+		}
+	}()
+
+	// Set initial db name.
+	if c.schemaName != "" {
+		err = l.handler.ComQuery(c, "use "+sqlescape.EscapeID(c.schemaName), func(result *sqltypes.Result) error {
+			return nil
+		})
+		if err != nil {
+			c.writeErrorPacketFromError(err)
+			return
+		}
+	}
+
+	// Negotiation worked, send OK packet.
+	if err := c.writeOKPacket(&PacketOK{statusFlags: c.StatusFlags}); err != nil {
+		log.Errorf("Cannot write OK packet to %s: %v", c, err)
+		return
+	}
+
+	// Both sides switch to compressed packets once authenticated.
+	c.enableCompression()
+
+	// Record how long we took to establish the connection
+	timings.Record(connectTimingKey, acceptTime)
+
+	// Log a warning if it took too long to connect
+	connectTime := time.Since(acceptTime).Nanoseconds()
+	if threshold := l.SlowConnectWarnThreshold.Load(); threshold != 0 && connectTime > threshold {
+		connSlow.Add(1)
+		log.Warningf("Slow connection from %s: %v", c, connectTime)
+	}
+
+	// Tell our handler that we're finished handshake and are ready to
+	// process commands.
+	l.handler.ConnectionReady(c)
+
+	for {
+		kontinue := c.handleNextCommand(l.handler)
+		// before going for next command check if the connection should be closed or not.
+		if !kontinue || c.IsMarkedForClose() {
+			return
+		}
+	}
+}
+
+// authenticate authenticates the user with the auth method and auth response sent by the
+// client, switching to another auth method if needed. It is used for the initial handshake,
+// and for COM_CHANGE_USER. It returns the UserData of the user, and false if authentication
+// failed, in which case the error was already sent to the client.
+func (l *Listener) authenticate(c *Conn, user string, clientAuthMethod AuthMethodDescription, clientAuthResponse, serverAuthPluginData []byte) (Getter, bool) {
 	// See what auth method the AuthServer wants to use for that user.
 	negotiatedAuthMethod, err := negotiateAuthMethod(c, l.authServer, user, clientAuthMethod)
 
@@ -475,86 +568,40 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 
 		if negotiatedAuthMethod == nil {
 			c.writeErrorPacket(sqlerror.CRServerHandshakeErr, sqlerror.SSUnknownSQLState, "No authentication methods available for authentication.")
-			return
+			return nil, false
 		}
 
 		if !l.AllowClearTextWithoutTLS.Load() && !c.TLSEnabled() && !negotiatedAuthMethod.AllowClearTextWithoutTLS() {
 			c.writeErrorPacket(sqlerror.CRServerHandshakeErr, sqlerror.SSUnknownSQLState, "Cannot use clear text authentication over non-SSL connections.")
-			return
+			return nil, false
 		}
 
 		serverAuthPluginData, err = negotiatedAuthMethod.AuthPluginData()
 		if err != nil {
 			log.Errorf("Error generating auth switch packet for %s: %v", c, err)
-			return
+			return nil, false
 		}
 
 		if err := c.writeAuthSwitchRequest(string(negotiatedAuthMethod.Name()), serverAuthPluginData); err != nil {
 			log.Errorf("Error writing auth switch packet for %s: %v", c, err)
-			return
+			return nil, false
 		}
 
 		clientAuthResponse, err = c.readEphemeralPacket()
 		if err != nil {
 			log.Errorf("Error reading auth switch response for %s: %v", c, err)
-			return
+			return nil, false
 		}
 		c.recycleReadPacket()
 	}
 
-	userData, err := negotiatedAuthMethod.HandleAuthPluginData(c, user, serverAuthPluginData, clientAuthResponse, conn.RemoteAddr())
+	userData, err := negotiatedAuthMethod.HandleAuthPluginData(c, user, serverAuthPluginData, clientAuthResponse, c.conn.RemoteAddr())
 	if err != nil {
 		log.Warningf("Error authenticating user %s using: %s", user, negotiatedAuthMethod.Name())
 		c.writeErrorPacketFromError(err)
-		return
-	}
-
-	c.User = user
-	c.UserData = userData
-
-	if c.User != "" {
-		connCountPerUser.Add(c.User, 1)
-		defer connCountPerUser.Add(c.User, -1)
-	}
-
-	// Set initial db name.
-	if c.schemaName != "" {
-		err = l.handler.ComQuery(c, "use "+sqlescape.EscapeID(c.schemaName), func(result *sqltypes.Result) error {
-			return nil
-		})
-		if err != nil {
-			c.writeErrorPacketFromError(err)
-			return
-		}
-	}
-
-	// Negotiation worked, send OK packet.
-	if err := c.writeOKPacket(&PacketOK{statusFlags: c.StatusFlags}); err != nil {
-		log.Errorf("Cannot write OK packet to %s: %v", c, err)
-		return
-	}
-
-	// Record how long we took to establish the connection
-	timings.Record(connectTimingKey, acceptTime)
-
-	// Log a warning if it took too long to connect
-	connectTime := time.Since(acceptTime).Nanoseconds()
-	if threshold := l.SlowConnectWarnThreshold.Load(); threshold != 0 && connectTime > threshold {
-		connSlow.Add(1)
-		log.Warningf("Slow connection from %s: %v", c, connectTime)
-	}
-
-	// Tell our handler that we're finished handshake and are ready to
-	// process commands.
-	l.handler.ConnectionReady(c)
-
-	for {
-		kontinue := c.handleNextCommand(l.handler)
-		// before going for next command check if the connection should be closed or not.
-		if !kontinue || c.IsMarkedForClose() {
-			return
-		}
+		return nil, false
 	}
+	return userData, true
 }
 
 // Close stops the listener, which prevents accept of any new connections. Existing connections won't be closed.
@@ -572,7 +619,7 @@ func (l *Listener) Shutdown() {
 
 // writeHandshakeV10 writes the Initial Handshake Packet, server side.
 // It returns the salt data.
-func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, charset uint8, enableTLS bool) ([]byte, error) {
+func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, charset uint8, enableTLS bool, enableCompression bool, enableLocalInfile bool) ([]byte, error) {
 	capabilities := CapabilityClientLongPassword |
 		CapabilityClientFoundRows |
 		CapabilityClientLongFlag |
@@ -585,10 +632,17 @@ func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, ch
 		CapabilityClientPluginAuth |
 		CapabilityClientPluginAuthLenencClientData |
 		CapabilityClientDeprecateEOF |
-		CapabilityClientConnAttr
+		CapabilityClientConnAttr |
+		CapabilityClientQueryAttributes
 	if enableTLS {
 		capabilities |= CapabilityClientSSL
 	}
+	if enableCompression {
+		capabilities |= CapabilityClientCompress | CapabilityClientZstdCompressionAlgorithm
+	}
+	if enableLocalInfile {
+		capabilities |= CapabilityClientLocalFiles
+	}
 
 	// Grab the default auth method. This can only be either
 	// mysql_native_password or caching_sha2_password. Both
@@ -706,7 +760,7 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 	// later in the protocol. If we re-received the handshake packet
 	// after SSL negotiation, do not overwrite capabilities.
 	if firstTime {
-		c.Capabilities = clientFlags & (CapabilityClientDeprecateEOF | CapabilityClientFoundRows)
+		c.Capabilities = clientFlags & (CapabilityClientDeprecateEOF | CapabilityClientFoundRows | CapabilityClientQueryAttributes)
 	}
 
 	// set connection capability for executing multi statements
@@ -714,6 +768,16 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 		c.Capabilities |= CapabilityClientMultiStatements
 	}
 
+	// set connection capability for protocol compression, if we support it
+	if l.EnableCompression {
+		c.Capabilities |= clientFlags & (CapabilityClientCompress | CapabilityClientZstdCompressionAlgorithm)
+	}
+
+	// set connection capability for LOCAL INFILE requests, if we support it
+	if l.EnableLocalInfile {
+		c.Capabilities |= clientFlags & CapabilityClientLocalFiles
+	}
+
 	// Max packet size. Don't do anything with this now.
 	// See doc.go for more information.
 	_, pos, ok = readUint32(data, pos)
@@ -809,6 +873,24 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 		if _, _, err := parseConnAttrs(data, pos); err != nil {
 			log.Warningf("Decode connection attributes send by the client: %v", err)
 		}
+		// Skip the attributes by their encoded length, even if they could not be
+		// decoded, so that the fields after them are read from the right offset.
+		attrsLen, attrsPos, ok := readLenEncInt(data, pos)
+		if ok && attrsLen <= uint64(len(data)-attrsPos) {
+			pos = attrsPos + int(attrsLen)
+		} else {
+			pos = len(data)
+		}
+	}
+
+	// zstd compression level, only sent if the client asked for zstd.
+	if clientFlags&CapabilityClientZstdCompressionAlgorithm != 0 {
+		var level byte
+		level, _, ok = readByte(data, pos)
+		if !ok {
+			return "", "", nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "parseClientHandshakePacket: can't read zstd compression level")
+		}
+		c.zstdCompressionLevel = int(level)
 	}
 
 	return username, AuthMethodDescription(authMethod), authResponse, nil
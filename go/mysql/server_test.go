@@ -471,6 +471,82 @@ func TestClientFoundRows(t *testing.T) {
 	c.Close()
 }
 
+func TestServerCompression(t *testing.T) {
+	th := &testHandler{}
+
+	listen := func(enableCompression bool) (*Listener, string, int) {
+		l, err := NewListener("tcp", "127.0.0.1:", NewAuthServerNone(), th, 0, 0, false, false, 0, 0)
+		require.NoError(t, err)
+		l.EnableCompression = enableCompression
+		go l.Accept()
+		host, port := getHostPort(t, l.Addr())
+		return l, host, port
+	}
+
+	l, host, port := listen(true)
+	defer l.Close()
+
+	rows := make([][]sqltypes.Value, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		rows = append(rows, []sqltypes.Value{
+			sqltypes.NewInt32(int32(i)),
+			sqltypes.NewVarChar(strings.Repeat("compressed name ", 10)),
+		})
+	}
+	largeResult := &sqltypes.Result{Fields: selectRowsResult.Fields, Rows: rows}
+
+	for _, flags := range []uint64{CapabilityClientCompress, CapabilityClientZstdCompressionAlgorithm, CapabilityClientCompress | CapabilityClientZstdCompressionAlgorithm} {
+		t.Run(fmt.Sprintf("%x", flags), func(t *testing.T) {
+			params := &ConnParams{
+				Host:  host,
+				Port:  port,
+				Flags: flags,
+			}
+			c, err := Connect(context.Background(), params)
+			require.NoError(t, err)
+			defer c.Close()
+			require.NotNil(t, c.compression)
+
+			result, err := c.ExecuteFetch("select rows", 10000, true)
+			require.NoError(t, err)
+			utils.MustMatch(t, selectRowsResult, result)
+
+			th.mu.Lock()
+			th.result = largeResult
+			th.mu.Unlock()
+			defer func() {
+				th.mu.Lock()
+				th.result = nil
+				th.mu.Unlock()
+			}()
+
+			// A query large enough to be compressed as well.
+			result, err = c.ExecuteFetch("select "+strings.Repeat("1, ", 1000)+"1", 10000, true)
+			require.NoError(t, err)
+			utils.MustMatch(t, largeResult.Rows, result.Rows)
+
+			require.NoError(t, c.Ping())
+		})
+	}
+
+	// Compression is only used if both sides ask for it.
+	c, err := Connect(context.Background(), &ConnParams{Host: host, Port: port})
+	require.NoError(t, err)
+	defer c.Close()
+	assert.Nil(t, c.compression)
+	_, err = c.ExecuteFetch("select rows", 10000, true)
+	require.NoError(t, err)
+
+	l, host, port = listen(false)
+	defer l.Close()
+	c, err = Connect(context.Background(), &ConnParams{Host: host, Port: port, Flags: CapabilityClientCompress})
+	require.NoError(t, err)
+	defer c.Close()
+	assert.Nil(t, c.compression)
+	_, err = c.ExecuteFetch("select rows", 10000, true)
+	require.NoError(t, err)
+}
+
 func TestConnCounts(t *testing.T) {
 	th := &testHandler{}
 
@@ -520,6 +596,117 @@ func TestConnCounts(t *testing.T) {
 	}, 1*time.Second, 10*time.Millisecond)
 }
 
+func TestComChangeUser(t *testing.T) {
+	th := &testHandler{}
+
+	authServer := NewAuthServerStatic("", "", 0)
+	authServer.entries["changeUser1"] = []*AuthServerStaticEntry{{
+		Password: "password1",
+		UserData: "userData1",
+	}}
+	authServer.entries["changeUser2"] = []*AuthServerStaticEntry{{
+		Password: "password2",
+		UserData: "userData2",
+	}}
+	defer authServer.close()
+	l, err := NewListener("tcp", "127.0.0.1:", authServer, th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	defer l.Close()
+	go l.Accept()
+
+	host, port := getHostPort(t, l.Addr())
+	c, err := Connect(context.Background(), &ConnParams{
+		Host:  host,
+		Port:  port,
+		Uname: "changeUser1",
+		Pass:  "password1",
+	})
+	require.NoError(t, err)
+	defer c.Close()
+	checkCountsForUser(t, "changeUser1", 1)
+
+	// A wrong password fails, and keeps the current user.
+	err = changeUser(c, &ConnParams{Uname: "changeUser2", Pass: "bad"}, MysqlNativePassword)
+	require.ErrorContains(t, err, "Access denied for user 'changeUser2'")
+	result, err := c.ExecuteFetch("userData echo", 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, `[[VARCHAR("changeUser1") VARCHAR("userData1")]]`, fmt.Sprintf("%v", result.Rows))
+
+	err = changeUser(c, &ConnParams{Uname: "changeUser2", Pass: "password2"}, MysqlNativePassword)
+	require.NoError(t, err)
+	result, err = c.ExecuteFetch("userData echo", 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, `[[VARCHAR("changeUser2") VARCHAR("userData2")]]`, fmt.Sprintf("%v", result.Rows))
+	assert.Equal(t, "changeUser2", c.User)
+
+	checkCountsForUser(t, "changeUser1", 0)
+	checkCountsForUser(t, "changeUser2", 1)
+}
+
+func TestComChangeUserAuthSwitch(t *testing.T) {
+	th := &testHandler{}
+
+	authServer := NewAuthServerStaticWithAuthMethodDescription("", "", 0, CachingSha2Password)
+	authServer.entries["user1"] = []*AuthServerStaticEntry{{
+		Password: "password1",
+		UserData: "userData1",
+	}}
+	authServer.entries["user2"] = []*AuthServerStaticEntry{{
+		Password: "password2",
+		UserData: "userData2",
+	}}
+	defer authServer.close()
+	// caching_sha2_password is only used over TLS or a unix socket.
+	unixSocket := path.Join(t.TempDir(), "mysql_vitess_test.sock")
+	l, err := NewListener("unix", unixSocket, authServer, th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	defer l.Close()
+	go l.Accept()
+
+	c, err := Connect(context.Background(), &ConnParams{
+		UnixSocket: unixSocket,
+		Uname:      "user1",
+		Pass:       "password1",
+	})
+	require.NoError(t, err)
+	defer c.Close()
+
+	// The server switches the client to caching_sha2_password.
+	err = changeUser(c, &ConnParams{UnixSocket: unixSocket, Uname: "user2", Pass: "password2"}, MysqlNativePassword)
+	require.NoError(t, err)
+	result, err := c.ExecuteFetch("userData echo", 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, `[[VARCHAR("user2") VARCHAR("userData2")]]`, fmt.Sprintf("%v", result.Rows))
+}
+
+// changeUser sends a COM_CHANGE_USER for the user of the params,
+// with the password scrambled for the auth method, and reads the response.
+func changeUser(c *Conn, params *ConnParams, authMethod AuthMethodDescription) error {
+	c.resetSequence()
+
+	var scrambledPassword []byte
+	switch authMethod {
+	case CachingSha2Password:
+		scrambledPassword = ScrambleCachingSha2Password(c.salt, []byte(params.Pass))
+	default:
+		scrambledPassword = ScrambleMysqlNativePassword(c.salt, []byte(params.Pass))
+	}
+
+	length := 1 + len(params.Uname) + 1 + 1 + len(scrambledPassword) + len(params.DbName) + 1 + 2 + len(authMethod) + 1
+	data, pos := c.startEphemeralPacketWithHeader(length)
+	pos = writeByte(data, pos, ComChangeUser)
+	pos = writeNullString(data, pos, params.Uname)
+	pos = writeByte(data, pos, byte(len(scrambledPassword)))
+	pos += copy(data[pos:], scrambledPassword)
+	pos = writeNullString(data, pos, params.DbName)
+	pos = writeUint16(data, pos, uint16(collations.CollationUtf8mb4ID))
+	_ = writeNullString(data, pos, string(authMethod))
+	if err := c.writeEphemeralPacket(); err != nil {
+		return err
+	}
+	return c.handleAuthResponse(params)
+}
+
 func checkCountsForUser(t assert.TestingT, user string, expected int64) {
 	connCounts := connCountPerUser.Counts()
 
@@ -1464,6 +1651,37 @@ func TestParseConnAttrs(t *testing.T) {
 	}
 }
 
+func TestParseClientHandshakePacketZstdLevel(t *testing.T) {
+	l := &Listener{EnableCompression: true}
+	handshake := func(attrs ...byte) []byte {
+		flags := CapabilityClientProtocol41 | CapabilityClientSecureConnection | CapabilityClientConnAttr | CapabilityClientZstdCompressionAlgorithm
+		data := []byte{byte(flags), byte(flags >> 8), byte(flags >> 16), byte(flags >> 24)}
+		data = append(data, 0, 0, 0, 1)          // max packet size
+		data = append(data, 0x21)                // character set
+		data = append(data, make([]byte, 23)...) // reserved
+		data = append(data, 'u', 's', 'e', 'r', 0)
+		data = append(data, 0) // empty auth-response
+		data = append(data, attrs...)
+		return append(data, 7) // zstd compression level
+	}
+
+	// The attributes are skipped by their encoded length, whether they can be decoded or not.
+	for _, attrs := range [][]byte{
+		{0x04, 0x01, 'k', 0x01, 'v'},
+		{0x05, 0x0a, 'a', 'b', 'c', 'd'},
+	} {
+		c := &Conn{}
+		user, _, _, err := l.parseClientHandshakePacket(c, true, handshake(attrs...))
+		require.NoError(t, err)
+		assert.Equal(t, "user", user)
+		assert.Equal(t, 7, c.zstdCompressionLevel)
+	}
+
+	// The compression level cannot be found after attributes longer than the packet.
+	_, _, _, err := l.parseClientHandshakePacket(&Conn{}, true, handshake(0x7f, 0x01, 'k'))
+	require.ErrorContains(t, err, "can't read zstd compression level")
+}
+
 func TestServerFlush(t *testing.T) {
 	mysqlServerFlushDelay := 10 * time.Millisecond
 	th := &testHandler{}
@@ -86,6 +86,7 @@ const (
 	ERDBAccessDenied            = ErrorCode(1044)
 	ERAccessDeniedError         = ErrorCode(1045)
 	ERKillDenied                = ErrorCode(1095)
+	ERColumnAccessDenied        = ErrorCode(1143)
 	ERNoPermissionToCreateUsers = ErrorCode(1211)
 	ERSpecifiedAccessDenied     = ErrorCode(1227)
 
@@ -242,6 +242,7 @@ var stateToMysqlCode = map[vterrors.State]mysqlCode{
 	vterrors.CharacterSetMismatch:         {num: ERCharacterSetMismatch, state: SSUnknownSQLState},
 	vterrors.WrongParametersToNativeFct:   {num: ERWrongParametersToNativeFct, state: SSUnknownSQLState},
 	vterrors.KillDeniedError:              {num: ERKillDenied, state: SSUnknownSQLState},
+	vterrors.ColumnAccessDeniedError:      {num: ERColumnAccessDenied, state: SSClientError},
 	vterrors.BadNullError:                 {num: ERBadNullError, state: SSConstraintViolation},
 	vterrors.InvalidGroupFuncUse:          {num: ERInvalidGroupFuncUse, state: SSUnknownSQLState},
 }
@@ -85,6 +85,9 @@ func UpdateThrottlerTopoConfigRaw(vtctldProcess *cluster.VtctldClientProcess, ke
 		if appRule.Exempt {
 			args = append(args, "--throttle-app-exempt")
 		}
+		if appRule.DryRun {
+			args = append(args, "--throttle-app-dry-run")
+		}
 	}
 	args = append(args, keyspaceName)
 
@@ -29,6 +29,7 @@ import (
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/tableacl"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -51,6 +52,8 @@ type VSchemaWrapper struct {
 	EnableViews           bool
 	TestBuilder           func(query string, vschema plancontext.VSchema, keyspace string) (*engine.Plan, error)
 	Env                   *vtenv.Environment
+	DataACL               *tableacl.DataACL
+	CallerID              *querypb.VTGateCallerID
 }
 
 func (vw *VSchemaWrapper) GetPrepareData(stmtName string) *vtgatepb.PrepareData {
@@ -151,6 +154,18 @@ func (vw *VSchemaWrapper) GetAggregateUDFs() (udfs []string) {
 	return vw.V.GetAggregateUDFs()
 }
 
+func (vw *VSchemaWrapper) TableRestrictions(table *vindexes.Table) *tableacl.TableRestrictions {
+	var keyspace string
+	if table.Keyspace != nil {
+		keyspace = table.Keyspace.Name
+	}
+	return vw.DataACL.Restrictions(keyspace, table.Name.String(), vw.CallerID)
+}
+
+func (vw *VSchemaWrapper) HasTableRestrictions() bool {
+	return vw.DataACL.IsRestricted(vw.CallerID)
+}
+
 func (vw *VSchemaWrapper) GetForeignKeyChecksState() *bool {
 	return vw.ForeignKeyChecksState
 }
@@ -122,6 +122,11 @@ func (this *RowMap) GetUint32(key string) uint32 {
 	return uint32(res)
 }
 
+func (this *RowMap) GetFloat64(key string) float64 {
+	res, _ := strconv.ParseFloat(this.GetString(key), 64)
+	return res
+}
+
 func (this *RowMap) GetBool(key string) bool {
 	return this.GetInt(key) != 0
 }
@@ -0,0 +1,297 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/textutil"
+	"vitess.io/vitess/go/vt/dbconfigs"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// verifyTablesQuery lists the tables checked by VerifyBackup.
+const verifyTablesQuery = "SELECT table_schema, table_name FROM information_schema.tables " +
+	"WHERE table_type = 'BASE TABLE' AND table_schema NOT IN ('mysql', 'sys', 'information_schema', 'performance_schema') " +
+	"ORDER BY table_schema, table_name"
+
+// VerifyBackupParams are the parameters of VerifyBackup.
+type VerifyBackupParams struct {
+	// Cnf and Mysqld are the scratch MySQL instance the backup is restored
+	// into. Its data is deleted, and it is shut down once the backup is
+	// verified.
+	Cnf    *Mycnf
+	Mysqld MysqlDaemon
+	Logger logutil.Logger
+	// Concurrency is the number of files restored in parallel.
+	Concurrency int
+	// Keyspace and Shard are used to infer the directory where backups are stored.
+	Keyspace string
+	Shard    string
+	// BackupName is the name of the backup to verify. The most recent
+	// complete backup is verified if it is empty.
+	BackupName string
+	// Stats let's restore engines report detailed restore timings.
+	Stats backupstats.Stats
+	// MysqlShutdownTimeout defines how long we wait for the scratch MySQL instance to shut down.
+	MysqlShutdownTimeout time.Duration
+}
+
+// BackupVerification is the result of VerifyBackup.
+type BackupVerification struct {
+	BackupName string
+	Engine     string
+	// FileCount is the number of files restored and checked against the
+	// hashes of the MANIFEST, for engines that record them.
+	FileCount int
+	// ManifestPosition is the position recorded in the MANIFEST, and
+	// RestoredPosition the one of the restored MySQL instance.
+	ManifestPosition replication.Position
+	RestoredPosition replication.Position
+	Tables           []TableVerification
+}
+
+// TableVerification is the result of the checks of one restored table.
+type TableVerification struct {
+	Schema string
+	Name   string
+	// CheckStatus is the message of the last row returned by CHECK TABLE.
+	CheckStatus string
+	// Checksum is the value returned by CHECKSUM TABLE.
+	Checksum string
+	// OK is set if CHECK TABLE reported the table as OK.
+	OK bool
+}
+
+// Verified returns true if the restored position matches the MANIFEST, and
+// all the tables are OK.
+func (v *BackupVerification) Verified() bool {
+	if !v.RestoredPosition.Equal(v.ManifestPosition) {
+		return false
+	}
+	for _, table := range v.Tables {
+		if !table.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// NewScratchMysqld returns a Mysqld and a Mycnf for a MySQL instance in a new
+// temporary directory, to restore backups into. The caller is responsible for
+// closing the Mysqld and removing the directory of the Mycnf.
+func NewScratchMysqld(collationEnv *collations.Environment) (*Mysqld, *Mycnf, error) {
+	dir, err := os.MkdirTemp("", "vt_verify_backup_")
+	if err != nil {
+		return nil, nil, err
+	}
+	// The instance is always started with --skip-networking, so it needs
+	// neither a tablet UID nor a port.
+	cnf := newMycnfInDir(dir, 0, 0)
+	if err := cnf.RandomizeMysqlServerID(); err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, fmt.Errorf("couldn't generate random MySQL server_id: %v", err)
+	}
+
+	dbcfgs := &dbconfigs.DBConfigs{}
+	dbcfgs.InitWithSocket(cnf.SocketFile, collationEnv)
+	mysqld := NewMysqld(dbcfgs)
+	if err := mysqld.InitConfig(cnf); err != nil {
+		mysqld.Close()
+		os.RemoveAll(dir)
+		return nil, nil, err
+	}
+	return mysqld, cnf, nil
+}
+
+// VerifyBackup restores a full backup into a scratch MySQL instance, which
+// checks the restored files against the hashes of the MANIFEST. It then
+// starts MySQL, runs CHECK TABLE and CHECKSUM TABLE on all the tables, and
+// reads the restored position. Failing checks are reported in the returned
+// BackupVerification, while an error is returned if the backup can't be
+// restored at all.
+func VerifyBackup(ctx context.Context, params VerifyBackupParams) (*BackupVerification, error) {
+	if params.Stats == nil {
+		params.Stats = backupstats.NoStats()
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer bs.Close()
+	bs = bs.WithParams(backupstorage.Params{
+		Logger: params.Logger,
+		Stats: params.Stats.Scope(
+			backupstats.Component(backupstats.BackupStorage),
+			backupstats.Implementation(textutil.Title(backupstorage.BackupStorageImplementation)),
+		),
+	})
+
+	backupDir := GetBackupDir(params.Keyspace, params.Shard)
+	bh, bm, err := findBackupToVerify(ctx, bs, backupDir, params.BackupName, params.Logger)
+	if err != nil {
+		return nil, err
+	}
+	if bm.Incremental {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "backup %v/%v is incremental, only full backups can be verified", backupDir, bh.Name())
+	}
+	re, err := GetRestoreEngine(ctx, bh)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to find restore engine")
+	}
+
+	verification := &BackupVerification{
+		BackupName:       bh.Name(),
+		Engine:           bm.BackupMethod,
+		FileCount:        len(bm.FileEntries),
+		ManifestPosition: bm.Position,
+	}
+	if verification.Engine == "" {
+		verification.Engine = builtinBackupEngineName
+	}
+
+	params.Logger.Infof("VerifyBackup: restoring backup %v/%v", backupDir, bh.Name())
+	restoreParams := RestoreParams{
+		Cnf:                  params.Cnf,
+		Mysqld:               params.Mysqld,
+		Logger:               params.Logger,
+		Concurrency:          params.Concurrency,
+		HookExtraEnv:         map[string]string{},
+		DeleteBeforeRestore:  true,
+		Keyspace:             params.Keyspace,
+		Shard:                params.Shard,
+		MysqlShutdownTimeout: params.MysqlShutdownTimeout,
+		Stats: params.Stats.Scope(
+			backupstats.Component(backupstats.BackupEngine),
+			backupstats.Implementation(textutil.Title(verification.Engine)),
+		),
+	}
+	if _, err := re.ExecuteRestore(ctx, restoreParams, bh); err != nil {
+		return nil, vterrors.Wrapf(err, "failed to restore backup %v/%v", backupDir, bh.Name())
+	}
+	if err := removeStateFile(params.Cnf); err != nil {
+		return nil, err
+	}
+
+	// The scratch instance must not be reachable, nor replicate from anywhere.
+	params.Logger.Infof("VerifyBackup: starting mysqld")
+	if err := params.Mysqld.Start(ctx, params.Cnf, "--skip-grant-tables", "--skip-networking"); err != nil {
+		return nil, vterrors.Wrap(err, "failed to start mysqld on the restored backup")
+	}
+	defer func() {
+		if err := params.Mysqld.Shutdown(context.Background(), params.Cnf, true, params.MysqlShutdownTimeout); err != nil {
+			params.Logger.Errorf("VerifyBackup: failed to shut down mysqld: %v", err)
+		}
+	}()
+	if err := params.Mysqld.RunMysqlUpgrade(ctx); err != nil {
+		return nil, vterrors.Wrap(err, "mysql_upgrade failed")
+	}
+
+	if verification.RestoredPosition, err = params.Mysqld.PrimaryPosition(); err != nil {
+		return nil, vterrors.Wrap(err, "failed to read the restored position")
+	}
+	if !verification.RestoredPosition.Equal(verification.ManifestPosition) {
+		params.Logger.Errorf("VerifyBackup: restored position %v does not match the MANIFEST position %v", verification.RestoredPosition, verification.ManifestPosition)
+	}
+
+	params.Logger.Infof("VerifyBackup: checking tables")
+	if verification.Tables, err = verifyTables(ctx, params.Mysqld, params.Logger); err != nil {
+		return nil, err
+	}
+	return verification, nil
+}
+
+// findBackupToVerify returns the given backup, or the most recent one with
+// a MANIFEST if no name is given, along with its MANIFEST.
+func findBackupToVerify(ctx context.Context, bs backupstorage.BackupStorage, backupDir, backupName string, logger logutil.Logger) (backupstorage.BackupHandle, *builtinBackupManifest, error) {
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+	for i := len(bhs) - 1; i >= 0; i-- {
+		bh := bhs[i]
+		if backupName != "" && bh.Name() != backupName {
+			continue
+		}
+		var bm builtinBackupManifest
+		if err := getBackupManifestInto(ctx, bh, &bm); err != nil {
+			if backupName != "" {
+				return nil, nil, vterrors.Wrapf(err, "backup %v/%v is not complete", backupDir, backupName)
+			}
+			logger.Warningf("Possibly incomplete backup %v in directory %v on BackupStorage: can't read MANIFEST: %v", bh.Name(), backupDir, err)
+			continue
+		}
+		return bh, &bm, nil
+	}
+	if backupName != "" {
+		return nil, nil, vterrors.Errorf(vtrpc.Code_NOT_FOUND, "backup %v/%v not found", backupDir, backupName)
+	}
+	return nil, nil, vterrors.Errorf(vtrpc.Code_NOT_FOUND, "no complete backup found in %v", backupDir)
+}
+
+// verifyTables runs CHECK TABLE and CHECKSUM TABLE on all the tables.
+func verifyTables(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger) ([]TableVerification, error) {
+	qr, err := mysqld.FetchSuperQuery(ctx, verifyTablesQuery)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to list tables")
+	}
+	tables := make([]TableVerification, 0, len(qr.Rows))
+	for _, row := range qr.Rows {
+		table := TableVerification{
+			Schema: row[0].ToString(),
+			Name:   row[1].ToString(),
+		}
+		name := sqlescape.EscapeID(table.Schema) + "." + sqlescape.EscapeID(table.Name)
+
+		// CHECK TABLE returns (Table, Op, Msg_type, Msg_text) rows, the last
+		// of which holds the status of the table.
+		check, err := mysqld.FetchSuperQuery(ctx, "CHECK TABLE "+name)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to check table %v", name)
+		}
+		if n := len(check.Rows); n > 0 && len(check.Rows[n-1]) == 4 {
+			last := check.Rows[n-1]
+			table.CheckStatus = last[3].ToString()
+			table.OK = last[2].ToString() == "status" && table.CheckStatus == "OK"
+		}
+
+		checksum, err := mysqld.FetchSuperQuery(ctx, "CHECKSUM TABLE "+name)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to checksum table %v", name)
+		}
+		if len(checksum.Rows) == 1 && len(checksum.Rows[0]) == 2 {
+			table.Checksum = checksum.Rows[0][1].ToString()
+		}
+
+		if !table.OK {
+			logger.Errorf("VerifyBackup: table %v is not OK: %v", name, table.CheckStatus)
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
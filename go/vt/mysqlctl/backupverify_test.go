@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func TestVerifyBackup(t *testing.T) {
+	ctx := context.Background()
+	defer func(root, implementation string) {
+		filebackupstorage.FileBackupStorageRoot = root
+		backupstorage.BackupStorageImplementation = implementation
+	}(filebackupstorage.FileBackupStorageRoot, backupstorage.BackupStorageImplementation)
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	backupstorage.BackupStorageImplementation = "file"
+	bs, err := backupstorage.GetBackupStorage()
+	require.NoError(t, err)
+
+	position, err := replication.DecodePosition("MySQL56/8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1-12")
+	require.NoError(t, err)
+
+	// Take a backup of a single file.
+	be := &BuiltinBackupEngine{}
+	backupCnf := newMycnfInDir(t.TempDir(), 0, 0)
+	fe := FileEntry{Base: backupData, Name: "vt_ks/t1.ibd"}
+	require.NoError(t, os.MkdirAll(path.Join(backupCnf.DataDir, "vt_ks"), os.ModePerm))
+	require.NoError(t, os.WriteFile(path.Join(backupCnf.DataDir, fe.Name), []byte("some table data"), 0600))
+	backupDir := GetBackupDir("ks", "0")
+	const backupName = "2024-01-01.000000.zone1-0000000100"
+	bh, err := bs.StartBackup(ctx, backupDir, backupName)
+	require.NoError(t, err)
+	require.NoError(t, be.backupFile(ctx, BackupParams{
+		Cnf:    backupCnf,
+		Logger: logutil.NewMemoryLogger(),
+		Stats:  backupstats.NewFakeStats(),
+	}, bh, &fe, "0", nil))
+	wc, err := bh.AddFile(ctx, backupManifestFileName, backupstorage.FileSizeUnknown)
+	require.NoError(t, err)
+	require.NoError(t, json.NewEncoder(wc).Encode(&builtinBackupManifest{
+		BackupManifest: BackupManifest{
+			BackupName:   backupName,
+			BackupMethod: builtinBackupEngineName,
+			Position:     position,
+		},
+		FileEntries:       []FileEntry{fe},
+		CompressionEngine: CompressionEngineName,
+	}))
+	require.NoError(t, wc.Close())
+	require.NoError(t, bh.EndBackup(ctx))
+
+	// An incomplete backup is not verified by default.
+	_, err = bs.StartBackup(ctx, backupDir, "2024-01-02.000000.zone1-0000000100")
+	require.NoError(t, err)
+
+	verify := func(restoredPosition replication.Position, backupName string) (*BackupVerification, *Mycnf, error) {
+		cnf := newMycnfInDir(t.TempDir(), 0, 0)
+		mysqld := NewFakeMysqlDaemon(nil)
+		mysqld.CurrentPrimaryPosition = restoredPosition
+		mysqld.FetchSuperQueryMap = map[string]*sqltypes.Result{
+			verifyTablesQuery: sqltypes.MakeTestResult(sqltypes.MakeTestFields("table_schema|table_name", "varchar|varchar"),
+				"vt_ks|t1", "vt_ks|t2"),
+			"CHECK TABLE `vt_ks`.`t1`": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Op|Msg_type|Msg_text", "varchar|varchar|varchar|varchar"),
+				"vt_ks.t1|check|status|OK"),
+			"CHECK TABLE `vt_ks`.`t2`": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Op|Msg_type|Msg_text", "varchar|varchar|varchar|varchar"),
+				"vt_ks.t2|check|warning|Table is marked as crashed",
+				"vt_ks.t2|check|error|Corrupt"),
+			"CHECKSUM TABLE `vt_ks`.`t1`": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Checksum", "varchar|int64"),
+				"vt_ks.t1|1234"),
+			"CHECKSUM TABLE `vt_ks`.`t2`": sqltypes.MakeTestResult(sqltypes.MakeTestFields("Table|Checksum", "varchar|int64"),
+				"vt_ks.t2|5678"),
+		}
+		verification, err := VerifyBackup(ctx, VerifyBackupParams{
+			Cnf:         cnf,
+			Mysqld:      mysqld,
+			Logger:      logutil.NewMemoryLogger(),
+			Concurrency: 1,
+			Keyspace:    "ks",
+			Shard:       "0",
+			BackupName:  backupName,
+		})
+		if err == nil {
+			assert.False(t, mysqld.Running, "mysqld is shut down")
+		}
+		return verification, cnf, err
+	}
+
+	verification, cnf, err := verify(position, "")
+	require.NoError(t, err)
+	assert.Equal(t, &BackupVerification{
+		BackupName:       backupName,
+		Engine:           builtinBackupEngineName,
+		FileCount:        1,
+		ManifestPosition: position,
+		RestoredPosition: position,
+		Tables: []TableVerification{
+			{Schema: "vt_ks", Name: "t1", CheckStatus: "OK", Checksum: "1234", OK: true},
+			{Schema: "vt_ks", Name: "t2", CheckStatus: "Corrupt", Checksum: "5678"},
+		},
+	}, verification)
+	assert.False(t, verification.Verified())
+	restored, err := os.ReadFile(path.Join(cnf.DataDir, fe.Name))
+	require.NoError(t, err)
+	assert.Equal(t, "some table data", string(restored))
+
+	verification.Tables = verification.Tables[:1]
+	assert.True(t, verification.Verified())
+	verification.RestoredPosition = replication.Position{}
+	assert.False(t, verification.Verified())
+
+	_, _, err = verify(position, "2024-01-02.000000.zone1-0000000100")
+	assert.ErrorContains(t, err, "backup ks/0/2024-01-02.000000.zone1-0000000100 is not complete")
+	_, _, err = verify(position, "2024-01-03.000000.zone1-0000000100")
+	assert.ErrorContains(t, err, "backup ks/0/2024-01-03.000000.zone1-0000000100 not found")
+
+	// A corrupted file fails the verification.
+	require.NoError(t, os.WriteFile(path.Join(filebackupstorage.FileBackupStorageRoot, backupDir, backupName, "0"), []byte("corrupted"), 0600))
+	_, _, err = verify(position, backupName)
+	assert.ErrorContains(t, err, "failed to restore backup ks/0/"+backupName)
+}
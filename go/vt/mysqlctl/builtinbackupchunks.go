@@ -0,0 +1,731 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/ioutil"
+	"vitess.io/vitess/go/vt/logutil"
+	stats "vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// Deduplicated backups split every file into content-defined chunks, and
+// store each chunk once, named after the SHA-256 hash of its content, in a
+// chunk directory shared by all the backups of a shard. Each chunk is stored
+// as a backup holding a single file, so that any BackupStorage can hold them.
+// The MANIFEST of a deduplicated backup lists the chunks of every file.
+//
+// A backup in progress holds a lease in the chunk directory, so that its
+// chunks are not pruned before its MANIFEST references them. The lease and
+// the backups with no MANIFEST expire after builtinBackupChunkGracePeriod, so
+// that the chunks of the backups that were abandoned are eventually pruned.
+// The chunks are pruned with a mark and sweep: each prune records the chunks
+// it found unreferenced in a marks file of the chunk directory, and a chunk is
+// only removed once it has been found unreferenced for at least
+// builtinBackupChunkGracePeriod.
+const (
+	// backupChunkDirSuffix is appended to the backup directory of a shard to
+	// get the directory of its chunks.
+	backupChunkDirSuffix = ".chunks"
+
+	// backupChunkFileName is the name of the file holding the chunk data.
+	backupChunkFileName = "chunk"
+
+	// backupChunkMarksName is the name of the backup of the chunk directory
+	// holding the chunks found unreferenced by the previous prune, in a file
+	// of the same name.
+	backupChunkMarksName = "marks"
+
+	// backupChunkLeasePrefix is prepended to the name of a backup in progress
+	// to get the name of its lease in the chunk directory.
+	backupChunkLeasePrefix = "lease."
+
+	// backupChunkLeaseFileName is the name of the empty file of a lease, as
+	// some storages only list the backups that hold files.
+	backupChunkLeaseFileName = "lease"
+
+	// minBackupChunkSize and maxBackupChunkSize bound the size of the chunks.
+	minBackupChunkSize = 512 * 1024
+	maxBackupChunkSize = 8 * 1024 * 1024
+
+	// backupChunkMask selects the bits of the rolling hash that must be
+	// zero at a chunk boundary. With 20 bits, chunks are on average 1 MiB
+	// larger than minBackupChunkSize.
+	backupChunkMask = uint64(1<<20-1) << 44
+)
+
+var (
+	// builtinBackupDeduplicate enables deduplicated full backups.
+	builtinBackupDeduplicate bool
+
+	// builtinBackupChunkGracePeriod is how long after it started a backup
+	// is considered in progress, if it has no MANIFEST, and how long a chunk
+	// must have been found unreferenced before it is pruned.
+	builtinBackupChunkGracePeriod = 24 * time.Hour
+)
+
+// FileChunk is one chunk of a file in a deduplicated backup.
+type FileChunk struct {
+	// ID is the name of the chunk in the chunk directory. It is the
+	// hex-encoded SHA-256 hash of the uncompressed chunk, followed by the
+	// extension of the compression engine, if the chunk is compressed.
+	ID string
+
+	// Size is the uncompressed size of the chunk.
+	Size int64
+}
+
+// hash returns the hex-encoded SHA-256 hash of the chunk.
+func (c *FileChunk) hash() string {
+	return c.ID[:min(len(c.ID), sha256.Size*2)]
+}
+
+// extension returns the extension of the compression engine of the chunk,
+// or an empty string if it is not compressed.
+func (c *FileChunk) extension() string {
+	return c.ID[len(c.hash()):]
+}
+
+// backupChunkGear is the table of random values used by the rolling hash.
+// It is generated with a fixed seed, as chunk boundaries must not change
+// from one backup to the next.
+var backupChunkGear = func() (gear [256]uint64) {
+	// splitmix64
+	seed := uint64(0x5bd1e9955bd1e995)
+	for i := range gear {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		gear[i] = z ^ (z >> 31)
+	}
+	return gear
+}()
+
+// backupChunker splits a stream into content-defined chunks, using a gear
+// rolling hash: a boundary is placed where the hash of the last 64 bytes
+// matches the mask. As boundaries only depend on the local content, data
+// inserted or removed in a file only changes the chunks around it.
+type backupChunker struct {
+	r       io.Reader
+	minSize int
+	mask    uint64
+
+	buf        []byte
+	start, end int
+	eof        bool
+}
+
+func newBackupChunker(r io.Reader, minSize, maxSize int, mask uint64) *backupChunker {
+	return &backupChunker{
+		r:       r,
+		minSize: minSize,
+		mask:    mask,
+		buf:     make([]byte, maxSize),
+	}
+}
+
+// next returns the next chunk, or io.EOF at the end of the stream. The
+// returned slice is only valid until the next call.
+func (c *backupChunker) next() ([]byte, error) {
+	// Move what is left after the previous chunk to the front of the buffer.
+	c.end = copy(c.buf, c.buf[c.start:c.end])
+	c.start = 0
+	for c.end < len(c.buf) && !c.eof {
+		n, err := c.r.Read(c.buf[c.end:])
+		c.end += n
+		if err == io.EOF {
+			c.eof = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if c.end == 0 {
+		return nil, io.EOF
+	}
+	c.start = c.boundary(c.buf[:c.end])
+	return c.buf[:c.start], nil
+}
+
+// boundary returns the length of the first chunk of data.
+func (c *backupChunker) boundary(data []byte) int {
+	var h uint64
+	for i := c.minSize; i < len(data); i++ {
+		h = (h << 1) + backupChunkGear[data[i]]
+		if h&c.mask == 0 {
+			return i + 1
+		}
+	}
+	return len(data)
+}
+
+// backupChunkStore reads and writes the chunks of a shard.
+type backupChunkStore struct {
+	bs  backupstorage.BackupStorage
+	dir string
+
+	// ext is the extension of the compression engine used for new chunks,
+	// or an empty string if they are not compressed.
+	ext string
+
+	// handles are the chunks found in the chunk directory.
+	handles map[string]backupstorage.BackupHandle
+
+	// leases are the names of the backups that hold a lease in the chunk
+	// directory.
+	leases []string
+
+	// lease is the name of the backup that holds a lease taken by this
+	// store, if any.
+	lease string
+
+	// marks is the handle of the marks of the previous prune, if any.
+	marks backupstorage.BackupHandle
+
+	mu sync.Mutex
+	// known are the chunks that can be referenced without being written:
+	// those referenced by complete backups, and those already written by
+	// this backup.
+	known map[string]bool
+}
+
+// backupChunkDir returns the chunk directory of the given backup directory.
+func backupChunkDir(backupDir string) string {
+	return backupDir + backupChunkDirSuffix
+}
+
+// openBackupChunkStore lists the chunks of the given chunk directory.
+func openBackupChunkStore(ctx context.Context, bs backupstorage.BackupStorage, dir string) (*backupChunkStore, error) {
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "can't list chunks in %v", dir)
+	}
+	cs := &backupChunkStore{
+		bs:      bs,
+		dir:     dir,
+		handles: make(map[string]backupstorage.BackupHandle, len(bhs)),
+		known:   make(map[string]bool),
+	}
+	for _, bh := range bhs {
+		if backupName, isLease := strings.CutPrefix(bh.Name(), backupChunkLeasePrefix); isLease {
+			cs.leases = append(cs.leases, backupName)
+			continue
+		}
+		if bh.Name() == backupChunkMarksName {
+			cs.marks = bh
+			continue
+		}
+		cs.handles[bh.Name()] = bh
+	}
+	return cs, nil
+}
+
+// backupInProgress returns true if the backup of the given name started
+// less than builtinBackupChunkGracePeriod ago, or if its start time is unknown.
+func backupInProgress(dir, name string) bool {
+	backupTime, _, err := ParseBackupName(dir, name)
+	if err != nil || backupTime == nil {
+		return true
+	}
+	return time.Since(*backupTime) < builtinBackupChunkGracePeriod
+}
+
+// activeLeases returns the names of the backups, other than the one of this
+// store, that hold a lease that has not expired.
+func (cs *backupChunkStore) activeLeases() []string {
+	var active []string
+	for _, backupName := range cs.leases {
+		if backupName != cs.lease && backupInProgress(cs.dir, backupName) {
+			active = append(active, backupName)
+		}
+	}
+	return active
+}
+
+// acquireLease takes a lease on the chunk directory for the given backup.
+func (cs *backupChunkStore) acquireLease(ctx context.Context, backupName string) (finalErr error) {
+	bh, err := cs.bs.StartBackup(ctx, cs.dir, backupChunkLeasePrefix+backupName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if finalErr != nil {
+			finalErr = errors.Join(finalErr, bh.AbortBackup(ctx))
+		}
+	}()
+	wc, err := bh.AddFile(ctx, backupChunkLeaseFileName, 0)
+	if err != nil {
+		return err
+	}
+	if err := wc.Close(); err != nil {
+		return err
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		return err
+	}
+	cs.lease = backupName
+	return nil
+}
+
+// releaseLease removes the lease taken by this store, if any. A lease that
+// can't be removed expires after builtinBackupChunkGracePeriod.
+func (cs *backupChunkStore) releaseLease(ctx context.Context, logger logutil.Logger) {
+	if cs.lease == "" {
+		return
+	}
+	if err := cs.bs.RemoveBackup(ctx, cs.dir, backupChunkLeasePrefix+cs.lease); err != nil {
+		logger.Warningf("Can't release the lease of backup %v on %v: %v", cs.lease, cs.dir, err)
+		return
+	}
+	cs.lease = ""
+}
+
+// newBackupChunkStore prepares the chunk store of a deduplicated backup.
+func newBackupChunkStore(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, encryption *BackupEncryption) (*backupChunkStore, error) {
+	if encryption != nil {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "deduplicated backups cannot be encrypted")
+	}
+	var ext string
+	if backupStorageCompress {
+		if ExternalCompressorCmd != "" {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "deduplicated backups cannot use an external compressor")
+		}
+		var err error
+		if ext, err = getExtensionFromEngine(CompressionEngineName); err != nil {
+			return nil, err
+		}
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, vterrors.Wrap(err, "unable to get backup storage")
+	}
+	backupDir := GetBackupDir(params.Keyspace, params.Shard)
+	cs, err := openBackupChunkStore(ctx, bs, backupChunkDir(backupDir))
+	if err != nil {
+		return nil, err
+	}
+	cs.ext = ext
+	if err := cs.acquireLease(ctx, bh.Name()); err != nil {
+		return nil, vterrors.Wrapf(err, "can't take a lease on %v", cs.dir)
+	}
+
+	// Only the chunks referenced by complete backups are reused. The others
+	// may have been partially written by a backup that failed.
+	refs, err := backupChunkReferences(ctx, bs, backupDir, cs.dir, bh.Name(), params.Logger)
+	if err != nil && err != errIncompleteBackups {
+		cs.releaseLease(ctx, params.Logger)
+		return nil, err
+	}
+	for id := range refs {
+		if cs.handles[id] != nil {
+			cs.known[id] = true
+		}
+	}
+	params.Logger.Infof("Deduplicating backup against %v existing chunks in %v", len(cs.known), cs.dir)
+	return cs, nil
+}
+
+// backupChunkReferences returns how many times each chunk of the chunk
+// directory is referenced by the backups of the backup directory, except the
+// excluded one. It returns
+// errIncompleteBackups, along with the references it found, if the MANIFEST
+// of some backup that may still be in progress can't be read. The backups
+// that have no MANIFEST after builtinBackupChunkGracePeriod are abandoned,
+// and ignored.
+func backupChunkReferences(ctx context.Context, bs backupstorage.BackupStorage, backupDir, chunkDir, excludeBackupName string, logger logutil.Logger) (map[string]int, error) {
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "can't list backups in %v", backupDir)
+	}
+	refs := make(map[string]int)
+	complete := true
+	for _, bh := range bhs {
+		if bh.Name() == excludeBackupName {
+			continue
+		}
+		var bm builtinBackupManifest
+		if err := getBackupManifestInto(ctx, bh, &bm); err != nil {
+			if !backupInProgress(backupDir, bh.Name()) {
+				logger.Warningf("Ignoring backup %v, which has no readable MANIFEST after %v: %v", bh.Name(), builtinBackupChunkGracePeriod, err)
+				continue
+			}
+			logger.Warningf("Can't read MANIFEST of backup %v, it may still be in progress: %v", bh.Name(), err)
+			complete = false
+			continue
+		}
+		if bm.ChunkDir != chunkDir {
+			continue
+		}
+		for _, fe := range bm.FileEntries {
+			for _, chunk := range fe.Chunks {
+				refs[chunk.ID]++
+			}
+		}
+	}
+	if !complete {
+		return refs, errIncompleteBackups
+	}
+	return refs, nil
+}
+
+// errIncompleteBackups is returned by backupChunkReferences when some
+// backups have no readable MANIFEST.
+var errIncompleteBackups = errors.New("some backups have no readable MANIFEST")
+
+// addChunk stores a chunk, unless it is already known, and returns its ID.
+func (cs *backupChunkStore) addChunk(ctx context.Context, logger logutil.Logger, data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	id := hex.EncodeToString(sum[:]) + cs.ext
+
+	cs.mu.Lock()
+	known := cs.known[id]
+	cs.known[id] = true
+	cs.mu.Unlock()
+	if known {
+		return id, nil
+	}
+
+	if err := cs.writeChunk(ctx, logger, id, data); err != nil {
+		return "", vterrors.Wrapf(err, "cannot write chunk %v", id)
+	}
+	return id, nil
+}
+
+// writeChunk writes a chunk to the chunk directory.
+func (cs *backupChunkStore) writeChunk(ctx context.Context, logger logutil.Logger, id string, data []byte) (finalErr error) {
+	// A chunk that is not referenced by any complete backup may have been
+	// partially written by a backup that failed, or may still be written by
+	// a backup in progress. It is reused if it is complete, and only replaced
+	// when no other backup is in progress.
+	if cs.handles[id] != nil {
+		err := cs.readChunk(ctx, logger, FileChunk{ID: id, Size: int64(len(data))}, io.Discard)
+		if err == nil {
+			return nil
+		}
+		if active := cs.activeLeases(); len(active) > 0 {
+			return vterrors.Wrapf(err, "chunk %v may still be written by backup %v", id, active[0])
+		}
+		if err := cs.bs.RemoveBackup(ctx, cs.dir, id); err != nil {
+			return err
+		}
+	}
+
+	bh, err := cs.bs.StartBackup(ctx, cs.dir, id)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if finalErr != nil {
+			cs.removePartialChunk(ctx, logger, id)
+		}
+	}()
+
+	wc, err := bh.AddFile(ctx, backupChunkFileName, int64(len(data)))
+	if err != nil {
+		return err
+	}
+	var writer io.Writer = wc
+	var compressor io.WriteCloser
+	if cs.ext != "" {
+		if compressor, err = newBuiltinCompressor(CompressionEngineName, wc, logger); err != nil {
+			wc.Close()
+			return err
+		}
+		writer = compressor
+	}
+	_, err = writer.Write(data)
+	if compressor != nil {
+		err = errors.Join(err, compressor.Close())
+	}
+	if err = errors.Join(err, wc.Close()); err != nil {
+		return err
+	}
+	return bh.EndBackup(ctx)
+}
+
+// removePartialChunk removes a chunk that failed to be written. As backups
+// in progress share the chunk directory, another one may write the same chunk
+// at the same time, in which case the partial chunk is left: it fails the
+// checks of readChunk, so it is replaced by a later backup, or pruned.
+func (cs *backupChunkStore) removePartialChunk(ctx context.Context, logger logutil.Logger, id string) {
+	latest, err := openBackupChunkStore(ctx, cs.bs, cs.dir)
+	if err != nil {
+		logger.Warningf("Leaving partial chunk %v in %v: %v", id, cs.dir, err)
+		return
+	}
+	latest.lease = cs.lease
+	if active := latest.activeLeases(); len(active) > 0 {
+		logger.Warningf("Leaving partial chunk %v in %v, since backup %v may also write it.", id, cs.dir, active[0])
+		return
+	}
+	if err := cs.bs.RemoveBackup(ctx, cs.dir, id); err != nil {
+		logger.Warningf("Leaving partial chunk %v in %v: %v", id, cs.dir, err)
+	}
+}
+
+// readChunk copies a chunk to w, and checks its size and hash.
+func (cs *backupChunkStore) readChunk(ctx context.Context, logger logutil.Logger, chunk FileChunk, w io.Writer) error {
+	bh := cs.handles[chunk.ID]
+	if bh == nil {
+		return vterrors.Errorf(vtrpc.Code_NOT_FOUND, "chunk %v not found in %v", chunk.ID, cs.dir)
+	}
+	source, err := bh.ReadFile(ctx, backupChunkFileName)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	var reader io.Reader = source
+	if ext := chunk.extension(); ext != "" {
+		engines, ok := engineExtensions[ext]
+		if !ok {
+			return vterrors.Errorf(vtrpc.Code_INTERNAL, "unknown compression extension %q for chunk %v", ext, chunk.ID)
+		}
+		decompressor, err := newBuiltinDecompressor(engines[0], source, logger)
+		if err != nil {
+			return err
+		}
+		defer decompressor.Close()
+		reader = decompressor
+	}
+
+	hasher := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, hasher), reader)
+	if err != nil {
+		return err
+	}
+	if n != chunk.Size {
+		return vterrors.Errorf(vtrpc.Code_INTERNAL, "size mismatch for chunk %v, got %v expected %v", chunk.ID, n, chunk.Size)
+	}
+	if hash := hex.EncodeToString(hasher.Sum(nil)); hash != chunk.hash() {
+		return vterrors.Errorf(vtrpc.Code_INTERNAL, "hash mismatch for chunk %v, got %v", chunk.ID, hash)
+	}
+	return nil
+}
+
+// backupFileChunks backs up an individual file as chunks.
+func (be *BuiltinBackupEngine) backupFileChunks(ctx context.Context, params BackupParams, cs *backupChunkStore, fe *FileEntry) error {
+	source, err := fe.open(params.Cnf, true)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	fi, err := source.Stat()
+	if err != nil {
+		return err
+	}
+
+	readStats := params.Stats.Scope(stats.Operation("Source:Read"))
+	br := newBackupReader(fe.Name, fi.Size(), ioutil.NewMeteredReader(source, readStats.TimedIncrementBytes))
+	go br.ReportProgress(builtinBackupProgress, params.Logger)
+	defer br.Close()
+
+	params.Logger.Infof("Backing up file as chunks: %v", fe.Name)
+	chunker := newBackupChunker(br, minBackupChunkSize, maxBackupChunkSize, backupChunkMask)
+	for {
+		data, err := chunker.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot read %v", fe.Name)
+		}
+		addChunkAt := time.Now()
+		id, err := cs.addChunk(ctx, params.Logger, data)
+		if err != nil {
+			return err
+		}
+		params.Stats.Scope(stats.Operation("Chunk:Add")).TimedIncrement(time.Since(addChunkAt))
+		fe.Chunks = append(fe.Chunks, FileChunk{ID: id, Size: int64(len(data))})
+	}
+	return nil
+}
+
+// restoreFileChunks restores an individual file from its chunks.
+func (be *BuiltinBackupEngine) restoreFileChunks(ctx context.Context, params RestoreParams, cs *backupChunkStore, fe *FileEntry) (finalErr error) {
+	dest, err := fe.open(params.Cnf, false)
+	if err != nil {
+		return vterrors.Wrap(err, "can't open destination file for writing")
+	}
+	defer func() {
+		if cerr := dest.Close(); cerr != nil {
+			finalErr = errors.Join(finalErr, vterrors.Wrap(cerr, "failed to close destination file"))
+		}
+	}()
+
+	writeStats := params.Stats.Scope(stats.Operation("Destination:Write"))
+	bufferedDest := bufio.NewWriterSize(ioutil.NewMeteredWriter(dest, writeStats.TimedIncrementBytes), int(builtinBackupFileWriteBufferSize))
+	for _, chunk := range fe.Chunks {
+		if err := cs.readChunk(ctx, params.Logger, chunk, bufferedDest); err != nil {
+			return vterrors.Wrapf(err, "can't restore chunk %v", chunk.ID)
+		}
+	}
+	if err := bufferedDest.Flush(); err != nil {
+		return vterrors.Wrap(err, "failed to flush destination buffer")
+	}
+	return nil
+}
+
+// readMarks returns the chunks found unreferenced by the previous prune, with
+// the time each was first found unreferenced. Marks that can't be read, e.g.
+// as the previous prune failed to write them, are ignored: the chunks are then
+// marked again.
+func (cs *backupChunkStore) readMarks(ctx context.Context, logger logutil.Logger) map[string]time.Time {
+	marks := make(map[string]time.Time)
+	if cs.marks == nil {
+		return marks
+	}
+	file, err := cs.marks.ReadFile(ctx, backupChunkMarksName)
+	if err != nil {
+		logger.Warningf("Ignoring the marks of %v, which can't be read: %v", cs.dir, err)
+		return marks
+	}
+	defer file.Close()
+	if err := json.NewDecoder(file).Decode(&marks); err != nil {
+		logger.Warningf("Ignoring the marks of %v, which can't be decoded: %v", cs.dir, err)
+		return make(map[string]time.Time)
+	}
+	return marks
+}
+
+// writeMarks replaces the marks of the chunk directory.
+func (cs *backupChunkStore) writeMarks(ctx context.Context, marks map[string]time.Time) (finalErr error) {
+	if cs.marks != nil {
+		if err := cs.bs.RemoveBackup(ctx, cs.dir, backupChunkMarksName); err != nil {
+			return vterrors.Wrapf(err, "can't remove the marks of %v", cs.dir)
+		}
+	}
+	bh, err := cs.bs.StartBackup(ctx, cs.dir, backupChunkMarksName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if finalErr != nil {
+			finalErr = errors.Join(finalErr, bh.AbortBackup(ctx))
+		}
+	}()
+	wc, err := bh.AddFile(ctx, backupChunkMarksName, backupstorage.FileSizeUnknown)
+	if err != nil {
+		return err
+	}
+	err = json.NewEncoder(wc).Encode(marks)
+	if err = errors.Join(err, wc.Close()); err != nil {
+		return err
+	}
+	return bh.EndBackup(ctx)
+}
+
+// PruneBackupChunks removes the chunks of deduplicated backups that are no
+// longer referenced by any backup of the given backup directory, and the
+// expired leases. It does nothing while some backup is in progress, i.e. holds
+// a lease or has no MANIFEST, for less than builtinBackupChunkGracePeriod.
+//
+// The chunks found unreferenced are marked, and only removed by a later prune
+// that still finds them unreferenced, builtinBackupChunkGracePeriod after they
+// were first marked. A backup that starts in between holds a lease, which stops
+// the prune, and a backup that completes in between references the chunks it
+// reuses, which unmarks them.
+func PruneBackupChunks(ctx context.Context, bs backupstorage.BackupStorage, backupDir string, logger logutil.Logger) error {
+	cs, err := openBackupChunkStore(ctx, bs, backupChunkDir(backupDir))
+	if err != nil {
+		return err
+	}
+	if len(cs.handles) == 0 && len(cs.leases) == 0 {
+		return nil
+	}
+	if active := cs.activeLeases(); len(active) > 0 {
+		logger.Infof("Not pruning chunks in %v, since backup %v is in progress.", cs.dir, active[0])
+		return nil
+	}
+	refs, err := backupChunkReferences(ctx, bs, backupDir, cs.dir, "", logger)
+	if err == errIncompleteBackups {
+		logger.Warningf("Not pruning chunks in %v, since some backups have no MANIFEST.", cs.dir)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// A backup that started while the MANIFESTs were read may reuse some of
+	// the unreferenced chunks, and one that completed may reference them, so
+	// both are checked again once before the chunks are swept.
+	latest, err := openBackupChunkStore(ctx, bs, cs.dir)
+	if err != nil {
+		return err
+	}
+	if active := latest.activeLeases(); len(active) > 0 {
+		logger.Infof("Not pruning chunks in %v, since backup %v is in progress.", cs.dir, active[0])
+		return nil
+	}
+	latestRefs, err := backupChunkReferences(ctx, bs, backupDir, cs.dir, "", logger)
+	if err == errIncompleteBackups {
+		logger.Warningf("Not pruning chunks in %v, since some backups have no MANIFEST.", cs.dir)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	previous := cs.readMarks(ctx, logger)
+	now := time.Now().UTC()
+	marks := make(map[string]time.Time)
+	removed := 0
+	for id := range cs.handles {
+		if refs[id] > 0 || latestRefs[id] > 0 {
+			continue
+		}
+		markedAt, marked := previous[id]
+		if !marked {
+			marks[id] = now
+			continue
+		}
+		if now.Sub(markedAt) < builtinBackupChunkGracePeriod {
+			marks[id] = markedAt
+			continue
+		}
+		if err := bs.RemoveBackup(ctx, cs.dir, id); err != nil {
+			return vterrors.Wrapf(err, "couldn't remove chunk %v from %v", id, cs.dir)
+		}
+		removed++
+	}
+	if err := cs.writeMarks(ctx, marks); err != nil {
+		return vterrors.Wrapf(err, "couldn't mark the unreferenced chunks of %v", cs.dir)
+	}
+	logger.Infof("Removed %v unreferenced chunks out of %v from %v, %v others are marked for removal", removed, len(cs.handles), cs.dir, len(marks))
+
+	for _, backupName := range cs.leases {
+		if err := bs.RemoveBackup(ctx, cs.dir, backupChunkLeasePrefix+backupName); err != nil {
+			return vterrors.Wrapf(err, "couldn't remove the expired lease of backup %v from %v", backupName, cs.dir)
+		}
+		logger.Infof("Removed the expired lease of backup %v from %v", backupName, cs.dir)
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+func chunksForTest(t *testing.T, data []byte) [][]byte {
+	const minSize, maxSize = 1024, 16 * 1024
+	chunker := newBackupChunker(bytes.NewReader(data), minSize, maxSize, uint64(1<<11-1)<<53)
+	var chunks [][]byte
+	for {
+		chunk, err := chunker.next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(chunk), maxSize)
+		chunks = append(chunks, append([]byte{}, chunk...))
+	}
+	for _, chunk := range chunks[:max(len(chunks)-1, 0)] {
+		require.GreaterOrEqual(t, len(chunk), minSize)
+	}
+	return chunks
+}
+
+func TestBackupChunker(t *testing.T) {
+	data := make([]byte, 1024*1024)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	chunks := chunksForTest(t, data)
+	assert.Equal(t, data, bytes.Join(chunks, nil))
+	assert.Empty(t, chunksForTest(t, nil))
+
+	// Inserting data only changes the chunks around it.
+	modified := append(append(append([]byte{}, data[:len(data)/2]...), "some inserted data"...), data[len(data)/2:]...)
+	modifiedChunks := chunksForTest(t, modified)
+	assert.Equal(t, modified, bytes.Join(modifiedChunks, nil))
+	existing := make(map[string]bool)
+	for _, chunk := range chunks {
+		existing[string(chunk)] = true
+	}
+	changed := 0
+	for _, chunk := range modifiedChunks {
+		if !existing[string(chunk)] {
+			changed++
+		}
+	}
+	assert.LessOrEqual(t, changed, 3)
+}
+
+func TestDeduplicatedBackup(t *testing.T) {
+	ctx := context.Background()
+	defer func(root, implementation string) {
+		filebackupstorage.FileBackupStorageRoot = root
+		backupstorage.BackupStorageImplementation = implementation
+	}(filebackupstorage.FileBackupStorageRoot, backupstorage.BackupStorageImplementation)
+	filebackupstorage.FileBackupStorageRoot = t.TempDir()
+	backupstorage.BackupStorageImplementation = "file"
+	bs, err := backupstorage.GetBackupStorage()
+	require.NoError(t, err)
+
+	be := &BuiltinBackupEngine{}
+	cnf := &Mycnf{DataDir: t.TempDir()}
+	fe := FileEntry{Base: backupData, Name: "vt_ks/t1.ibd"}
+	require.NoError(t, os.MkdirAll(path.Join(cnf.DataDir, "vt_ks"), os.ModePerm))
+	data := make([]byte, 8*1024*1024)
+	_, err = rand.Read(data)
+	require.NoError(t, err)
+
+	backupDir := GetBackupDir("ks", "0")
+	backup := func(name string) FileEntry {
+		require.NoError(t, os.WriteFile(path.Join(cnf.DataDir, fe.Name), data, 0600))
+		bh, err := bs.StartBackup(ctx, backupDir, name)
+		require.NoError(t, err)
+		params := BackupParams{
+			Cnf:      cnf,
+			Logger:   logutil.NewMemoryLogger(),
+			Stats:    backupstats.NewFakeStats(),
+			Keyspace: "ks",
+			Shard:    "0",
+		}
+		cs, err := newBackupChunkStore(ctx, params, bh, nil)
+		require.NoError(t, err)
+		backupFe := fe
+		require.NoError(t, be.backupFileChunks(ctx, params, cs, &backupFe))
+
+		wc, err := bh.AddFile(ctx, backupManifestFileName, backupstorage.FileSizeUnknown)
+		require.NoError(t, err)
+		require.NoError(t, json.NewEncoder(wc).Encode(&builtinBackupManifest{FileEntries: []FileEntry{backupFe}, ChunkDir: cs.dir}))
+		require.NoError(t, wc.Close())
+		cs.releaseLease(ctx, params.Logger)
+		require.NoError(t, bh.EndBackup(ctx))
+		return backupFe
+	}
+	restore := func(backupFe FileEntry) ([]byte, error) {
+		restoreCnf := &Mycnf{DataDir: t.TempDir()}
+		params := RestoreParams{
+			Cnf:    restoreCnf,
+			Logger: logutil.NewMemoryLogger(),
+			Stats:  backupstats.NewFakeStats(),
+		}
+		cs, err := openBackupChunkStore(ctx, bs, backupChunkDir(backupDir))
+		require.NoError(t, err)
+		if err := be.restoreFileChunks(ctx, params, cs, &backupFe); err != nil {
+			return nil, err
+		}
+		return os.ReadFile(path.Join(restoreCnf.DataDir, fe.Name))
+	}
+	// storedChunks counts the chunks and the leases of the chunk directory.
+	storedChunks := func() int {
+		bhs, err := bs.ListBackups(ctx, backupChunkDir(backupDir))
+		require.NoError(t, err)
+		stored := 0
+		for _, bh := range bhs {
+			if bh.Name() != backupChunkMarksName {
+				stored++
+			}
+		}
+		return stored
+	}
+
+	fe1 := backup("2024-01-01.000000.zone1-0000000100")
+	require.Greater(t, len(fe1.Chunks), 1)
+	assert.Equal(t, len(fe1.Chunks), storedChunks())
+	restored, err := restore(fe1)
+	require.NoError(t, err)
+	assert.Equal(t, data, restored)
+
+	// Only the chunks that changed are stored by the next backup.
+	copy(data[len(data)/2:], "some modified data")
+	fe2 := backup("2024-01-02.000000.zone1-0000000100")
+	newChunks := storedChunks() - len(fe1.Chunks)
+	assert.GreaterOrEqual(t, newChunks, 1)
+	assert.LessOrEqual(t, newChunks, 2)
+	restored, err = restore(fe2)
+	require.NoError(t, err)
+	assert.Equal(t, data, restored)
+
+	// Chunks are not pruned while some backup has no MANIFEST.
+	logger := logutil.NewMemoryLogger()
+	require.NoError(t, bs.RemoveBackup(ctx, backupDir, "2024-01-01.000000.zone1-0000000100"))
+	inProgress := time.Now().UTC().Format(BackupTimestampFormat) + ".zone1-0000000101"
+	_, err = bs.StartBackup(ctx, backupDir, inProgress)
+	require.NoError(t, err)
+	require.NoError(t, PruneBackupChunks(ctx, bs, backupDir, logger))
+	assert.Equal(t, len(fe1.Chunks)+newChunks, storedChunks())
+	require.NoError(t, bs.RemoveBackup(ctx, backupDir, inProgress))
+
+	// Nor while some backup holds a lease, even if it is not listed yet.
+	cs, err := openBackupChunkStore(ctx, bs, backupChunkDir(backupDir))
+	require.NoError(t, err)
+	require.NoError(t, cs.acquireLease(ctx, inProgress))
+	require.NoError(t, PruneBackupChunks(ctx, bs, backupDir, logger))
+	assert.Equal(t, len(fe1.Chunks)+newChunks+1, storedChunks())
+
+	// An incomplete chunk is not replaced while another backup is in progress,
+	// as that backup may still be writing it, but it is replaced otherwise.
+	chunk := fe2.Chunks[0]
+	chunkPath := path.Join(filebackupstorage.FileBackupStorageRoot, backupChunkDir(backupDir), chunk.ID, backupChunkFileName)
+	chunkFile, err := os.ReadFile(chunkPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(chunkPath, chunkFile[:len(chunkFile)/2], 0600))
+	other, err := openBackupChunkStore(ctx, bs, backupChunkDir(backupDir))
+	require.NoError(t, err)
+	other.ext = chunk.extension()
+	err = other.writeChunk(ctx, logger, chunk.ID, data[:chunk.Size])
+	assert.ErrorContains(t, err, "may still be written by backup "+inProgress)
+	cs.releaseLease(ctx, logger)
+	other, err = openBackupChunkStore(ctx, bs, backupChunkDir(backupDir))
+	require.NoError(t, err)
+	other.ext = chunk.extension()
+	require.NoError(t, other.writeChunk(ctx, logger, chunk.ID, data[:chunk.Size]))
+	restored, err = restore(fe2)
+	require.NoError(t, err)
+	assert.Equal(t, data, restored)
+
+	// The backups that still have no MANIFEST after the grace period, and their
+	// leases, are abandoned: the expired lease is pruned, and the chunks that
+	// are only referenced by the removed backup are marked by a first prune,
+	// then swept by the next one, as they are still unreferenced.
+	abandoned := "2024-01-03.000000.zone1-0000000100"
+	_, err = bs.StartBackup(ctx, backupDir, abandoned)
+	require.NoError(t, err)
+	require.NoError(t, cs.acquireLease(ctx, abandoned))
+	defer func(gracePeriod time.Duration) {
+		builtinBackupChunkGracePeriod = gracePeriod
+	}(builtinBackupChunkGracePeriod)
+	builtinBackupChunkGracePeriod = 0
+	require.NoError(t, PruneBackupChunks(ctx, bs, backupDir, logger))
+	assert.Equal(t, len(fe1.Chunks)+newChunks, storedChunks())
+	require.NoError(t, PruneBackupChunks(ctx, bs, backupDir, logger))
+	assert.Equal(t, len(fe2.Chunks), storedChunks())
+	restored, err = restore(fe2)
+	require.NoError(t, err)
+	assert.Equal(t, data, restored)
+
+	// A missing chunk fails the restore.
+	require.NoError(t, bs.RemoveBackup(ctx, backupChunkDir(backupDir), fe2.Chunks[0].ID))
+	_, err = restore(fe2)
+	assert.ErrorContains(t, err, "not found")
+}
@@ -129,6 +129,14 @@ type builtinBackupManifest struct {
 	// ExternalDecompressor will be used. If neither are set, the restore will
 	// abort.
 	ExternalDecompressor string
+
+	// Encryption describes how the backup files were encrypted, after
+	// compression. It is nil if they were not encrypted.
+	Encryption *BackupEncryption `json:",omitempty"`
+
+	// ChunkDir is the directory of the chunks of a deduplicated backup.
+	// It is empty if the backup was not deduplicated.
+	ChunkDir string `json:",omitempty"`
 }
 
 // FileEntry is one file to backup
@@ -150,6 +158,11 @@ type FileEntry struct {
 	// ParentPath is an optional prefix to the Base path. If empty, it is ignored. Useful
 	// for writing files in a temporary directory
 	ParentPath string
+
+	// Chunks lists the chunks of the file in a deduplicated backup, in
+	// order. Hash is not set for such files, as each chunk is checked
+	// against its own hash.
+	Chunks []FileChunk `json:",omitempty"`
 }
 
 func init() {
@@ -163,6 +176,8 @@ func registerBuiltinBackupEngineFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&builtinBackupProgress, "builtinbackup_progress", builtinBackupProgress, "how often to send progress updates when backing up large files.")
 	fs.UintVar(&builtinBackupFileReadBufferSize, "builtinbackup-file-read-buffer-size", builtinBackupFileReadBufferSize, "read files using an IO buffer of this many bytes. Golang defaults are used when set to 0.")
 	fs.UintVar(&builtinBackupFileWriteBufferSize, "builtinbackup-file-write-buffer-size", builtinBackupFileWriteBufferSize, "write files using an IO buffer of this many bytes. Golang defaults are used when set to 0.")
+	fs.BoolVar(&builtinBackupDeduplicate, "builtinbackup-deduplicate", builtinBackupDeduplicate, "split the files of full backups into content-defined chunks, and only upload the chunks that are not already stored by previous backups of the shard. Not compatible with backup encryption and external compressors.")
+	fs.DurationVar(&builtinBackupChunkGracePeriod, "builtinbackup-chunk-grace-period", builtinBackupChunkGracePeriod, "how long after it started a deduplicated backup that has no MANIFEST is considered in progress. The chunks are not pruned while a backup is in progress, and the chunks of the backups that are abandoned are pruned once they are no longer referenced. Unreferenced chunks are only pruned once they have been found unreferenced for this long. Must be longer than the backups take.")
 	fs.StringVar(&builtinIncrementalRestorePath, "builtinbackup-incremental-restore-path", builtinIncrementalRestorePath, "the directory where incremental restore files, namely binlog files, are extracted to. In k8s environments, this should be set to a directory that is shared between the vttablet and mysqld pods. The path should exist. When empty, the default OS temp dir is assumed.")
 }
 
@@ -601,6 +616,23 @@ func (be *BuiltinBackupEngine) backupFiles(
 	}
 	params.Logger.Infof("found %v files to backup", len(fes))
 
+	// Generate the data key to encrypt the files, if necessary.
+	encryption, err := newBackupEncryption(ctx)
+	if err != nil {
+		return vterrors.Wrap(err, "can't set up backup encryption")
+	}
+
+	// Set up the chunk store, if the files are deduplicated.
+	var chunks *backupChunkStore
+	if builtinBackupDeduplicate && !isIncrementalBackup(params) {
+		chunks, err = newBackupChunkStore(ctx, params, bh, encryption)
+		if err != nil {
+			return vterrors.Wrap(err, "can't set up backup deduplication")
+		}
+		// The lease is released once the MANIFEST is written.
+		defer chunks.releaseLease(ctx, params.Logger)
+	}
+
 	// Backup with the provided concurrency.
 	sema := semaphore.NewWeighted(int64(params.Concurrency))
 	wg := sync.WaitGroup{}
@@ -635,8 +667,12 @@ func (be *BuiltinBackupEngine) backupFiles(
 			}
 
 			// Backup the individual file.
+			if chunks != nil {
+				bh.RecordError(be.backupFileChunks(ctx, params, chunks, fe))
+				return
+			}
 			name := fmt.Sprintf("%v", i)
-			bh.RecordError(be.backupFile(ctx, params, bh, fe, name))
+			bh.RecordError(be.backupFile(ctx, params, bh, fe, name, encryption))
 		}(i)
 	}
 
@@ -693,6 +729,10 @@ func (be *BuiltinBackupEngine) backupFiles(
 		SkipCompress:         !backupStorageCompress,
 		CompressionEngine:    CompressionEngineName,
 		ExternalDecompressor: ManifestExternalDecompressorCmd,
+		Encryption:           encryption,
+	}
+	if chunks != nil {
+		bm.ChunkDir = chunks.dir
 	}
 	data, err := json.MarshalIndent(bm, "", "  ")
 	if err != nil {
@@ -789,7 +829,7 @@ func (bp *backupPipe) ReportProgress(period time.Duration, logger logutil.Logger
 }
 
 // backupFile backs up an individual file.
-func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string) (finalErr error) {
+func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle, fe *FileEntry, name string, encryption *BackupEncryption) (finalErr error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	// Open the source file for reading.
@@ -842,8 +882,8 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 	bw := newBackupWriter(fe.Name, builtinBackupStorageWriteBufferSize, fi.Size(), timedDest)
 
 	// We create the following inner function because:
-	// - we must `defer` the compressor's Close() function
-	// - but it must take place before we close the pipe reader&writer
+	// - we must `defer` the compressor's and encryptor's Close() functions
+	// - but they must take place before we close the pipe reader&writer
 	createAndCopy := func() (createAndCopyErr error) {
 		var reader io.Reader = br
 		var writer io.Writer = bw
@@ -858,6 +898,22 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 				createAndCopyErr = errors.Join(createAndCopyErr, vterrors.Wrap(err, "failed to close the source reader"))
 			}
 		}()
+		// Create the encryption pipe, if necessary. The data is compressed
+		// before it is encrypted, as encrypted data does not compress.
+		if encryption != nil {
+			encryptor, err := newEncryptor(writer, encryption.dataKey, encryption.ChunkSize)
+			if err != nil {
+				return vterrors.Wrap(err, "can't create encryptor")
+			}
+			writer = encryptor
+
+			defer func() {
+				// Close the encryptor to write the last chunk, before the backupPipe is closed.
+				if err := encryptor.Close(); err != nil {
+					createAndCopyErr = errors.Join(createAndCopyErr, vterrors.Wrapf(err, "failed to close encryptor %v", name))
+				}
+			}()
+		}
 		// Create the gzip compression pipe, if necessary.
 		if backupStorageCompress {
 			var compressor io.WriteCloser
@@ -1005,6 +1061,25 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 		}()
 	}
 
+	// Unwrap the data key to decrypt the files, if necessary.
+	if bm.Encryption != nil {
+		if err := bm.Encryption.unwrapDataKey(ctx); err != nil {
+			return "", vterrors.Wrap(err, "can't set up backup decryption")
+		}
+	}
+
+	// Find the chunks of the files, if the backup was deduplicated.
+	var chunks *backupChunkStore
+	if bm.ChunkDir != "" {
+		bs, err := backupstorage.GetBackupStorage()
+		if err != nil {
+			return "", vterrors.Wrap(err, "unable to get backup storage")
+		}
+		if chunks, err = openBackupChunkStore(ctx, bs, bm.ChunkDir); err != nil {
+			return "", err
+		}
+	}
+
 	if bm.Incremental {
 		createdDir, err = os.MkdirTemp(builtinIncrementalRestorePath, "restore-incremental-*")
 		if err != nil {
@@ -1049,7 +1124,12 @@ func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreP
 			// And restore the file.
 			name := fmt.Sprintf("%v", i)
 			params.Logger.Infof("Copying file %v: %v", name, fe.Name)
-			err := be.restoreFile(ctx, params, bh, fe, bm, name)
+			var err error
+			if chunks != nil {
+				err = be.restoreFileChunks(ctx, params, chunks, fe)
+			} else {
+				err = be.restoreFile(ctx, params, bh, fe, bm, name)
+			}
 			if err != nil {
 				rec.RecordError(vterrors.Wrapf(err, "can't restore file %v to %v", name, fe.Name))
 			}
@@ -1105,6 +1185,16 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 
 	bufferedDest := bufio.NewWriterSize(timedDest, int(builtinBackupFileWriteBufferSize))
 
+	// Create the decrypter if needed.
+	if bm.Encryption != nil {
+		decryptor, err := newDecryptor(reader, bm.Encryption.dataKey, bm.Encryption.ChunkSize)
+		if err != nil {
+			return vterrors.Wrap(err, "can't create decryptor")
+		}
+		defer decryptor.Close()
+		reader = decryptor
+	}
+
 	// Create the uncompresser if needed.
 	if !bm.SkipCompress {
 		var decompressor io.ReadCloser
@@ -0,0 +1,474 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	// AES256GCMEncryption is the algorithm used to encrypt the files of builtin backups.
+	// The files are split in chunks, each of them sealed with AES-256-GCM.
+	AES256GCMEncryption = "aes-256-gcm-chunked"
+
+	// FileBackupKeyProvider reads the master key from the file set with --backup-encryption-key-file.
+	FileBackupKeyProvider = "file"
+	// EnvBackupKeyProvider reads the master key from the environment variable set with --backup-encryption-key-env.
+	EnvBackupKeyProvider = "env"
+
+	// encryptionKeySize is the size of the AES-256 keys.
+	encryptionKeySize = 32
+	// encryptionNoncePrefixSize is the size of the random prefix of the nonces of a file.
+	// The rest of the nonce is the index of the chunk, and a flag marking the last chunk.
+	encryptionNoncePrefixSize = 7
+	// defaultEncryptionChunkSize is the size of the plaintext chunks that are sealed separately.
+	defaultEncryptionChunkSize = 64 * 1024
+)
+
+var (
+	// backupEncryptionKeyProvider is the name of the key provider used to encrypt
+	// builtin backups. Backups are not encrypted when it is empty.
+	backupEncryptionKeyProvider string
+	backupEncryptionKeyFile     string
+	backupEncryptionKeyEnv      = "VT_BACKUP_ENCRYPTION_KEY"
+
+	backupKeyProvidersMu sync.Mutex
+	backupKeyProviders   = map[string]BackupKeyProvider{}
+
+	errEncryptedChunkTruncated = errors.New("encrypted backup file is truncated")
+)
+
+// BackupKeyProvider provides the data keys used to encrypt builtin backups.
+// Each backup is encrypted with its own data key, which is stored in the
+// backup manifest wrapped (i.e. encrypted) with a master key held by the
+// provider, in the fashion of a KMS.
+type BackupKeyProvider interface {
+	// GenerateDataKey returns a new data key, its wrapped form, and the
+	// identifier of the master key that wrapped it.
+	GenerateDataKey(ctx context.Context) (key []byte, wrappedKey []byte, keyID string, err error)
+
+	// DecryptDataKey unwraps a data key returned by GenerateDataKey.
+	DecryptDataKey(ctx context.Context, wrappedKey []byte, keyID string) ([]byte, error)
+}
+
+// BackupEncryption describes how the files of a builtin backup were encrypted.
+// It is stored in the backup manifest, so that restores can decrypt the files.
+type BackupEncryption struct {
+	// Algorithm is the encryption algorithm. Only AES256GCMEncryption is supported.
+	Algorithm string
+	// KeyProvider is the name of the BackupKeyProvider that wrapped the data key.
+	KeyProvider string
+	// KeyID identifies the master key that wrapped the data key.
+	KeyID string `json:",omitempty"`
+	// WrappedKey is the data key, wrapped by the key provider.
+	WrappedKey []byte
+	// ChunkSize is the size of the plaintext chunks that were sealed separately.
+	ChunkSize int
+
+	// dataKey is the unwrapped data key. It is never stored.
+	dataKey []byte
+}
+
+func init() {
+	for _, cmd := range []string{"vtbackup", "vtcombo", "vttablet", "vttestserver"} {
+		servenv.OnParseFor(cmd, registerBackupEncryptionFlags)
+	}
+
+	RegisterBackupKeyProvider(FileBackupKeyProvider, &localBackupKeyProvider{name: FileBackupKeyProvider, masterKey: func() (string, error) {
+		if backupEncryptionKeyFile == "" {
+			return "", fmt.Errorf("--backup-encryption-key-file is required by the %q backup key provider", FileBackupKeyProvider)
+		}
+		data, err := os.ReadFile(backupEncryptionKeyFile)
+		return string(data), err
+	}})
+	RegisterBackupKeyProvider(EnvBackupKeyProvider, &localBackupKeyProvider{name: EnvBackupKeyProvider, masterKey: func() (string, error) {
+		key, ok := os.LookupEnv(backupEncryptionKeyEnv)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", backupEncryptionKeyEnv)
+		}
+		return key, nil
+	}})
+}
+
+func registerBackupEncryptionFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&backupEncryptionKeyProvider, "backup-encryption-key-provider", backupEncryptionKeyProvider, fmt.Sprintf("key provider used to encrypt builtin backups with AES-256-GCM, e.g. %q or %q. Backups are not encrypted when empty. Restores decrypt backups with the key provider recorded in their manifest.", FileBackupKeyProvider, EnvBackupKeyProvider))
+	fs.StringVar(&backupEncryptionKeyFile, "backup-encryption-key-file", backupEncryptionKeyFile, "file holding the hex or base64 encoded 256-bit master key of the 'file' backup key provider.")
+	fs.StringVar(&backupEncryptionKeyEnv, "backup-encryption-key-env", backupEncryptionKeyEnv, "environment variable holding the hex or base64 encoded 256-bit master key of the 'env' backup key provider.")
+}
+
+// RegisterBackupKeyProvider registers a BackupKeyProvider under the given name,
+// which can then be used with --backup-encryption-key-provider.
+func RegisterBackupKeyProvider(name string, provider BackupKeyProvider) {
+	backupKeyProvidersMu.Lock()
+	defer backupKeyProvidersMu.Unlock()
+	if _, ok := backupKeyProviders[name]; ok {
+		panic(fmt.Sprintf("backup key provider %q is already registered", name))
+	}
+	backupKeyProviders[name] = provider
+}
+
+func getBackupKeyProvider(name string) (BackupKeyProvider, error) {
+	backupKeyProvidersMu.Lock()
+	defer backupKeyProvidersMu.Unlock()
+	provider, ok := backupKeyProviders[name]
+	if !ok {
+		names := make([]string, 0, len(backupKeyProviders))
+		for name := range backupKeyProviders {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown backup key provider %q, supported values are %s", name, strings.Join(names, ", "))
+	}
+	return provider, nil
+}
+
+// newBackupEncryption generates the data key of a new backup, and returns the
+// description of its encryption to store in the backup manifest, or nil if
+// backups are not encrypted.
+func newBackupEncryption(ctx context.Context) (*BackupEncryption, error) {
+	if backupEncryptionKeyProvider == "" {
+		return nil, nil
+	}
+	provider, err := getBackupKeyProvider(backupEncryptionKeyProvider)
+	if err != nil {
+		return nil, err
+	}
+	key, wrappedKey, keyID, err := provider.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot generate data key with backup key provider %q", backupEncryptionKeyProvider)
+	}
+	return &BackupEncryption{
+		Algorithm:   AES256GCMEncryption,
+		KeyProvider: backupEncryptionKeyProvider,
+		KeyID:       keyID,
+		WrappedKey:  wrappedKey,
+		ChunkSize:   defaultEncryptionChunkSize,
+		dataKey:     key,
+	}, nil
+}
+
+// unwrapDataKey unwraps the data key of a backup with the key provider that wrapped it.
+func (e *BackupEncryption) unwrapDataKey(ctx context.Context) error {
+	if e.Algorithm != AES256GCMEncryption {
+		return fmt.Errorf("unsupported backup encryption algorithm %q", e.Algorithm)
+	}
+	if e.ChunkSize <= 0 {
+		return fmt.Errorf("invalid backup encryption chunk size %d", e.ChunkSize)
+	}
+	provider, err := getBackupKeyProvider(e.KeyProvider)
+	if err != nil {
+		return err
+	}
+	key, err := provider.DecryptDataKey(ctx, e.WrappedKey, e.KeyID)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot decrypt data key with backup key provider %q", e.KeyProvider)
+	}
+	e.dataKey = key
+	return nil
+}
+
+// localBackupKeyProvider is a BackupKeyProvider wrapping the data keys with a
+// master key it reads locally. It stands in for a KMS.
+type localBackupKeyProvider struct {
+	name      string
+	masterKey func() (string, error)
+}
+
+func (p *localBackupKeyProvider) aead() (cipher.AEAD, string, error) {
+	encoded, err := p.masterKey()
+	if err != nil {
+		return nil, "", err
+	}
+	key, err := parseEncryptionKey(encoded)
+	if err != nil {
+		return nil, "", vterrors.Wrapf(err, "invalid master key for backup key provider %q", p.name)
+	}
+	aead, err := newEncryptionAEAD(key)
+	if err != nil {
+		return nil, "", err
+	}
+	// The key ID is a fingerprint of the master key, so that restoring
+	// with another master key fails with a clear error.
+	fingerprint := sha256.Sum256(key)
+	return aead, hex.EncodeToString(fingerprint[:8]), nil
+}
+
+// GenerateDataKey is part of the BackupKeyProvider interface.
+func (p *localBackupKeyProvider) GenerateDataKey(ctx context.Context) ([]byte, []byte, string, error) {
+	aead, keyID, err := p.aead()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	key := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, "", err
+	}
+	return key, aead.Seal(nonce, nonce, key, []byte(keyID)), keyID, nil
+}
+
+// DecryptDataKey is part of the BackupKeyProvider interface.
+func (p *localBackupKeyProvider) DecryptDataKey(ctx context.Context, wrappedKey []byte, keyID string) ([]byte, error) {
+	aead, masterKeyID, err := p.aead()
+	if err != nil {
+		return nil, err
+	}
+	if keyID != masterKeyID {
+		return nil, fmt.Errorf("backup was encrypted with master key %s, but the master key of backup key provider %q is %s", keyID, p.name, masterKeyID)
+	}
+	if len(wrappedKey) < aead.NonceSize() {
+		return nil, errors.New("wrapped data key is too short")
+	}
+	nonce, sealed := wrappedKey[:aead.NonceSize()], wrappedKey[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, []byte(keyID))
+}
+
+// parseEncryptionKey decodes a hex or base64 encoded 256-bit key.
+func parseEncryptionKey(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+	key, err := hex.DecodeString(encoded)
+	if err != nil {
+		key, err = base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, errors.New("key is neither hex nor base64 encoded")
+		}
+	}
+	if len(key) != encryptionKeySize {
+		return nil, fmt.Errorf("key must be %d bytes long, got %d", encryptionKeySize, len(key))
+	}
+	return key, nil
+}
+
+func newEncryptionAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptionNonce returns the nonce of a chunk: the random prefix of the file,
+// the index of the chunk, and whether it is the last chunk of the file, which
+// protects against the reordering and truncation of the chunks.
+func encryptionNonce(nonce []byte, prefix []byte, index uint32, last bool) error {
+	if index == math.MaxUint32 {
+		return errors.New("too many chunks in encrypted backup file")
+	}
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[encryptionNoncePrefixSize:], index)
+	nonce[len(nonce)-1] = 0
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nil
+}
+
+// encryptor is an io.WriteCloser encrypting the data written to it. The data
+// is written as a random nonce prefix, followed by the sealed chunks.
+type encryptor struct {
+	w         io.Writer
+	aead      cipher.AEAD
+	prefix    []byte
+	nonce     []byte
+	buf       []byte
+	sealed    []byte
+	chunkSize int
+	index     uint32
+	closed    bool
+}
+
+// newEncryptor returns an io.WriteCloser encrypting the data written to it
+// with the given data key, and writing it to w. Close must be called to write
+// the last chunk.
+func newEncryptor(w io.Writer, key []byte, chunkSize int) (io.WriteCloser, error) {
+	aead, err := newEncryptionAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	prefix := make([]byte, encryptionNoncePrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(prefix); err != nil {
+		return nil, err
+	}
+	return &encryptor{
+		w:         w,
+		aead:      aead,
+		prefix:    prefix,
+		nonce:     make([]byte, aead.NonceSize()),
+		buf:       make([]byte, 0, chunkSize),
+		sealed:    make([]byte, 0, chunkSize+aead.Overhead()),
+		chunkSize: chunkSize,
+	}, nil
+}
+
+// Write is part of the io.Writer interface.
+func (e *encryptor) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to closed encryptor")
+	}
+	n := 0
+	for len(p) > 0 {
+		// A full chunk is only sealed when more data comes, as the last
+		// chunk is sealed differently.
+		if len(e.buf) == e.chunkSize {
+			if err := e.seal(false); err != nil {
+				return n, err
+			}
+		}
+		c := copy(e.buf[len(e.buf):e.chunkSize], p)
+		e.buf = e.buf[:len(e.buf)+c]
+		p = p[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// Close is part of the io.Closer interface. It does not close the underlying writer.
+func (e *encryptor) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.seal(true)
+}
+
+func (e *encryptor) seal(last bool) error {
+	if err := encryptionNonce(e.nonce, e.prefix, e.index, last); err != nil {
+		return err
+	}
+	e.sealed = e.aead.Seal(e.sealed[:0], e.nonce, e.buf, nil)
+	e.buf = e.buf[:0]
+	e.index++
+	_, err := e.w.Write(e.sealed)
+	return err
+}
+
+// decryptor is an io.ReadCloser decrypting the data written by an encryptor.
+type decryptor struct {
+	r         *bufio.Reader
+	aead      cipher.AEAD
+	prefix    []byte
+	nonce     []byte
+	sealed    []byte
+	plain     []byte
+	pos       int
+	chunkSize int
+	index     uint32
+	done      bool
+}
+
+// newDecryptor returns an io.ReadCloser decrypting the data read from r with
+// the given data key. It fails if the data was altered or truncated.
+func newDecryptor(r io.Reader, key []byte, chunkSize int) (io.ReadCloser, error) {
+	aead, err := newEncryptionAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	d := &decryptor{
+		r:         bufio.NewReaderSize(r, chunkSize+aead.Overhead()+1),
+		aead:      aead,
+		prefix:    make([]byte, encryptionNoncePrefixSize),
+		nonce:     make([]byte, aead.NonceSize()),
+		sealed:    make([]byte, chunkSize+aead.Overhead()),
+		chunkSize: chunkSize,
+	}
+	if _, err := io.ReadFull(d.r, d.prefix); err != nil {
+		return nil, errEncryptedChunkTruncated
+	}
+	return d, nil
+}
+
+// Read is part of the io.Reader interface.
+func (d *decryptor) Read(p []byte) (int, error) {
+	for d.pos == len(d.plain) {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain[d.pos:])
+	d.pos += n
+	return n, nil
+}
+
+// Close is part of the io.Closer interface. It does not close the underlying reader.
+func (d *decryptor) Close() error {
+	return nil
+}
+
+func (d *decryptor) open() error {
+	n, err := io.ReadFull(d.r, d.sealed)
+	last := false
+	switch {
+	case err == io.ErrUnexpectedEOF || err == io.EOF:
+		last = true
+	case err != nil:
+		return err
+	default:
+		// A full chunk is the last one if nothing follows it.
+		if _, err := d.r.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+	if n < d.aead.Overhead() {
+		return errEncryptedChunkTruncated
+	}
+	if err := encryptionNonce(d.nonce, d.prefix, d.index, last); err != nil {
+		return err
+	}
+	d.plain, err = d.aead.Open(d.plain[:0], d.nonce, d.sealed[:n], nil)
+	if err != nil {
+		if last {
+			return vterrors.Wrap(err, "cannot decrypt last chunk of backup file, it may be truncated")
+		}
+		return vterrors.Wrap(err, "cannot decrypt backup file")
+	}
+	d.pos = 0
+	d.index++
+	d.done = last
+	return nil
+}
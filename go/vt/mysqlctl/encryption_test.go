@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encryptForTest(t *testing.T, data, key []byte, chunkSize int) []byte {
+	var encrypted bytes.Buffer
+	encryptor, err := newEncryptor(&encrypted, key, chunkSize)
+	require.NoError(t, err)
+	// Write in small pieces, so that chunks span several writes.
+	for len(data) > 0 {
+		n := min(len(data), 7)
+		_, err = encryptor.Write(data[:n])
+		require.NoError(t, err)
+		data = data[n:]
+	}
+	require.NoError(t, encryptor.Close())
+	return encrypted.Bytes()
+}
+
+func decryptForTest(encrypted, key []byte, chunkSize int) ([]byte, error) {
+	decryptor, err := newDecryptor(bytes.NewReader(encrypted), key, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	defer decryptor.Close()
+	return io.ReadAll(decryptor)
+}
+
+func TestEncryptor(t *testing.T) {
+	key := make([]byte, encryptionKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	const chunkSize = 16
+
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 2 * chunkSize, 10*chunkSize + 3} {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+
+		encrypted := encryptForTest(t, data, key, chunkSize)
+
+		decrypted, err := decryptForTest(encrypted, key, chunkSize)
+		require.NoError(t, err, "size %d", size)
+		assert.Equal(t, data, append([]byte{}, decrypted...), "size %d", size)
+
+		// A truncated file cannot be decrypted, even at a chunk boundary.
+		for _, truncated := range []int{len(encrypted) - 1, len(encrypted) - chunkSize - 16, encryptionNoncePrefixSize} {
+			if truncated < encryptionNoncePrefixSize || truncated >= len(encrypted) {
+				continue
+			}
+			_, err = decryptForTest(encrypted[:truncated], key, chunkSize)
+			assert.Error(t, err, "size %d truncated to %d", size, truncated)
+		}
+
+		// An altered file cannot be decrypted.
+		altered := append([]byte{}, encrypted...)
+		altered[len(altered)-1] ^= 1
+		_, err = decryptForTest(altered, key, chunkSize)
+		assert.Error(t, err, "size %d", size)
+	}
+
+	// Another key cannot decrypt the file.
+	otherKey := make([]byte, encryptionKeySize)
+	encrypted := encryptForTest(t, []byte("hello, world!"), key, chunkSize)
+	_, err = decryptForTest(encrypted, otherKey, chunkSize)
+	assert.Error(t, err)
+}
+
+func TestParseEncryptionKey(t *testing.T) {
+	key := make([]byte, encryptionKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	parsed, err := parseEncryptionKey(hex.EncodeToString(key) + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, key, parsed)
+
+	parsed, err = parseEncryptionKey(base64.StdEncoding.EncodeToString(key))
+	require.NoError(t, err)
+	assert.Equal(t, key, parsed)
+
+	_, err = parseEncryptionKey(hex.EncodeToString(key[:16]))
+	assert.ErrorContains(t, err, "key must be 32 bytes long, got 16")
+
+	_, err = parseEncryptionKey("not a key")
+	assert.ErrorContains(t, err, "key is neither hex nor base64 encoded")
+}
+
+func TestBackupEncryption(t *testing.T) {
+	ctx := context.Background()
+	defer func(provider, file, env string) {
+		backupEncryptionKeyProvider, backupEncryptionKeyFile, backupEncryptionKeyEnv = provider, file, env
+	}(backupEncryptionKeyProvider, backupEncryptionKeyFile, backupEncryptionKeyEnv)
+
+	// Backups are not encrypted by default.
+	backupEncryptionKeyProvider = ""
+	encryption, err := newBackupEncryption(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, encryption)
+
+	masterKey := make([]byte, encryptionKeySize)
+	_, err = rand.Read(masterKey)
+	require.NoError(t, err)
+	backupEncryptionKeyFile = path.Join(t.TempDir(), "backup.key")
+	require.NoError(t, os.WriteFile(backupEncryptionKeyFile, []byte(hex.EncodeToString(masterKey)), 0600))
+	backupEncryptionKeyEnv = "TEST_BACKUP_ENCRYPTION_KEY"
+	t.Setenv(backupEncryptionKeyEnv, base64.StdEncoding.EncodeToString(masterKey))
+
+	for _, provider := range []string{FileBackupKeyProvider, EnvBackupKeyProvider} {
+		t.Run(provider, func(t *testing.T) {
+			backupEncryptionKeyProvider = provider
+			encryption, err := newBackupEncryption(ctx)
+			require.NoError(t, err)
+			require.NotNil(t, encryption)
+			assert.Equal(t, AES256GCMEncryption, encryption.Algorithm)
+			assert.Equal(t, provider, encryption.KeyProvider)
+			assert.Len(t, encryption.dataKey, encryptionKeySize)
+			assert.NotContains(t, string(encryption.WrappedKey), string(encryption.dataKey))
+
+			// The data key is not stored in the manifest, but can be unwrapped from it.
+			data, err := json.Marshal(encryption)
+			require.NoError(t, err)
+			var restored BackupEncryption
+			require.NoError(t, json.Unmarshal(data, &restored))
+			assert.Nil(t, restored.dataKey)
+			require.NoError(t, restored.unwrapDataKey(ctx))
+			assert.Equal(t, encryption.dataKey, restored.dataKey)
+		})
+	}
+
+	backupEncryptionKeyProvider = FileBackupKeyProvider
+	encryption, err = newBackupEncryption(ctx)
+	require.NoError(t, err)
+
+	// Another master key cannot unwrap the data key.
+	_, err = rand.Read(masterKey)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(backupEncryptionKeyFile, []byte(hex.EncodeToString(masterKey)), 0600))
+	err = encryption.unwrapDataKey(ctx)
+	assert.ErrorContains(t, err, "backup was encrypted with master key "+encryption.KeyID)
+
+	backupEncryptionKeyProvider = "kms"
+	_, err = newBackupEncryption(ctx)
+	assert.ErrorContains(t, err, `unknown backup key provider "kms", supported values are env, file`)
+}
@@ -184,6 +184,9 @@ func (be *XtrabackupEngine) executeFullBackup(ctx context.Context, params Backup
 	if xtrabackupUser == "" {
 		return BackupUnusable, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "xtrabackupUser must be specified.")
 	}
+	if backupEncryptionKeyProvider != "" {
+		return BackupUnusable, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "--backup-encryption-key-provider is only supported by the builtin backup engine, use the xtrabackup encryption flags instead.")
+	}
 
 	// an extension is required when using an external compressor
 	if backupStorageCompress && ExternalCompressorCmd != "" && ExternalCompressorExt == "" {
@@ -563,8 +563,42 @@ type (
 	// DDLAction is an enum for DDL.Action
 	DDLAction int8
 
-	// Load represents a LOAD statement
+	// Load represents a LOAD DATA statement.
+	// A LOAD DATA FROM S3 statement is not parsed, and is represented by an empty Load.
 	Load struct {
+		Priority    LoadPriority
+		Local       bool
+		FileName    string
+		Duplicate   LoadDuplicate
+		Table       TableName
+		Partitions  Partitions
+		Charset     ColumnCharset
+		Fields      *LoadFields
+		Lines       *LoadLines
+		IgnoreLines int
+		// Columns holds the column names and user variables the fields of each line are assigned to
+		Columns  Exprs
+		SetExprs UpdateExprs
+	}
+
+	// LoadPriority is an enum for Load.Priority
+	LoadPriority int8
+
+	// LoadDuplicate is an enum for Load.Duplicate
+	LoadDuplicate int8
+
+	// LoadFields represents the FIELDS or COLUMNS clause of a LOAD DATA statement
+	LoadFields struct {
+		TerminatedBy       *Literal
+		EnclosedBy         *Literal
+		OptionallyEnclosed bool
+		EscapedBy          *Literal
+	}
+
+	// LoadLines represents the LINES clause of a LOAD DATA statement
+	LoadLines struct {
+		StartingBy   *Literal
+		TerminatedBy *Literal
 	}
 
 	// PurgeBinaryLogs represents a PURGE BINARY LOGS statement
@@ -303,6 +303,10 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfLiteral(in)
 	case *Load:
 		return CloneRefOfLoad(in)
+	case *LoadFields:
+		return CloneRefOfLoadFields(in)
+	case *LoadLines:
+		return CloneRefOfLoadLines(in)
 	case *LocateExpr:
 		return CloneRefOfLocateExpr(in)
 	case *LockOption:
@@ -2063,6 +2067,36 @@ func CloneRefOfLoad(n *Load) *Load {
 		return nil
 	}
 	out := *n
+	out.Table = CloneTableName(n.Table)
+	out.Partitions = ClonePartitions(n.Partitions)
+	out.Charset = CloneColumnCharset(n.Charset)
+	out.Fields = CloneRefOfLoadFields(n.Fields)
+	out.Lines = CloneRefOfLoadLines(n.Lines)
+	out.Columns = CloneExprs(n.Columns)
+	out.SetExprs = CloneUpdateExprs(n.SetExprs)
+	return &out
+}
+
+// CloneRefOfLoadFields creates a deep clone of the input.
+func CloneRefOfLoadFields(n *LoadFields) *LoadFields {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.TerminatedBy = CloneRefOfLiteral(n.TerminatedBy)
+	out.EnclosedBy = CloneRefOfLiteral(n.EnclosedBy)
+	out.EscapedBy = CloneRefOfLiteral(n.EscapedBy)
+	return &out
+}
+
+// CloneRefOfLoadLines creates a deep clone of the input.
+func CloneRefOfLoadLines(n *LoadLines) *LoadLines {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.StartingBy = CloneRefOfLiteral(n.StartingBy)
+	out.TerminatedBy = CloneRefOfLiteral(n.TerminatedBy)
 	return &out
 }
 
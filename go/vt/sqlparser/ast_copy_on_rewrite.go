@@ -302,6 +302,10 @@ func (c *cow) copyOnRewriteSQLNode(n SQLNode, parent SQLNode) (out SQLNode, chan
 		return c.copyOnRewriteRefOfLiteral(n, parent)
 	case *Load:
 		return c.copyOnRewriteRefOfLoad(n, parent)
+	case *LoadFields:
+		return c.copyOnRewriteRefOfLoadFields(n, parent)
+	case *LoadLines:
+		return c.copyOnRewriteRefOfLoadLines(n, parent)
 	case *LocateExpr:
 		return c.copyOnRewriteRefOfLocateExpr(n, parent)
 	case *LockOption:
@@ -3775,6 +3779,76 @@ func (c *cow) copyOnRewriteRefOfLoad(n *Load, parent SQLNode) (out SQLNode, chan
 	}
 	out = n
 	if c.pre == nil || c.pre(n, parent) {
+		_Table, changedTable := c.copyOnRewriteTableName(n.Table, n)
+		_Partitions, changedPartitions := c.copyOnRewritePartitions(n.Partitions, n)
+		_Fields, changedFields := c.copyOnRewriteRefOfLoadFields(n.Fields, n)
+		_Lines, changedLines := c.copyOnRewriteRefOfLoadLines(n.Lines, n)
+		_Columns, changedColumns := c.copyOnRewriteExprs(n.Columns, n)
+		_SetExprs, changedSetExprs := c.copyOnRewriteUpdateExprs(n.SetExprs, n)
+		if changedTable || changedPartitions || changedFields || changedLines || changedColumns || changedSetExprs {
+			res := *n
+			res.Table, _ = _Table.(TableName)
+			res.Partitions, _ = _Partitions.(Partitions)
+			res.Fields, _ = _Fields.(*LoadFields)
+			res.Lines, _ = _Lines.(*LoadLines)
+			res.Columns, _ = _Columns.(Exprs)
+			res.SetExprs, _ = _SetExprs.(UpdateExprs)
+			out = &res
+			if c.cloned != nil {
+				c.cloned(n, out)
+			}
+			changed = true
+		}
+	}
+	if c.post != nil {
+		out, changed = c.postVisit(out, parent, changed)
+	}
+	return
+}
+func (c *cow) copyOnRewriteRefOfLoadFields(n *LoadFields, parent SQLNode) (out SQLNode, changed bool) {
+	if n == nil || c.cursor.stop {
+		return n, false
+	}
+	out = n
+	if c.pre == nil || c.pre(n, parent) {
+		_TerminatedBy, changedTerminatedBy := c.copyOnRewriteRefOfLiteral(n.TerminatedBy, n)
+		_EnclosedBy, changedEnclosedBy := c.copyOnRewriteRefOfLiteral(n.EnclosedBy, n)
+		_EscapedBy, changedEscapedBy := c.copyOnRewriteRefOfLiteral(n.EscapedBy, n)
+		if changedTerminatedBy || changedEnclosedBy || changedEscapedBy {
+			res := *n
+			res.TerminatedBy, _ = _TerminatedBy.(*Literal)
+			res.EnclosedBy, _ = _EnclosedBy.(*Literal)
+			res.EscapedBy, _ = _EscapedBy.(*Literal)
+			out = &res
+			if c.cloned != nil {
+				c.cloned(n, out)
+			}
+			changed = true
+		}
+	}
+	if c.post != nil {
+		out, changed = c.postVisit(out, parent, changed)
+	}
+	return
+}
+func (c *cow) copyOnRewriteRefOfLoadLines(n *LoadLines, parent SQLNode) (out SQLNode, changed bool) {
+	if n == nil || c.cursor.stop {
+		return n, false
+	}
+	out = n
+	if c.pre == nil || c.pre(n, parent) {
+		_StartingBy, changedStartingBy := c.copyOnRewriteRefOfLiteral(n.StartingBy, n)
+		_TerminatedBy, changedTerminatedBy := c.copyOnRewriteRefOfLiteral(n.TerminatedBy, n)
+		if changedStartingBy || changedTerminatedBy {
+			res := *n
+			res.StartingBy, _ = _StartingBy.(*Literal)
+			res.TerminatedBy, _ = _TerminatedBy.(*Literal)
+			out = &res
+			if c.cloned != nil {
+				c.cloned(n, out)
+			}
+			changed = true
+		}
 	}
 	if c.post != nil {
 		out, changed = c.postVisit(out, parent, changed)
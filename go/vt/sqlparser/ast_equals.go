@@ -866,6 +866,18 @@ func (cmp *Comparator) SQLNode(inA, inB SQLNode) bool {
 			return false
 		}
 		return cmp.RefOfLoad(a, b)
+	case *LoadFields:
+		b, ok := inB.(*LoadFields)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfLoadFields(a, b)
+	case *LoadLines:
+		b, ok := inB.(*LoadLines)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfLoadLines(a, b)
 	case *LocateExpr:
 		b, ok := inB.(*LocateExpr)
 		if !ok {
@@ -3380,7 +3392,44 @@ func (cmp *Comparator) RefOfLoad(a, b *Load) bool {
 	if a == nil || b == nil {
 		return false
 	}
-	return true
+	return a.Local == b.Local &&
+		a.FileName == b.FileName &&
+		a.IgnoreLines == b.IgnoreLines &&
+		a.Priority == b.Priority &&
+		a.Duplicate == b.Duplicate &&
+		cmp.TableName(a.Table, b.Table) &&
+		cmp.Partitions(a.Partitions, b.Partitions) &&
+		cmp.ColumnCharset(a.Charset, b.Charset) &&
+		cmp.RefOfLoadFields(a.Fields, b.Fields) &&
+		cmp.RefOfLoadLines(a.Lines, b.Lines) &&
+		cmp.Exprs(a.Columns, b.Columns) &&
+		cmp.UpdateExprs(a.SetExprs, b.SetExprs)
+}
+
+// RefOfLoadFields does deep equals between the two objects.
+func (cmp *Comparator) RefOfLoadFields(a, b *LoadFields) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.OptionallyEnclosed == b.OptionallyEnclosed &&
+		cmp.RefOfLiteral(a.TerminatedBy, b.TerminatedBy) &&
+		cmp.RefOfLiteral(a.EnclosedBy, b.EnclosedBy) &&
+		cmp.RefOfLiteral(a.EscapedBy, b.EscapedBy)
+}
+
+// RefOfLoadLines does deep equals between the two objects.
+func (cmp *Comparator) RefOfLoadLines(a, b *LoadLines) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return cmp.RefOfLiteral(a.StartingBy, b.StartingBy) &&
+		cmp.RefOfLiteral(a.TerminatedBy, b.TerminatedBy)
 }
 
 // RefOfLocateExpr does deep equals between the two objects.
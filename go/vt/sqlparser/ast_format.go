@@ -2037,7 +2037,78 @@ func (node IdentifierCS) Format(buf *TrackedBuffer) {
 
 // Format formats the node.
 func (node *Load) Format(buf *TrackedBuffer) {
-	buf.literal("AST node missing for Load type")
+	if node.Table.IsEmpty() {
+		buf.literal("AST node missing for Load type")
+		return
+	}
+	buf.literal("load data")
+	switch node.Priority {
+	case LowPriorityLoad:
+		buf.literal(" low_priority")
+	case ConcurrentLoad:
+		buf.literal(" concurrent")
+	}
+	if node.Local {
+		buf.literal(" local")
+	}
+	buf.astPrintf(node, " infile %#s", encodeSQLString(node.FileName))
+	switch node.Duplicate {
+	case LoadDuplicateReplace:
+		buf.literal(" replace")
+	case LoadDuplicateIgnore:
+		buf.literal(" ignore")
+	}
+	buf.astPrintf(node, " into table %v%v", node.Table, node.Partitions)
+	if node.Charset.Name != "" {
+		buf.astPrintf(node, " character set %#s", node.Charset.Name)
+	}
+	if node.Charset.Binary {
+		buf.astPrintf(node, " %#s", keywordStrings[BINARY])
+	}
+	buf.astPrintf(node, "%v%v", node.Fields, node.Lines)
+	if node.IgnoreLines > 0 {
+		buf.astPrintf(node, " ignore %d lines", node.IgnoreLines)
+	}
+	if len(node.Columns) > 0 {
+		buf.astPrintf(node, " (%v)", node.Columns)
+	}
+	if len(node.SetExprs) > 0 {
+		buf.astPrintf(node, " set %v", node.SetExprs)
+	}
+}
+
+// Format formats the node.
+func (node *LoadFields) Format(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.literal(" fields")
+	if node.TerminatedBy != nil {
+		buf.astPrintf(node, " terminated by %v", node.TerminatedBy)
+	}
+	if node.EnclosedBy != nil {
+		if node.OptionallyEnclosed {
+			buf.literal(" optionally")
+		}
+		buf.astPrintf(node, " enclosed by %v", node.EnclosedBy)
+	}
+	if node.EscapedBy != nil {
+		buf.astPrintf(node, " escaped by %v", node.EscapedBy)
+	}
+}
+
+// Format formats the node.
+func (node *LoadLines) Format(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.literal(" lines")
+	if node.StartingBy != nil {
+		buf.astPrintf(node, " starting by %v", node.StartingBy)
+	}
+	if node.TerminatedBy != nil {
+		buf.astPrintf(node, " terminated by %v", node.TerminatedBy)
+	}
 }
 
 // Format formats the node.
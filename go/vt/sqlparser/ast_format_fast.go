@@ -2708,7 +2708,94 @@ func (node IdentifierCS) FormatFast(buf *TrackedBuffer) {
 
 // FormatFast formats the node.
 func (node *Load) FormatFast(buf *TrackedBuffer) {
-	buf.WriteString("AST node missing for Load type")
+	if node.Table.IsEmpty() {
+		buf.WriteString("AST node missing for Load type")
+		return
+	}
+	buf.WriteString("load data")
+	switch node.Priority {
+	case LowPriorityLoad:
+		buf.WriteString(" low_priority")
+	case ConcurrentLoad:
+		buf.WriteString(" concurrent")
+	}
+	if node.Local {
+		buf.WriteString(" local")
+	}
+	buf.WriteString(" infile ")
+	buf.WriteString(encodeSQLString(node.FileName))
+	switch node.Duplicate {
+	case LoadDuplicateReplace:
+		buf.WriteString(" replace")
+	case LoadDuplicateIgnore:
+		buf.WriteString(" ignore")
+	}
+	buf.WriteString(" into table ")
+	node.Table.FormatFast(buf)
+	node.Partitions.FormatFast(buf)
+	if node.Charset.Name != "" {
+		buf.WriteString(" character set ")
+		buf.WriteString(node.Charset.Name)
+	}
+	if node.Charset.Binary {
+		buf.WriteByte(' ')
+		buf.WriteString(keywordStrings[BINARY])
+	}
+	node.Fields.FormatFast(buf)
+	node.Lines.FormatFast(buf)
+	if node.IgnoreLines > 0 {
+		buf.WriteString(" ignore ")
+		buf.WriteString(fmt.Sprintf("%d", node.IgnoreLines))
+		buf.WriteString(" lines")
+	}
+	if len(node.Columns) > 0 {
+		buf.WriteString(" (")
+		node.Columns.FormatFast(buf)
+		buf.WriteByte(')')
+	}
+	if len(node.SetExprs) > 0 {
+		buf.WriteString(" set ")
+		node.SetExprs.FormatFast(buf)
+	}
+}
+
+// FormatFast formats the node.
+func (node *LoadFields) FormatFast(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.WriteString(" fields")
+	if node.TerminatedBy != nil {
+		buf.WriteString(" terminated by ")
+		node.TerminatedBy.FormatFast(buf)
+	}
+	if node.EnclosedBy != nil {
+		if node.OptionallyEnclosed {
+			buf.WriteString(" optionally")
+		}
+		buf.WriteString(" enclosed by ")
+		node.EnclosedBy.FormatFast(buf)
+	}
+	if node.EscapedBy != nil {
+		buf.WriteString(" escaped by ")
+		node.EscapedBy.FormatFast(buf)
+	}
+}
+
+// FormatFast formats the node.
+func (node *LoadLines) FormatFast(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.WriteString(" lines")
+	if node.StartingBy != nil {
+		buf.WriteString(" starting by ")
+		node.StartingBy.FormatFast(buf)
+	}
+	if node.TerminatedBy != nil {
+		buf.WriteString(" terminated by ")
+		node.TerminatedBy.FormatFast(buf)
+	}
 }
 
 // FormatFast formats the node.
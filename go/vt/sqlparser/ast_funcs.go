@@ -1858,6 +1858,8 @@ func (ty VExplainType) ToString() string {
 		return QueriesStr
 	case AllVExplainType:
 		return AllVExplainStr
+	case TraceVExplainType:
+		return TraceStr
 	default:
 		return "Unknown VExplainType"
 	}
@@ -2138,6 +2140,11 @@ func ContainsAggregation(e SQLNode) bool {
 			// so we don't need to worry about aggregation in the original
 			return false, nil
 		case AggrFunc:
+			if IsWindowFunc(node) {
+				// aggregations with an OVER clause are window functions,
+				// but their arguments could still contain aggregations
+				return true, nil
+			}
 			hasAggregates = true
 			return false, io.EOF
 		}
@@ -2147,6 +2154,61 @@ func ContainsAggregation(e SQLNode) bool {
 	return hasAggregates
 }
 
+// GetOverClause returns the OVER clause of a window function.
+// If the node is not a window function, nil is returned.
+func GetOverClause(node SQLNode) *OverClause {
+	switch node := node.(type) {
+	case *ArgumentLessWindowExpr:
+		return node.OverClause
+	case *FirstOrLastValueExpr:
+		return node.OverClause
+	case *NtileExpr:
+		return node.OverClause
+	case *NTHValueExpr:
+		return node.OverClause
+	case *LagLeadExpr:
+		return node.OverClause
+	case *Count:
+		return node.OverClause
+	case *CountStar:
+		return node.OverClause
+	case *Avg:
+		return node.OverClause
+	case *Max:
+		return node.OverClause
+	case *Min:
+		return node.OverClause
+	case *Sum:
+		return node.OverClause
+	case *BitAnd:
+		return node.OverClause
+	case *BitOr:
+		return node.OverClause
+	case *BitXor:
+		return node.OverClause
+	case *Std:
+		return node.OverClause
+	case *StdDev:
+		return node.OverClause
+	case *StdPop:
+		return node.OverClause
+	case *StdSamp:
+		return node.OverClause
+	case *VarPop:
+		return node.OverClause
+	case *VarSamp:
+		return node.OverClause
+	case *Variance:
+		return node.OverClause
+	}
+	return nil
+}
+
+// IsWindowFunc returns true if the node is a window function, or an aggregation used as one
+func IsWindowFunc(node SQLNode) bool {
+	return GetOverClause(node) != nil
+}
+
 // GetFirstSelect gets the first select statement
 func GetFirstSelect(selStmt SelectStatement) *Select {
 	if selStmt == nil {
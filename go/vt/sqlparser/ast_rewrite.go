@@ -302,6 +302,10 @@ func (a *application) rewriteSQLNode(parent SQLNode, node SQLNode, replacer repl
 		return a.rewriteRefOfLiteral(parent, node, replacer)
 	case *Load:
 		return a.rewriteRefOfLoad(parent, node, replacer)
+	case *LoadFields:
+		return a.rewriteRefOfLoadFields(parent, node, replacer)
+	case *LoadLines:
+		return a.rewriteRefOfLoadLines(parent, node, replacer)
 	case *LocateExpr:
 		return a.rewriteRefOfLocateExpr(parent, node, replacer)
 	case *LockOption:
@@ -5319,12 +5323,109 @@ func (a *application) rewriteRefOfLoad(parent SQLNode, node *Load, replacer repl
 			return true
 		}
 	}
+	if !a.rewriteTableName(node, node.Table, func(newNode, parent SQLNode) {
+		parent.(*Load).Table = newNode.(TableName)
+	}) {
+		return false
+	}
+	if !a.rewritePartitions(node, node.Partitions, func(newNode, parent SQLNode) {
+		parent.(*Load).Partitions = newNode.(Partitions)
+	}) {
+		return false
+	}
+	if !a.rewriteRefOfLoadFields(node, node.Fields, func(newNode, parent SQLNode) {
+		parent.(*Load).Fields = newNode.(*LoadFields)
+	}) {
+		return false
+	}
+	if !a.rewriteRefOfLoadLines(node, node.Lines, func(newNode, parent SQLNode) {
+		parent.(*Load).Lines = newNode.(*LoadLines)
+	}) {
+		return false
+	}
+	if !a.rewriteExprs(node, node.Columns, func(newNode, parent SQLNode) {
+		parent.(*Load).Columns = newNode.(Exprs)
+	}) {
+		return false
+	}
+	if !a.rewriteUpdateExprs(node, node.SetExprs, func(newNode, parent SQLNode) {
+		parent.(*Load).SetExprs = newNode.(UpdateExprs)
+	}) {
+		return false
+	}
 	if a.post != nil {
-		if a.pre == nil {
-			a.cur.replacer = replacer
-			a.cur.parent = parent
-			a.cur.node = node
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
+		if !a.post(&a.cur) {
+			return false
+		}
+	}
+	return true
+}
+func (a *application) rewriteRefOfLoadFields(parent SQLNode, node *LoadFields, replacer replacerFunc) bool {
+	if node == nil {
+		return true
+	}
+	if a.pre != nil {
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
+		if !a.pre(&a.cur) {
+			return true
 		}
+	}
+	if !a.rewriteRefOfLiteral(node, node.TerminatedBy, func(newNode, parent SQLNode) {
+		parent.(*LoadFields).TerminatedBy = newNode.(*Literal)
+	}) {
+		return false
+	}
+	if !a.rewriteRefOfLiteral(node, node.EnclosedBy, func(newNode, parent SQLNode) {
+		parent.(*LoadFields).EnclosedBy = newNode.(*Literal)
+	}) {
+		return false
+	}
+	if !a.rewriteRefOfLiteral(node, node.EscapedBy, func(newNode, parent SQLNode) {
+		parent.(*LoadFields).EscapedBy = newNode.(*Literal)
+	}) {
+		return false
+	}
+	if a.post != nil {
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
+		if !a.post(&a.cur) {
+			return false
+		}
+	}
+	return true
+}
+func (a *application) rewriteRefOfLoadLines(parent SQLNode, node *LoadLines, replacer replacerFunc) bool {
+	if node == nil {
+		return true
+	}
+	if a.pre != nil {
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
+		if !a.pre(&a.cur) {
+			return true
+		}
+	}
+	if !a.rewriteRefOfLiteral(node, node.StartingBy, func(newNode, parent SQLNode) {
+		parent.(*LoadLines).StartingBy = newNode.(*Literal)
+	}) {
+		return false
+	}
+	if !a.rewriteRefOfLiteral(node, node.TerminatedBy, func(newNode, parent SQLNode) {
+		parent.(*LoadLines).TerminatedBy = newNode.(*Literal)
+	}) {
+		return false
+	}
+	if a.post != nil {
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
 		if !a.post(&a.cur) {
 			return false
 		}
@@ -302,6 +302,10 @@ func VisitSQLNode(in SQLNode, f Visit) error {
 		return VisitRefOfLiteral(in, f)
 	case *Load:
 		return VisitRefOfLoad(in, f)
+	case *LoadFields:
+		return VisitRefOfLoadFields(in, f)
+	case *LoadLines:
+		return VisitRefOfLoadLines(in, f)
 	case *LocateExpr:
 		return VisitRefOfLocateExpr(in, f)
 	case *LockOption:
@@ -2593,6 +2597,57 @@ func VisitRefOfLoad(in *Load, f Visit) error {
 	if cont, err := f(in); err != nil || !cont {
 		return err
 	}
+	if err := VisitTableName(in.Table, f); err != nil {
+		return err
+	}
+	if err := VisitPartitions(in.Partitions, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLoadFields(in.Fields, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLoadLines(in.Lines, f); err != nil {
+		return err
+	}
+	if err := VisitExprs(in.Columns, f); err != nil {
+		return err
+	}
+	if err := VisitUpdateExprs(in.SetExprs, f); err != nil {
+		return err
+	}
+	return nil
+}
+func VisitRefOfLoadFields(in *LoadFields, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.TerminatedBy, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.EnclosedBy, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.EscapedBy, f); err != nil {
+		return err
+	}
+	return nil
+}
+func VisitRefOfLoadLines(in *LoadLines, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.StartingBy, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.TerminatedBy, f); err != nil {
+		return err
+	}
 	return nil
 }
 func VisitRefOfLocateExpr(in *LocateExpr, f Visit) error {
@@ -2647,6 +2647,79 @@ func (cached *Literal) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.Val)))
 	return size
 }
+func (cached *Load) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(192)
+	}
+	// field FileName string
+	size += hack.RuntimeAllocSize(int64(len(cached.FileName)))
+	// field Table vitess.io/vitess/go/vt/sqlparser.TableName
+	size += cached.Table.CachedSize(false)
+	// field Partitions vitess.io/vitess/go/vt/sqlparser.Partitions
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Partitions)) * int64(32))
+		for _, elem := range cached.Partitions {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field Charset vitess.io/vitess/go/vt/sqlparser.ColumnCharset
+	size += cached.Charset.CachedSize(false)
+	// field Fields *vitess.io/vitess/go/vt/sqlparser.LoadFields
+	size += cached.Fields.CachedSize(true)
+	// field Lines *vitess.io/vitess/go/vt/sqlparser.LoadLines
+	size += cached.Lines.CachedSize(true)
+	// field Columns vitess.io/vitess/go/vt/sqlparser.Exprs
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Columns)) * int64(16))
+		for _, elem := range cached.Columns {
+			if cc, ok := elem.(cachedObject); ok {
+				size += cc.CachedSize(true)
+			}
+		}
+	}
+	// field SetExprs vitess.io/vitess/go/vt/sqlparser.UpdateExprs
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.SetExprs)) * int64(8))
+		for _, elem := range cached.SetExprs {
+			size += elem.CachedSize(true)
+		}
+	}
+	return size
+}
+func (cached *LoadFields) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(32)
+	}
+	// field TerminatedBy *vitess.io/vitess/go/vt/sqlparser.Literal
+	size += cached.TerminatedBy.CachedSize(true)
+	// field EnclosedBy *vitess.io/vitess/go/vt/sqlparser.Literal
+	size += cached.EnclosedBy.CachedSize(true)
+	// field EscapedBy *vitess.io/vitess/go/vt/sqlparser.Literal
+	size += cached.EscapedBy.CachedSize(true)
+	return size
+}
+func (cached *LoadLines) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(16)
+	}
+	// field StartingBy *vitess.io/vitess/go/vt/sqlparser.Literal
+	size += cached.StartingBy.CachedSize(true)
+	// field TerminatedBy *vitess.io/vitess/go/vt/sqlparser.Literal
+	size += cached.TerminatedBy.CachedSize(true)
+	return size
+}
 func (cached *LocateExpr) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -644,3 +644,65 @@ func GetWorkloadNameFromStatement(statement Statement) string {
 
 	return workloadName
 }
+
+// queryAttributeDirectives are the directives that can also be set with
+// MySQL query attributes, in the order they are added to the comments.
+var queryAttributeDirectives = []string{
+	DirectiveWorkloadName,
+	DirectivePriority,
+	DirectiveQueryTimeout,
+	DirectiveQueryPlanner,
+	DirectiveAllowScatter,
+	DirectiveAllowHashJoin,
+	DirectiveScatterErrorsAsWarnings,
+	DirectiveIgnoreMaxMemoryRows,
+	DirectiveMultiShardAutocommit,
+	DirectiveSkipQueryPlanCache,
+	DirectiveConsolidator,
+}
+
+// AddQueryAttributeDirectives adds the directives set by the given query attributes,
+// whose names are matched case-insensitively, to the comments of a DML statement.
+// Directives already set in the comments of the statement take precedence.
+// It returns true if the comments of the statement were changed.
+func AddQueryAttributeDirectives(stmt Statement, attributes map[string]string) bool {
+	var cmt Commented
+	switch stmt := stmt.(type) {
+	case SelectStatement:
+		cmt = stmt
+	case *Insert:
+		cmt = stmt
+	case *Update:
+		cmt = stmt
+	case *Delete:
+		cmt = stmt
+	default:
+		return false
+	}
+
+	comments := cmt.GetParsedComments()
+	directives := comments.Directives()
+	var added []string
+	for _, directive := range queryAttributeDirectives {
+		if _, isSet := directives.GetString(directive, ""); isSet {
+			continue
+		}
+		for name, val := range attributes {
+			if !strings.EqualFold(name, directive) {
+				continue
+			}
+			// The value must be kept as a single directive in the comment.
+			if val == "" || strings.ContainsFunc(val, unicode.IsSpace) || strings.Contains(val, "*/") {
+				break
+			}
+			added = append(added, directive+"="+val)
+			break
+		}
+	}
+	if len(added) == 0 {
+		return false
+	}
+
+	cmt.SetComments(comments.Prepend(commentDirectivePreamble + " " + strings.Join(added, " ") + " */"))
+	return true
+}
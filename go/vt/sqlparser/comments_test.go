@@ -661,3 +661,50 @@ func TestSetMySQLSetVarValue(t *testing.T) {
 		})
 	}
 }
+
+func TestAddQueryAttributeDirectives(t *testing.T) {
+	testCases := []struct {
+		query      string
+		attributes map[string]string
+		expected   string
+		changed    bool
+	}{{
+		query:      "select * from users",
+		attributes: map[string]string{"workload_name": "billing", "PRIORITY": "10", "unrelated": "1"},
+		expected:   "select /*vt+ WORKLOAD_NAME=billing PRIORITY=10 */ * from users",
+		changed:    true,
+	}, {
+		query:      "select /*vt+ PRIORITY=20 */ * from users union select * from customers",
+		attributes: map[string]string{"priority": "10", "allow_scatter": "true"},
+		expected:   "select /*vt+ ALLOW_SCATTER=true */ /*vt+ PRIORITY=20 */ * from users union select * from customers",
+		changed:    true,
+	}, {
+		query:      "update users set name = 'a'",
+		attributes: map[string]string{"query_timeout_ms": "100"},
+		expected:   "update /*vt+ QUERY_TIMEOUT_MS=100 */ users set `name` = 'a'",
+		changed:    true,
+	}, {
+		query:      "select * from users",
+		attributes: map[string]string{"workload_name": "a b", "planner": "gen4*/", "priority": ""},
+		expected:   "select * from users",
+	}, {
+		query:      "create table t (id int)",
+		attributes: map[string]string{"workload_name": "billing"},
+		expected:   "create table t (\n\tid int\n)",
+	}, {
+		query:      "select * from users",
+		attributes: nil,
+		expected:   "select * from users",
+	}}
+
+	parser := NewTestParser()
+	for _, test := range testCases {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, err := parser.Parse(test.query)
+			require.NoError(t, err)
+			changed := AddQueryAttributeDirectives(stmt, test.attributes)
+			assert.Equal(t, test.changed, changed)
+			assert.Equal(t, test.expected, String(stmt))
+		})
+	}
+}
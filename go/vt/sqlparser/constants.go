@@ -272,6 +272,7 @@ const (
 	QueriesStr     = "queries"
 	AllVExplainStr = "all"
 	PlanStr        = "plan"
+	TraceStr       = "trace"
 
 	// Lock Types
 	ReadStr             = "read"
@@ -486,6 +487,20 @@ const (
 	ReplaceAct
 )
 
+// Constants for Enum Type - Load.Priority
+const (
+	NoLoadPriority LoadPriority = iota
+	LowPriorityLoad
+	ConcurrentLoad
+)
+
+// Constants for Enum Type - Load.Duplicate
+const (
+	LoadDuplicateError LoadDuplicate = iota
+	LoadDuplicateReplace
+	LoadDuplicateIgnore
+)
+
 // Constants for Enum Type - DDL.Action
 const (
 	CreateDDLAction DDLAction = iota
@@ -824,6 +839,7 @@ const (
 	QueriesVExplainType VExplainType = iota
 	PlanVExplainType
 	AllVExplainType
+	TraceVExplainType
 )
 
 // Constant for Enum Type - SelectIntoType
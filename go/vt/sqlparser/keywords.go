@@ -179,6 +179,7 @@ var keywords = []keyword{
 	{"complete", COMPLETE},
 	{"compressed", COMPRESSED},
 	{"compression", COMPRESSION},
+	{"concurrent", CONCURRENT},
 	{"condition", UNUSED},
 	{"connection", CONNECTION},
 	{"consistent", CONSISTENT},
@@ -326,7 +327,7 @@ var keywords = []keyword{
 	{"in", IN},
 	{"index", INDEX},
 	{"indexes", INDEXES},
-	{"infile", UNUSED},
+	{"infile", INFILE},
 	{"inout", UNUSED},
 	{"inner", INNER},
 	{"inplace", INPLACE},
@@ -705,6 +706,7 @@ var keywords = []keyword{
 	{"tinyint", TINYINT},
 	{"tinytext", TINYTEXT},
 	{"to", TO},
+	{"trace", TRACE},
 	{"trailing", TRAILING},
 	{"transaction", TRANSACTION},
 	{"tree", TREE},
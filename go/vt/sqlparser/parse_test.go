@@ -2541,6 +2541,11 @@ var (
 		input: "vexplain all select * from t",
 	}, {
 		input: "vexplain plan select * from t",
+	}, {
+		input: "vexplain trace select * from t",
+	}, {
+		input:  "select trace from t",
+		output: "select `trace` from t",
 	}, {
 		input:  "vexplain select * from t",
 		output: "vexplain plan select * from t",
@@ -3594,6 +3599,9 @@ var (
 	}, {
 		input:  "SELECT time, subject, val, FIRST_VALUE(val)  OVER w AS 'first', LAST_VALUE(val) OVER w AS 'last', NTH_VALUE(val, 2) OVER w AS 'second', NTH_VALUE(val, 4) OVER w AS 'fourth' FROM observations WINDOW w AS (PARTITION BY subject ORDER BY time ASC RANGE BETWEEN 10 PRECEDING AND 10 FOLLOWING);",
 		output: "select `time`, subject, val, first_value(val) over w as `first`, last_value(val) over w as `last`, nth_value(val, 2) over w as `second`, nth_value(val, 4) over w as fourth from observations window w AS ( partition by subject order by `time` asc range between 10 preceding and 10 following)",
+	}, {
+		input:  "SELECT col, FIRST_VALUE(id) OVER (w ORDER BY id) FROM t WINDOW w AS (PARTITION BY col)",
+		output: "select col, first_value(id) over ( w order by id asc) from t window w AS ( partition by col)",
 	}, {
 		input:  "SELECT ExtractValue('<a><b/></a>', '/a/b')",
 		output: "select extractvalue('<a><b/></a>', '/a/b') from dual",
@@ -4679,7 +4687,7 @@ func TestLoadData(t *testing.T) {
 		"load data from s3 'x.txt'",
 		"load data from s3 manifest 'x.txt'",
 		"load data from s3 file 'x.txt'",
-		"load data infile 'x.txt' into table 'c'",
+		"load data infile 'x.txt' into table c",
 		"load data from s3 'x.txt' into table x"}
 
 	parser := NewTestParser()
@@ -4687,6 +4695,42 @@ func TestLoadData(t *testing.T) {
 		_, err := parser.Parse(tcase)
 		require.NoError(t, err)
 	}
+
+	testcases := []struct {
+		input  string
+		output string
+	}{{
+		input:  "load data infile 'x.txt' into table c",
+		output: "load data infile 'x.txt' into table c",
+	}, {
+		input:  "LOAD DATA LOW_PRIORITY LOCAL INFILE '/tmp/x.csv' REPLACE INTO TABLE ks.c PARTITION (p0, p1) CHARACTER SET utf8mb4",
+		output: "load data low_priority local infile '/tmp/x.csv' replace into table ks.c partition (p0, p1) character set utf8mb4",
+	}, {
+		input:  "load data concurrent local infile 'x.csv' ignore into table c columns terminated by ',' optionally enclosed by '\"' escaped by '\\\\' lines starting by 'x' terminated by '\\r\\n' ignore 1 rows (a, @dummy, b) set c = now()",
+		output: "load data concurrent local infile 'x.csv' ignore into table c fields terminated by ',' optionally enclosed by '\\\"' escaped by '\\\\' lines starting by 'x' terminated by '\\r\\n' ignore 1 lines (a, @dummy, b) set c = now()",
+	}}
+	for _, tcase := range testcases {
+		t.Run(tcase.input, func(t *testing.T) {
+			tree, err := parser.Parse(tcase.input)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.output, String(tree))
+		})
+	}
+
+	// The FIELDS clause must come before the LINES clause.
+	_, err := parser.Parse("load data local infile 'x.csv' into table c lines terminated by '\\n' fields terminated by ','")
+	require.Error(t, err)
+
+	tree, err := parser.Parse("load data local infile 'x.csv' into table c fields terminated by ';' enclosed by '\"' (a, @b)")
+	require.NoError(t, err)
+	load := tree.(*Load)
+	assert.True(t, load.Local)
+	assert.Equal(t, "x.csv", load.FileName)
+	assert.Equal(t, ";", load.Fields.TerminatedBy.Val)
+	assert.Equal(t, "\"", load.Fields.EnclosedBy.Val)
+	assert.Nil(t, load.Fields.EscapedBy)
+	assert.Nil(t, load.Lines)
+	assert.Equal(t, Exprs{NewColName("a"), NewVariableExpression("b", SingleAt)}, load.Columns)
 }
 
 func TestCreateTable(t *testing.T) {
@@ -202,6 +202,10 @@ func markBindVariable(yylex yyLexer, bvar string) {
   jtOnResponse	*JtOnResponse
   variables      []*Variable
   variable       *Variable
+  loadPriority   LoadPriority
+  loadDuplicate  LoadDuplicate
+  loadFields     *LoadFields
+  loadLines      *LoadLines
 }
 
 // These precedence rules are there to handle shift-reduce conflicts.
@@ -243,7 +247,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %token <str> SELECT STREAM VSTREAM INSERT UPDATE DELETE FROM WHERE GROUP HAVING ORDER BY LIMIT OFFSET FOR
 %token <str> ALL DISTINCT AS EXISTS ASC DESC INTO DUPLICATE DEFAULT SET LOCK UNLOCK KEYS DO CALL
 %token <str> DISTINCTROW PARSER GENERATED ALWAYS
-%token <str> OUTFILE S3 DATA LOAD LINES TERMINATED ESCAPED ENCLOSED
+%token <str> OUTFILE S3 DATA LOAD LINES TERMINATED ESCAPED ENCLOSED INFILE CONCURRENT
 %token <str> DUMPFILE CSV HEADER MANIFEST OVERWRITE STARTING OPTIONALLY
 %token <str> VALUES LAST_INSERT_ID
 %token <str> NEXT VALUE SHARE MODE
@@ -393,7 +397,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %token <str> GTID_SUBSET GTID_SUBTRACT WAIT_FOR_EXECUTED_GTID_SET WAIT_UNTIL_SQL_THREAD_AFTER_GTIDS
 
 // Explain tokens
-%token <str> FORMAT TREE VITESS TRADITIONAL VTEXPLAIN VEXPLAIN PLAN
+%token <str> FORMAT TREE VITESS TRADITIONAL VTEXPLAIN VEXPLAIN PLAN TRACE
 
 // Lock type tokens
 %token <str> LOCAL LOW_PRIORITY
@@ -524,6 +528,14 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %type <lock> locking_clause
 %type <columns> ins_column_list column_list column_list_opt column_list_empty index_list
 %type <variable> variable_expr set_variable user_defined_variable
+%type <loadPriority> load_priority_opt
+%type <loadDuplicate> load_duplicate_opt
+%type <loadFields> load_fields_opt load_field_list
+%type <loadLines> load_lines_opt load_line_list
+%type <integer> load_ignore_lines_opt
+%type <exprs> load_column_list_opt load_column_list
+%type <expr> load_column
+%type <updateExprs> load_set_opt
 %type <variables> at_id_list execute_statement_list_opt
 %type <partitions> opt_partition_clause partition_list
 %type <updateExprs> on_dup_opt
@@ -702,10 +714,168 @@ do_statement:
   }
 
 load_statement:
-  LOAD DATA skip_to_end
+  LOAD DATA FROM skip_to_end
   {
     $$ = &Load{}
   }
+| LOAD DATA load_priority_opt local_opt INFILE STRING load_duplicate_opt INTO TABLE table_name opt_partition_clause charset_opt load_fields_opt load_lines_opt load_ignore_lines_opt load_column_list_opt load_set_opt
+  {
+    $$ = &Load{Priority: $3, Local: $4, FileName: $6, Duplicate: $7, Table: $10, Partitions: $11, Charset: $12, Fields: $13, Lines: $14, IgnoreLines: $15, Columns: $16, SetExprs: $17}
+  }
+
+load_priority_opt:
+  {
+    $$ = NoLoadPriority
+  }
+| LOW_PRIORITY
+  {
+    $$ = LowPriorityLoad
+  }
+| CONCURRENT
+  {
+    $$ = ConcurrentLoad
+  }
+
+load_duplicate_opt:
+  {
+    $$ = LoadDuplicateError
+  }
+| REPLACE
+  {
+    $$ = LoadDuplicateReplace
+  }
+| IGNORE
+  {
+    $$ = LoadDuplicateIgnore
+  }
+
+load_fields_opt:
+  {
+    $$ = nil
+  }
+| columns_or_fields load_field_list
+  {
+    $$ = $2
+  }
+
+load_field_list:
+  TERMINATED BY STRING
+  {
+    $$ = &LoadFields{TerminatedBy: NewStrLiteral($3)}
+  }
+| ENCLOSED BY STRING
+  {
+    $$ = &LoadFields{EnclosedBy: NewStrLiteral($3)}
+  }
+| OPTIONALLY ENCLOSED BY STRING
+  {
+    $$ = &LoadFields{EnclosedBy: NewStrLiteral($4), OptionallyEnclosed: true}
+  }
+| ESCAPED BY STRING
+  {
+    $$ = &LoadFields{EscapedBy: NewStrLiteral($3)}
+  }
+| load_field_list TERMINATED BY STRING
+  {
+    $1.TerminatedBy = NewStrLiteral($4)
+    $$ = $1
+  }
+| load_field_list ENCLOSED BY STRING
+  {
+    $1.EnclosedBy = NewStrLiteral($4)
+    $$ = $1
+  }
+| load_field_list OPTIONALLY ENCLOSED BY STRING
+  {
+    $1.EnclosedBy = NewStrLiteral($5)
+    $1.OptionallyEnclosed = true
+    $$ = $1
+  }
+| load_field_list ESCAPED BY STRING
+  {
+    $1.EscapedBy = NewStrLiteral($4)
+    $$ = $1
+  }
+
+load_lines_opt:
+  {
+    $$ = nil
+  }
+| LINES load_line_list
+  {
+    $$ = $2
+  }
+
+load_line_list:
+  STARTING BY STRING
+  {
+    $$ = &LoadLines{StartingBy: NewStrLiteral($3)}
+  }
+| TERMINATED BY STRING
+  {
+    $$ = &LoadLines{TerminatedBy: NewStrLiteral($3)}
+  }
+| load_line_list STARTING BY STRING
+  {
+    $1.StartingBy = NewStrLiteral($4)
+    $$ = $1
+  }
+| load_line_list TERMINATED BY STRING
+  {
+    $1.TerminatedBy = NewStrLiteral($4)
+    $$ = $1
+  }
+
+load_ignore_lines_opt:
+  {
+    $$ = 0
+  }
+| IGNORE INTEGRAL LINES
+  {
+    $$ = convertStringToInt($2)
+  }
+| IGNORE INTEGRAL ROWS
+  {
+    $$ = convertStringToInt($2)
+  }
+
+load_column_list_opt:
+  {
+    $$ = nil
+  }
+| openb load_column_list closeb
+  {
+    $$ = $2
+  }
+
+load_column_list:
+  load_column
+  {
+    $$ = Exprs{$1}
+  }
+| load_column_list ',' load_column
+  {
+    $$ = append($1, $3)
+  }
+
+load_column:
+  column_name
+  {
+    $$ = $1
+  }
+| user_defined_variable
+  {
+    $$ = $1
+  }
+
+load_set_opt:
+  {
+    $$ = nil
+  }
+| SET update_list
+  {
+    $$ = $2
+  }
 
 with_clause:
   WITH with_list
@@ -4457,6 +4627,10 @@ vexplain_type_opt:
   {
     $$ = QueriesVExplainType
   }
+| TRACE
+  {
+    $$ = TraceVExplainType
+  }
 
 explain_synonyms:
   EXPLAIN
@@ -5646,6 +5820,7 @@ window_partition_clause_opt:
 
 sql_id_opt:
   {
+    $$ = IdentifierCI{}
   }
 | sql_id
   {
@@ -8044,6 +8219,7 @@ reserved_keyword:
 | IGNORE
 | IN
 | INDEX
+| INFILE
 | INNER
 | INSERT
 | INTERVAL
@@ -8192,6 +8368,7 @@ non_reserved_keyword:
 | COMPONENT
 | COMPRESSED
 | COMPRESSION
+| CONCURRENT
 | CONNECTION
 | CONSISTENT
 | COPY
@@ -8537,6 +8714,7 @@ non_reserved_keyword:
 | TINYBLOB
 | TINYINT
 | TINYTEXT
+| TRACE
 | TRADITIONAL
 | TRANSACTION
 | TREE
@@ -0,0 +1,233 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tableacl
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/json2"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tableaclpb "vitess.io/vitess/go/vt/proto/tableacl"
+	"vitess.io/vitess/go/vt/tableacl/acl"
+)
+
+type columnACL struct {
+	// columns are lower-cased, as column names are case-insensitive.
+	columns       []string
+	deniedReaders acl.ACL
+}
+
+type rowFilter struct {
+	predicate string
+	readers   acl.ACL
+}
+
+func loadColumnACLs(specs []*tableaclpb.ColumnACL, newACL func([]string) (acl.ACL, error)) ([]columnACL, error) {
+	columnACLs := make([]columnACL, 0, len(specs))
+	for _, spec := range specs {
+		deniedReaders, err := newACL(spec.DeniedReaders)
+		if err != nil {
+			return nil, err
+		}
+		columns := make([]string, 0, len(spec.Columns))
+		for _, column := range spec.Columns {
+			columns = append(columns, strings.ToLower(column))
+		}
+		columnACLs = append(columnACLs, columnACL{columns: columns, deniedReaders: deniedReaders})
+	}
+	return columnACLs, nil
+}
+
+func loadRowFilters(specs []*tableaclpb.RowFilter, newACL func([]string) (acl.ACL, error)) ([]rowFilter, error) {
+	rowFilters := make([]rowFilter, 0, len(specs))
+	for _, spec := range specs {
+		readers, err := newACL(spec.Readers)
+		if err != nil {
+			return nil, err
+		}
+		rowFilters = append(rowFilters, rowFilter{predicate: spec.Predicate, readers: readers})
+	}
+	return rowFilters, nil
+}
+
+func validateDataACLs(group *tableaclpb.TableGroupSpec) error {
+	for _, columnACL := range group.ColumnAcls {
+		if len(columnACL.Columns) == 0 {
+			return fmt.Errorf("column ACL of table group %q has no columns", group.Name)
+		}
+	}
+	for _, rowFilter := range group.RowFilters {
+		if strings.TrimSpace(rowFilter.Predicate) == "" {
+			return fmt.Errorf("row filter of table group %q has no predicate", group.Name)
+		}
+	}
+	return nil
+}
+
+// DataACL holds the column and row level ACLs of a Config. Unlike the
+// table roles, which are enforced by vttablet, they are enforced by vtgate.
+// The names of the table groups can be qualified with a keyspace, as in
+// "commerce.customers", while unqualified names apply in all the keyspaces.
+type DataACL struct {
+	entries aclEntries
+	config  *tableaclpb.Config
+}
+
+// TableRestrictions are the column and row level ACLs of a table that apply
+// to a caller.
+type TableRestrictions struct {
+	// DeniedColumns are the lower-cased names of the columns the caller cannot read.
+	DeniedColumns map[string]bool
+	// RowFilters are the predicates the rows read by the caller must all match.
+	RowFilters []string
+}
+
+// IsColumnDenied returns true if the caller cannot read the given column.
+func (r *TableRestrictions) IsColumnDenied(column string) bool {
+	return r != nil && r.DeniedColumns[strings.ToLower(column)]
+}
+
+// LoadDataACL reads a Config from a file, in the same formats as Init,
+// and returns its DataACL.
+func LoadDataACL(configFile string, factory acl.Factory) (*DataACL, error) {
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tableaclpb.Config{}
+	if err := config.UnmarshalVT(data); err != nil {
+		if jsonErr := json2.Unmarshal(data, config); jsonErr != nil {
+			return nil, fmt.Errorf("unable to parse table ACL config file %v as a protobuf or json file. protobuf err: %v json err: %v", configFile, err, jsonErr)
+		}
+	}
+	return NewDataACL(config, factory)
+}
+
+// NewDataACL returns the DataACL of a Config.
+func NewDataACL(config *tableaclpb.Config, factory acl.Factory) (*DataACL, error) {
+	entries, err := load(config, factory.New)
+	if err != nil {
+		return nil, err
+	}
+	return &DataACL{entries: entries, config: config.CloneVT()}, nil
+}
+
+// Predicates returns all the row filter predicates, so they can be validated.
+func (d *DataACL) Predicates() []string {
+	var predicates []string
+	for _, group := range d.config.TableGroups {
+		for _, rowFilter := range group.RowFilters {
+			predicates = append(predicates, rowFilter.Predicate)
+		}
+	}
+	return predicates
+}
+
+// Equal returns true if both DataACLs were created from the same Config.
+func (d *DataACL) Equal(other *DataACL) bool {
+	if d == nil || other == nil {
+		return d == other
+	}
+	return proto.Equal(d.config, other.config)
+}
+
+// Restrictions returns the restrictions of the caller on the given table of
+// the keyspace, or nil if there are none. The restrictions of the table groups
+// matching the qualified and the unqualified table name all apply.
+func (d *DataACL) Restrictions(keyspace, table string, caller *querypb.VTGateCallerID) *TableRestrictions {
+	if d == nil {
+		return nil
+	}
+	caller = nonNilCaller(caller)
+	var restrictions *TableRestrictions
+	for i := range d.entries {
+		entry := &d.entries[i]
+		name := table
+		if strings.Contains(entry.tableNameOrPrefix, ".") {
+			name = keyspace + "." + table
+		}
+		if !entry.matches(name) {
+			continue
+		}
+		for _, columnACL := range entry.columnACLs {
+			if !columnACL.deniedReaders.IsMember(caller) {
+				continue
+			}
+			if restrictions == nil {
+				restrictions = &TableRestrictions{}
+			}
+			if restrictions.DeniedColumns == nil {
+				restrictions.DeniedColumns = make(map[string]bool)
+			}
+			for _, column := range columnACL.columns {
+				restrictions.DeniedColumns[column] = true
+			}
+		}
+		for _, rowFilter := range entry.rowFilters {
+			if !rowFilter.readers.IsMember(caller) {
+				continue
+			}
+			if restrictions == nil {
+				restrictions = &TableRestrictions{}
+			}
+			restrictions.RowFilters = append(restrictions.RowFilters, rowFilter.predicate)
+		}
+	}
+	return restrictions
+}
+
+// CallerKey identifies the column and row level ACLs that apply to the caller,
+// across all the tables. Callers with the same key have the same restrictions.
+// It is empty if the caller has no restrictions.
+func (d *DataACL) CallerKey(caller *querypb.VTGateCallerID) string {
+	if d == nil {
+		return ""
+	}
+	caller = nonNilCaller(caller)
+	var key []string
+	for i, entry := range d.entries {
+		for j, columnACL := range entry.columnACLs {
+			if columnACL.deniedReaders.IsMember(caller) {
+				key = append(key, fmt.Sprintf("%d.c%d", i, j))
+			}
+		}
+		for j, rowFilter := range entry.rowFilters {
+			if rowFilter.readers.IsMember(caller) {
+				key = append(key, fmt.Sprintf("%d.r%d", i, j))
+			}
+		}
+	}
+	return strings.Join(key, ",")
+}
+
+// IsRestricted returns true if the caller has column or row level ACLs on any table.
+func (d *DataACL) IsRestricted(caller *querypb.VTGateCallerID) bool {
+	return d.CallerKey(caller) != ""
+}
+
+// nonNilCaller lets ACL implementations safely check the membership of callers
+// that did not identify themselves.
+func nonNilCaller(caller *querypb.VTGateCallerID) *querypb.VTGateCallerID {
+	if caller == nil {
+		return &querypb.VTGateCallerID{}
+	}
+	return caller
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tableacl
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tableaclpb "vitess.io/vitess/go/vt/proto/tableacl"
+	"vitess.io/vitess/go/vt/tableacl/simpleacl"
+)
+
+func testDataACLConfig() *tableaclpb.Config {
+	return &tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			Name:                 "customers",
+			TableNamesOrPrefixes: []string{"customer%"},
+			Readers:              []string{"support", "analysts"},
+			ColumnAcls: []*tableaclpb.ColumnACL{{
+				Columns:       []string{"SSN", "email"},
+				DeniedReaders: []string{"analysts"},
+			}},
+			RowFilters: []*tableaclpb.RowFilter{{
+				Predicate: "region = 'eu'",
+				Readers:   []string{"support", "analysts"},
+			}, {
+				Predicate: "deleted = 0",
+				Readers:   []string{"analysts"},
+			}},
+		}, {
+			Name:                 "orders",
+			TableNamesOrPrefixes: []string{"orders"},
+			Readers:              []string{"support", "analysts"},
+		}, {
+			Name:                 "commerce_orders",
+			TableNamesOrPrefixes: []string{"commerce.orders"},
+			Readers:              []string{"support", "analysts"},
+			ColumnAcls: []*tableaclpb.ColumnACL{{
+				Columns:       []string{"card"},
+				DeniedReaders: []string{"analysts"},
+			}},
+		}},
+	}
+}
+
+func TestDataACLRestrictions(t *testing.T) {
+	dataACL, err := NewDataACL(testDataACLConfig(), &simpleacl.Factory{})
+	require.NoError(t, err)
+
+	analyst := &querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}}
+	support := &querypb.VTGateCallerID{Username: "bob", Groups: []string{"support"}}
+
+	r := dataACL.Restrictions("commerce", "customers", analyst)
+	require.NotNil(t, r)
+	assert.True(t, r.IsColumnDenied("ssn"))
+	assert.True(t, r.IsColumnDenied("Email"))
+	assert.False(t, r.IsColumnDenied("name"))
+	assert.Equal(t, []string{"region = 'eu'", "deleted = 0"}, r.RowFilters)
+
+	r = dataACL.Restrictions("commerce", "customer_addresses", support)
+	require.NotNil(t, r)
+	assert.False(t, r.IsColumnDenied("ssn"))
+	assert.Equal(t, []string{"region = 'eu'"}, r.RowFilters)
+
+	assert.Nil(t, dataACL.Restrictions("commerce", "unknown", analyst))
+
+	// the table groups qualified with a keyspace only apply to the tables of that keyspace
+	r = dataACL.Restrictions("commerce", "orders", analyst)
+	require.NotNil(t, r)
+	assert.True(t, r.IsColumnDenied("card"))
+	assert.Nil(t, dataACL.Restrictions("customer", "orders", analyst))
+	assert.NotNil(t, dataACL.Restrictions("customer", "customers", analyst))
+	assert.Nil(t, dataACL.Restrictions("commerce", "customers", nil))
+
+	var noACL *DataACL
+	assert.Nil(t, noACL.Restrictions("commerce", "customers", analyst))
+	assert.False(t, noACL.Restrictions("commerce", "customers", analyst).IsColumnDenied("ssn"))
+}
+
+func TestDataACLCallerKey(t *testing.T) {
+	dataACL, err := NewDataACL(testDataACLConfig(), &simpleacl.Factory{})
+	require.NoError(t, err)
+
+	analyst := dataACL.CallerKey(&querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}})
+	support := dataACL.CallerKey(&querypb.VTGateCallerID{Username: "bob", Groups: []string{"support"}})
+	assert.NotEmpty(t, analyst)
+	assert.NotEmpty(t, support)
+	assert.NotEqual(t, analyst, support)
+	assert.Equal(t, analyst, dataACL.CallerKey(&querypb.VTGateCallerID{Username: "carol", Groups: []string{"analysts"}}))
+	assert.Empty(t, dataACL.CallerKey(&querypb.VTGateCallerID{Username: "dave"}))
+	assert.Empty(t, dataACL.CallerKey(nil))
+}
+
+func TestDataACLEqual(t *testing.T) {
+	dataACL, err := NewDataACL(testDataACLConfig(), &simpleacl.Factory{})
+	require.NoError(t, err)
+	same, err := NewDataACL(testDataACLConfig(), &simpleacl.Factory{})
+	require.NoError(t, err)
+	assert.True(t, dataACL.Equal(same))
+
+	config := testDataACLConfig()
+	config.TableGroups[0].RowFilters[1].Predicate = "deleted = 1"
+	other, err := NewDataACL(config, &simpleacl.Factory{})
+	require.NoError(t, err)
+	assert.False(t, dataACL.Equal(other))
+	assert.False(t, dataACL.Equal(nil))
+
+	var noACL *DataACL
+	assert.True(t, noACL.Equal(nil))
+}
+
+func TestDataACLValidation(t *testing.T) {
+	config := testDataACLConfig()
+	config.TableGroups[0].ColumnAcls[0].Columns = nil
+	_, err := NewDataACL(config, &simpleacl.Factory{})
+	assert.ErrorContains(t, err, `column ACL of table group "customers" has no columns`)
+
+	config = testDataACLConfig()
+	config.TableGroups[0].RowFilters[1].Predicate = " "
+	_, err = NewDataACL(config, &simpleacl.Factory{})
+	assert.ErrorContains(t, err, `row filter of table group "customers" has no predicate`)
+}
+
+func TestLoadDataACL(t *testing.T) {
+	config := testDataACLConfig()
+	data, err := config.MarshalVT()
+	require.NoError(t, err)
+	configFile := path.Join(t.TempDir(), "data_acl.pb")
+	require.NoError(t, os.WriteFile(configFile, data, 0o600))
+
+	dataACL, err := LoadDataACL(configFile, &simpleacl.Factory{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"region = 'eu'", "deleted = 0"}, dataACL.Predicates())
+
+	_, err = LoadDataACL(path.Join(t.TempDir(), "missing.json"), &simpleacl.Factory{})
+	assert.Error(t, err)
+}
@@ -43,6 +43,8 @@ type aclEntry struct {
 	tableNameOrPrefix string
 	groupName         string
 	acl               map[Role]acl.ACL
+	columnACLs        []columnACL
+	rowFilters        []rowFilter
 }
 
 type aclEntries []aclEntry
@@ -59,6 +61,31 @@ func (aes aclEntries) Swap(i, j int) {
 	aes[i], aes[j] = aes[j], aes[i]
 }
 
+// matches returns true if the table name or prefix of the entry matches the table.
+func (ae *aclEntry) matches(table string) bool {
+	val := ae.tableNameOrPrefix
+	return table == val || (strings.HasSuffix(val, "%") && strings.HasPrefix(table, val[:len(val)-1]))
+}
+
+// find returns the entry of the given table, or nil if there is none.
+// The entries must be sorted.
+func (aes aclEntries) find(table string) *aclEntry {
+	start := 0
+	end := len(aes)
+	for start < end {
+		mid := start + (end-start)/2
+		val := aes[mid].tableNameOrPrefix
+		if aes[mid].matches(table) {
+			return &aes[mid]
+		} else if table < val {
+			end = mid
+		} else {
+			start = mid + 1
+		}
+	}
+	return nil
+}
+
 // mu protects acls and defaultACL.
 var mu sync.Mutex
 
@@ -152,6 +179,14 @@ func load(config *tableaclpb.Config, newACL func([]string) (acl.ACL, error)) (en
 		if err != nil {
 			return nil, err
 		}
+		columnACLs, err := loadColumnACLs(group.ColumnAcls, newACL)
+		if err != nil {
+			return nil, err
+		}
+		rowFilters, err := loadRowFilters(group.RowFilters, newACL)
+		if err != nil {
+			return nil, err
+		}
 		for _, tableNameOrPrefix := range group.TableNamesOrPrefixes {
 			entries = append(entries, aclEntry{
 				tableNameOrPrefix: tableNameOrPrefix,
@@ -161,6 +196,8 @@ func load(config *tableaclpb.Config, newACL func([]string) (acl.ACL, error)) (en
 					WRITER: writers,
 					ADMIN:  admins,
 				},
+				columnACLs: columnACLs,
+				rowFilters: rowFilters,
 			})
 		}
 	}
@@ -208,6 +245,9 @@ func (tacl *tableACL) Valid() bool {
 func ValidateProto(config *tableaclpb.Config) (err error) {
 	t := patricia.NewTrie()
 	for _, group := range config.TableGroups {
+		if err := validateDataACLs(group); err != nil {
+			return err
+		}
 		for _, name := range group.TableNamesOrPrefixes {
 			var prefix patricia.Prefix
 			if strings.HasSuffix(name, "%") {
@@ -241,24 +281,12 @@ func Authorized(table string, role Role) *ACLResult {
 func (tacl *tableACL) Authorized(table string, role Role) *ACLResult {
 	tacl.RLock()
 	defer tacl.RUnlock()
-	start := 0
-	end := len(tacl.entries)
-	for start < end {
-		mid := start + (end-start)/2
-		val := tacl.entries[mid].tableNameOrPrefix
-		if table == val || (strings.HasSuffix(val, "%") && strings.HasPrefix(table, val[:len(val)-1])) {
-			acl, ok := tacl.entries[mid].acl[role]
-			if ok {
-				return &ACLResult{
-					ACL:       acl,
-					GroupName: tacl.entries[mid].groupName,
-				}
+	if entry := tacl.entries.find(table); entry != nil {
+		if acl, ok := entry.acl[role]; ok {
+			return &ACLResult{
+				ACL:       acl,
+				GroupName: entry.groupName,
 			}
-			break
-		} else if table < val {
-			end = mid
-		} else {
-			start = mid + 1
 		}
 	}
 	return &ACLResult{
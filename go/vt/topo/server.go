@@ -80,6 +80,7 @@ const (
 	ExternalClustersFile     = "ExternalClusters"
 	ShardRoutingRulesFile    = "ShardRoutingRules"
 	KeyspaceRoutingRulesFile = "KeyspaceRoutingRules"
+	MirrorRulesFile          = "MirrorRules"
 )
 
 // Path for all object types.
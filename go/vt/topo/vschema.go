@@ -190,3 +190,37 @@ func (ts *Server) GetKeyspaceRoutingRules(ctx context.Context) (*vschemapb.Keysp
 	}
 	return rules, nil
 }
+
+// SaveMirrorRules saves the mirror rules into the topo.
+func (ts *Server) SaveMirrorRules(ctx context.Context, rules *vschemapb.MirrorRules) error {
+	data, err := rules.MarshalVT()
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		// No rules, remove it.
+		if err := ts.globalCell.Delete(ctx, MirrorRulesFile, nil); err != nil && !IsErrType(err, NoNode) {
+			return err
+		}
+		return nil
+	}
+	_, err = ts.globalCell.Update(ctx, MirrorRulesFile, data, nil)
+	return err
+}
+
+// GetMirrorRules fetches the mirror rules from the topo. It returns nil if there are none.
+func (ts *Server) GetMirrorRules(ctx context.Context) (*vschemapb.MirrorRules, error) {
+	rules := &vschemapb.MirrorRules{}
+	data, _, err := ts.globalCell.Get(ctx, MirrorRulesFile)
+	if err != nil {
+		if IsErrType(err, NoNode) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	err = rules.UnmarshalVT(data)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "bad mirror rules data: %q", data)
+	}
+	return rules, nil
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"path/filepath"
 	"runtime/debug"
 	"sort"
@@ -34,6 +35,7 @@ import (
 	"google.golang.org/grpc"
 
 	"vitess.io/vitess/go/event"
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/netutil"
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sets"
@@ -63,6 +65,7 @@ import (
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/base"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
@@ -87,6 +90,7 @@ const (
 // VtctldServer implements the Vtctld RPC service protocol.
 type VtctldServer struct {
 	vtctlservicepb.UnimplementedVtctldServer
+	env *vtenv.Environment
 	ts  *topo.Server
 	tmc tmclient.TabletManagerClient
 	ws  *workflow.Server
@@ -97,6 +101,7 @@ func NewVtctldServer(env *vtenv.Environment, ts *topo.Server) *VtctldServer {
 	tmc := tmclient.NewTabletManagerClient()
 
 	return &VtctldServer{
+		env: env,
 		ts:  ts,
 		tmc: tmc,
 		ws:  workflow.NewServer(env, ts, tmc),
@@ -106,10 +111,12 @@ func NewVtctldServer(env *vtenv.Environment, ts *topo.Server) *VtctldServer {
 // NewTestVtctldServer returns a new VtctldServer for the given topo server
 // AND tmclient for use in tests. This should NOT be used in production.
 func NewTestVtctldServer(ts *topo.Server, tmc tmclient.TabletManagerClient) *VtctldServer {
+	env := vtenv.NewTestEnv()
 	return &VtctldServer{
+		env: env,
 		ts:  ts,
 		tmc: tmc,
-		ws:  workflow.NewServer(vtenv.NewTestEnv(), ts, tmc),
+		ws:  workflow.NewServer(env, ts, tmc),
 	}
 }
 
@@ -165,6 +172,35 @@ func (s *VtctldServer) AddCellsAlias(ctx context.Context, req *vtctldatapb.AddCe
 	return &vtctldatapb.AddCellsAliasResponse{}, nil
 }
 
+// ApplyMirrorRules is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ApplyMirrorRules(ctx context.Context, req *vtctldatapb.ApplyMirrorRulesRequest) (resp *vtctldatapb.ApplyMirrorRulesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyMirrorRules")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("skip_rebuild", req.SkipRebuild)
+	span.Annotate("rebuild_cells", strings.Join(req.RebuildCells, ","))
+
+	if err = s.ts.SaveMirrorRules(ctx, req.MirrorRules); err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.ApplyMirrorRulesResponse{}
+
+	if req.SkipRebuild {
+		log.Warningf("Skipping rebuild of SrvVSchema, will need to run RebuildVSchemaGraph for changes to take effect")
+		return resp, nil
+	}
+
+	if err = s.ts.RebuildSrvVSchema(ctx, req.RebuildCells); err != nil {
+		err = vterrors.Wrapf(err, "RebuildSrvVSchema(%v) failed: %v", req.RebuildCells, err)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ApplyRoutingRules(ctx context.Context, req *vtctldatapb.ApplyRoutingRulesRequest) (resp *vtctldatapb.ApplyRoutingRulesResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyRoutingRules")
@@ -1548,6 +1584,23 @@ func (s *VtctldServer) GetKeyspaces(ctx context.Context, req *vtctldatapb.GetKey
 	return &vtctldatapb.GetKeyspacesResponse{Keyspaces: keyspaces}, nil
 }
 
+// GetMirrorRules is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetMirrorRules(ctx context.Context, req *vtctldatapb.GetMirrorRulesRequest) (resp *vtctldatapb.GetMirrorRulesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetMirrorRules")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	rules, err := s.ts.GetMirrorRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.GetMirrorRulesResponse{
+		MirrorRules: rules,
+	}, nil
+}
+
 // GetPermissions is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetPermissions(ctx context.Context, req *vtctldatapb.GetPermissionsRequest) (resp *vtctldatapb.GetPermissionsResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetPermissions")
@@ -1929,6 +1982,28 @@ func (s *VtctldServer) UpdateThrottlerConfig(ctx context.Context, req *vtctldata
 	if req.CheckAsCheckSelf && req.CheckAsCheckShard {
 		return nil, fmt.Errorf("--check-as-check-self and --check-as-check-shard are mutually exclusive")
 	}
+	if req.MetricName != "" {
+		if _, err := base.ParseMetricNames([]string{req.MetricName}); err != nil {
+			return nil, err
+		}
+	}
+	if req.CustomMetricUrlSet && req.CustomMetricUrl != "" {
+		if _, err := base.NewMetricSourceFromURL(req.CustomMetricUrl, nil); err != nil {
+			return nil, err
+		}
+	}
+	var appCheckedMetrics []string
+	if req.AppName != "" {
+		metricNames, err := base.ParseMetricNames(req.AppCheckedMetrics)
+		if err != nil {
+			return nil, err
+		}
+		for _, metricName := range metricNames {
+			appCheckedMetrics = append(appCheckedMetrics, metricName.String())
+		}
+	} else if len(req.AppCheckedMetrics) > 0 {
+		return nil, fmt.Errorf("--app-metrics requires --app-name")
+	}
 
 	update := func(throttlerConfig *topodatapb.ThrottlerConfig) *topodatapb.ThrottlerConfig {
 		if throttlerConfig == nil {
@@ -1937,16 +2012,43 @@ func (s *VtctldServer) UpdateThrottlerConfig(ctx context.Context, req *vtctldata
 		if throttlerConfig.ThrottledApps == nil {
 			throttlerConfig.ThrottledApps = make(map[string]*topodatapb.ThrottledAppRule)
 		}
-		if req.CustomQuerySet {
-			// custom query provided
-			throttlerConfig.CustomQuery = req.CustomQuery
-			throttlerConfig.Threshold = req.Threshold // allowed to be zero/negative because who knows what kind of custom query this is
-		} else {
+		if throttlerConfig.MetricThresholds == nil {
+			throttlerConfig.MetricThresholds = make(map[string]float64)
+		}
+		if throttlerConfig.AppCheckedMetrics == nil {
+			throttlerConfig.AppCheckedMetrics = make(map[string]*topodatapb.ThrottlerConfig_MetricNames)
+		}
+		if req.CustomQuerySet || req.CustomMetricUrlSet {
+			// custom query or custom metric URL provided
+			if req.CustomQuerySet {
+				throttlerConfig.CustomQuery = req.CustomQuery
+			}
+			if req.CustomMetricUrlSet {
+				throttlerConfig.CustomMetricUrl = req.CustomMetricUrl
+			}
+			if req.MetricName == "" {
+				throttlerConfig.Threshold = req.Threshold // allowed to be zero/negative because who knows what kind of custom query this is
+			}
+		} else if req.MetricName == "" {
 			// no custom query, throttler works by querying replication lag. We only allow positive values
 			if req.Threshold > 0 {
 				throttlerConfig.Threshold = req.Threshold
 			}
 		}
+		if req.MetricName != "" {
+			// The threshold applies to the given metric only
+			if req.Threshold > 0 || req.MetricName == base.CustomMetricName.String() {
+				throttlerConfig.MetricThresholds[req.MetricName] = req.Threshold
+			}
+		}
+		if req.AppName != "" {
+			if len(appCheckedMetrics) == 0 {
+				// The app checks the default metrics
+				delete(throttlerConfig.AppCheckedMetrics, req.AppName)
+			} else {
+				throttlerConfig.AppCheckedMetrics[req.AppName] = &topodatapb.ThrottlerConfig_MetricNames{Names: appCheckedMetrics}
+			}
+		}
 		if req.Enable {
 			throttlerConfig.Enabled = true
 		}
@@ -4995,6 +5097,69 @@ func (s *VtctldServer) VDiffStop(ctx context.Context, req *vtctldatapb.VDiffStop
 	return resp, err
 }
 
+// VerifyBackup is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) VerifyBackup(ctx context.Context, req *vtctldatapb.VerifyBackupRequest) (resp *vtctldatapb.VerifyBackupResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.VerifyBackup")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("backup_name", req.BackupName)
+	span.Annotate("concurrency", req.Concurrency)
+
+	concurrency := int(req.Concurrency)
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	mysqld, cnf, err := mysqlctl.NewScratchMysqld(s.env.CollationEnv())
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to set up a MySQL instance to restore the backup into")
+	}
+	defer func() {
+		mysqld.Close()
+		if rerr := os.RemoveAll(cnf.TabletDir()); rerr != nil {
+			log.Warningf("VerifyBackup: failed to remove %v: %v", cnf.TabletDir(), rerr)
+		}
+	}()
+
+	verification, err := mysqlctl.VerifyBackup(ctx, mysqlctl.VerifyBackupParams{
+		Cnf:                  cnf,
+		Mysqld:               mysqld,
+		Logger:               logutil.NewConsoleLogger(),
+		Concurrency:          concurrency,
+		Keyspace:             req.Keyspace,
+		Shard:                req.Shard,
+		BackupName:           req.BackupName,
+		MysqlShutdownTimeout: mysqlctl.DefaultShutdownTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.VerifyBackupResponse{
+		BackupName:       verification.BackupName,
+		Engine:           verification.Engine,
+		FileCount:        int32(verification.FileCount),
+		ManifestPosition: replication.EncodePosition(verification.ManifestPosition),
+		RestoredPosition: replication.EncodePosition(verification.RestoredPosition),
+		Tables:           make([]*vtctldatapb.VerifyBackupResponse_Table, 0, len(verification.Tables)),
+		Verified:         verification.Verified(),
+	}
+	for _, table := range verification.Tables {
+		resp.Tables = append(resp.Tables, &vtctldatapb.VerifyBackupResponse_Table{
+			Schema:      table.Schema,
+			Name:        table.Name,
+			CheckStatus: table.CheckStatus,
+			Checksum:    table.Checksum,
+			Ok:          table.OK,
+		})
+	}
+	return resp, nil
+}
+
 // WorkflowDelete is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) WorkflowDelete(ctx context.Context, req *vtctldatapb.WorkflowDeleteRequest) (resp *vtctldatapb.WorkflowDeleteResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.WorkflowDelete")
@@ -231,6 +231,126 @@ func TestAddCellsAlias(t *testing.T) {
 	}
 }
 
+func TestApplyMirrorRules(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tests := []struct {
+		name          string
+		cells         []string
+		req           *vtctldatapb.ApplyMirrorRulesRequest
+		expectedRules *vschemapb.MirrorRules
+		topoDown      bool
+		shouldErr     bool
+	}{
+		{
+			name:  "success",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyMirrorRulesRequest{
+				MirrorRules: &vschemapb.MirrorRules{
+					Rules: []*vschemapb.MirrorRule{
+						{
+							FromTable: "ks1.t1",
+							ToTable:   "ks2.t1",
+							Percent:   10,
+						},
+					},
+				},
+			},
+			expectedRules: &vschemapb.MirrorRules{
+				Rules: []*vschemapb.MirrorRule{
+					{
+						FromTable: "ks1.t1",
+						ToTable:   "ks2.t1",
+						Percent:   10,
+					},
+				},
+			},
+		},
+		{
+			name:  "rebuild failed (bad cell)",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyMirrorRulesRequest{
+				MirrorRules: &vschemapb.MirrorRules{
+					Rules: []*vschemapb.MirrorRule{
+						{
+							FromTable: "ks1.t1",
+							ToTable:   "ks2.t1",
+							Percent:   10,
+						},
+					},
+				},
+				RebuildCells: []string{"zone1", "zone2"},
+			},
+			shouldErr: true,
+		},
+		{
+			name:  "rebuild skipped",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyMirrorRulesRequest{
+				MirrorRules: &vschemapb.MirrorRules{
+					Rules: []*vschemapb.MirrorRule{
+						{
+							FromTable: "ks1.t1",
+							ToTable:   "ks2.t1",
+							Percent:   10,
+						},
+					},
+				},
+				SkipRebuild:  true,
+				RebuildCells: []string{"zone1", "zone2"},
+			},
+			expectedRules: &vschemapb.MirrorRules{
+				Rules: []*vschemapb.MirrorRule{
+					{
+						FromTable: "ks1.t1",
+						ToTable:   "ks2.t1",
+						Percent:   10,
+					},
+				},
+			},
+		},
+		{
+			name:      "topo down",
+			cells:     []string{"zone1"},
+			req:       &vtctldatapb.ApplyMirrorRulesRequest{},
+			topoDown:  true,
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, factory := memorytopo.NewServerAndFactory(ctx, tt.cells...)
+			if tt.topoDown {
+				factory.SetError(errors.New("topo down for testing"))
+			}
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			_, err := vtctld.ApplyMirrorRules(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err, "ApplyMirrorRules(%+v) failed", tt.req)
+
+			mr, err := ts.GetMirrorRules(ctx)
+			require.NoError(t, err, "failed to get mirror rules from topo to compare")
+			utils.MustMatch(t, tt.expectedRules, mr)
+
+			if !tt.req.SkipRebuild {
+				srvVSchema, err := ts.GetSrvVSchema(ctx, "zone1")
+				require.NoError(t, err)
+				utils.MustMatch(t, tt.expectedRules, srvVSchema.MirrorRules)
+			}
+		})
+	}
+}
+
 func TestApplyRoutingRules(t *testing.T) {
 	t.Parallel()
 
@@ -5574,6 +5694,81 @@ func TestGetKeyspaces(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestGetMirrorRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		topoDown  bool
+		mrIn      *vschemapb.MirrorRules
+		expected  *vschemapb.MirrorRules
+		shouldErr bool
+	}{
+		{
+			name: "success",
+			mrIn: &vschemapb.MirrorRules{
+				Rules: []*vschemapb.MirrorRule{
+					{
+						FromTable: "ks1.t1@replica",
+						ToTable:   "ks2.t1",
+						Percent:   50,
+					},
+				},
+			},
+			expected: &vschemapb.MirrorRules{
+				Rules: []*vschemapb.MirrorRule{
+					{
+						FromTable: "ks1.t1@replica",
+						ToTable:   "ks2.t1",
+						Percent:   50,
+					},
+				},
+			},
+		},
+		{
+			name:     "no mirror rules",
+			mrIn:     nil,
+			expected: nil,
+		},
+		{
+			name:      "topo error",
+			topoDown:  true,
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts, factory := memorytopo.NewServerAndFactory(ctx)
+			if tt.mrIn != nil {
+				err := ts.SaveMirrorRules(ctx, tt.mrIn)
+				require.NoError(t, err, "could not save mirror rules: %+v", tt.mrIn)
+			}
+
+			if tt.topoDown {
+				factory.SetError(errors.New("topo down for testing"))
+			}
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.GetMirrorRules(ctx, &vtctldatapb.GetMirrorRulesRequest{})
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp.MirrorRules)
+		})
+	}
+}
+
 func TestGetPermissions(t *testing.T) {
 	t.Parallel()
 
@@ -706,7 +706,7 @@ var commands = []commandGroup{
 			{
 				name:   "UpdateThrottlerConfig",
 				method: commandUpdateThrottlerConfig,
-				params: "[--enable|--disable] [--threshold=<float64>] [--custom-query=<query>] [--check-as-check-self|--check-as-check-shard] [--throttle-app|unthrottle-app=<name>] [--throttle-app-ratio=<float, range [0..1]>] [--throttle-app-duration=<duration>] [--throttle-app-exempt] <keyspace>",
+				params: "[--enable|--disable] [--threshold=<float64>] [--custom-query=<query>] [--custom-metric-url=<url>] [--check-as-check-self|--check-as-check-shard] [--throttle-app|unthrottle-app=<name>] [--throttle-app-ratio=<float, range [0..1]>] [--throttle-app-duration=<duration>] [--throttle-app-exempt] [--throttle-app-dry-run] <keyspace>",
 				help:   "Update the table throttler configuration for all cells and tablets of a given keyspace",
 			},
 			{
@@ -3599,17 +3599,20 @@ func commandUpdateThrottlerConfig(ctx context.Context, wr *wrangler.Wrangler, su
 	disable := subFlags.Bool("disable", false, "Disable the throttler")
 	threshold := subFlags.Float64("threshold", 0, "threshold for the either default check (replication lag seconds) or custom check")
 	customQuery := subFlags.String("custom-query", "", "custom throttler check query")
+	customMetricURL := subFlags.String("custom-metric-url", "", "URL of a local HTTP endpoint the custom metric is read from instead of the custom query, e.g. http://localhost:9100/metrics#node_load1 to read a metric from a Prometheus exposition. The host must be a loopback address")
 	checkAsCheckSelf := subFlags.Bool("check-as-check-self", false, "/throttler/check requests behave as is /throttler/check-self was called")
 	checkAsCheckShard := subFlags.Bool("check-as-check-shard", false, "use standard behavior for /throttler/check requests")
 	unthrottledApp := subFlags.String("unthrottle-app", "", "an app name to unthrottle")
 	throttledApp := subFlags.String("throttle-app", "", "an app name to throttle")
-	throttledAppRatio := subFlags.Float64("throttle-app-ratio", throttle.DefaultThrottleRatio, "ratio to throttle app (app specififed in --throttled-app)")
+	throttledAppRatio := subFlags.Float64("throttle-app-ratio", throttle.DefaultThrottleRatio, "ratio to throttle app (app specififed in --throttled-app). Defaults to 0 with --throttle-app-dry-run")
 	throttledAppDuration := subFlags.Duration("throttle-app-duration", throttle.DefaultAppThrottleDuration, "duration after which throttled app rule expires (app specified in --throttled-app)")
 	throttledAppExempt := subFlags.Bool("throttle-app-exempt", false, "exempt this app from being at all throttled. WARNING: use with extreme care, as this is likely to push metrics beyond the throttler's threshold, and starve other apps (app specified in --throttled-app)")
+	throttledAppDryRun := subFlags.Bool("throttle-app-dry-run", false, "evaluate and record the checks of this app, but always respond to them with OK. Use with --throttle-app=all to put all apps in dry-run mode. The app is not throttled by ratio unless --throttle-app-ratio is also given (app specified in --throttled-app)")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
 	customQuerySet := subFlags.Changed("custom-query")
+	customMetricURLSet := subFlags.Changed("custom-metric-url")
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <keyspace> arguments are required for the SetThrottlerConfig command")
 	}
@@ -3632,24 +3635,34 @@ func commandUpdateThrottlerConfig(ctx context.Context, wr *wrangler.Wrangler, su
 	if subFlags.Changed("throttle-app-exempt") && *throttledApp == "" {
 		return fmt.Errorf("--throttle-app-exempt requires --throttle-app")
 	}
+	if subFlags.Changed("throttle-app-dry-run") && *throttledApp == "" {
+		return fmt.Errorf("--throttle-app-dry-run requires --throttle-app")
+	}
+	if *throttledAppDryRun && !subFlags.Changed("throttle-app-ratio") {
+		// A dry-run rule only records how the app would be throttled, unless explicitly given a ratio
+		*throttledAppRatio = 0
+	}
 
 	keyspace := subFlags.Arg(0)
 
 	req := &vtctldatapb.UpdateThrottlerConfigRequest{
-		Keyspace:          keyspace,
-		Enable:            *enable,
-		Disable:           *disable,
-		CustomQuery:       *customQuery,
-		CustomQuerySet:    customQuerySet,
-		Threshold:         *threshold,
-		CheckAsCheckSelf:  *checkAsCheckSelf,
-		CheckAsCheckShard: *checkAsCheckShard,
+		Keyspace:           keyspace,
+		Enable:             *enable,
+		Disable:            *disable,
+		CustomQuery:        *customQuery,
+		CustomQuerySet:     customQuerySet,
+		CustomMetricUrl:    *customMetricURL,
+		CustomMetricUrlSet: customMetricURLSet,
+		Threshold:          *threshold,
+		CheckAsCheckSelf:   *checkAsCheckSelf,
+		CheckAsCheckShard:  *checkAsCheckShard,
 	}
 	if *throttledApp != "" {
 		req.ThrottledApp = &topodatapb.ThrottledAppRule{
 			Name:      *throttledApp,
 			Ratio:     *throttledAppRatio,
 			Exempt:    *throttledAppExempt,
+			DryRun:    *throttledAppDryRun,
 			ExpiresAt: protoutil.TimeToProto(time.Now().Add(*throttledAppDuration)),
 		}
 	} else if *unthrottledApp != "" {
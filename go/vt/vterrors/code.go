@@ -58,6 +58,10 @@ var (
 	VT03030 = errorWithState("VT03030", vtrpcpb.Code_INVALID_ARGUMENT, WrongValueCountOnRow, "lookup column count does not match value count with the row (columns, count): (%v, %d)", "The number of columns you want to insert do not match the number of columns of your SELECT query.")
 	VT03031 = errorWithoutState("VT03031", vtrpcpb.Code_INVALID_ARGUMENT, "EXPLAIN is only supported for single keyspace", "EXPLAIN has to be sent down as a single query to the underlying MySQL, and this is not possible if it uses tables from multiple keyspaces")
 	VT03032 = errorWithState("VT03032", vtrpcpb.Code_INVALID_ARGUMENT, NonUpdateableTable, "the target table %s of the UPDATE is not updatable", "You cannot update a table that is not a real MySQL table.")
+	VT03033 = errorWithoutState("VT03033", vtrpcpb.Code_INVALID_ARGUMENT, "window name '%s' is not defined", "The OVER clause refers to a named window that is not defined in the WINDOW clause of the query.")
+	VT03034 = errorWithoutState("VT03034", vtrpcpb.Code_INVALID_ARGUMENT, "recursive common table expression '%s' should contain a UNION", "A recursive common table expression must be a UNION of a non-recursive anchor query and a recursive query block that refers to the common table expression.")
+	VT03035 = errorWithoutState("VT03035", vtrpcpb.Code_INVALID_ARGUMENT, "recursive common table expression '%s' should have one or more non-recursive query blocks followed by one or more recursive ones", "The first query block of a recursive common table expression must not refer to the common table expression itself.")
+	VT03036 = errorWithoutState("VT03036", vtrpcpb.Code_INVALID_ARGUMENT, "recursive common table expression '%s' can contain neither aggregation nor window functions in recursive query block", "The recursive query block of a recursive common table expression cannot use aggregation or window functions.")
 
 	VT05001 = errorWithState("VT05001", vtrpcpb.Code_NOT_FOUND, DbDropExists, "cannot drop database '%s'; database does not exists", "The given database does not exist; Vitess cannot drop it.")
 	VT05002 = errorWithState("VT05002", vtrpcpb.Code_NOT_FOUND, BadDb, "cannot alter database '%s'; unknown database", "The given database does not exist; Vitess cannot alter it.")
@@ -95,6 +99,7 @@ var (
 	VT09022 = errorWithoutState("VT09022", vtrpcpb.Code_FAILED_PRECONDITION, "Destination does not have exactly one shard: %v", "Cannot send query to multiple shards.")
 	VT09023 = errorWithoutState("VT09023", vtrpcpb.Code_FAILED_PRECONDITION, "could not map %v to a keyspace id", "Unable to determine the shard for the given row.")
 	VT09024 = errorWithoutState("VT09024", vtrpcpb.Code_FAILED_PRECONDITION, "could not map %v to a unique keyspace id: %v", "Unable to determine the shard for the given row.")
+	VT09025 = errorWithoutState("VT09025", vtrpcpb.Code_FAILED_PRECONDITION, "recursive query aborted after %d iterations; try increasing --cte-max-recursion-depth to a larger value", "The recursive common table expression did not reach a fixpoint within the configured maximum recursion depth.")
 
 	VT10001 = errorWithoutState("VT10001", vtrpcpb.Code_ABORTED, "foreign key constraints are not allowed", "Foreign key constraints are not allowed, see https://vitess.io/blog/2021-06-15-online-ddl-why-no-fk/.")
 
@@ -146,6 +151,10 @@ var (
 		VT03030,
 		VT03031,
 		VT03032,
+		VT03033,
+		VT03034,
+		VT03035,
+		VT03036,
 		VT05001,
 		VT05002,
 		VT05003,
@@ -179,6 +188,7 @@ var (
 		VT09022,
 		VT09023,
 		VT09024,
+		VT09025,
 		VT10001,
 		VT12001,
 		VT12002,
@@ -87,6 +87,7 @@ const (
 	// permission denied
 	AccessDeniedError
 	KillDeniedError
+	ColumnAccessDeniedError
 
 	// server not available
 	ServerNotAvailable
@@ -0,0 +1,264 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package balancer orders the tablets a vtgate sends the queries of a target to,
+so that the load is spread evenly across the tablets of all the cells.
+
+Each cell that has vtgates is assumed to receive the same share of the queries,
+and every tablet should serve the same share of them. The queries of a vtgate are
+first sent to the tablets of its own cell, up to their share. A cell whose tablets
+cannot serve all the queries of its vtgates sends the rest to the cells that have
+spare capacity, in proportion to it.
+
+For example, with vtgates in cell1 and cell2, one replica in cell1 and three in
+cell2, each replica serves a quarter of the queries. The vtgates of cell1 send half
+of their queries to the replica of cell1 and half to the replicas of cell2, while
+the vtgates of cell2 send all of their queries to the replicas of cell2.
+
+When the balancer is load aware, the share of a tablet is also divided by one plus
+the number of queries in flight to it from this vtgate, so that the slow or busy
+tablets are picked less often.
+*/
+package balancer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// allocationPerCell is the share of the queries of the vtgates of a cell. The
+// allocations of the tablets are in the same unit.
+const allocationPerCell = 1000000
+
+// TabletBalancer orders the tablets of a target for a vtgate.
+type TabletBalancer interface {
+	// ShuffleTablets orders the tablets of the target in the order they should be
+	// tried: the first one is picked according to the allocations of the tablets,
+	// and the others are shuffled randomly.
+	ShuffleTablets(target *querypb.Target, tablets []*discovery.TabletHealth)
+
+	// QueryStarted records that a query was sent to a tablet. It returns the function
+	// to call once the query completes.
+	QueryStarted(alias *topodatapb.TabletAlias) func()
+
+	// DebugHandler writes the allocations of the tablets of the targets.
+	DebugHandler(w http.ResponseWriter, r *http.Request)
+}
+
+// NewTabletBalancer returns a TabletBalancer for a vtgate of localCell, when the
+// vtgates are in vtgateCells. If loadAware is set, the tablets are also balanced by
+// the number of queries in flight to them.
+func NewTabletBalancer(localCell string, vtgateCells []string, loadAware bool) TabletBalancer {
+	cells := slices.Clone(vtgateCells)
+	if !slices.Contains(cells, localCell) {
+		cells = append(cells, localCell)
+	}
+	return &tabletBalancer{
+		localCell:   localCell,
+		vtgateCells: cells,
+		loadAware:   loadAware,
+		allocations: make(map[string]*targetAllocation),
+	}
+}
+
+type tabletBalancer struct {
+	localCell   string
+	vtgateCells []string
+	loadAware   bool
+
+	// mu protects allocations.
+	mu sync.Mutex
+	// allocations are the allocations of the tablets of the targets, by target.
+	allocations map[string]*targetAllocation
+
+	// inFlight are the numbers of queries in flight to the tablets, by tablet alias.
+	inFlight sync.Map // map[string]*atomic.Int64
+}
+
+// targetAllocation is the allocation of the tablets of a target.
+type targetAllocation struct {
+	// Outflow is the share of the queries of the local cell that are sent to the other cells.
+	Outflow int
+	// Tablets are the allocations of the tablets, by tablet alias.
+	Tablets map[string]int
+	// Total is the sum of the allocations of the tablets.
+	Total int
+}
+
+// ShuffleTablets is part of the TabletBalancer interface.
+func (b *tabletBalancer) ShuffleTablets(target *querypb.Target, tablets []*discovery.TabletHealth) {
+	rand.Shuffle(len(tablets), func(i, j int) {
+		tablets[i], tablets[j] = tablets[j], tablets[i]
+	})
+
+	allocation := b.getAllocation(target, tablets)
+	weights := make([]float64, len(tablets))
+	var total float64
+	for i, th := range tablets {
+		alias := topoproto.TabletAliasString(th.Tablet.Alias)
+		weights[i] = float64(allocation.Tablets[alias])
+		if b.loadAware {
+			weights[i] /= float64(1 + b.inFlightCounter(alias).Load())
+		}
+		total += weights[i]
+	}
+	if total == 0 {
+		return
+	}
+
+	// Pick a random point in the allocation space, and swap the tablet it falls on to the front.
+	r := rand.Float64() * total
+	for i, weight := range weights {
+		if r < weight {
+			tablets[0], tablets[i] = tablets[i], tablets[0]
+			return
+		}
+		r -= weight
+	}
+}
+
+// QueryStarted is part of the TabletBalancer interface.
+func (b *tabletBalancer) QueryStarted(alias *topodatapb.TabletAlias) func() {
+	if !b.loadAware {
+		return func() {}
+	}
+	counter := b.inFlightCounter(topoproto.TabletAliasString(alias))
+	counter.Add(1)
+	return func() {
+		counter.Add(-1)
+	}
+}
+
+func (b *tabletBalancer) inFlightCounter(alias string) *atomic.Int64 {
+	if counter, ok := b.inFlight.Load(alias); ok {
+		return counter.(*atomic.Int64)
+	}
+	counter, _ := b.inFlight.LoadOrStore(alias, &atomic.Int64{})
+	return counter.(*atomic.Int64)
+}
+
+// getAllocation returns the allocation of the tablets of the target, which is only
+// computed again when the tablets change.
+func (b *tabletBalancer) getAllocation(target *querypb.Target, tablets []*discovery.TabletHealth) *targetAllocation {
+	key := fmt.Sprintf("%s/%s/%s", target.Keyspace, target.Shard, topoproto.TabletTypeLString(target.TabletType))
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if allocation, ok := b.allocations[key]; ok && allocation.matches(tablets) {
+		return allocation
+	}
+	allocation := b.allocateFlows(tablets)
+	b.allocations[key] = allocation
+	return allocation
+}
+
+// matches returns whether the allocation is the one of the given tablets.
+func (a *targetAllocation) matches(tablets []*discovery.TabletHealth) bool {
+	if len(a.Tablets) != len(tablets) {
+		return false
+	}
+	for _, th := range tablets {
+		if _, ok := a.Tablets[topoproto.TabletAliasString(th.Tablet.Alias)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// allocateFlows computes the allocation of the tablets for the local cell.
+func (b *tabletBalancer) allocateFlows(tablets []*discovery.TabletHealth) *targetAllocation {
+	tabletsByCell := make(map[string]int)
+	for _, th := range tablets {
+		tabletsByCell[th.Tablet.Alias.Cell]++
+	}
+
+	// Every tablet should serve the same share of the queries of all the vtgate cells.
+	perTablet := float64(len(b.vtgateCells)*allocationPerCell) / float64(len(tablets))
+
+	// The queries of the vtgates of a cell are sent to the tablets of the cell up to
+	// their capacity. The capacity they have left is shared by the other cells.
+	spare := make(map[string]float64)
+	for cell, count := range tabletsByCell {
+		spare[cell] = float64(count) * perTablet
+	}
+	localFlow := 0.0
+	for _, cell := range b.vtgateCells {
+		flow := min(allocationPerCell, spare[cell])
+		spare[cell] -= flow
+		if cell == b.localCell {
+			localFlow = flow
+		}
+	}
+	outflow := allocationPerCell - localFlow
+	totalSpare := 0.0
+	for _, s := range spare {
+		totalSpare += s
+	}
+
+	allocation := &targetAllocation{
+		Outflow: int(outflow),
+		Tablets: make(map[string]int, len(tablets)),
+	}
+	for _, th := range tablets {
+		cell := th.Tablet.Alias.Cell
+		var flow float64
+		if cell == b.localCell {
+			flow = localFlow
+		} else if totalSpare > 0 {
+			flow = outflow * spare[cell] / totalSpare
+		}
+		tabletFlow := int(flow / float64(tabletsByCell[cell]))
+		allocation.Tablets[topoproto.TabletAliasString(th.Tablet.Alias)] = tabletFlow
+		allocation.Total += tabletFlow
+	}
+	return allocation
+}
+
+// DebugHandler is part of the TabletBalancer interface.
+func (b *tabletBalancer) DebugHandler(w http.ResponseWriter, r *http.Request) {
+	if err := acl.CheckAccessHTTP(r, acl.DEBUGGING); err != nil {
+		acl.SendError(w, err)
+		return
+	}
+
+	b.mu.Lock()
+	data, err := json.MarshalIndent(map[string]any{
+		"LocalCell":   b.localCell,
+		"VtgateCells": b.vtgateCells,
+		"LoadAware":   b.loadAware,
+		"Allocations": b.allocations,
+	}, "", "  ")
+	b.mu.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(data)
+}
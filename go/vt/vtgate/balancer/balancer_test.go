@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package balancer
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/topo"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+var target = &querypb.Target{Keyspace: "ks", Shard: "-", TabletType: topodatapb.TabletType_REPLICA}
+
+func createTablets(cells ...string) []*discovery.TabletHealth {
+	var tablets []*discovery.TabletHealth
+	for i, cell := range cells {
+		tablets = append(tablets, &discovery.TabletHealth{
+			Tablet:  topo.NewTablet(uint32(i+1), cell, "host"),
+			Target:  target,
+			Serving: true,
+		})
+	}
+	return tablets
+}
+
+func TestAllocateFlows(t *testing.T) {
+	tcases := []struct {
+		name        string
+		localCell   string
+		vtgateCells []string
+		tablets     []string
+		want        map[string]int
+	}{{
+		name:        "balanced cells",
+		localCell:   "a",
+		vtgateCells: []string{"a", "b"},
+		tablets:     []string{"a", "a", "b", "b"},
+		want:        map[string]int{"a-0000000001": 500000, "a-0000000002": 500000, "b-0000000003": 0, "b-0000000004": 0},
+	}, {
+		name:        "small local cell",
+		localCell:   "a",
+		vtgateCells: []string{"a", "b"},
+		tablets:     []string{"a", "b", "b", "b"},
+		want:        map[string]int{"a-0000000001": 500000, "b-0000000002": 166666, "b-0000000003": 166666, "b-0000000004": 166666},
+	}, {
+		name:        "large local cell",
+		localCell:   "b",
+		vtgateCells: []string{"a", "b"},
+		tablets:     []string{"a", "b", "b", "b"},
+		want:        map[string]int{"a-0000000001": 0, "b-0000000002": 333333, "b-0000000003": 333333, "b-0000000004": 333333},
+	}, {
+		name:        "no local tablets",
+		localCell:   "a",
+		vtgateCells: []string{"a"},
+		tablets:     []string{"b", "c", "c"},
+		want:        map[string]int{"b-0000000001": 333333, "c-0000000002": 333333, "c-0000000003": 333333},
+	}, {
+		name:        "spare capacity in cells without vtgates",
+		localCell:   "a",
+		vtgateCells: []string{"a", "b"},
+		tablets:     []string{"a", "b", "c", "c"},
+		want:        map[string]int{"a-0000000001": 500000, "b-0000000002": 0, "c-0000000003": 250000, "c-0000000004": 250000},
+	}, {
+		name:      "local cell is added to the vtgate cells",
+		localCell: "a",
+		tablets:   []string{"a", "b"},
+		want:      map[string]int{"a-0000000001": 500000, "b-0000000002": 500000},
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			b := NewTabletBalancer(tcase.localCell, tcase.vtgateCells, false).(*tabletBalancer)
+			allocation := b.allocateFlows(createTablets(tcase.tablets...))
+			assert.Equal(t, tcase.want, allocation.Tablets)
+
+			total := 0
+			for _, flow := range tcase.want {
+				total += flow
+			}
+			assert.Equal(t, total, allocation.Total)
+		})
+	}
+}
+
+func TestShuffleTablets(t *testing.T) {
+	b := NewTabletBalancer("a", []string{"a", "b"}, false)
+	tablets := createTablets("a", "b", "b", "b")
+
+	picks := make(map[string]int)
+	const n = 10000
+	for i := 0; i < n; i++ {
+		b.ShuffleTablets(target, tablets)
+		require.Len(t, tablets, 4)
+		picks[tablets[0].Tablet.Alias.Cell]++
+	}
+	// Half of the queries stay in the local cell.
+	assert.InDelta(t, n/2, picks["a"], n/10)
+	assert.InDelta(t, n/2, picks["b"], n/10)
+
+	// The allocation is computed again when the tablets change.
+	tablets = createTablets("a", "a", "b", "b")
+	for i := 0; i < 100; i++ {
+		b.ShuffleTablets(target, tablets)
+		assert.Equal(t, "a", tablets[0].Tablet.Alias.Cell)
+	}
+}
+
+func TestLoadAwareShuffleTablets(t *testing.T) {
+	b := NewTabletBalancer("a", []string{"a"}, true)
+	tablets := createTablets("a", "a")
+
+	// The first tablet has many queries in flight, so the second one is almost always picked.
+	var done []func()
+	for i := 0; i < 99; i++ {
+		done = append(done, b.QueryStarted(tablets[0].Tablet.Alias))
+	}
+	busy := tablets[0]
+	picks := 0
+	const n = 1000
+	for i := 0; i < n; i++ {
+		b.ShuffleTablets(target, tablets)
+		if tablets[0] == busy {
+			picks++
+		}
+	}
+	assert.Less(t, picks, n/20)
+
+	// Once the queries complete, the tablets are picked evenly again.
+	for _, queryDone := range done {
+		queryDone()
+	}
+	picks = 0
+	for i := 0; i < n; i++ {
+		b.ShuffleTablets(target, tablets)
+		if tablets[0] == busy {
+			picks++
+		}
+	}
+	assert.InDelta(t, n/2, picks, n/10)
+}
+
+func TestDebugHandler(t *testing.T) {
+	// Both cells have one tablet, so the queries stay in the local cell.
+	b := NewTabletBalancer("a", []string{"a", "b"}, false)
+	b.ShuffleTablets(target, createTablets("a", "b"))
+
+	w := httptest.NewRecorder()
+	b.DebugHandler(w, httptest.NewRequest("GET", "/debug/balancer", nil))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"ks/-/replica": {`)
+	assert.Contains(t, w.Body.String(), `"a-0000000001": 1000000`)
+}
@@ -19,6 +19,8 @@ package engine
 import (
 	"fmt"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/slice"
@@ -42,6 +44,10 @@ type AggregateParams struct {
 	WCol   int
 	Type   evalengine.Type
 
+	// ExtraCols holds the arguments after the first one, for aggregations
+	// over more than one expression, such as COUNT(DISTINCT a, b) or GROUP_CONCAT(a, b)
+	ExtraCols []CheckCol `json:",omitempty"`
+
 	Alias    string `json:",omitempty"`
 	Expr     sqlparser.Expr
 	Original *sqlparser.AliasedExpr
@@ -72,12 +78,13 @@ func (ap *AggregateParams) WAssigned() bool {
 }
 
 func (ap *AggregateParams) String() string {
-	keyCol := strconv.Itoa(ap.Col)
-	if ap.WAssigned() {
-		keyCol = fmt.Sprintf("%s|%d", keyCol, ap.WCol)
-	}
-	if sqltypes.IsText(ap.Type.Type()) && ap.CollationEnv.IsSupported(ap.Type.Collation()) {
-		keyCol += " COLLATE " + ap.CollationEnv.LookupName(ap.Type.Collation())
+	keyCol := ap.keyColString(ap.Col, ap.WCol, ap.Type)
+	for _, col := range ap.ExtraCols {
+		wCol := -1
+		if col.WsCol != nil {
+			wCol = *col.WsCol
+		}
+		keyCol += ", " + ap.keyColString(col.Col, wCol, col.Type)
 	}
 	dispOrigOp := ""
 	if ap.OrigOpcode != AggregateUnassigned && ap.OrigOpcode != ap.Opcode {
@@ -89,6 +96,17 @@ func (ap *AggregateParams) String() string {
 	return fmt.Sprintf("%s%s(%s)", ap.Opcode.String(), dispOrigOp, keyCol)
 }
 
+func (ap *AggregateParams) keyColString(col, wCol int, typ evalengine.Type) string {
+	keyCol := strconv.Itoa(col)
+	if wCol >= 0 {
+		keyCol = fmt.Sprintf("%s|%d", keyCol, wCol)
+	}
+	if sqltypes.IsText(typ.Type()) && ap.CollationEnv.IsSupported(typ.Collation()) {
+		keyCol += " COLLATE " + ap.CollationEnv.LookupName(typ.Collation())
+	}
+	return keyCol
+}
+
 func (ap *AggregateParams) typ(inputType querypb.Type) querypb.Type {
 	if ap.OrigOpcode != AggregateUnassigned {
 		return ap.OrigOpcode.SQLType(inputType)
@@ -107,40 +125,76 @@ type aggregatorDistinct struct {
 	last         sqltypes.Value
 	coll         collations.ID
 	collationEnv *collations.Environment
+
+	// extra holds the remaining columns of distinct aggregations over more than one expression.
+	// A row is only skipped when all the columns are equal to the ones of the previous row.
+	extra []aggregatorDistinct
 }
 
 func (a *aggregatorDistinct) shouldReturn(row []sqltypes.Value) (bool, error) {
-	if a.column >= 0 {
-		last := a.last
-		next := row[a.column]
-		if !last.IsNull() {
-			if last.TinyWeightCmp(next) == 0 {
-				cmp, err := evalengine.NullsafeCompare(last, next, a.collationEnv, a.coll)
-				if err != nil {
-					return true, err
-				}
-				if cmp == 0 {
-					return true, nil
-				}
-			}
+	if a.column < 0 {
+		return false, nil
+	}
+	same, err := a.sameAsLast(row)
+	if err != nil {
+		return true, err
+	}
+	for i := range a.extra {
+		extraSame, err := a.extra[i].sameAsLast(row)
+		if err != nil {
+			return true, err
 		}
-		a.last = next
+		same = same && extraSame
+	}
+	if same {
+		return true, nil
+	}
+	a.last = row[a.column]
+	for i := range a.extra {
+		a.extra[i].last = row[a.extra[i].column]
 	}
 	return false, nil
 }
 
+func (a *aggregatorDistinct) sameAsLast(row []sqltypes.Value) (bool, error) {
+	last := a.last
+	next := row[a.column]
+	if last.IsNull() || last.TinyWeightCmp(next) != 0 {
+		return false, nil
+	}
+	cmp, err := evalengine.NullsafeCompare(last, next, a.collationEnv, a.coll)
+	if err != nil {
+		return false, err
+	}
+	return cmp == 0, nil
+}
+
 func (a *aggregatorDistinct) reset() {
 	a.last = sqltypes.NULL
+	for i := range a.extra {
+		a.extra[i].reset()
+	}
+}
+
+// anyNull returns true if any of the given columns of the row is NULL
+func anyNull(row []sqltypes.Value, cols []int) bool {
+	for _, col := range cols {
+		if row[col].IsNull() {
+			return true
+		}
+	}
+	return false
 }
 
 type aggregatorCount struct {
 	from     int
+	extra    []int
 	n        int64
 	distinct aggregatorDistinct
 }
 
 func (a *aggregatorCount) add(row []sqltypes.Value) error {
-	if row[a.from].IsNull() {
+	if row[a.from].IsNull() || anyNull(row, a.extra) {
 		return nil
 	}
 	if ret, err := a.distinct.shouldReturn(row); ret {
@@ -254,21 +308,31 @@ func (a *aggregatorScalar) reset() {
 }
 
 type aggregatorGroupConcat struct {
-	from  int
-	type_ sqltypes.Type
+	from      int
+	extra     []int
+	type_     sqltypes.Type
+	separator []byte
+	maxLen    int
+	distinct  aggregatorDistinct
 
 	concat []byte
 	n      int
 }
 
 func (a *aggregatorGroupConcat) add(row []sqltypes.Value) error {
-	if row[a.from].IsNull() {
+	if row[a.from].IsNull() || anyNull(row, a.extra) {
 		return nil
 	}
+	if ret, err := a.distinct.shouldReturn(row); ret {
+		return err
+	}
 	if a.n > 0 {
-		a.concat = append(a.concat, ',')
+		a.concat = append(a.concat, a.separator...)
 	}
 	a.concat = append(a.concat, row[a.from].Raw()...)
+	for _, col := range a.extra {
+		a.concat = append(a.concat, row[col].Raw()...)
+	}
 	a.n++
 	return nil
 }
@@ -277,12 +341,55 @@ func (a *aggregatorGroupConcat) finish() sqltypes.Value {
 	if a.n == 0 {
 		return sqltypes.NULL
 	}
-	return sqltypes.MakeTrusted(a.type_, a.concat)
+	concat := a.concat
+	if a.maxLen > 0 && len(concat) > a.maxLen {
+		// just like MySQL, we cut the result at group_concat_max_len,
+		// making sure we don't split a multibyte character in text results
+		end := a.maxLen
+		if !sqltypes.IsBinary(a.type_) {
+			for end > 0 && !utf8.RuneStart(concat[end]) {
+				end--
+			}
+		}
+		concat = concat[:end]
+	}
+	return sqltypes.MakeTrusted(a.type_, concat)
 }
 
 func (a *aggregatorGroupConcat) reset() {
 	a.n = 0
 	a.concat = nil // not safe to reuse this byte slice as it's returned as MakeTrusted
+	a.distinct.reset()
+}
+
+// groupConcatSeparator returns the separator used between the values of a GROUP_CONCAT
+func groupConcatSeparator(expr sqlparser.Expr) ([]byte, error) {
+	gc, ok := expr.(*sqlparser.GroupConcatExpr)
+	if !ok || gc.Separator == "" {
+		return []byte{','}, nil
+	}
+	separator, err := sqltypes.DecodeStringSQL(gc.Separator)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(separator), nil
+}
+
+// groupConcatMaxLen returns the value of group_concat_max_len if it has been set on the session.
+// When it has not been set, the result is not truncated on the vtgate, as the value in effect on
+// the tablets, which may be raised in their configuration, is not known.
+func groupConcatMaxLen(vcursor VCursor) int {
+	maxLen := 0
+	vcursor.Session().GetSystemVariables(func(k string, v string) {
+		if k != "group_concat_max_len" {
+			return
+		}
+		val, err := strconv.Atoi(strings.Trim(v, "'"))
+		if err == nil {
+			maxLen = val
+		}
+	})
+	return maxLen
 }
 
 type aggregatorGtid struct {
@@ -351,7 +458,7 @@ func isComparable(typ sqltypes.Type) bool {
 	return false
 }
 
-func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (aggregationState, []*querypb.Field, error) {
+func newAggregation(vcursor VCursor, fields []*querypb.Field, aggregates []*AggregateParams) (aggregationState, []*querypb.Field, error) {
 	fields = slice.Map(fields, func(from *querypb.Field) *querypb.Field { return from.CloneVT() })
 
 	agstate := make([]aggregator, len(fields))
@@ -361,13 +468,26 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 
 		var ag aggregator
 		var distinct = -1
+		var extraDistinct []aggregatorDistinct
 
 		if aggr.Opcode.IsDistinct() {
 			distinct = aggr.KeyCol
 			if aggr.WAssigned() && !isComparable(sourceType) {
 				distinct = aggr.WCol
 			}
+			for _, col := range aggr.ExtraCols {
+				column := col.Col
+				if col.WsCol != nil && !isComparable(fields[col.Col].Type) {
+					column = *col.WsCol
+				}
+				extraDistinct = append(extraDistinct, aggregatorDistinct{
+					column:       column,
+					coll:         col.Type.Collation(),
+					collationEnv: aggr.CollationEnv,
+				})
+			}
 		}
+		extraCols := slice.Map(aggr.ExtraCols, func(col CheckCol) int { return col.Col })
 
 		if aggr.Opcode == AggregateMin || aggr.Opcode == AggregateMax {
 			if aggr.WAssigned() && !isComparable(sourceType) {
@@ -381,11 +501,13 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 
 		case AggregateCount, AggregateCountDistinct:
 			ag = &aggregatorCount{
-				from: aggr.Col,
+				from:  aggr.Col,
+				extra: extraCols,
 				distinct: aggregatorDistinct{
 					column:       distinct,
 					coll:         aggr.Type.Collation(),
 					collationEnv: aggr.CollationEnv,
+					extra:        extraDistinct,
 				},
 			}
 
@@ -430,8 +552,24 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 		case AggregateAnyValue:
 			ag = &aggregatorScalar{from: aggr.Col}
 
-		case AggregateGroupConcat:
-			ag = &aggregatorGroupConcat{from: aggr.Col, type_: targetType}
+		case AggregateGroupConcat, AggregateGroupConcatDistinct:
+			separator, err := groupConcatSeparator(aggr.Expr)
+			if err != nil {
+				return nil, nil, err
+			}
+			ag = &aggregatorGroupConcat{
+				from:      aggr.Col,
+				extra:     extraCols,
+				type_:     targetType,
+				separator: separator,
+				maxLen:    groupConcatMaxLen(vcursor),
+				distinct: aggregatorDistinct{
+					column:       distinct,
+					coll:         aggr.Type.Collation(),
+					collationEnv: aggr.CollationEnv,
+					extra:        extraDistinct,
+				},
+			}
 
 		default:
 			panic("BUG: unexpected Aggregation opcode")
@@ -0,0 +1,241 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*CorrelatedSubquery)(nil)
+
+// CorrelatedSubquery executes a subquery once for every row of the outer query.
+// The values the subquery needs from the outer row are sent in as bind variables,
+// and the result of the subquery is used in one of two ways:
+//   - when Predicate is set, it is evaluated for the outer row, and only the rows
+//     it evaluates to true for are returned
+//   - otherwise, the value of the subquery is added as the first column of the row
+type CorrelatedSubquery struct {
+	Opcode PulloutOpcode
+
+	// SubqueryResult and HasValues are the bind variables that hold the result
+	// of the subquery when evaluating the Predicate
+	SubqueryResult string
+	HasValues      string
+
+	// Vars defines the bind variables that are built from
+	// the outer row before executing the subquery
+	Vars map[string]int `json:",omitempty"`
+
+	Outer    Primitive
+	Subquery Primitive
+
+	Predicate    evalengine.Expr
+	ASTPredicate sqlparser.Expr
+}
+
+// TryExecute implements the Primitive interface
+func (cs *CorrelatedSubquery) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	outer, err := vcursor.ExecutePrimitive(ctx, cs.Outer, bindVars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+	result := &sqltypes.Result{}
+	if wantfields {
+		result.Fields, err = cs.fields(ctx, vcursor, bindVars, outer.Fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+	result.Rows, err = cs.evaluateRows(ctx, vcursor, bindVars, outer.Rows)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (cs *CorrelatedSubquery) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	return vcursor.StreamExecutePrimitive(ctx, cs.Outer, bindVars, wantfields, func(outer *sqltypes.Result) error {
+		result := &sqltypes.Result{}
+		if outer.Fields != nil {
+			fields, err := cs.fields(ctx, vcursor, bindVars, outer.Fields)
+			if err != nil {
+				return err
+			}
+			result.Fields = fields
+		}
+		rows, err := cs.evaluateRows(ctx, vcursor, bindVars, outer.Rows)
+		if err != nil {
+			return err
+		}
+		result.Rows = rows
+		return callback(result)
+	})
+}
+
+func (cs *CorrelatedSubquery) evaluateRows(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, rows []sqltypes.Row) ([]sqltypes.Row, error) {
+	var result []sqltypes.Row
+	for _, row := range rows {
+		combinedVars, err := cs.execSubquery(ctx, vcursor, bindVars, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if cs.Predicate == nil {
+			value, err := sqltypes.BindVariableToValue(combinedVars[cs.valueBindVar()])
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, append(sqltypes.Row{value}, row...))
+			continue
+		}
+
+		env := evalengine.NewExpressionEnv(ctx, combinedVars, vcursor)
+		env.Row = row
+		evalResult, err := env.Evaluate(cs.Predicate)
+		if err != nil {
+			return nil, err
+		}
+		if evalResult.ToBoolean() {
+			result = append(result, row)
+		}
+	}
+	return result, nil
+}
+
+func (cs *CorrelatedSubquery) execSubquery(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, row sqltypes.Row) (map[string]*querypb.BindVariable, error) {
+	joinVars := make(map[string]*querypb.BindVariable, len(cs.Vars))
+	for k, col := range cs.Vars {
+		joinVars[k] = sqltypes.ValueBindVariable(row[col])
+	}
+	combinedVars := combineVars(bindVars, joinVars)
+	result, err := vcursor.ExecutePrimitive(ctx, cs.Subquery, combinedVars, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := addPulloutVars(cs.Opcode, cs.SubqueryResult, cs.HasValues, result, combinedVars); err != nil {
+		return nil, err
+	}
+	return combinedVars, nil
+}
+
+// valueBindVar returns the bind variable that holds the value added to the rows
+// when the subquery is used in the SELECT expressions
+func (cs *CorrelatedSubquery) valueBindVar() string {
+	if cs.Opcode == PulloutExists {
+		return cs.HasValues
+	}
+	return cs.SubqueryResult
+}
+
+func (cs *CorrelatedSubquery) fields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, outer []*querypb.Field) ([]*querypb.Field, error) {
+	if cs.Predicate != nil {
+		return outer, nil
+	}
+	field := &querypb.Field{
+		Name:    cs.valueBindVar(),
+		Type:    sqltypes.Int64,
+		Charset: uint32(collations.CollationBinaryID),
+		Flags:   uint32(querypb.MySqlFlag_BINARY_FLAG | querypb.MySqlFlag_NUM_FLAG),
+	}
+	if cs.Opcode == PulloutValue {
+		sqFields, err := cs.Subquery.GetFields(ctx, vcursor, bindVars)
+		if err != nil {
+			return nil, err
+		}
+		field = sqFields.Fields[0].CloneVT()
+		field.Name = cs.valueBindVar()
+	}
+	return append([]*querypb.Field{field}, outer...), nil
+}
+
+// GetFields implements the Primitive interface
+func (cs *CorrelatedSubquery) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	outer, err := cs.Outer.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := cs.fields(ctx, vcursor, bindVars, outer.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: fields}, nil
+}
+
+// Inputs implements the Primitive interface
+func (cs *CorrelatedSubquery) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{cs.Outer, cs.Subquery}, []map[string]any{{
+		inputName: "Outer",
+	}, {
+		inputName: "SubQuery",
+	}}
+}
+
+// RouteType implements the Primitive interface
+func (cs *CorrelatedSubquery) RouteType() string {
+	return cs.Opcode.String()
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (cs *CorrelatedSubquery) GetKeyspaceName() string {
+	if cs.Outer.GetKeyspaceName() == cs.Subquery.GetKeyspaceName() {
+		return cs.Outer.GetKeyspaceName()
+	}
+	return cs.Outer.GetKeyspaceName() + "_" + cs.Subquery.GetKeyspaceName()
+}
+
+// GetTableName implements the Primitive interface
+func (cs *CorrelatedSubquery) GetTableName() string {
+	return cs.Outer.GetTableName() + "_" + cs.Subquery.GetTableName()
+}
+
+// NeedsTransaction implements the Primitive interface
+func (cs *CorrelatedSubquery) NeedsTransaction() bool {
+	return cs.Outer.NeedsTransaction() || cs.Subquery.NeedsTransaction()
+}
+
+func (cs *CorrelatedSubquery) description() PrimitiveDescription {
+	other := map[string]any{}
+	var pulloutVars []string
+	if cs.HasValues != "" {
+		pulloutVars = append(pulloutVars, cs.HasValues)
+	}
+	if cs.SubqueryResult != "" {
+		pulloutVars = append(pulloutVars, cs.SubqueryResult)
+	}
+	if len(pulloutVars) > 0 {
+		other["PulloutVars"] = pulloutVars
+	}
+	if len(cs.Vars) > 0 {
+		other["JoinVars"] = orderedStringIntMap(cs.Vars)
+	}
+	if cs.ASTPredicate != nil {
+		other["Predicate"] = sqlparser.String(cs.ASTPredicate)
+	}
+	return PrimitiveDescription{
+		OperatorType: "CorrelatedSubquery",
+		Variant:      cs.Opcode.String(),
+		Other:        other,
+	}
+}
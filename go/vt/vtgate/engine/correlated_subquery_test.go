@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func TestCorrelatedSubqueryPredicate(t *testing.T) {
+	// :__sq_has_values and :1 in ::__sq1
+	predicate := &sqlparser.AndExpr{
+		Left: sqlparser.NewArgument("__sq_has_values"),
+		Right: &sqlparser.ComparisonExpr{
+			Operator: sqlparser.InOp,
+			Left:     sqlparser.NewOffset(1, nil),
+			Right:    sqlparser.ListArg("__sq1"),
+		},
+	}
+	pred, err := evalengine.Translate(predicate, &evalengine.Config{
+		Collation:   collations.MySQL8().DefaultConnectionCharset(),
+		Environment: vtenv.NewTestEnv(),
+	})
+	require.NoError(t, err)
+
+	outer := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields("id|col", "int64|int64"),
+				"1|10",
+				"2|20",
+				"3|30",
+			),
+		},
+	}
+	sqFields := sqltypes.MakeTestFields("col", "int64")
+	subquery := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqFields, "10", "11"),
+			sqltypes.MakeTestResult(sqFields),
+			sqltypes.MakeTestResult(sqFields, "5"),
+		},
+	}
+	cs := &CorrelatedSubquery{
+		Opcode:         PulloutIn,
+		SubqueryResult: "__sq1",
+		HasValues:      "__sq_has_values",
+		Vars:           map[string]int{"col": 1},
+		Outer:          outer,
+		Subquery:       subquery,
+		Predicate:      pred,
+	}
+
+	result, err := cs.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	outer.ExpectLog(t, []string{`Execute  false`})
+	subquery.ExpectLog(t, []string{
+		`Execute col: type:INT64 value:"10" false`,
+		`Execute col: type:INT64 value:"20" false`,
+		`Execute col: type:INT64 value:"30" false`,
+	})
+	require.Equal(t, "[[INT64(1) INT64(10)]]", fmt.Sprintf("%v", result.Rows))
+}
+
+func TestCorrelatedSubqueryValue(t *testing.T) {
+	outer := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields("id|col", "int64|int64"),
+				"1|10",
+				"2|20",
+			),
+		},
+	}
+	sqFields := sqltypes.MakeTestFields("name", "varchar")
+	subquery := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqFields),
+			sqltypes.MakeTestResult(sqFields, "a"),
+			sqltypes.MakeTestResult(sqFields),
+		},
+	}
+	cs := &CorrelatedSubquery{
+		Opcode:         PulloutValue,
+		SubqueryResult: "__sq1",
+		Vars:           map[string]int{"col": 1},
+		Outer:          outer,
+		Subquery:       subquery,
+	}
+
+	result, err := cs.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	expectResult(t, result, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("__sq1|id|col", "varchar|int64|int64"),
+		"a|1|10",
+		"null|2|20",
+	))
+}
+
+func TestCorrelatedSubqueryValueBadRows(t *testing.T) {
+	outer := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|col", "int64|int64"), "1|10"),
+		},
+	}
+	subquery := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("name", "varchar"), "a", "b"),
+		},
+	}
+	cs := &CorrelatedSubquery{
+		Opcode:         PulloutValue,
+		SubqueryResult: "__sq1",
+		Vars:           map[string]int{"col": 1},
+		Outer:          outer,
+		Subquery:       subquery,
+	}
+
+	_, err := cs.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, false)
+	require.EqualError(t, err, "subquery returned more than one row")
+}
+
+func TestCorrelatedSubqueryExistsStream(t *testing.T) {
+	outer := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields("id|col", "int64|int64"),
+				"1|10",
+				"2|20",
+				"3|30",
+			),
+		},
+	}
+	sqFields := sqltypes.MakeTestFields("1", "int64")
+	subquery := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqFields, "1"),
+			sqltypes.MakeTestResult(sqFields),
+			sqltypes.MakeTestResult(sqFields, "1", "1"),
+		},
+	}
+	cs := &CorrelatedSubquery{
+		Opcode:    PulloutExists,
+		HasValues: "__sq_has_values",
+		Vars:      map[string]int{"col": 1},
+		Outer:     outer,
+		Subquery:  subquery,
+	}
+
+	result, err := wrapStreamExecute(cs, &noopVCursor{}, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	outer.ExpectLog(t, []string{`StreamExecute  false`})
+	require.Equal(t, "[[INT64(1) INT64(1) INT64(10)] [INT64(0) INT64(2) INT64(20)] [INT64(1) INT64(3) INT64(30)]]", fmt.Sprintf("%v", result.Rows))
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"reflect"
 	"slices"
 	"sort"
@@ -48,6 +49,7 @@ import (
 
 var testMaxMemoryRows = 100
 var testIgnoreMaxMemoryRows = false
+var testCTEMaxRecursionDepth = 1000
 
 var _ VCursor = (*noopVCursor)(nil)
 var _ SessionActions = (*noopVCursor)(nil)
@@ -112,6 +114,22 @@ func (t *noopVCursor) CloneForReplicaWarming(ctx context.Context) VCursor {
 	panic("implement me")
 }
 
+func (t *noopVCursor) GetMirrorChannel() chan bool {
+	panic("implement me")
+}
+
+func (t *noopVCursor) CloneForMirroring(ctx context.Context) (context.Context, context.CancelFunc, VCursor) {
+	panic("implement me")
+}
+
+func (t *noopVCursor) RecordMirrorStats(string, string, time.Duration, time.Duration, error, error) {
+	panic("implement me")
+}
+
+func (t *noopVCursor) StartPrimitiveTrace() func() map[Primitive]PrimitiveStats {
+	panic("implement me")
+}
+
 func (t *noopVCursor) SetExec(ctx context.Context, name string, value string) error {
 	panic("implement me")
 }
@@ -164,7 +182,6 @@ func (t *noopVCursor) HasSystemVariables() bool {
 }
 
 func (t *noopVCursor) GetSystemVariables(func(k string, v string)) {
-	panic("implement me")
 }
 
 func (t *noopVCursor) GetWarnings() []*querypb.QueryWarning {
@@ -337,6 +354,10 @@ func (t *noopVCursor) ExceedsMaxMemoryRows(numRows int) bool {
 	return !testIgnoreMaxMemoryRows && numRows > testMaxMemoryRows
 }
 
+func (t *noopVCursor) CTEMaxRecursionDepth() int {
+	return testCTEMaxRecursionDepth
+}
+
 func (t *noopVCursor) GetKeyspace() string {
 	return ""
 }
@@ -356,6 +377,10 @@ func (t *noopVCursor) AutocommitApproval() bool {
 	panic("unimplemented")
 }
 
+func (t *noopVCursor) ReadLocalInfile(ctx context.Context, fileName string) (io.ReadCloser, error) {
+	panic("unimplemented")
+}
+
 func (t *noopVCursor) ExecuteStandalone(ctx context.Context, primitive Primitive, query string, bindvars map[string]*querypb.BindVariable, rs *srvtopo.ResolvedShard) (*sqltypes.Result, error) {
 	panic("unimplemented")
 }
@@ -421,6 +446,16 @@ type loggingVCursor struct {
 	shardSession []*srvtopo.ResolvedShard
 
 	parser *sqlparser.Parser
+
+	tracer *PrimitiveTracer
+
+	// localInfiles holds the content of the files read by LOAD DATA LOCAL INFILE statements.
+	localInfiles map[string]string
+
+	// mirrorChannel bounds the queries mirrored concurrently, and mirrorStats
+	// receives the stats recorded for them.
+	mirrorChannel chan bool
+	mirrorStats   chan string
 }
 
 func (f *loggingVCursor) HasCreatedTempTable() {
@@ -442,19 +477,36 @@ type tableRoutes struct {
 }
 
 func (f *loggingVCursor) ExecutePrimitive(ctx context.Context, primitive Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
-	return primitive.TryExecute(ctx, f, bindVars, wantfields)
+	return f.tracer.TraceExecute(primitive, func() (*sqltypes.Result, error) {
+		return primitive.TryExecute(ctx, f, bindVars, wantfields)
+	})
 }
 
 func (f *loggingVCursor) ExecutePrimitiveStandalone(ctx context.Context, primitive Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
-	return primitive.TryExecute(ctx, f, bindVars, wantfields)
+	return f.tracer.TraceExecute(primitive, func() (*sqltypes.Result, error) {
+		return primitive.TryExecute(ctx, f, bindVars, wantfields)
+	})
 }
 
 func (f *loggingVCursor) StreamExecutePrimitive(ctx context.Context, primitive Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
-	return primitive.TryStreamExecute(ctx, f, bindVars, wantfields, callback)
+	return f.tracer.TraceStreamExecute(primitive, callback, func(callback func(*sqltypes.Result) error) error {
+		return primitive.TryStreamExecute(ctx, f, bindVars, wantfields, callback)
+	})
 }
 
 func (f *loggingVCursor) StreamExecutePrimitiveStandalone(ctx context.Context, primitive Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(result *sqltypes.Result) error) error {
-	return primitive.TryStreamExecute(ctx, f, bindVars, wantfields, callback)
+	return f.tracer.TraceStreamExecute(primitive, callback, func(callback func(*sqltypes.Result) error) error {
+		return primitive.TryStreamExecute(ctx, f, bindVars, wantfields, callback)
+	})
+}
+
+func (f *loggingVCursor) StartPrimitiveTrace() func() map[Primitive]PrimitiveStats {
+	f.tracer = NewPrimitiveTracer()
+	return func() map[Primitive]PrimitiveStats {
+		stats := f.tracer.Stats()
+		f.tracer = nil
+		return stats
+	}
 }
 
 func (f *loggingVCursor) KeyspaceAvailable(ks string) bool {
@@ -465,8 +517,10 @@ func (f *loggingVCursor) HasSystemVariables() bool {
 	return len(f.systemVariables) > 0
 }
 
-func (f *loggingVCursor) GetSystemVariables(func(k string, v string)) {
-	panic("implement me")
+func (f *loggingVCursor) GetSystemVariables(fn func(k string, v string)) {
+	for k, v := range f.systemVariables {
+		fn(k, v)
+	}
 }
 
 func (f *loggingVCursor) SetFoundRows(u uint64) {
@@ -524,6 +578,10 @@ func (f *loggingVCursor) RecordWarning(warning *querypb.QueryWarning) {
 	f.warnings = append(f.warnings, warning)
 }
 
+func (f *loggingVCursor) GetWarnings() []*querypb.QueryWarning {
+	return f.warnings
+}
+
 func (f *loggingVCursor) GetWarmingReadsPercent() int {
 	return 0
 }
@@ -536,6 +594,24 @@ func (f *loggingVCursor) CloneForReplicaWarming(ctx context.Context) VCursor {
 	return f
 }
 
+func (f *loggingVCursor) GetMirrorChannel() chan bool {
+	if f.mirrorChannel == nil {
+		return make(chan bool)
+	}
+	return f.mirrorChannel
+}
+
+func (f *loggingVCursor) CloneForMirroring(ctx context.Context) (context.Context, context.CancelFunc, VCursor) {
+	mirrorCtx, cancel := context.WithCancel(context.Background())
+	return mirrorCtx, cancel, f
+}
+
+func (f *loggingVCursor) RecordMirrorStats(sourceKeyspace, targetKeyspace string, sourceTime, targetTime time.Duration, sourceErr, targetErr error) {
+	if f.mirrorStats != nil {
+		f.mirrorStats <- fmt.Sprintf("%s %s %v %v", sourceKeyspace, targetKeyspace, sourceErr, targetErr)
+	}
+}
+
 func (f *loggingVCursor) Execute(ctx context.Context, method string, query string, bindvars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error) {
 	name := "Unknown"
 	switch co {
@@ -554,6 +630,7 @@ func (f *loggingVCursor) Execute(ctx context.Context, method string, query strin
 
 func (f *loggingVCursor) ExecuteMultiShard(ctx context.Context, primitive Primitive, rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, rollbackOnError, canAutocommit bool) (*sqltypes.Result, []error) {
 	f.log = append(f.log, fmt.Sprintf("ExecuteMultiShard %v%v %v", printResolvedShardQueries(rss, queries), rollbackOnError, canAutocommit))
+	f.tracer.RecordShardCalls(primitive, len(rss))
 	res, err := f.nextResult()
 	if err != nil {
 		return nil, []error{err}
@@ -566,14 +643,25 @@ func (f *loggingVCursor) AutocommitApproval() bool {
 	return true
 }
 
+func (f *loggingVCursor) ReadLocalInfile(ctx context.Context, fileName string) (io.ReadCloser, error) {
+	f.log = append(f.log, fmt.Sprintf("ReadLocalInfile %s", fileName))
+	content, ok := f.localInfiles[fileName]
+	if !ok {
+		return nil, fmt.Errorf("file %s not found", fileName)
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
 func (f *loggingVCursor) ExecuteStandalone(ctx context.Context, primitive Primitive, query string, bindvars map[string]*querypb.BindVariable, rs *srvtopo.ResolvedShard) (*sqltypes.Result, error) {
 	f.log = append(f.log, fmt.Sprintf("ExecuteStandalone %s %v %s %s", query, printBindVars(bindvars), rs.Target.Keyspace, rs.Target.Shard))
+	f.tracer.RecordShardCalls(primitive, 1)
 	return f.nextResult()
 }
 
 func (f *loggingVCursor) StreamExecuteMulti(ctx context.Context, primitive Primitive, query string, rss []*srvtopo.ResolvedShard, bindVars []map[string]*querypb.BindVariable, rollbackOnError bool, autocommit bool, callback func(reply *sqltypes.Result) error) []error {
 	f.mu.Lock()
 	f.log = append(f.log, fmt.Sprintf("StreamExecuteMulti %s %s", query, printResolvedShardsBindVars(rss, bindVars)))
+	f.tracer.RecordShardCalls(primitive, len(rss))
 	r, err := f.nextResult()
 	f.mu.Unlock()
 	if err != nil {
@@ -0,0 +1,219 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+const (
+	// loadBatchRows is the maximum number of rows of an insert executed by a Load.
+	loadBatchRows = 500
+	// loadBatchSize is the size after which the insert being built by a Load is executed.
+	loadBatchSize = 1024 * 1024
+)
+
+var _ Primitive = (*Load)(nil)
+
+// Load executes a LOAD DATA LOCAL INFILE statement: it reads the file from the
+// MySQL client, parses its lines and inserts them in batches. The inserts are
+// planned and executed like any other insert, so the rows are routed to their
+// shards, and sequences and lookup vindexes are honored.
+type Load struct {
+	txNeeded
+	noInputs
+
+	Keyspace  *vindexes.Keyspace
+	TableName string
+	FileName  string
+	Format    LoadFormat
+	// IgnoreLines is the number of lines skipped at the beginning of the file.
+	IgnoreLines int
+	Duplicate   sqlparser.LoadDuplicate
+
+	// Insert is the insert statement the lines are added to, up to its rows,
+	// e.g. "insert into t(a, b) values ".
+	Insert string
+	// Fields tells, for each field of a line, whether it is inserted into the next
+	// column of the insert, or assigned to a user variable and discarded. It is empty
+	// if the statement has no column list, in which case all the fields are inserted.
+	Fields []bool
+}
+
+// RouteType implements the Primitive interface
+func (l *Load) RouteType() string {
+	return "Load"
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (l *Load) GetKeyspaceName() string {
+	return l.Keyspace.Name
+}
+
+// GetTableName implements the Primitive interface
+func (l *Load) GetTableName() string {
+	return l.TableName
+}
+
+// TryExecute implements the Primitive interface
+func (l *Load) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	file, err := vcursor.ReadLocalInfile(ctx, l.FileName)
+	if err != nil {
+		return nil, err
+	}
+	// The file must be read to its end before the result is sent to the client.
+	defer file.Close()
+
+	result := &sqltypes.Result{}
+	var records uint64
+	var query strings.Builder
+	rows := 0
+	execute := func() error {
+		if rows == 0 {
+			return nil
+		}
+		qr, err := vcursor.Execute(ctx, "Load", query.String(), nil, true, vtgatepb.CommitOrder_NORMAL)
+		if err != nil {
+			return err
+		}
+		result.RowsAffected += qr.RowsAffected
+		if result.InsertID == 0 {
+			result.InsertID = qr.InsertID
+		}
+		query.Reset()
+		rows = 0
+		return nil
+	}
+
+	reader := newLoadReader(file, l.Format)
+	for line := 0; ; line++ {
+		fields, err := reader.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line < l.IgnoreLines {
+			continue
+		}
+
+		if rows == 0 {
+			query.WriteString(l.Insert)
+		} else {
+			query.WriteString(", ")
+		}
+		l.writeRow(&query, fields)
+		rows++
+		records++
+		if rows >= loadBatchRows || query.Len() >= loadBatchSize {
+			if err := execute(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := execute(); err != nil {
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	var deleted, skipped uint64
+	switch {
+	case l.Duplicate == sqlparser.LoadDuplicateReplace && result.RowsAffected > records:
+		// A replaced row counts as a deleted and an inserted row.
+		deleted = result.RowsAffected - records
+	case l.Duplicate == sqlparser.LoadDuplicateIgnore && result.RowsAffected < records:
+		skipped = records - result.RowsAffected
+	}
+	result.Info = fmt.Sprintf("Records: %d  Deleted: %d  Skipped: %d  Warnings: %d", records, deleted, skipped, len(vcursor.Session().GetWarnings()))
+	return result, nil
+}
+
+// writeRow writes the values of the fields of a line as a row of the insert. The
+// columns that have no field in the line are set to their default value.
+func (l *Load) writeRow(query *strings.Builder, fields []sqltypes.Value) {
+	query.WriteByte('(')
+	if len(l.Fields) == 0 {
+		for i, field := range fields {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			field.EncodeSQL(query)
+		}
+		query.WriteByte(')')
+		return
+	}
+
+	columns := 0
+	for i, inserted := range l.Fields {
+		if !inserted {
+			continue
+		}
+		if columns > 0 {
+			query.WriteString(", ")
+		}
+		columns++
+		if i < len(fields) {
+			fields[i].EncodeSQL(query)
+		} else {
+			query.WriteString("default")
+		}
+	}
+	query.WriteByte(')')
+}
+
+// TryStreamExecute implements the Primitive interface
+func (l *Load) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	result, err := l.TryExecute(ctx, vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(result)
+}
+
+// GetFields implements the Primitive interface
+func (l *Load) GetFields(context.Context, VCursor, map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return nil, vterrors.VT13001("unexpected fields call for a load statement")
+}
+
+func (l *Load) description() PrimitiveDescription {
+	other := map[string]any{
+		"TableName": l.TableName,
+		"FileName":  l.FileName,
+		"Query":     l.Insert,
+	}
+	if l.IgnoreLines > 0 {
+		other["IgnoreLines"] = l.IgnoreLines
+	}
+	return PrimitiveDescription{
+		OperatorType: "Load",
+		Keyspace:     l.Keyspace,
+		Other:        other,
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+// LoadFormat is the format of the lines of a file read by LOAD DATA,
+// as set by the FIELDS and LINES clauses of the statement.
+type LoadFormat struct {
+	FieldsTerminatedBy string
+	// FieldsEnclosedBy is empty, or the character that may enclose the fields.
+	FieldsEnclosedBy string
+	// FieldsEscapedBy is empty, or the character that escapes special characters.
+	FieldsEscapedBy   string
+	LinesStartingBy   string
+	LinesTerminatedBy string
+}
+
+// DefaultLoadFormat returns the format of a LOAD DATA statement with no FIELDS and LINES clauses.
+func DefaultLoadFormat() LoadFormat {
+	return LoadFormat{
+		FieldsTerminatedBy: "\t",
+		FieldsEscapedBy:    "\\",
+		LinesTerminatedBy:  "\n",
+	}
+}
+
+// fieldEnd is how a field read by a loadReader ends.
+type fieldEnd int
+
+const (
+	endOfField fieldEnd = iota
+	endOfLine
+	endOfFile
+)
+
+// loadReader reads the lines of a file in a LoadFormat, the way MySQL does:
+//   - A field that starts with the enclosing character ends at the next enclosing
+//     character followed by a terminator, and a doubled enclosing character in it
+//     is read as a single one.
+//   - The escape character followed by 0, b, n, r, t or Z is read as the matching
+//     special character, and followed by any other character is read as that character.
+//   - A field that is the escape character followed by N is read as NULL, and so is an
+//     unenclosed field that is the word NULL if the fields may be enclosed.
+//   - Everything up to the line prefix is skipped, along with the lines that lack it.
+type loadReader struct {
+	r      *bufio.Reader
+	format LoadFormat
+
+	fieldTerminator []byte
+	lineTerminator  []byte
+	linePrefix      []byte
+
+	field []byte
+}
+
+func newLoadReader(r io.Reader, format LoadFormat) *loadReader {
+	return &loadReader{
+		r:               bufio.NewReader(r),
+		format:          format,
+		fieldTerminator: []byte(format.FieldsTerminatedBy),
+		lineTerminator:  []byte(format.LinesTerminatedBy),
+		linePrefix:      []byte(format.LinesStartingBy),
+	}
+}
+
+// next returns the fields of the next line, or io.EOF once all the lines were read.
+func (lr *loadReader) next() ([]sqltypes.Value, error) {
+	if len(lr.linePrefix) > 0 {
+		found, err := lr.skipToLinePrefix()
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, io.EOF
+		}
+	} else if _, err := lr.r.Peek(1); err != nil {
+		return nil, err
+	}
+
+	var fields []sqltypes.Value
+	for {
+		value, end, err := lr.readField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, value)
+		if end != endOfField {
+			return fields, nil
+		}
+	}
+}
+
+// skipToLinePrefix skips everything up to and including the next line prefix.
+// It returns false if the file has no more line prefixes.
+func (lr *loadReader) skipToLinePrefix() (bool, error) {
+	skipped := lr.field[:0]
+	defer func() {
+		lr.field = skipped[:0]
+	}()
+	for {
+		b, err := lr.r.ReadByte()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		skipped = append(skipped, b)
+		if bytes.HasSuffix(skipped, lr.linePrefix) {
+			return true, nil
+		}
+	}
+}
+
+// readField reads the next field of the current line.
+func (lr *loadReader) readField() (sqltypes.Value, fieldEnd, error) {
+	field := lr.field[:0]
+	defer func() {
+		lr.field = field[:0]
+	}()
+
+	var enclose, escape byte
+	hasEnclose := lr.format.FieldsEnclosedBy != ""
+	if hasEnclose {
+		enclose = lr.format.FieldsEnclosedBy[0]
+	}
+	hasEscape := lr.format.FieldsEscapedBy != ""
+	if hasEscape {
+		escape = lr.format.FieldsEscapedBy[0]
+	}
+
+	enclosed := false
+	if hasEnclose {
+		if b, err := lr.r.Peek(1); err == nil && b[0] == enclose {
+			_, _ = lr.r.ReadByte()
+			enclosed = true
+		}
+	}
+	wasEnclosed := enclosed
+
+	// protected is the length of the beginning of the field that cannot be part of
+	// a terminator, because it was escaped or enclosed.
+	protected := 0
+	escapedNull := false
+	end := endOfFile
+	for {
+		b, err := lr.r.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return sqltypes.Value{}, end, err
+		}
+
+		if hasEscape && b == escape {
+			next, err := lr.r.ReadByte()
+			if err == io.EOF {
+				field = append(field, b)
+				break
+			}
+			if err != nil {
+				return sqltypes.Value{}, end, err
+			}
+			escapedNull = next == 'N' && len(field) == 0 && !wasEnclosed
+			field = append(field, unescapeLoadByte(next))
+			protected = len(field)
+			continue
+		}
+
+		if enclosed && b == enclose {
+			following, _ := lr.r.Peek(max(len(lr.fieldTerminator), len(lr.lineTerminator)))
+			switch {
+			case len(following) > 0 && following[0] == enclose:
+				// A doubled enclosing character.
+				_, _ = lr.r.ReadByte()
+				field = append(field, enclose)
+			case len(following) == 0, bytes.HasPrefix(following, lr.fieldTerminator), bytes.HasPrefix(following, lr.lineTerminator):
+				enclosed = false
+			default:
+				field = append(field, b)
+			}
+			protected = len(field)
+			continue
+		}
+
+		field = append(field, b)
+		if enclosed {
+			protected = len(field)
+			continue
+		}
+		if len(field)-protected >= len(lr.fieldTerminator) && bytes.HasSuffix(field, lr.fieldTerminator) {
+			field = field[:len(field)-len(lr.fieldTerminator)]
+			end = endOfField
+			break
+		}
+		if len(field)-protected >= len(lr.lineTerminator) && bytes.HasSuffix(field, lr.lineTerminator) {
+			field = field[:len(field)-len(lr.lineTerminator)]
+			end = endOfLine
+			break
+		}
+	}
+
+	if escapedNull && len(field) == 1 {
+		return sqltypes.NULL, end, nil
+	}
+	if hasEnclose && !wasEnclosed && string(field) == "NULL" {
+		return sqltypes.NULL, end, nil
+	}
+	return sqltypes.NewVarChar(string(field)), end, nil
+}
+
+// unescapeLoadByte returns the character read for the given character following the escape character.
+func unescapeLoadByte(b byte) byte {
+	switch b {
+	case '0':
+		return 0
+	case 'b':
+		return '\b'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'Z':
+		return 26
+	}
+	return b
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestLoadReader(t *testing.T) {
+	csv := DefaultLoadFormat()
+	csv.FieldsTerminatedBy = ","
+	csv.FieldsEnclosedBy = `"`
+
+	prefixed := DefaultLoadFormat()
+	prefixed.LinesStartingBy = "xxx"
+	prefixed.LinesTerminatedBy = "\r\n"
+
+	tcases := []struct {
+		name    string
+		format  LoadFormat
+		content string
+		want    [][]string
+	}{{
+		name:    "default format",
+		format:  DefaultLoadFormat(),
+		content: "1\ta\n2\tb\n",
+		want:    [][]string{{"'1'", "'a'"}, {"'2'", "'b'"}},
+	}, {
+		name:    "last line without terminator",
+		format:  DefaultLoadFormat(),
+		content: "1\ta\n2\tb",
+		want:    [][]string{{"'1'", "'a'"}, {"'2'", "'b'"}},
+	}, {
+		name:    "escapes and nulls",
+		format:  DefaultLoadFormat(),
+		content: "a\\tb\\\\c\t\\N\tx\\\ty\n",
+		want:    [][]string{{`'a\tb\\c'`, "NULL", `'x\ty'`}},
+	}, {
+		name:    "enclosed fields",
+		format:  csv,
+		content: "1,\"a,b\",\"say \"\"hi\"\"\"\n2,NULL,\"NULL\"\n3,\"multi\nline\",c\n",
+		want:    [][]string{{"'1'", "'a,b'", `'say \"hi\"'`}, {"'2'", "NULL", "'NULL'"}, {"'3'", `'multi\nline'`, "'c'"}},
+	}, {
+		name:    "line prefix",
+		format:  prefixed,
+		content: "skipped\r\nxxx1\ta\r\nyyyxxx2\tb\r\n",
+		want:    [][]string{{"'1'", "'a'"}, {"'2'", "'b'"}},
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			reader := newLoadReader(strings.NewReader(tcase.content), tcase.format)
+			var got [][]string
+			for {
+				fields, err := reader.next()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				var line []string
+				for _, field := range fields {
+					// NULL fields are told apart from the 'NULL' strings.
+					line = append(line, sqltypes.EncodeStringSQL(field.ToString()))
+					if field.IsNull() {
+						line[len(line)-1] = "NULL"
+					}
+				}
+				got = append(got, line)
+			}
+			assert.Equal(t, tcase.want, got)
+		})
+	}
+}
+
+func TestLoadExecute(t *testing.T) {
+	ks := &vindexes.Keyspace{Name: "ks", Sharded: true}
+	load := &Load{
+		Keyspace:    ks,
+		TableName:   "t",
+		FileName:    "/tmp/t.csv",
+		Format:      LoadFormat{FieldsTerminatedBy: ",", FieldsEscapedBy: "\\", LinesTerminatedBy: "\n"},
+		IgnoreLines: 1,
+		Duplicate:   sqlparser.LoadDuplicateIgnore,
+		Insert:      "insert ignore into t(id, name) values ",
+		Fields:      []bool{true, false, true},
+	}
+
+	var content strings.Builder
+	content.WriteString("id,skipped,name\n")
+	for i := 1; i <= loadBatchRows+1; i++ {
+		fmt.Fprintf(&content, "%d,x,n%d\n", i, i)
+	}
+	content.WriteString("0\n")
+
+	vc := &loggingVCursor{
+		localInfiles: map[string]string{"/tmp/t.csv": content.String()},
+		results: []*sqltypes.Result{
+			{RowsAffected: loadBatchRows, InsertID: 1},
+			{RowsAffected: 1},
+		},
+	}
+	result, err := load.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	assert.EqualValues(t, loadBatchRows+1, result.RowsAffected)
+	assert.EqualValues(t, 1, result.InsertID)
+	assert.Equal(t, "Records: 502  Deleted: 0  Skipped: 1  Warnings: 0", result.Info)
+
+	require.Len(t, vc.log, 3)
+	assert.Equal(t, "ReadLocalInfile /tmp/t.csv", vc.log[0])
+	assert.True(t, strings.HasPrefix(vc.log[1], "Execute insert ignore into t(id, name) values ('1', 'n1'), ('2', 'n2'), "), vc.log[1])
+	assert.True(t, strings.HasSuffix(vc.log[1], "('500', 'n500')  true"), vc.log[1])
+	assert.Equal(t, "Execute insert ignore into t(id, name) values ('501', 'n501'), ('0', default)  true", vc.log[2])
+
+	vc = &loggingVCursor{}
+	_, err = load.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.EqualError(t, err, "file /tmp/t.csv not found")
+}
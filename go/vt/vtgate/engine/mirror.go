@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"maps"
+	"math/rand/v2"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+var _ Primitive = (*PercentBasedMirror)(nil)
+
+// PercentBasedMirror executes its source primitive and, for a percentage of the
+// queries, also executes its target primitive in the background, to mirror the
+// query to another keyspace. The results of the target are discarded: only its
+// latency and error are compared to the ones of the source, in the mirror stats.
+type PercentBasedMirror struct {
+	// Percent is the percentage of the queries that are mirrored.
+	Percent float32
+	// Primitive is the source primitive, whose result is returned.
+	Primitive Primitive
+	// Target is the primitive the queries are mirrored to.
+	Target Primitive
+}
+
+// mirrorSource is the outcome of the source primitive of a mirrored query.
+type mirrorSource struct {
+	time time.Duration
+	err  error
+}
+
+// NeedsTransaction implements the Primitive interface
+func (m *PercentBasedMirror) NeedsTransaction() bool {
+	return m.Primitive.NeedsTransaction()
+}
+
+// RouteType implements the Primitive interface
+func (m *PercentBasedMirror) RouteType() string {
+	return "Mirror"
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (m *PercentBasedMirror) GetKeyspaceName() string {
+	return m.Primitive.GetKeyspaceName()
+}
+
+// GetTableName implements the Primitive interface
+func (m *PercentBasedMirror) GetTableName() string {
+	return m.Primitive.GetTableName()
+}
+
+// GetFields implements the Primitive interface
+func (m *PercentBasedMirror) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return m.Primitive.GetFields(ctx, vcursor, bindVars)
+}
+
+// TryExecute implements the Primitive interface
+func (m *PercentBasedMirror) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	sourceDone := m.startMirror(ctx, vcursor, bindVars)
+	start := time.Now()
+	result, err := vcursor.ExecutePrimitive(ctx, m.Primitive, bindVars, wantfields)
+	sourceDone(time.Since(start), err)
+	return result, err
+}
+
+// TryStreamExecute implements the Primitive interface
+func (m *PercentBasedMirror) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	sourceDone := m.startMirror(ctx, vcursor, bindVars)
+	start := time.Now()
+	err := vcursor.StreamExecutePrimitive(ctx, m.Primitive, bindVars, wantfields, callback)
+	sourceDone(time.Since(start), err)
+	return err
+}
+
+// startMirror starts executing the target primitive in the background if the query
+// is mirrored. It returns the function to call with the latency and the error of the
+// source primitive once it is executed, which the mirror stats are recorded with.
+func (m *PercentBasedMirror) startMirror(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) func(time.Duration, error) {
+	if m.Percent <= 0 || rand.Float32()*100 >= m.Percent {
+		return func(time.Duration, error) {}
+	}
+
+	mirrorChannel := vcursor.GetMirrorChannel()
+	select {
+	case mirrorChannel <- true:
+	default:
+		// if there's no more room in the channel, the query is not mirrored
+		log.Warning("Failed to mirror query as the mirror pool is full")
+		return func(time.Duration, error) {}
+	}
+
+	mirrorCtx, cancel, mirrorVCursor := vcursor.CloneForMirroring(ctx)
+	// The source primitive may add bind variables while the target is executed.
+	mirrorBindVars := maps.Clone(bindVars)
+	source := make(chan mirrorSource, 1)
+	go func() {
+		defer func() {
+			<-mirrorChannel
+		}()
+		defer cancel()
+
+		start := time.Now()
+		err := mirrorVCursor.StreamExecutePrimitive(mirrorCtx, m.Target, mirrorBindVars, false, func(*sqltypes.Result) error {
+			return nil
+		})
+		targetTime := time.Since(start)
+
+		select {
+		case src := <-source:
+			mirrorVCursor.RecordMirrorStats(m.Primitive.GetKeyspaceName(), m.Target.GetKeyspaceName(), src.time, targetTime, src.err, err)
+		case <-mirrorCtx.Done():
+			// The source did not complete within the mirror query timeout: its
+			// outcome cannot be compared to the one of the target.
+		}
+	}()
+
+	return func(sourceTime time.Duration, sourceErr error) {
+		source <- mirrorSource{time: sourceTime, err: sourceErr}
+	}
+}
+
+// Inputs implements the Primitive interface
+func (m *PercentBasedMirror) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{m.Primitive, m.Target}, []map[string]any{{
+		inputName: "Source",
+	}, {
+		inputName: "Mirror",
+	}}
+}
+
+func (m *PercentBasedMirror) description() PrimitiveDescription {
+	return PrimitiveDescription{
+		OperatorType: "Mirror",
+		Variant:      "PercentBased",
+		Other: map[string]any{
+			"Percent": m.Percent,
+		},
+	}
+}
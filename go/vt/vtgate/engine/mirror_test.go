@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestPercentBasedMirror(t *testing.T) {
+	fields := sqltypes.MakeTestFields("id", "int64")
+	sourceResult := sqltypes.MakeTestResult(fields, "1", "2")
+	targetResult := sqltypes.MakeTestResult(fields, "3")
+
+	tcases := []struct {
+		name      string
+		percent   float32
+		stream    bool
+		full      bool
+		targetErr error
+		wantStats string
+	}{{
+		name:      "mirrored",
+		percent:   100,
+		wantStats: "fakeKs fakeKs <nil> <nil>",
+	}, {
+		name:      "mirrored when streaming",
+		percent:   100,
+		stream:    true,
+		wantStats: "fakeKs fakeKs <nil> <nil>",
+	}, {
+		name:      "target error",
+		percent:   100,
+		targetErr: errors.New("target failed"),
+		wantStats: "fakeKs fakeKs <nil> target failed",
+	}, {
+		name:    "not mirrored",
+		percent: 0,
+	}, {
+		name:    "mirror pool full",
+		percent: 100,
+		full:    true,
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			source := &fakePrimitive{results: []*sqltypes.Result{sourceResult}}
+			target := &fakePrimitive{results: []*sqltypes.Result{targetResult}}
+			if tcase.targetErr != nil {
+				target = &fakePrimitive{results: []*sqltypes.Result{nil}, sendErr: tcase.targetErr}
+			}
+			mirror := &PercentBasedMirror{
+				Percent:   tcase.percent,
+				Primitive: source,
+				Target:    target,
+			}
+
+			vc := &loggingVCursor{
+				mirrorChannel: make(chan bool, 1),
+				mirrorStats:   make(chan string, 1),
+			}
+			if tcase.full {
+				vc.mirrorChannel <- true
+			}
+
+			bindVars := map[string]*querypb.BindVariable{"a": sqltypes.Int64BindVariable(1)}
+			if tcase.stream {
+				result, err := wrapStreamExecute(mirror, vc, bindVars, true)
+				require.NoError(t, err)
+				expectResult(t, result, sourceResult)
+			} else {
+				result, err := mirror.TryExecute(context.Background(), vc, bindVars, true)
+				require.NoError(t, err)
+				assert.Equal(t, sourceResult, result)
+			}
+
+			if tcase.wantStats == "" {
+				assert.Empty(t, target.log)
+				assert.Empty(t, vc.mirrorStats)
+				return
+			}
+			assert.Equal(t, tcase.wantStats, <-vc.mirrorStats)
+			assert.Equal(t, []string{"StreamExecute a: type:INT64 value:\"1\" false"}, target.log)
+			// The mirror released its slot in the mirror pool.
+			assert.Eventually(t, func() bool {
+				return len(vc.mirrorChannel) == 0
+			}, time.Second, 10*time.Millisecond)
+		})
+	}
+}
@@ -77,7 +77,8 @@ const (
 	AggregateCountStar
 	AggregateGroupConcat
 	AggregateAvg
-	AggregateUDF  // This is an opcode used to represent UDFs
+	AggregateUDF // This is an opcode used to represent UDFs
+	AggregateGroupConcatDistinct
 	_NumOfOpCodes // This line must be last of the opcodes!
 )
 
@@ -91,26 +92,28 @@ var SupportedAggregates = map[string]AggregateOpcode{
 	"avg":   AggregateAvg,
 	// These functions don't exist in mysql, but are used
 	// to display the plan.
-	"count_distinct": AggregateCountDistinct,
-	"sum_distinct":   AggregateSumDistinct,
-	"vgtid":          AggregateGtid,
-	"count_star":     AggregateCountStar,
-	"any_value":      AggregateAnyValue,
-	"group_concat":   AggregateGroupConcat,
+	"count_distinct":        AggregateCountDistinct,
+	"sum_distinct":          AggregateSumDistinct,
+	"vgtid":                 AggregateGtid,
+	"count_star":            AggregateCountStar,
+	"any_value":             AggregateAnyValue,
+	"group_concat":          AggregateGroupConcat,
+	"group_concat_distinct": AggregateGroupConcatDistinct,
 }
 
 var AggregateName = map[AggregateOpcode]string{
-	AggregateCount:         "count",
-	AggregateSum:           "sum",
-	AggregateMin:           "min",
-	AggregateMax:           "max",
-	AggregateCountDistinct: "count_distinct",
-	AggregateSumDistinct:   "sum_distinct",
-	AggregateGtid:          "vgtid",
-	AggregateCountStar:     "count_star",
-	AggregateGroupConcat:   "group_concat",
-	AggregateAnyValue:      "any_value",
-	AggregateAvg:           "avg",
+	AggregateCount:               "count",
+	AggregateSum:                 "sum",
+	AggregateMin:                 "min",
+	AggregateMax:                 "max",
+	AggregateCountDistinct:       "count_distinct",
+	AggregateSumDistinct:         "sum_distinct",
+	AggregateGtid:                "vgtid",
+	AggregateCountStar:           "count_star",
+	AggregateGroupConcat:         "group_concat",
+	AggregateGroupConcatDistinct: "group_concat_distinct",
+	AggregateAnyValue:            "any_value",
+	AggregateAvg:                 "avg",
 }
 
 func (code AggregateOpcode) String() string {
@@ -132,7 +135,7 @@ func (code AggregateOpcode) SQLType(typ querypb.Type) querypb.Type {
 	switch code {
 	case AggregateUnassigned:
 		return sqltypes.Null
-	case AggregateGroupConcat:
+	case AggregateGroupConcat, AggregateGroupConcatDistinct:
 		if typ == sqltypes.Unknown {
 			return sqltypes.Unknown
 		}
@@ -185,7 +188,7 @@ func (code AggregateOpcode) ResolveType(t evalengine.Type, env *collations.Envir
 
 func (code AggregateOpcode) NeedsComparableValues() bool {
 	switch code {
-	case AggregateCountDistinct, AggregateSumDistinct, AggregateMin, AggregateMax:
+	case AggregateCountDistinct, AggregateSumDistinct, AggregateGroupConcatDistinct, AggregateMin, AggregateMax:
 		return true
 	default:
 		return false
@@ -194,9 +197,76 @@ func (code AggregateOpcode) NeedsComparableValues() bool {
 
 func (code AggregateOpcode) IsDistinct() bool {
 	switch code {
-	case AggregateCountDistinct, AggregateSumDistinct:
+	case AggregateCountDistinct, AggregateSumDistinct, AggregateGroupConcatDistinct:
 		return true
 	default:
 		return false
 	}
 }
+
+// WindowOpcode is the opcode for functions evaluated by the Window primitive.
+type WindowOpcode int
+
+// These constants list the possible window opcodes.
+const (
+	WindowUnassigned = WindowOpcode(iota)
+	WindowRowNumber
+	WindowRank
+	WindowDenseRank
+	WindowPercentRank
+	WindowCumeDist
+	WindowNtile
+	WindowLag
+	WindowLead
+	WindowFirstValue
+	WindowLastValue
+	WindowNthValue
+	WindowAggregate   // An aggregate function used with an OVER clause
+	_NumOfWindowCodes // This line must be last of the opcodes!
+)
+
+var WindowName = map[WindowOpcode]string{
+	WindowRowNumber:   "row_number",
+	WindowRank:        "rank",
+	WindowDenseRank:   "dense_rank",
+	WindowPercentRank: "percent_rank",
+	WindowCumeDist:    "cume_dist",
+	WindowNtile:       "ntile",
+	WindowLag:         "lag",
+	WindowLead:        "lead",
+	WindowFirstValue:  "first_value",
+	WindowLastValue:   "last_value",
+	WindowNthValue:    "nth_value",
+	WindowAggregate:   "aggregate",
+}
+
+func (code WindowOpcode) String() string {
+	name := WindowName[code]
+	if name == "" {
+		name = "ERROR"
+	}
+	return name
+}
+
+// MarshalJSON serializes the WindowOpcode as a JSON string.
+// It's used for testing and diagnostics.
+func (code WindowOpcode) MarshalJSON() ([]byte, error) {
+	return ([]byte)(fmt.Sprintf("\"%s\"", code.String())), nil
+}
+
+// SQLType returns the type produced by the window function, given the type of its argument.
+// For WindowAggregate, the type is decided by the aggregation opcode instead.
+func (code WindowOpcode) SQLType(typ querypb.Type) querypb.Type {
+	switch code {
+	case WindowUnassigned:
+		return sqltypes.Null
+	case WindowRowNumber, WindowRank, WindowDenseRank, WindowNtile:
+		return sqltypes.Uint64
+	case WindowPercentRank, WindowCumeDist:
+		return sqltypes.Float64
+	case WindowLag, WindowLead, WindowFirstValue, WindowLastValue, WindowNthValue, WindowAggregate:
+		return typ
+	default:
+		panic(code.String()) // we have a unit test checking we never reach here
+	}
+}
@@ -34,6 +34,13 @@ func TestCheckAllAggrOpCodes(t *testing.T) {
 	}
 }
 
+func TestCheckAllWindowOpCodes(t *testing.T) {
+	// This test is just checking that we never reach the panic when using SQLType() on valid opcodes
+	for i := WindowOpcode(0); i < _NumOfWindowCodes; i++ {
+		i.SQLType(sqltypes.Null)
+	}
+}
+
 func TestType(t *testing.T) {
 	tt := []struct {
 		opcode AggregateOpcode
@@ -44,6 +51,7 @@ func TestType(t *testing.T) {
 		{AggregateGroupConcat, sqltypes.VarChar, sqltypes.Text},
 		{AggregateGroupConcat, sqltypes.Blob, sqltypes.Blob},
 		{AggregateGroupConcat, sqltypes.Unknown, sqltypes.Unknown},
+		{AggregateGroupConcatDistinct, sqltypes.VarChar, sqltypes.Text},
 		{AggregateMax, sqltypes.Int64, sqltypes.Int64},
 		{AggregateMax, sqltypes.Float64, sqltypes.Float64},
 		{AggregateSumDistinct, sqltypes.Unknown, sqltypes.Unknown},
@@ -144,7 +152,7 @@ func TestAggregateOpcode_MarshalJSON(t *testing.T) {
 
 func TestNeedsComparableValues(t *testing.T) {
 	for i := AggregateOpcode(0); i < _NumOfOpCodes; i++ {
-		if i == AggregateCountDistinct || i == AggregateSumDistinct || i == AggregateMin || i == AggregateMax {
+		if i == AggregateCountDistinct || i == AggregateSumDistinct || i == AggregateGroupConcatDistinct || i == AggregateMin || i == AggregateMax {
 			assert.True(t, i.NeedsComparableValues())
 		} else {
 			assert.False(t, i.NeedsComparableValues())
@@ -154,7 +162,7 @@ func TestNeedsComparableValues(t *testing.T) {
 
 func TestIsDistinct(t *testing.T) {
 	for i := AggregateOpcode(0); i < _NumOfOpCodes; i++ {
-		if i == AggregateCountDistinct || i == AggregateSumDistinct {
+		if i == AggregateCountDistinct || i == AggregateSumDistinct || i == AggregateGroupConcatDistinct {
 			assert.True(t, i.IsDistinct())
 		} else {
 			assert.False(t, i.IsDistinct())
@@ -153,7 +153,7 @@ func (oa *OrderedAggregate) execute(ctx context.Context, vcursor VCursor, bindVa
 		return oa.executeGroupBy(result)
 	}
 
-	agg, fields, err := newAggregation(result.Fields, oa.Aggregates)
+	agg, fields, err := newAggregation(vcursor, result.Fields, oa.Aggregates)
 	if err != nil {
 		return nil, err
 	}
@@ -258,7 +258,7 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 		var err error
 
 		if agg == nil && len(qr.Fields) != 0 {
-			agg, fields, err = newAggregation(qr.Fields, oa.Aggregates)
+			agg, fields, err = newAggregation(vcursor, qr.Fields, oa.Aggregates)
 			if err != nil {
 				return err
 			}
@@ -313,7 +313,7 @@ func (oa *OrderedAggregate) GetFields(ctx context.Context, vcursor VCursor, bind
 		return nil, err
 	}
 
-	_, fields, err := newAggregation(qr.Fields, oa.Aggregates)
+	_, fields, err := newAggregation(vcursor, qr.Fields, oa.Aggregates)
 	if err != nil {
 		return nil, err
 	}
@@ -105,6 +105,14 @@ func (pd PrimitiveDescription) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// addOther adds a field to the Other map, creating it if needed
+func (pd *PrimitiveDescription) addOther(key string, value any) {
+	if pd.Other == nil {
+		pd.Other = map[string]any{}
+	}
+	pd.Other[key] = value
+}
+
 func (pd PrimitiveDescription) addToGraph(g *graphviz.Graph) (*graphviz.Node, error) {
 	var nodes []*graphviz.Node
 	for _, input := range pd.Inputs {
@@ -18,6 +18,7 @@ package engine
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"vitess.io/vitess/go/mysql/collations"
@@ -56,9 +57,17 @@ type (
 		// if the max memory rows override directive is set to true
 		ExceedsMaxMemoryRows(numRows int) bool
 
+		// CTEMaxRecursionDepth returns the number of iterations a recursive
+		// common table expression is allowed to run before it is aborted
+		CTEMaxRecursionDepth() int
+
 		Execute(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error)
 		AutocommitApproval() bool
 
+		// ReadLocalInfile asks the MySQL client for the content of the given file,
+		// as part of a LOAD DATA LOCAL INFILE statement. The reader must be closed.
+		ReadLocalInfile(ctx context.Context, fileName string) (io.ReadCloser, error)
+
 		// Execute the given primitive
 		ExecutePrimitive(ctx context.Context, primitive Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error)
 		// Execute the given primitive in a new autocommit session
@@ -131,6 +140,21 @@ type (
 
 		// CloneForReplicaWarming clones the VCursor for re-use in warming queries to replicas
 		CloneForReplicaWarming(ctx context.Context) VCursor
+
+		// GetMirrorChannel returns the channel bounding the number of queries mirrored concurrently
+		GetMirrorChannel() chan bool
+
+		// CloneForMirroring clones the VCursor to execute a mirrored query, with a context
+		// detached from the one of the query and bounded by the mirror query timeout
+		CloneForMirroring(ctx context.Context) (context.Context, context.CancelFunc, VCursor)
+
+		// RecordMirrorStats records the latencies and the errors of a mirrored query in the
+		// source and target keyspaces
+		RecordMirrorStats(sourceKeyspace, targetKeyspace string, sourceTime, targetTime time.Duration, sourceErr, targetErr error)
+
+		// StartPrimitiveTrace starts collecting the execution statistics of all the primitives
+		// executed through this VCursor. The returned function stops the trace and returns them.
+		StartPrimitiveTrace() func() map[Primitive]PrimitiveStats
 	}
 
 	// SessionActions gives primitives ability to interact with the session state
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+// PrimitiveStats are the execution statistics of a primitive, as reported by VEXPLAIN TRACE.
+type PrimitiveStats struct {
+	// Calls is the number of times the primitive was executed.
+	Calls int
+	// Rows is the total number of rows returned by the primitive.
+	Rows int
+	// ShardCalls is the total number of shards the primitive sent queries to.
+	ShardCalls int
+	// WallTime is the total time spent executing the primitive, including its inputs.
+	WallTime time.Duration
+}
+
+// PrimitiveTracer collects the PrimitiveStats of the primitives executed by a VCursor.
+// It is safe for concurrent use. A nil PrimitiveTracer executes primitives without
+// collecting anything.
+type PrimitiveTracer struct {
+	mu    sync.Mutex
+	stats map[Primitive]*PrimitiveStats
+}
+
+// NewPrimitiveTracer returns a PrimitiveTracer with no statistics.
+func NewPrimitiveTracer() *PrimitiveTracer {
+	return &PrimitiveTracer{stats: make(map[Primitive]*PrimitiveStats)}
+}
+
+// TraceExecute runs exec, which executes the given primitive, and records its statistics.
+func (t *PrimitiveTracer) TraceExecute(primitive Primitive, exec func() (*sqltypes.Result, error)) (*sqltypes.Result, error) {
+	if t == nil {
+		return exec()
+	}
+	start := time.Now()
+	res, err := exec()
+	rows := 0
+	if res != nil {
+		rows = len(res.Rows)
+	}
+	t.record(primitive, func(stats *PrimitiveStats) {
+		stats.Calls++
+		stats.Rows += rows
+		stats.WallTime += time.Since(start)
+	})
+	return res, err
+}
+
+// TraceStreamExecute runs exec, which streams the results of the given primitive to the
+// callback it is given, and records the statistics of the primitive.
+func (t *PrimitiveTracer) TraceStreamExecute(primitive Primitive, callback func(*sqltypes.Result) error, exec func(callback func(*sqltypes.Result) error) error) error {
+	if t == nil {
+		return exec(callback)
+	}
+	start := time.Now()
+	var rows atomic.Int64
+	err := exec(func(result *sqltypes.Result) error {
+		rows.Add(int64(len(result.Rows)))
+		return callback(result)
+	})
+	t.record(primitive, func(stats *PrimitiveStats) {
+		stats.Calls++
+		stats.Rows += int(rows.Load())
+		stats.WallTime += time.Since(start)
+	})
+	return err
+}
+
+// RecordShardCalls records that the given primitive sent queries to a number of shards.
+func (t *PrimitiveTracer) RecordShardCalls(primitive Primitive, shards int) {
+	if t == nil || primitive == nil {
+		return
+	}
+	t.record(primitive, func(stats *PrimitiveStats) {
+		stats.ShardCalls += shards
+	})
+}
+
+// Stats returns a copy of the statistics collected so far.
+func (t *PrimitiveTracer) Stats() map[Primitive]PrimitiveStats {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := make(map[Primitive]PrimitiveStats, len(t.stats))
+	for primitive, s := range t.stats {
+		stats[primitive] = *s
+	}
+	return stats
+}
+
+func (t *PrimitiveTracer) record(primitive Primitive, update func(stats *PrimitiveStats)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats, ok := t.stats[primitive]
+	if !ok {
+		stats = &PrimitiveStats{}
+		t.stats[primitive] = stats
+	}
+	update(stats)
+}
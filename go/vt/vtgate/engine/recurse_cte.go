@@ -0,0 +1,231 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+var _ Primitive = (*RecurseCTE)(nil)
+
+// RecurseCTE is used to evaluate recursive common table expressions.
+// The Seed primitive produces the initial rows. The Term primitive is then executed
+// once for every row produced by the previous iteration, with the columns of that row
+// available as bind variables. This is repeated until an iteration produces no new rows.
+type RecurseCTE struct {
+	// Seed is the non-recursive part of the CTE
+	Seed Primitive
+	// Term is the recursive part of the CTE
+	Term Primitive
+
+	// Vars maps the bind variables used by the Term to the
+	// columns of the row from the previous iteration
+	Vars map[string]int `json:",omitempty"`
+
+	// Distinct is set when the CTE uses UNION DISTINCT. Rows that have already
+	// been produced are then discarded, and do not take part in the next iteration.
+	Distinct  bool       `json:",omitempty"`
+	CheckCols []CheckCol `json:",omitempty"`
+}
+
+// TryExecute implements the Primitive interface
+func (r *RecurseCTE) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	res, err := vcursor.ExecutePrimitive(ctx, r.Seed, bindVars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+
+	pt := r.newProbeTable(vcursor)
+	seedRows, err := pt.filter(res.Rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &sqltypes.Result{Fields: res.Fields}
+	result.Rows = append(result.Rows, seedRows...)
+	err = r.recurse(ctx, vcursor, bindVars, pt, seedRows, func(rows []sqltypes.Row) error {
+		result.Rows = append(result.Rows, rows...)
+		if vcursor.ExceedsMaxMemoryRows(len(result.Rows)) {
+			return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (r *RecurseCTE) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	var mu sync.Mutex
+
+	pt := r.newProbeTable(vcursor)
+	var seedRows []sqltypes.Row
+	err := vcursor.StreamExecutePrimitive(ctx, r.Seed, bindVars, wantfields, func(qr *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		rows, err := pt.filter(qr.Rows)
+		if err != nil {
+			return err
+		}
+		seedRows = append(seedRows, rows...)
+		return callback(&sqltypes.Result{Fields: qr.Fields, Rows: rows})
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.recurse(ctx, vcursor, bindVars, pt, seedRows, func(rows []sqltypes.Row) error {
+		return callback(&sqltypes.Result{Rows: rows})
+	})
+}
+
+// recurse runs the Term until an iteration produces no new rows. The rows produced by
+// every iteration are handed to the callback before the next iteration starts.
+func (r *RecurseCTE) recurse(
+	ctx context.Context,
+	vcursor VCursor,
+	bindVars map[string]*querypb.BindVariable,
+	pt *cteProbeTable,
+	rows []sqltypes.Row,
+	callback func([]sqltypes.Row) error,
+) error {
+	maxDepth := vcursor.CTEMaxRecursionDepth()
+	for depth := 1; len(rows) > 0; depth++ {
+		if depth > maxDepth {
+			return vterrors.VT09025(depth)
+		}
+		var newRows []sqltypes.Row
+		for _, row := range rows {
+			termRows, err := r.executeTerm(ctx, vcursor, bindVars, row)
+			if err != nil {
+				return err
+			}
+			termRows, err = pt.filter(termRows)
+			if err != nil {
+				return err
+			}
+			newRows = append(newRows, termRows...)
+		}
+		if len(newRows) > 0 {
+			if err := callback(newRows); err != nil {
+				return err
+			}
+		}
+		rows = newRows
+	}
+	return nil
+}
+
+func (r *RecurseCTE) executeTerm(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, row sqltypes.Row) ([]sqltypes.Row, error) {
+	joinVars := make(map[string]*querypb.BindVariable, len(r.Vars))
+	for k, col := range r.Vars {
+		joinVars[k] = sqltypes.ValueBindVariable(row[col])
+	}
+	res, err := vcursor.ExecutePrimitive(ctx, r.Term, combineVars(bindVars, joinVars), false)
+	if err != nil {
+		return nil, err
+	}
+	return res.Rows, nil
+}
+
+// cteProbeTable discards rows that have been seen before when the CTE is using UNION DISTINCT
+type cteProbeTable struct {
+	pt *probeTable
+}
+
+func (r *RecurseCTE) newProbeTable(vcursor VCursor) *cteProbeTable {
+	if !r.Distinct {
+		return &cteProbeTable{}
+	}
+	return &cteProbeTable{pt: newProbeTable(r.CheckCols, vcursor.Environment().CollationEnv())}
+}
+
+func (c *cteProbeTable) filter(rows []sqltypes.Row) ([]sqltypes.Row, error) {
+	if c.pt == nil {
+		return rows, nil
+	}
+	var result []sqltypes.Row
+	for _, row := range rows {
+		newRow, err := c.pt.exists(row)
+		if err != nil {
+			return nil, err
+		}
+		if newRow != nil {
+			result = append(result, newRow)
+		}
+	}
+	return result, nil
+}
+
+// GetFields implements the Primitive interface
+func (r *RecurseCTE) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return r.Seed.GetFields(ctx, vcursor, bindVars)
+}
+
+// Inputs implements the Primitive interface
+func (r *RecurseCTE) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{r.Seed, r.Term}, []map[string]any{{
+		inputName: "Seed",
+	}, {
+		inputName: "Term",
+	}}
+}
+
+// RouteType implements the Primitive interface
+func (r *RecurseCTE) RouteType() string {
+	return "RecurseCTE"
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (r *RecurseCTE) GetKeyspaceName() string {
+	if r.Seed.GetKeyspaceName() == r.Term.GetKeyspaceName() {
+		return r.Seed.GetKeyspaceName()
+	}
+	return r.Seed.GetKeyspaceName() + "_" + r.Term.GetKeyspaceName()
+}
+
+// GetTableName implements the Primitive interface
+func (r *RecurseCTE) GetTableName() string {
+	return r.Seed.GetTableName()
+}
+
+// NeedsTransaction implements the Primitive interface
+func (r *RecurseCTE) NeedsTransaction() bool {
+	return r.Seed.NeedsTransaction() || r.Term.NeedsTransaction()
+}
+
+func (r *RecurseCTE) description() PrimitiveDescription {
+	other := map[string]any{}
+	if len(r.Vars) > 0 {
+		other["JoinVars"] = orderedStringIntMap(r.Vars)
+	}
+	if r.Distinct {
+		other["Distinct"] = true
+	}
+	return PrimitiveDescription{
+		OperatorType: "RecurseCTE",
+		Other:        other,
+	}
+}
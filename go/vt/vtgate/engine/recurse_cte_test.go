@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func TestRecurseCTEExecute(t *testing.T) {
+	fields := sqltypes.MakeTestFields("n", "int64")
+	seed := &fakePrimitive{results: []*sqltypes.Result{
+		sqltypes.MakeTestResult(fields, "1", "10"),
+	}}
+	term := &fakePrimitive{results: []*sqltypes.Result{
+		sqltypes.MakeTestResult(fields, "2"),
+		sqltypes.MakeTestResult(fields, "11"),
+		sqltypes.MakeTestResult(fields, "3"),
+		sqltypes.MakeTestResult(fields),
+		sqltypes.MakeTestResult(fields),
+	}}
+	cte := &RecurseCTE{
+		Seed: seed,
+		Term: term,
+		Vars: map[string]int{"cte_n": 0},
+	}
+
+	qr, err := cte.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	require.Equal(t, fields, qr.Fields)
+	require.Equal(t, `[[INT64(1)] [INT64(10)] [INT64(2)] [INT64(11)] [INT64(3)]]`, fmt.Sprintf("%v", qr.Rows))
+	require.Equal(t, []string{
+		`Execute cte_n: type:INT64 value:"1" false`,
+		`Execute cte_n: type:INT64 value:"10" false`,
+		`Execute cte_n: type:INT64 value:"2" false`,
+		`Execute cte_n: type:INT64 value:"11" false`,
+		`Execute cte_n: type:INT64 value:"3" false`,
+	}, term.log)
+
+	seed.rewind()
+	term.rewind()
+	var rows []string
+	err = cte.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		rows = append(rows, fmt.Sprintf("%v", qr.Rows))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{`[]`, `[[INT64(1)] [INT64(10)]]`, `[[INT64(2)] [INT64(11)]]`, `[[INT64(3)]]`}, rows)
+}
+
+func TestRecurseCTEDistinct(t *testing.T) {
+	fields := sqltypes.MakeTestFields("n", "int64")
+	cte := &RecurseCTE{
+		Seed: &fakePrimitive{results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "1", "2", "1"),
+		}},
+		Term: &fakePrimitive{results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "2"),
+			sqltypes.MakeTestResult(fields, "3", "1"),
+			sqltypes.MakeTestResult(fields, "2"),
+		}},
+		Vars:     map[string]int{"cte_n": 0},
+		Distinct: true,
+		CheckCols: []CheckCol{{
+			Col:          0,
+			Type:         evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID),
+			CollationEnv: collations.MySQL8(),
+		}},
+	}
+
+	qr, err := cte.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	require.Equal(t, `[[INT64(1)] [INT64(2)] [INT64(3)]]`, fmt.Sprintf("%v", qr.Rows))
+}
+
+func TestRecurseCTEMaxRecursionDepth(t *testing.T) {
+	saveMax := testCTEMaxRecursionDepth
+	testCTEMaxRecursionDepth = 3
+	defer func() {
+		testCTEMaxRecursionDepth = saveMax
+	}()
+
+	fields := sqltypes.MakeTestFields("n", "int64")
+	results := []*sqltypes.Result{sqltypes.MakeTestResult(fields, "1")}
+	for i := 2; i < 10; i++ {
+		results = append(results, sqltypes.MakeTestResult(fields, fmt.Sprintf("%d", i)))
+	}
+	cte := &RecurseCTE{
+		Seed: &fakePrimitive{results: results[:1]},
+		Term: &fakePrimitive{results: results[1:]},
+		Vars: map[string]int{"cte_n": 0},
+	}
+
+	_, err := cte.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.EqualError(t, err, "VT09025: recursive query aborted after 4 iterations; try increasing --cte-max-recursion-depth to a larger value")
+}
+
+func TestRecurseCTEStreamAsync(t *testing.T) {
+	fields := sqltypes.MakeTestFields("n", "int64")
+	var seedResults, termResults []*sqltypes.Result
+	for i := 1; i <= 10; i++ {
+		seedResults = append(seedResults, sqltypes.MakeTestResult(fields, fmt.Sprintf("%d", i), fmt.Sprintf("%d", i+1), "1"))
+	}
+	for i := 1; i <= 11; i++ {
+		termResults = append(termResults, sqltypes.MakeTestResult(fields))
+	}
+	cte := &RecurseCTE{
+		Seed:     &fakePrimitive{results: seedResults, async: true},
+		Term:     &fakePrimitive{results: termResults},
+		Vars:     map[string]int{"cte_n": 0},
+		Distinct: true,
+		CheckCols: []CheckCol{{
+			Col:          0,
+			Type:         evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID),
+			CollationEnv: collations.MySQL8(),
+		}},
+	}
+
+	var rows []sqltypes.Row
+	err := cte.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		rows = append(rows, qr.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 11)
+}
@@ -19,6 +19,7 @@ package engine
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,7 +28,9 @@ import (
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/sqlparser"
 	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 )
 
 func TestEmptyRows(outer *testing.T) {
@@ -415,3 +418,117 @@ func TestScalarGroupConcat(t *testing.T) {
 		})
 	}
 }
+
+// TestScalarGroupConcatOptions tests group_concat evaluated on the engine with a separator,
+// multiple columns, distinct values and a maximum length set on the session.
+func TestScalarGroupConcatOptions(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2",
+		"varchar|varchar",
+	)
+	input := sqltypes.MakeTestResult(fields,
+		"a|x", "a|x", "a|y", "b|null", "b|z")
+
+	var tcases = []struct {
+		name    string
+		params  *AggregateParams
+		sysVars map[string]string
+		expRow  string
+	}{{
+		name: "separator",
+		params: &AggregateParams{
+			Opcode: AggregateGroupConcat,
+			Expr:   &sqlparser.GroupConcatExpr{Separator: "'--'"},
+		},
+		expRow: `[[TEXT("a--a--a--b--b")]]`,
+	}, {
+		name: "empty separator",
+		params: &AggregateParams{
+			Opcode: AggregateGroupConcat,
+			Expr:   &sqlparser.GroupConcatExpr{Separator: "''"},
+		},
+		expRow: `[[TEXT("aaabb")]]`,
+	}, {
+		name: "multiple columns",
+		params: &AggregateParams{
+			Opcode:    AggregateGroupConcat,
+			ExtraCols: []CheckCol{{Col: 1}},
+		},
+		expRow: `[[TEXT("ax,ax,ay,bz")]]`,
+	}, {
+		name: "distinct over multiple columns",
+		params: &AggregateParams{
+			Opcode:       AggregateGroupConcatDistinct,
+			ExtraCols:    []CheckCol{{Col: 1, Type: evalengine.NewType(sqltypes.VarChar, collations.CollationUtf8mb4ID)}},
+			Type:         evalengine.NewType(sqltypes.VarChar, collations.CollationUtf8mb4ID),
+			CollationEnv: collations.MySQL8(),
+		},
+		expRow: `[[TEXT("ax,ay,bz")]]`,
+	}, {
+		name: "group_concat_max_len",
+		params: &AggregateParams{
+			Opcode: AggregateGroupConcat,
+		},
+		sysVars: map[string]string{"group_concat_max_len": "6"},
+		expRow:  `[[TEXT("a,a,a,")]]`,
+	}}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			fp := &fakePrimitive{results: []*sqltypes.Result{input}}
+			sa := &ScalarAggregate{
+				Aggregates:          []*AggregateParams{tcase.params},
+				Input:               fp,
+				TruncateColumnCount: 1,
+			}
+			vc := &loggingVCursor{systemVariables: tcase.sysVars}
+			qr, err := sa.TryExecute(context.Background(), vc, nil, false)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.expRow, fmt.Sprintf("%v", qr.Rows))
+		})
+	}
+}
+
+// TestScalarGroupConcatUnsetMaxLen tests that group_concat is not truncated on the vtgate
+// when group_concat_max_len is not set on the session, as the tablets may use a larger value.
+func TestScalarGroupConcatUnsetMaxLen(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1",
+		"varchar",
+	)
+	var rows []string
+	for i := 0; i < 300; i++ {
+		rows = append(rows, "abcd")
+	}
+	fp := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, rows...)}}
+	sa := &ScalarAggregate{
+		Aggregates: []*AggregateParams{{
+			Opcode: AggregateGroupConcat,
+		}},
+		Input:               fp,
+		TruncateColumnCount: 1,
+	}
+	qr, err := sa.TryExecute(context.Background(), &loggingVCursor{}, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("abcd,", 300)[:300*5-1], qr.Rows[0][0].ToString())
+}
+
+func TestScalarCountDistinctMultipleColumns(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2",
+		"int64|int64",
+	)
+	fp := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields,
+		"1|1", "1|1", "1|2", "1|null", "2|2", "null|2")}}
+	sa := &ScalarAggregate{
+		Aggregates: []*AggregateParams{{
+			Opcode:    AggregateCountDistinct,
+			ExtraCols: []CheckCol{{Col: 1}},
+		}},
+		Input:               fp,
+		TruncateColumnCount: 1,
+	}
+	qr, err := sa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, `[[INT64(3)]]`, fmt.Sprintf("%v", qr.Rows))
+}
@@ -22,8 +22,10 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/key"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -41,6 +43,13 @@ type Send struct {
 	// TargetDestination specifies an explicit target destination to send the query to.
 	TargetDestination key.Destination
 
+	// Vindex, if set, maps the value of Values to the shards to send the query to,
+	// instead of TargetDestination.
+	Vindex vindexes.SingleColumn
+
+	// Values specifies the vindex value to use for routing.
+	Values []evalengine.Expr
+
 	// Query specifies the query to be executed.
 	Query string
 
@@ -91,7 +100,7 @@ func (s *Send) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[str
 	ctx, cancelFunc := addQueryTimeout(ctx, vcursor, 0)
 	defer cancelFunc()
 
-	rss, err := s.checkAndReturnShards(ctx, vcursor)
+	rss, err := s.checkAndReturnShards(ctx, vcursor, bindVars)
 	if err != nil {
 		return nil, err
 	}
@@ -118,8 +127,8 @@ func (s *Send) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[str
 	return result, nil
 }
 
-func (s *Send) checkAndReturnShards(ctx context.Context, vcursor VCursor) ([]*srvtopo.ResolvedShard, error) {
-	rss, _, err := vcursor.ResolveDestinations(ctx, s.Keyspace.Name, nil, []key.Destination{s.TargetDestination})
+func (s *Send) checkAndReturnShards(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) ([]*srvtopo.ResolvedShard, error) {
+	rss, err := s.resolveShards(ctx, vcursor, bindVars)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +147,20 @@ func (s *Send) checkAndReturnShards(ctx context.Context, vcursor VCursor) ([]*sr
 	return rss, nil
 }
 
+func (s *Send) resolveShards(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) ([]*srvtopo.ResolvedShard, error) {
+	if s.Vindex == nil {
+		rss, _, err := vcursor.ResolveDestinations(ctx, s.Keyspace.Name, nil, []key.Destination{s.TargetDestination})
+		return rss, err
+	}
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	value, err := env.Evaluate(s.Values[0])
+	if err != nil {
+		return nil, err
+	}
+	rss, _, err := resolveShards(ctx, vcursor, s.Vindex, s.Keyspace, []sqltypes.Value{value.Value(vcursor.ConnCollation())})
+	return rss, err
+}
+
 func (s *Send) canAutoCommit(vcursor VCursor, rss []*srvtopo.ResolvedShard) bool {
 	if s.IsDML {
 		return (len(rss) == 1 || s.MultishardAutocommit) && vcursor.AutocommitApproval()
@@ -155,7 +178,7 @@ func copyBindVars(in map[string]*querypb.BindVariable) map[string]*querypb.BindV
 
 // TryStreamExecute implements Primitive interface
 func (s *Send) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
-	rss, err := s.checkAndReturnShards(ctx, vcursor)
+	rss, err := s.checkAndReturnShards(ctx, vcursor, bindVars)
 	if err != nil {
 		return err
 	}
@@ -193,6 +216,14 @@ func (s *Send) description() PrimitiveDescription {
 		"MultishardAutocommit":     s.MultishardAutocommit,
 		"ReservedConnectionNeeded": s.ReservedConnectionNeeded,
 	}
+	if s.Vindex != nil {
+		other["Vindex"] = s.Vindex.String()
+		formattedValues := make([]string, 0, len(s.Values))
+		for _, value := range s.Values {
+			formattedValues = append(formattedValues, sqlparser.String(value))
+		}
+		other["Values"] = formattedValues
+	}
 	return PrimitiveDescription{
 		OperatorType:      "Send",
 		Keyspace:          s.Keyspace,
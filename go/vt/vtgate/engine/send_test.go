@@ -21,12 +21,14 @@ import (
 	"errors"
 	"testing"
 
+	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/sqltypes"
 
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/vt/key"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
@@ -318,3 +320,32 @@ func TestSendGetFields(t *testing.T) {
 	require.Nil(t, qr.Rows)
 	require.Equal(t, 4, len(qr.Fields))
 }
+
+func TestSendVindex(t *testing.T) {
+	vindex, _ := vindexes.CreateVindex("hash", "", nil)
+	send := &Send{
+		Keyspace: &vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: true,
+		},
+		Query:  "call proc(:arg)",
+		Vindex: vindex.(vindexes.SingleColumn),
+		Values: []evalengine.Expr{evalengine.NewBindVar("arg", evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID))},
+	}
+	bindVars := map[string]*querypb.BindVariable{"arg": sqltypes.Int64BindVariable(1)}
+	vc := &loggingVCursor{shards: []string{"-20", "20-"}, results: []*sqltypes.Result{defaultSelectResult}}
+	_, err := send.TryExecute(context.Background(), vc, bindVars, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [type:INT64 value:"1"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6)`,
+		`ExecuteMultiShard ks.-20: call proc(:arg) {arg: type:INT64 value:"1"} false false`,
+	})
+
+	vc.Rewind()
+	_, err = wrapStreamExecute(send, vc, bindVars, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [type:INT64 value:"1"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6)`,
+		`StreamExecuteMulti call proc(:arg) ks.-20: {arg: type:INT64 value:"1"} `,
+	})
+}
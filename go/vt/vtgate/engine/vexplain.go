@@ -73,6 +73,15 @@ func (v *VExplain) NeedsTransaction() bool {
 
 // TryExecute implements the Primitive interface
 func (v *VExplain) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if v.Type == sqlparser.TraceVExplainType {
+		stopTrace := vcursor.StartPrimitiveTrace()
+		_, err := vcursor.ExecutePrimitive(ctx, v.Input, bindVars, wantfields)
+		stats := stopTrace()
+		if err != nil {
+			return nil, err
+		}
+		return v.convertToVExplainTraceResult(stats)
+	}
 	vcursor.Session().VExplainLogging()
 	_, err := vcursor.ExecutePrimitive(ctx, v.Input, bindVars, wantfields)
 	if err != nil {
@@ -83,6 +92,21 @@ func (v *VExplain) TryExecute(ctx context.Context, vcursor VCursor, bindVars map
 
 // TryStreamExecute implements the Primitive interface
 func (v *VExplain) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	if v.Type == sqlparser.TraceVExplainType {
+		stopTrace := vcursor.StartPrimitiveTrace()
+		err := vcursor.StreamExecutePrimitive(ctx, v.Input, bindVars, wantfields, func(result *sqltypes.Result) error {
+			return nil
+		})
+		stats := stopTrace()
+		if err != nil {
+			return err
+		}
+		result, err := v.convertToVExplainTraceResult(stats)
+		if err != nil {
+			return err
+		}
+		return callback(result)
+	}
 	vcursor.Session().VExplainLogging()
 	err := vcursor.StreamExecutePrimitive(ctx, v.Input, bindVars, wantfields, func(result *sqltypes.Result) error {
 		return nil
@@ -138,6 +162,34 @@ func (v *VExplain) convertToVExplainAllResult(ctx context.Context, vcursor VCurs
 	}
 
 	planDescription := primitiveToPlanDescriptionWithSQLResults(v.Input, explainResults)
+	return planDescriptionToVExplainResult(planDescription)
+}
+
+// traceDescription is how the PrimitiveStats of a primitive are shown in the VEXPLAIN TRACE output
+type traceDescription struct {
+	Calls      int
+	Rows       int
+	ShardCalls int `json:",omitempty"`
+	WallTime   string
+}
+
+func (v *VExplain) convertToVExplainTraceResult(stats map[Primitive]PrimitiveStats) (*sqltypes.Result, error) {
+	planDescription := annotatedPlanDescription(v.Input, func(in Primitive, pd *PrimitiveDescription) {
+		s, found := stats[in]
+		if !found {
+			return
+		}
+		pd.addOther("Trace", traceDescription{
+			Calls:      s.Calls,
+			Rows:       s.Rows,
+			ShardCalls: s.ShardCalls,
+			WallTime:   s.WallTime.String(),
+		})
+	})
+	return planDescriptionToVExplainResult(planDescription)
+}
+
+func planDescriptionToVExplainResult(planDescription PrimitiveDescription) (*sqltypes.Result, error) {
 	resultBytes, err := json.MarshalIndent(planDescription, "", "\t")
 	if err != nil {
 		return nil, err
@@ -164,22 +216,29 @@ func (v *VExplain) convertToVExplainAllResult(ctx context.Context, vcursor VCurs
 // primitiveToPlanDescriptionWithSQLResults transforms a primitive tree into a corresponding PlanDescription tree
 // and adds the given res ...
 func primitiveToPlanDescriptionWithSQLResults(in Primitive, res map[Primitive]string) PrimitiveDescription {
-	this := in.description()
+	return annotatedPlanDescription(in, func(in Primitive, pd *PrimitiveDescription) {
+		if v, found := res[in]; found {
+			pd.addOther("mysql_explain_json", json.RawMessage(v))
+		}
+	})
+}
 
-	if v, found := res[in]; found {
-		this.Other["mysql_explain_json"] = json.RawMessage(v)
-	}
+// annotatedPlanDescription transforms a primitive tree into a corresponding PlanDescription tree,
+// calling annotate on the description of every primitive
+func annotatedPlanDescription(in Primitive, annotate func(in Primitive, pd *PrimitiveDescription)) PrimitiveDescription {
+	this := in.description()
+	annotate(in, &this)
 
 	inputs, infos := in.Inputs()
 	for idx, input := range inputs {
-		pd := primitiveToPlanDescriptionWithSQLResults(input, res)
+		pd := annotatedPlanDescription(input, annotate)
 		if infos != nil {
 			for k, v := range infos[idx] {
 				if k == inputName {
 					pd.InputName = v.(string)
 					continue
 				}
-				pd.Other[k] = v
+				pd.addOther(k, v)
 			}
 		}
 		this.Inputs = append(this.Inputs, pd)
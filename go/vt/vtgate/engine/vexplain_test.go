@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestVExplainTrace(t *testing.T) {
+	route := NewRoute(
+		Scatter,
+		&vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: true,
+		},
+		"dummy_select",
+		"dummy_select_field",
+	)
+	vexplain := &VExplain{
+		Type: sqlparser.TraceVExplainType,
+		Input: &Limit{
+			Count: evalengine.NewLiteralInt(2),
+			Input: route,
+		},
+	}
+	// Limit truncates the result of its input, so every execution needs its own.
+	inputResult := func() *sqltypes.Result {
+		return sqltypes.MakeTestResult(sqltypes.MakeTestFields("col1", "int64"), "1", "2", "3")
+	}
+
+	type trace struct {
+		Calls      int
+		Rows       int
+		ShardCalls int
+		WallTime   string
+	}
+	type plan struct {
+		OperatorType string
+		Trace        trace
+		Inputs       []plan
+	}
+	assertTrace := func(t *testing.T, result *sqltypes.Result) {
+		require.Len(t, result.Rows, 1)
+		var got plan
+		require.NoError(t, json.Unmarshal([]byte(result.Rows[0][0].ToString()), &got))
+
+		assert.Equal(t, "Limit", got.OperatorType)
+		assert.Equal(t, 1, got.Trace.Calls)
+		assert.Equal(t, 2, got.Trace.Rows)
+		assert.Zero(t, got.Trace.ShardCalls)
+		assert.NotEmpty(t, got.Trace.WallTime)
+
+		require.Len(t, got.Inputs, 1)
+		assert.Equal(t, "Route", got.Inputs[0].OperatorType)
+		assert.Equal(t, 1, got.Inputs[0].Trace.Calls)
+		assert.Equal(t, 3, got.Inputs[0].Trace.Rows)
+		assert.Equal(t, 2, got.Inputs[0].Trace.ShardCalls)
+	}
+
+	vc := &loggingVCursor{
+		shards:  []string{"-20", "20-"},
+		results: []*sqltypes.Result{inputResult()},
+	}
+	result, err := vexplain.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	assertTrace(t, result)
+	assert.Nil(t, vc.tracer, "the trace is stopped")
+
+	vc.Rewind()
+	vc.results = []*sqltypes.Result{inputResult()}
+	result, err = wrapStreamExecute(vexplain, vc, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	assertTrace(t, result)
+	assert.Nil(t, vc.tracer, "the trace is stopped")
+}
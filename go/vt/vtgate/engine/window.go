@@ -0,0 +1,644 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*Window)(nil)
+
+// Window is a primitive that evaluates window functions at the vtgate level.
+// It is used when the rows of a window partition can come from more than one shard.
+// All input rows are buffered, sorted by the window specification of each function,
+// and the window function results are added in front of the input columns.
+type Window struct {
+	noTxNeeded
+
+	Functions []*WindowFunc
+	Input     Primitive
+}
+
+type (
+	// WindowFunc specifies a single window function evaluated by the Window primitive
+	WindowFunc struct {
+		Opcode WindowOpcode
+		// AggrOpcode is the aggregation evaluated over the frame when Opcode is WindowAggregate
+		AggrOpcode AggregateOpcode
+		// Col is the offset of the function argument in the input, or -1 if the function has no argument
+		Col int
+		// N is the integer argument of NTILE, NTH_VALUE, LAG and LEAD
+		N evalengine.Expr
+		// Default is returned by LAG and LEAD when there is no row at the requested offset
+		Default evalengine.Expr
+		// Avg calculates the average from a row containing the sum and the count of the frame.
+		// It is only used by AVG
+		Avg evalengine.Expr
+
+		PartitionBy evalengine.Comparison
+		OrderBy     evalengine.Comparison
+		// Frame is nil when the default frame is used
+		Frame *WindowFrame
+
+		// Type is the type of the function argument
+		Type         evalengine.Type
+		Alias        string `json:",omitempty"`
+		Expr         sqlparser.Expr
+		CollationEnv *collations.Environment
+	}
+
+	// WindowFrame is the frame clause of a window specification
+	WindowFrame struct {
+		Unit  sqlparser.FrameUnitType
+		Start WindowFramePoint
+		End   WindowFramePoint
+	}
+
+	// WindowFramePoint is one of the boundaries of a window frame.
+	// Offset is only set for the ExprPrecedingType and ExprFollowingType boundaries.
+	WindowFramePoint struct {
+		Type   sqlparser.FramePointType
+		Offset evalengine.Expr
+	}
+
+	// windowPartition holds the rows of a single partition, in window order
+	windowPartition struct {
+		rows []sqltypes.Row
+		// peerStart and peerEnd hold, for every row, the boundaries of the group of rows
+		// that are equal to it according to the ORDER BY of the window
+		peerStart, peerEnd []int
+	}
+)
+
+// RouteType implements the Primitive interface
+func (w *Window) RouteType() string {
+	return w.Input.RouteType()
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (w *Window) GetKeyspaceName() string {
+	return w.Input.GetKeyspaceName()
+}
+
+// GetTableName implements the Primitive interface
+func (w *Window) GetTableName() string {
+	return w.Input.GetTableName()
+}
+
+// TryExecute implements the Primitive interface
+func (w *Window) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	result, err := vcursor.ExecutePrimitive(ctx, w.Input, bindVars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+	return w.evaluate(ctx, vcursor, bindVars, result)
+}
+
+// TryStreamExecute implements the Primitive interface
+func (w *Window) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	var mu sync.Mutex
+	result := &sqltypes.Result{}
+	err := vcursor.StreamExecutePrimitive(ctx, w.Input, bindVars, wantfields, func(qr *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(qr.Fields) != 0 {
+			result.Fields = qr.Fields
+		}
+		result.Rows = append(result.Rows, qr.Rows...)
+		if vcursor.ExceedsMaxMemoryRows(len(result.Rows)) {
+			return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	out, err := w.evaluate(ctx, vcursor, bindVars, result)
+	if err != nil {
+		return err
+	}
+	return callback(out)
+}
+
+// GetFields implements the Primitive interface
+func (w *Window) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	qr, err := w.Input.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: w.fields(qr.Fields)}, nil
+}
+
+// Inputs implements the Primitive interface
+func (w *Window) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{w.Input}, nil
+}
+
+func (w *Window) description() PrimitiveDescription {
+	return PrimitiveDescription{
+		OperatorType: "Window",
+		Other: map[string]any{
+			"Functions": GenericJoin(w.Functions, windowFuncToString),
+		},
+	}
+}
+
+func windowFuncToString(i any) string {
+	return i.(*WindowFunc).String()
+}
+
+func (w *Window) fields(input []*querypb.Field) []*querypb.Field {
+	if input == nil {
+		return nil
+	}
+	fields := make([]*querypb.Field, 0, len(w.Functions)+len(input))
+	for _, fn := range w.Functions {
+		argType := sqltypes.Null
+		if fn.Col >= 0 && fn.Col < len(input) {
+			argType = input[fn.Col].Type
+		}
+		fields = append(fields, &querypb.Field{
+			Name: fn.Alias,
+			Type: fn.sqlType(argType),
+		})
+	}
+	return append(fields, input...)
+}
+
+func (w *Window) evaluate(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, input *sqltypes.Result) (_ *sqltypes.Result, err error) {
+	// comparing values can panic, so we need to protect ourselves here
+	defer evalengine.PanicHandler(&err)
+
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	rows := input.Rows
+	values := make([][]sqltypes.Value, len(w.Functions))
+	var order []int
+	for i, fn := range w.Functions {
+		order = fn.sort(rows)
+		values[i], err = fn.evaluate(env, vcursor.ConnCollation(), input.Fields, rows, order)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the output is returned in the order of the last window evaluated
+	out := &sqltypes.Result{
+		Fields: w.fields(input.Fields),
+		Rows:   make([]sqltypes.Row, 0, len(rows)),
+	}
+	for _, idx := range order {
+		row := make(sqltypes.Row, 0, len(w.Functions)+len(rows[idx]))
+		for i := range w.Functions {
+			row = append(row, values[i][idx])
+		}
+		out.Rows = append(out.Rows, append(row, rows[idx]...))
+	}
+	return out, nil
+}
+
+func (fn *WindowFunc) sqlType(argType querypb.Type) querypb.Type {
+	if fn.Opcode != WindowAggregate {
+		return fn.Opcode.SQLType(argType)
+	}
+	return fn.AggrOpcode.SQLType(argType)
+}
+
+// sort returns the offsets of the input rows, ordered by the PARTITION BY and ORDER BY of the window
+func (fn *WindowFunc) sort(rows []sqltypes.Row) []int {
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+	}
+	cmp := append(slices.Clone(fn.PartitionBy), fn.OrderBy...)
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(rows[a], rows[b])
+	})
+	return order
+}
+
+// evaluate calculates the value of the window function for every input row.
+// The values are returned in the same order as the input rows.
+func (fn *WindowFunc) evaluate(env *evalengine.ExpressionEnv, coll collations.ID, fields []*querypb.Field, rows []sqltypes.Row, order []int) ([]sqltypes.Value, error) {
+	out := make([]sqltypes.Value, len(rows))
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && fn.PartitionBy.Compare(rows[order[start]], rows[order[end]]) == 0 {
+			end++
+		}
+
+		partition := fn.newPartition(rows, order[start:end])
+		values, err := fn.evaluatePartition(env, coll, fields, partition)
+		if err != nil {
+			return nil, err
+		}
+		for i, idx := range order[start:end] {
+			out[idx] = values[i]
+		}
+		start = end
+	}
+	return out, nil
+}
+
+func (fn *WindowFunc) newPartition(rows []sqltypes.Row, order []int) *windowPartition {
+	p := &windowPartition{
+		rows:      make([]sqltypes.Row, len(order)),
+		peerStart: make([]int, len(order)),
+		peerEnd:   make([]int, len(order)),
+	}
+	for i, idx := range order {
+		p.rows[i] = rows[idx]
+	}
+	for start := 0; start < len(p.rows); {
+		end := start + 1
+		for end < len(p.rows) && fn.OrderBy.Compare(p.rows[start], p.rows[end]) == 0 {
+			end++
+		}
+		for i := start; i < end; i++ {
+			p.peerStart[i] = start
+			p.peerEnd[i] = end
+		}
+		start = end
+	}
+	return p
+}
+
+func (fn *WindowFunc) evaluatePartition(env *evalengine.ExpressionEnv, coll collations.ID, fields []*querypb.Field, p *windowPartition) ([]sqltypes.Value, error) {
+	n := len(p.rows)
+	out := make([]sqltypes.Value, n)
+	switch fn.Opcode {
+	case WindowRowNumber:
+		for i := range out {
+			out[i] = sqltypes.NewUint64(uint64(i + 1))
+		}
+	case WindowRank:
+		for i := range out {
+			out[i] = sqltypes.NewUint64(uint64(p.peerStart[i] + 1))
+		}
+	case WindowDenseRank:
+		var rank uint64
+		for i := range out {
+			if p.peerStart[i] == i {
+				rank++
+			}
+			out[i] = sqltypes.NewUint64(rank)
+		}
+	case WindowPercentRank:
+		for i := range out {
+			var pr float64
+			if n > 1 {
+				pr = float64(p.peerStart[i]) / float64(n-1)
+			}
+			out[i] = sqltypes.NewFloat64(pr)
+		}
+	case WindowCumeDist:
+		for i := range out {
+			out[i] = sqltypes.NewFloat64(float64(p.peerEnd[i]) / float64(n))
+		}
+	case WindowNtile:
+		buckets, err := fn.evalInt(env, fn.N)
+		if err != nil {
+			return nil, err
+		}
+		if buckets <= 0 {
+			return nil, vterrors.VT03011(fmt.Sprintf("%d for ntile", buckets))
+		}
+		size, extra := int64(n)/buckets, int64(n)%buckets
+		for i := range out {
+			row := int64(i)
+			var bucket int64
+			if row < extra*(size+1) {
+				bucket = row/(size+1) + 1
+			} else {
+				bucket = (row-extra)/size + 1
+			}
+			out[i] = sqltypes.NewUint64(uint64(bucket))
+		}
+	case WindowLag, WindowLead:
+		offset := int64(1)
+		if fn.N != nil {
+			var err error
+			if offset, err = fn.evalInt(env, fn.N); err != nil {
+				return nil, err
+			}
+		}
+		if fn.Opcode == WindowLag {
+			offset = -offset
+		}
+		for i := range out {
+			target := int64(i) + offset
+			if target >= 0 && target < int64(n) {
+				out[i] = p.rows[target][fn.Col]
+				continue
+			}
+			if fn.Default == nil {
+				out[i] = sqltypes.NULL
+				continue
+			}
+			env.Row = p.rows[i]
+			res, err := env.Evaluate(fn.Default)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = res.Value(coll)
+		}
+	case WindowFirstValue, WindowLastValue, WindowNthValue:
+		return fn.evaluateValueInFrame(env, p)
+	case WindowAggregate:
+		return fn.evaluateAggregate(env, coll, fields, p)
+	default:
+		return nil, vterrors.VT13001(fmt.Sprintf("unexpected window function opcode: %s", fn.Opcode.String()))
+	}
+	return out, nil
+}
+
+func (fn *WindowFunc) evaluateValueInFrame(env *evalengine.ExpressionEnv, p *windowPartition) ([]sqltypes.Value, error) {
+	start, end, err := fn.frameOffsets(env)
+	if err != nil {
+		return nil, err
+	}
+	nth := int64(1)
+	if fn.Opcode == WindowNthValue {
+		if nth, err = fn.evalInt(env, fn.N); err != nil {
+			return nil, err
+		}
+		if nth <= 0 {
+			return nil, vterrors.VT03011(fmt.Sprintf("%d for nth_value", nth))
+		}
+	}
+
+	out := make([]sqltypes.Value, len(p.rows))
+	for i := range out {
+		from, to := fn.frame(p, i, start, end)
+		out[i] = sqltypes.NULL
+		switch {
+		case from >= to:
+		case fn.Opcode == WindowLastValue:
+			out[i] = p.rows[to-1][fn.Col]
+		case int64(from)+nth-1 < int64(to):
+			out[i] = p.rows[int64(from)+nth-1][fn.Col]
+		}
+	}
+	return out, nil
+}
+
+func (fn *WindowFunc) evaluateAggregate(env *evalengine.ExpressionEnv, coll collations.ID, fields []*querypb.Field, p *windowPartition) ([]sqltypes.Value, error) {
+	start, end, err := fn.frameOffsets(env)
+	if err != nil {
+		return nil, err
+	}
+	aggr, count, err := fn.newAggregator(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// when the frame always starts at the beginning of the partition, the frame end can only
+	// move forward, and we can keep adding rows to the aggregation instead of starting over
+	incremental := fn.Frame == nil || fn.Frame.Start.Type == sqlparser.UnboundedPrecedingType
+	added := 0
+
+	out := make([]sqltypes.Value, len(p.rows))
+	for i := range out {
+		from, to := fn.frame(p, i, start, end)
+		if !incremental {
+			aggr.reset()
+			if count != nil {
+				count.reset()
+			}
+			added = from
+		}
+		for ; added < to; added++ {
+			if err := aggr.add(p.rows[added]); err != nil {
+				return nil, err
+			}
+			if count != nil {
+				if err := count.add(p.rows[added]); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if count == nil {
+			out[i] = aggr.finish()
+			continue
+		}
+
+		// AVG is calculated by dividing the SUM with the COUNT of the frame
+		if count.n == 0 {
+			out[i] = sqltypes.NULL
+			continue
+		}
+		env.Row = []sqltypes.Value{aggr.finish(), count.finish()}
+		res, err := env.Evaluate(fn.Avg)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = res.Value(coll)
+	}
+	return out, nil
+}
+
+// newAggregator creates the aggregator used for the frame. For AVG, a second
+// aggregator counting the rows of the frame is returned as well.
+func (fn *WindowFunc) newAggregator(fields []*querypb.Field) (aggr aggregator, count *aggregatorCount, err error) {
+	sourceType := fn.Type.Type()
+	if sourceType == sqltypes.Unknown && fn.Col >= 0 && fn.Col < len(fields) {
+		sourceType = fields[fn.Col].Type
+	}
+	noDistinct := aggregatorDistinct{column: -1}
+
+	switch fn.AggrOpcode {
+	case AggregateCountStar:
+		return &aggregatorCountStar{}, nil, nil
+	case AggregateCount:
+		return &aggregatorCount{from: fn.Col, distinct: noDistinct}, nil, nil
+	case AggregateSum:
+		return &aggregatorSum{from: fn.Col, sum: evalengine.NewAggregationSum(sourceType), distinct: noDistinct}, nil, nil
+	case AggregateAvg:
+		if fn.Avg == nil {
+			return nil, nil, vterrors.VT13001("missing expression to calculate AVG")
+		}
+		sum := &aggregatorSum{from: fn.Col, sum: evalengine.NewAggregationSum(sourceType), distinct: noDistinct}
+		return sum, &aggregatorCount{from: fn.Col, distinct: noDistinct}, nil
+	case AggregateMin:
+		return &aggregatorMin{aggregatorMinMax{
+			from:   fn.Col,
+			minmax: evalengine.NewAggregationMinMax(sourceType, fn.CollationEnv, fn.Type.Collation()),
+		}}, nil, nil
+	case AggregateMax:
+		return &aggregatorMax{aggregatorMinMax{
+			from:   fn.Col,
+			minmax: evalengine.NewAggregationMinMax(sourceType, fn.CollationEnv, fn.Type.Collation()),
+		}}, nil, nil
+	default:
+		return nil, nil, vterrors.VT12001(fmt.Sprintf("window aggregation '%s' on vtgate", fn.AggrOpcode.String()))
+	}
+}
+
+// frameOffsets evaluates the offsets of the frame boundaries. They can only
+// depend on literals and bind variables, so they are the same for every row.
+func (fn *WindowFunc) frameOffsets(env *evalengine.ExpressionEnv) (start, end int64, err error) {
+	if fn.Frame == nil {
+		return 0, 0, nil
+	}
+	if fn.Frame.Start.Offset != nil {
+		if start, err = fn.evalInt(env, fn.Frame.Start.Offset); err != nil {
+			return 0, 0, err
+		}
+	}
+	if fn.Frame.End.Offset != nil {
+		if end, err = fn.evalInt(env, fn.Frame.End.Offset); err != nil {
+			return 0, 0, err
+		}
+	}
+	if start < 0 || end < 0 {
+		return 0, 0, vterrors.VT03011("negative window frame offset")
+	}
+	return start, end, nil
+}
+
+// frame returns the first row, and the row after the last, of the frame for row i of the partition
+func (fn *WindowFunc) frame(p *windowPartition, i int, startOffset, endOffset int64) (int, int) {
+	n := len(p.rows)
+	if fn.Frame == nil {
+		// without ORDER BY, the frame is the whole partition.
+		// Otherwise, it goes from the start of the partition to the last peer of the current row
+		if len(fn.OrderBy) == 0 {
+			return 0, n
+		}
+		return 0, p.peerEnd[i]
+	}
+
+	rows := fn.Frame.Unit == sqlparser.FrameRowsType
+	point := func(fp WindowFramePoint, offset int64, isEnd bool) int {
+		var pos int64
+		switch fp.Type {
+		case sqlparser.UnboundedPrecedingType:
+			return 0
+		case sqlparser.UnboundedFollowingType:
+			return n
+		case sqlparser.CurrentRowType:
+			if !rows {
+				if isEnd {
+					return p.peerEnd[i]
+				}
+				return p.peerStart[i]
+			}
+			pos = int64(i)
+		case sqlparser.ExprPrecedingType:
+			pos = int64(i) - offset
+		case sqlparser.ExprFollowingType:
+			pos = int64(i) + offset
+		}
+		if isEnd {
+			pos++
+		}
+		return int(max(0, min(pos, int64(n))))
+	}
+
+	return point(fn.Frame.Start, startOffset, false), point(fn.Frame.End, endOffset, true)
+}
+
+func (fn *WindowFunc) evalInt(env *evalengine.ExpressionEnv, expr evalengine.Expr) (int64, error) {
+	env.Row = nil
+	res, err := env.Evaluate(expr)
+	if err != nil {
+		return 0, err
+	}
+	value := res.Value(collations.Unknown)
+	if !value.IsIntegral() {
+		return 0, sqltypes.ErrIncompatibleTypeCast
+	}
+	return value.ToInt64()
+}
+
+// String returns a string. Used for plan descriptions
+func (fn *WindowFunc) String() string {
+	name := fn.Opcode.String()
+	if fn.Opcode == WindowAggregate {
+		name = fn.AggrOpcode.String()
+	}
+	var args []string
+	if fn.Col >= 0 {
+		args = append(args, strconv.Itoa(fn.Col))
+	}
+	if fn.N != nil {
+		args = append(args, sqlparser.String(fn.N))
+	}
+	if fn.Default != nil {
+		args = append(args, sqlparser.String(fn.Default))
+	}
+
+	var over []string
+	if len(fn.PartitionBy) > 0 {
+		partitions := make([]string, 0, len(fn.PartitionBy))
+		for _, p := range fn.PartitionBy {
+			col := strconv.Itoa(p.Col)
+			if p.WeightStringCol != -1 && p.WeightStringCol != p.Col {
+				col = fmt.Sprintf("(%s|%d)", col, p.WeightStringCol)
+			}
+			partitions = append(partitions, col)
+		}
+		over = append(over, "PARTITION BY "+strings.Join(partitions, ", "))
+	}
+	if len(fn.OrderBy) > 0 {
+		over = append(over, "ORDER BY "+GenericJoin(fn.OrderBy, orderByParamsToString))
+	}
+	if fn.Frame != nil {
+		over = append(over, fn.Frame.String())
+	}
+
+	out := fmt.Sprintf("%s(%s) OVER (%s)", name, strings.Join(args, ", "), strings.Join(over, " "))
+	if fn.Alias != "" {
+		out += " AS " + fn.Alias
+	}
+	return out
+}
+
+// String returns a string. Used for plan descriptions
+func (wf *WindowFrame) String() string {
+	unit := "ROWS"
+	if wf.Unit == sqlparser.FrameRangeType {
+		unit = "RANGE"
+	}
+	return fmt.Sprintf("%s BETWEEN %s AND %s", unit, wf.Start.String(), wf.End.String())
+}
+
+// String returns a string. Used for plan descriptions
+func (fp WindowFramePoint) String() string {
+	switch fp.Type {
+	case sqlparser.ExprPrecedingType:
+		return sqlparser.String(fp.Offset) + " PRECEDING"
+	case sqlparser.ExprFollowingType:
+		return sqlparser.String(fp.Offset) + " FOLLOWING"
+	default:
+		return strings.ToUpper(fp.Type.ToString())
+	}
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func windowInput() *sqltypes.Result {
+	return sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("grp|val", "int64|int64"),
+		"2|20",
+		"1|30",
+		"1|10",
+		"2|10",
+		"1|20",
+		"1|20",
+	)
+}
+
+func windowFunc(code WindowOpcode, col int) *WindowFunc {
+	collationEnv := collations.MySQL8()
+	return &WindowFunc{
+		Opcode:       code,
+		Col:          col,
+		PartitionBy:  evalengine.Comparison{{Col: 0, WeightStringCol: -1, Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID), CollationEnv: collationEnv}},
+		OrderBy:      evalengine.Comparison{{Col: 1, WeightStringCol: -1, Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID), CollationEnv: collationEnv}},
+		CollationEnv: collationEnv,
+	}
+}
+
+func literal(v int64) evalengine.Expr {
+	return evalengine.NewLiteralInt(v)
+}
+
+func TestWindowRanking(t *testing.T) {
+	tcases := []struct {
+		name   string
+		fn     *WindowFunc
+		expRes string
+	}{{
+		name:   "row_number",
+		fn:     windowFunc(WindowRowNumber, -1),
+		expRes: `[[UINT64(1) INT64(1) INT64(10)] [UINT64(2) INT64(1) INT64(20)] [UINT64(3) INT64(1) INT64(20)] [UINT64(4) INT64(1) INT64(30)] [UINT64(1) INT64(2) INT64(10)] [UINT64(2) INT64(2) INT64(20)]]`,
+	}, {
+		name:   "rank",
+		fn:     windowFunc(WindowRank, -1),
+		expRes: `[[UINT64(1) INT64(1) INT64(10)] [UINT64(2) INT64(1) INT64(20)] [UINT64(2) INT64(1) INT64(20)] [UINT64(4) INT64(1) INT64(30)] [UINT64(1) INT64(2) INT64(10)] [UINT64(2) INT64(2) INT64(20)]]`,
+	}, {
+		name:   "dense_rank",
+		fn:     windowFunc(WindowDenseRank, -1),
+		expRes: `[[UINT64(1) INT64(1) INT64(10)] [UINT64(2) INT64(1) INT64(20)] [UINT64(2) INT64(1) INT64(20)] [UINT64(3) INT64(1) INT64(30)] [UINT64(1) INT64(2) INT64(10)] [UINT64(2) INT64(2) INT64(20)]]`,
+	}, {
+		name:   "cume_dist",
+		fn:     windowFunc(WindowCumeDist, -1),
+		expRes: `[[FLOAT64(0.25) INT64(1) INT64(10)] [FLOAT64(0.75) INT64(1) INT64(20)] [FLOAT64(0.75) INT64(1) INT64(20)] [FLOAT64(1) INT64(1) INT64(30)] [FLOAT64(0.5) INT64(2) INT64(10)] [FLOAT64(1) INT64(2) INT64(20)]]`,
+	}, {
+		name: "ntile",
+		fn: func() *WindowFunc {
+			fn := windowFunc(WindowNtile, -1)
+			fn.N = literal(3)
+			return fn
+		}(),
+		expRes: `[[UINT64(1) INT64(1) INT64(10)] [UINT64(1) INT64(1) INT64(20)] [UINT64(2) INT64(1) INT64(20)] [UINT64(3) INT64(1) INT64(30)] [UINT64(1) INT64(2) INT64(10)] [UINT64(2) INT64(2) INT64(20)]]`,
+	}, {
+		name: "lag with default",
+		fn: func() *WindowFunc {
+			fn := windowFunc(WindowLag, 1)
+			fn.Default = literal(0)
+			return fn
+		}(),
+		expRes: `[[INT64(0) INT64(1) INT64(10)] [INT64(10) INT64(1) INT64(20)] [INT64(20) INT64(1) INT64(20)] [INT64(20) INT64(1) INT64(30)] [INT64(0) INT64(2) INT64(10)] [INT64(10) INT64(2) INT64(20)]]`,
+	}, {
+		name: "lead by two",
+		fn: func() *WindowFunc {
+			fn := windowFunc(WindowLead, 1)
+			fn.N = literal(2)
+			return fn
+		}(),
+		expRes: `[[INT64(20) INT64(1) INT64(10)] [INT64(30) INT64(1) INT64(20)] [NULL INT64(1) INT64(20)] [NULL INT64(1) INT64(30)] [NULL INT64(2) INT64(10)] [NULL INT64(2) INT64(20)]]`,
+	}}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &Window{
+				Functions: []*WindowFunc{tc.fn},
+				Input:     &fakePrimitive{results: []*sqltypes.Result{windowInput()}},
+			}
+			qr, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+			require.NoError(t, err)
+			require.Equal(t, tc.expRes, fmt.Sprintf("%v", qr.Rows))
+			require.Len(t, qr.Fields, 3)
+		})
+	}
+}
+
+func TestWindowAggregates(t *testing.T) {
+	tcases := []struct {
+		name   string
+		fn     *WindowFunc
+		expRes string
+	}{{
+		name: "running sum",
+		fn: func() *WindowFunc {
+			fn := windowFunc(WindowAggregate, 1)
+			fn.AggrOpcode = AggregateSum
+			return fn
+		}(),
+		expRes: `[[DECIMAL(10) INT64(1) INT64(10)] [DECIMAL(50) INT64(1) INT64(20)] [DECIMAL(50) INT64(1) INT64(20)] [DECIMAL(80) INT64(1) INT64(30)] [DECIMAL(10) INT64(2) INT64(10)] [DECIMAL(30) INT64(2) INT64(20)]]`,
+	}, {
+		name: "count over the partition",
+		fn: func() *WindowFunc {
+			fn := windowFunc(WindowAggregate, 1)
+			fn.AggrOpcode = AggregateCount
+			fn.OrderBy = nil
+			return fn
+		}(),
+		expRes: `[[INT64(4) INT64(1) INT64(30)] [INT64(4) INT64(1) INT64(10)] [INT64(4) INT64(1) INT64(20)] [INT64(4) INT64(1) INT64(20)] [INT64(2) INT64(2) INT64(20)] [INT64(2) INT64(2) INT64(10)]]`,
+	}, {
+		name: "max over a sliding frame",
+		fn: func() *WindowFunc {
+			fn := windowFunc(WindowAggregate, 1)
+			fn.AggrOpcode = AggregateMax
+			fn.Frame = &WindowFrame{
+				Unit:  sqlparser.FrameRowsType,
+				Start: WindowFramePoint{Type: sqlparser.ExprPrecedingType, Offset: literal(1)},
+				End:   WindowFramePoint{Type: sqlparser.CurrentRowType},
+			}
+			return fn
+		}(),
+		expRes: `[[INT64(10) INT64(1) INT64(10)] [INT64(20) INT64(1) INT64(20)] [INT64(20) INT64(1) INT64(20)] [INT64(30) INT64(1) INT64(30)] [INT64(10) INT64(2) INT64(10)] [INT64(20) INT64(2) INT64(20)]]`,
+	}, {
+		name: "last_value over the whole partition",
+		fn: func() *WindowFunc {
+			fn := windowFunc(WindowLastValue, 1)
+			fn.Frame = &WindowFrame{
+				Unit:  sqlparser.FrameRowsType,
+				Start: WindowFramePoint{Type: sqlparser.UnboundedPrecedingType},
+				End:   WindowFramePoint{Type: sqlparser.UnboundedFollowingType},
+			}
+			return fn
+		}(),
+		expRes: `[[INT64(30) INT64(1) INT64(10)] [INT64(30) INT64(1) INT64(20)] [INT64(30) INT64(1) INT64(20)] [INT64(30) INT64(1) INT64(30)] [INT64(20) INT64(2) INT64(10)] [INT64(20) INT64(2) INT64(20)]]`,
+	}}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &Window{
+				Functions: []*WindowFunc{tc.fn},
+				Input:     &fakePrimitive{results: []*sqltypes.Result{windowInput()}},
+			}
+			qr, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+			require.NoError(t, err)
+			require.Equal(t, tc.expRes, fmt.Sprintf("%v", qr.Rows))
+		})
+	}
+}
+
+func TestWindowStreaming(t *testing.T) {
+	fn := windowFunc(WindowRowNumber, -1)
+	fn.Alias = "rn"
+	w := &Window{
+		Functions: []*WindowFunc{fn},
+		Input: &fakePrimitive{
+			results: sqltypes.MakeTestStreamingResults(
+				sqltypes.MakeTestFields("grp|val", "int64|int64"),
+				"1|20",
+				"---",
+				"2|10",
+				"1|10",
+			),
+			allResultsInOneCall: true,
+		},
+	}
+
+	var rows []sqltypes.Row
+	err := w.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		rows = append(rows, qr.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, `[[UINT64(1) INT64(1) INT64(10)] [UINT64(2) INT64(1) INT64(20)] [UINT64(1) INT64(2) INT64(10)]]`, fmt.Sprintf("%v", rows))
+}
+
+func TestWindowInvalidNtile(t *testing.T) {
+	fn := windowFunc(WindowNtile, -1)
+	fn.N = literal(0)
+	w := &Window{
+		Functions: []*WindowFunc{fn},
+		Input:     &fakePrimitive{results: []*sqltypes.Result{windowInput()}},
+	}
+	_, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.EqualError(t, err, "VT03011: invalid value type: 0 for ntile")
+}
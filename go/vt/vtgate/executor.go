@@ -51,6 +51,7 @@ import (
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/sysvars"
+	"vitess.io/vitess/go/vt/tableacl"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -127,6 +128,12 @@ type Executor struct {
 
 	warmingReadsPercent int
 	warmingReadsChannel chan bool
+
+	// mirrorChannel bounds the number of queries mirrored concurrently by mirror rules.
+	mirrorChannel chan bool
+
+	// dataACL holds the column and row level ACLs enforced by the planner, if any.
+	dataACL atomic.Pointer[tableacl.DataACL]
 }
 
 var executorOnce sync.Once
@@ -175,6 +182,7 @@ func NewExecutor(
 		plans:               plans,
 		warmingReadsPercent: warmingReadsPercent,
 		warmingReadsChannel: make(chan bool, warmingReadsConcurrency),
+		mirrorChannel:       make(chan bool, mirrorConcurrency),
 	}
 
 	vschemaacl.Init()
@@ -1267,6 +1275,42 @@ func (e *Executor) ClearPlans() {
 	e.epoch.Add(1)
 }
 
+// SetDataACL sets the column and row level ACLs enforced by the planner.
+// The cached plans are cleared, as they were planned with the previous ACLs.
+func (e *Executor) SetDataACL(dataACL *tableacl.DataACL) {
+	e.dataACL.Store(dataACL)
+	e.ClearPlans()
+}
+
+// loadDataACL loads the column and row level ACLs from a table ACL config file,
+// validates their row filters and makes the planner enforce them.
+func (e *Executor) loadDataACL(configFile string) error {
+	factory, err := tableacl.GetCurrentACLFactory()
+	if err != nil {
+		return err
+	}
+	dataACL, err := tableacl.LoadDataACL(configFile, factory)
+	if err != nil {
+		return err
+	}
+	if dataACL.Equal(e.DataACL()) {
+		// the config did not change, so the cached plans are still valid
+		return nil
+	}
+	for _, predicate := range dataACL.Predicates() {
+		if _, err := e.env.Parser().ParseExpr(predicate); err != nil {
+			return vterrors.Wrapf(err, "invalid row filter %q", predicate)
+		}
+	}
+	e.SetDataACL(dataACL)
+	return nil
+}
+
+// DataACL returns the column and row level ACLs enforced by the planner, or nil if there are none.
+func (e *Executor) DataACL() *tableacl.DataACL {
+	return e.dataACL.Load()
+}
+
 func (e *Executor) updateQueryCounts(planType, keyspace, tableName string, shardQueries int64) {
 	queriesProcessed.Add(planType, 1)
 	queriesRouted.Add(planType, shardQueries)
@@ -1415,6 +1459,41 @@ func (e *Executor) handlePrepare(ctx context.Context, safeSession *SafeSession,
 	return qr.Fields, err
 }
 
+// FieldList returns the columns of a table whose names match the wildcard, a LIKE
+// pattern. It is used for COM_FIELD_LIST. The columns are always taken from the
+// fields of a query to the table, even when the vschema knows their types, so that
+// the column and row level ACLs of vtgate and the table ACLs of the tablets apply.
+func (e *Executor) FieldList(ctx context.Context, safeSession *SafeSession, table, wildcard string) ([]*querypb.Field, error) {
+	logStats := logstats.NewLogStats(ctx, "FieldList", table, safeSession.GetSessionUUID(), nil)
+	vcursor, err := newVCursorImpl(safeSession, sqlparser.MarginComments{}, e, logStats, e.vm, e.VSchema(), e.resolver.resolver, e.serv, e.warnShardedOnly, e.pv)
+	if err != nil {
+		return nil, err
+	}
+	tbl, _, _, _, err := vcursor.FindTable(sqlparser.TableName{Name: sqlparser.NewIdentifierCS(table)})
+	if err != nil {
+		return nil, err
+	}
+
+	query := "select * from " + sqlparser.String(sqlparser.TableName{Name: tbl.Name, Qualifier: sqlparser.NewIdentifierCS(tbl.Keyspace.Name)})
+	fields, err := e.Prepare(ctx, "FieldList", safeSession, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if wildcard == "" {
+		return fields, nil
+	}
+	// Column names are case insensitive.
+	pattern := sqlparser.LikeToRegexp(strings.ToLower(wildcard))
+	var matching []*querypb.Field
+	for _, field := range fields {
+		if pattern.MatchString(strings.ToLower(field.Name)) {
+			matching = append(matching, field)
+		}
+	}
+	return matching, nil
+}
+
 func parseAndValidateQuery(query string, parser *sqlparser.Parser) (sqlparser.Statement, *sqlparser.ReservedVars, error) {
 	stmt, reserved, err := parser.Parse2(query)
 	if err != nil {
@@ -16,6 +16,27 @@ limitations under the License.
 
 package vtgate
 
+import "vitess.io/vitess/go/stats"
+
+var (
+	// mirrorQueries counts the queries mirrored to target keyspaces by mirror rules, by
+	// outcome: Ok, SourceError, TargetError, TargetTimeout or BothErrors.
+	mirrorQueries = stats.NewCountersWithMultiLabels(
+		"MirrorQueries",
+		"Queries mirrored to target keyspaces by mirror rules, by source keyspace, target keyspace and outcome",
+		[]string{"SourceKeyspace", "TargetKeyspace", "Outcome"})
+	// mirrorLatencies times the mirrored queries in the source and the target keyspaces.
+	mirrorLatencies = stats.NewMultiTimings(
+		"MirrorLatencies",
+		"Latencies of the queries mirrored to target keyspaces by mirror rules, in the source and the target keyspaces",
+		[]string{"SourceKeyspace", "TargetKeyspace", "Side"})
+	// mirrorTargetLatencyExcess sums how much longer the mirrored queries took in the target keyspaces.
+	mirrorTargetLatencyExcess = stats.NewCountersWithMultiLabels(
+		"MirrorTargetLatencyExcessNanoseconds",
+		"Total time, in nanoseconds, by which the mirrored queries were slower in the target keyspace than in the source keyspace",
+		[]string{"SourceKeyspace", "TargetKeyspace"})
+)
+
 const (
 	// ExecutorTemplate is the HTML template to display ExecutorStats.
 	ExecutorTemplate = `
@@ -24,6 +24,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path"
 	"reflect"
 	"sort"
 	"strings"
@@ -36,6 +38,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
 
+	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/sqltypes"
@@ -43,6 +46,8 @@ import (
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/tableacl"
+	"vitess.io/vitess/go/vt/tableacl/simpleacl"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vtgate/buffer"
 	"vitess.io/vitess/go/vt/vtgate/engine"
@@ -52,6 +57,7 @@ import (
 	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	tableaclpb "vitess.io/vitess/go/vt/proto/tableacl"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
@@ -1504,6 +1510,96 @@ func TestExecutorVindexDDLACL(t *testing.T) {
 	vschemaacl.AuthorizedDDLUsers = ""
 }
 
+func TestExecutorDataACL(t *testing.T) {
+	executor, sbc1, _, _, ctx := createExecutorEnv(t)
+
+	dataACL, err := tableacl.NewDataACL(&tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			Name:                 "user",
+			TableNamesOrPrefixes: []string{"user"},
+			ColumnAcls: []*tableaclpb.ColumnACL{{
+				Columns:       []string{"costly"},
+				DeniedReaders: []string{"redUser"},
+			}},
+			RowFilters: []*tableaclpb.RowFilter{{
+				Predicate: "predef1 = 'eu'",
+				Readers:   []string{"redUser"},
+			}},
+		}},
+	}, &simpleacl.Factory{})
+	require.NoError(t, err)
+	executor.SetDataACL(dataACL)
+
+	session := NewSafeSession(&vtgatepb.Session{TargetString: KsTestSharded})
+	ctxRedUser := callerid.NewContext(ctx, &vtrpcpb.CallerID{}, &querypb.VTGateCallerID{Username: "redUser"})
+	ctxBlueUser := callerid.NewContext(ctx, &vtrpcpb.CallerID{}, &querypb.VTGateCallerID{Username: "blueUser"})
+
+	_, err = executor.Execute(ctxRedUser, nil, "TestExecute", session, "select costly from user where id = 1", nil)
+	require.EqualError(t, err, "SELECT command denied for column 'costly' in table 'user'")
+	assert.Equal(t, sqlerror.ERColumnAccessDenied, sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError).Number())
+	_, err = executor.Execute(ctxBlueUser, nil, "TestExecute", session, "select costly from user where id = 1", nil)
+	require.NoError(t, err)
+
+	// the same query is planned separately for callers with different restrictions
+	sbc1.Queries = nil
+	_, err = executor.Execute(ctxRedUser, nil, "TestExecute", session, "select id from user where id = 1", nil)
+	require.NoError(t, err)
+	_, err = executor.Execute(ctxBlueUser, nil, "TestExecute", session, "select id from user where id = 1", nil)
+	require.NoError(t, err)
+	require.Len(t, sbc1.Queries, 2)
+	assert.Equal(t, "select id from `user` where id = 1 and `user`.predef1 = 'eu'", sbc1.Queries[0].Sql)
+	assert.Equal(t, "select id from `user` where id = 1", sbc1.Queries[1].Sql)
+
+	// the statements sent as is to a shard can't have the ACLs enforced on them
+	shardSession := NewSafeSession(&vtgatepb.Session{TargetString: KsTestSharded + "/-20"})
+	sbc1.Queries = nil
+	_, err = executor.Execute(ctxRedUser, nil, "TestExecute", shardSession, "select costly from user where id = 1", nil)
+	require.EqualError(t, err, "statements targeting TestExecutor/-20 are not allowed for callers with column or row level ACLs")
+	assert.Equal(t, sqlerror.ERAccessDeniedError, sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError).Number())
+	_, err = executor.Execute(ctxRedUser, nil, "TestExecute", shardSession, "update user set costly = 1 where id = 1", nil)
+	require.Error(t, err)
+	assert.Empty(t, sbc1.Queries)
+	_, err = executor.Execute(ctxBlueUser, nil, "TestExecute", shardSession, "select costly from user where id = 1", nil)
+	require.NoError(t, err)
+	require.Len(t, sbc1.Queries, 1)
+	assert.Equal(t, "select costly from `user` where id = 1", sbc1.Queries[0].Sql)
+}
+
+func TestExecutorLoadDataACL(t *testing.T) {
+	executor, _, _, _, _ := createExecutorEnv(t)
+	if _, err := tableacl.GetCurrentACLFactory(); err != nil {
+		tableacl.Register("simpleacl", &simpleacl.Factory{})
+	}
+
+	configFile := path.Join(t.TempDir(), "data_acl.json")
+	writeConfig := func(predicate string) {
+		config := fmt.Sprintf(`{"table_groups": [{"name": "user", "table_names_or_prefixes": ["TestExecutor.user"], "row_filters": [{"predicate": %q, "readers": ["redUser"]}]}]}`, predicate)
+		require.NoError(t, os.WriteFile(configFile, []byte(config), 0o600))
+	}
+
+	writeConfig("predef1 = 'eu'")
+	require.NoError(t, executor.loadDataACL(configFile))
+	dataACL := executor.DataACL()
+	require.NotNil(t, dataACL)
+	epoch := executor.epoch.Load()
+
+	// reloading the same config keeps the ACL and the cached plans
+	require.NoError(t, executor.loadDataACL(configFile))
+	assert.Same(t, dataACL, executor.DataACL())
+	assert.Equal(t, epoch, executor.epoch.Load())
+
+	writeConfig("predef1 = 'us'")
+	require.NoError(t, executor.loadDataACL(configFile))
+	assert.NotSame(t, dataACL, executor.DataACL())
+	assert.Greater(t, executor.epoch.Load(), epoch)
+
+	// an invalid config keeps the previous ACL
+	dataACL = executor.DataACL()
+	writeConfig("predef1 = ")
+	require.Error(t, executor.loadDataACL(configFile))
+	assert.Same(t, dataACL, executor.DataACL())
+}
+
 func TestExecutorUnrecognized(t *testing.T) {
 	executor, _, _, _, ctx := createExecutorEnv(t)
 
@@ -2684,6 +2780,44 @@ func TestExecutorVExplainQueries(t *testing.T) {
 	require.Contains(t, txt, lookupQuery)
 }
 
+func TestExecutorVExplainTrace(t *testing.T) {
+	executor, _, _, _, ctx := createExecutorEnv(t)
+
+	session := NewAutocommitSession(&vtgatepb.Session{})
+	qr, err := executor.Execute(ctx, nil, "TestExecutorVExplainTrace", session, "vexplain trace select * from user", nil)
+	require.NoError(t, err)
+	require.Len(t, qr.Rows, 1)
+	assertTrace := func(t *testing.T, output string) {
+		var plan struct {
+			OperatorType string
+			Trace        struct {
+				Calls      int
+				Rows       int
+				ShardCalls int
+				WallTime   string
+			}
+		}
+		require.NoError(t, json.Unmarshal([]byte(output), &plan))
+		assert.Equal(t, "Route", plan.OperatorType)
+		assert.Equal(t, 1, plan.Trace.Calls)
+		assert.Equal(t, 8, plan.Trace.Rows)
+		assert.Equal(t, 8, plan.Trace.ShardCalls)
+		assert.NotEmpty(t, plan.Trace.WallTime)
+	}
+	assertTrace(t, qr.Rows[0][0].ToString())
+
+	// Test the streaming side as well
+	var results []sqltypes.Row
+	session = NewAutocommitSession(&vtgatepb.Session{})
+	err = executor.StreamExecute(ctx, nil, "TestExecutorVExplainTrace", session, "vexplain trace select * from user", nil, func(result *sqltypes.Result) error {
+		results = append(results, result.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assertTrace(t, results[0][0].ToString())
+}
+
 func TestExecutorStartTxnStmt(t *testing.T) {
 	executor, _, _, _, ctx := createExecutorEnv(t)
 
@@ -2937,3 +3071,77 @@ func exec(executor *Executor, session *SafeSession, sql string) (*sqltypes.Resul
 func makeComments(text string) sqlparser.MarginComments {
 	return sqlparser.MarginComments{Trailing: text}
 }
+
+func TestExecutorFieldList(t *testing.T) {
+	executor, _, _, _, ctx := createExecutorEnv(t)
+	session := NewAutocommitSession(&vtgatepb.Session{TargetString: KsTestSharded})
+
+	fieldNames := func(fields []*querypb.Field) []string {
+		var names []string
+		for _, field := range fields {
+			names = append(names, field.Name)
+		}
+		return names
+	}
+
+	// The fields come from a tablet.
+	fields, err := executor.FieldList(ctx, session, "user", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "value"}, fieldNames(fields))
+
+	fields, err = executor.FieldList(ctx, session, "user", "VAL%")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"value"}, fieldNames(fields))
+
+	// The fields of a table whose column types are known come from a tablet too,
+	// so that its table ACLs apply.
+	ks := executor.VSchema().Keyspaces[KsTestSharded]
+	ks.Tables["field_list"] = &vindexes.Table{
+		Name:                    sqlparser.NewIdentifierCS("field_list"),
+		Keyspace:                ks.Keyspace,
+		ColumnListAuthoritative: true,
+		Columns: []vindexes.Column{
+			{Name: sqlparser.NewIdentifierCI("id"), Type: sqltypes.Int64},
+			{Name: sqlparser.NewIdentifierCI("name"), Type: sqltypes.VarChar, CollationName: "utf8mb4_0900_ai_ci", Size: 20, Nullable: true},
+		},
+	}
+	fields, err = executor.FieldList(ctx, session, "field_list", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "value"}, fieldNames(fields))
+
+	_, err = executor.FieldList(ctx, session, "unknown", "")
+	require.ErrorContains(t, err, "table unknown not found")
+}
+
+func TestExecutorQueryAttributes(t *testing.T) {
+	executor, _, _, sbclookup, ctx := createExecutorEnv(t)
+	logChan := executor.queryLogger.Subscribe("Test")
+	defer executor.queryLogger.Unsubscribe(logChan)
+
+	ctx = withQueryAttributes(ctx, []mysql.QueryAttribute{
+		{Name: "workload_name", Value: sqltypes.NewVarBinary("billing")},
+		{Name: "PRIORITY", Value: sqltypes.NewInt64(10)},
+		{Name: "trace_id", Value: sqltypes.NULL},
+	})
+	session := &vtgatepb.Session{TargetString: KsTestUnsharded}
+	_, err := executorExec(ctx, executor, session, "select id from music_user_map", nil)
+	require.NoError(t, err)
+
+	require.Len(t, sbclookup.Options, 1)
+	assert.Equal(t, "billing", sbclookup.Options[0].WorkloadName)
+	assert.Equal(t, "10", sbclookup.Options[0].Priority)
+
+	logStats := getQueryLog(logChan)
+	require.NotNil(t, logStats)
+	utils.MustMatch(t, map[string]*querypb.BindVariable{
+		"workload_name": sqltypes.BytesBindVariable([]byte("billing")),
+		"PRIORITY":      sqltypes.Int64BindVariable(10),
+		"trace_id":      sqltypes.NullBindVariable,
+	}, logStats.QueryAttributes)
+
+	// A directive in the query takes precedence over the query attribute.
+	_, err = executorExec(ctx, executor, session, "select /*vt+ WORKLOAD_NAME=reports */ id from music_user_map", nil)
+	require.NoError(t, err)
+	require.Len(t, sbclookup.Options, 2)
+	assert.Equal(t, "reports", sbclookup.Options[1].WorkloadName)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"io"
+)
+
+// localInfileRequester requests the content of a file from a MySQL client,
+// as part of a LOAD DATA LOCAL INFILE statement. It is implemented by *mysql.Conn.
+type localInfileRequester interface {
+	RequestLocalInfile(fileName string) (io.ReadCloser, error)
+}
+
+// localInfileKey is the context key of the MySQL client connection
+// the query being executed was received on.
+type localInfileKey struct{}
+
+// withLocalInfile returns a copy of ctx carrying the connection LOAD DATA LOCAL INFILE
+// statements read their files from.
+func withLocalInfile(ctx context.Context, requester localInfileRequester) context.Context {
+	return context.WithValue(ctx, localInfileKey{}, requester)
+}
+
+// localInfileFromContext returns the connection carried by ctx, if any.
+func localInfileFromContext(ctx context.Context) localInfileRequester {
+	requester, _ := ctx.Value(localInfileKey{}).(localInfileRequester)
+	return requester
+}
@@ -52,6 +52,8 @@ type LogStats struct {
 	SessionUUID    string
 	CachedPlan     bool
 	ActiveKeyspace string // ActiveKeyspace is the selected keyspace `use ks`
+	// QueryAttributes are the query attributes sent by the MySQL client with the query.
+	QueryAttributes map[string]*querypb.BindVariable
 }
 
 // NewLogStats constructs a new LogStats with supplied Method and ctx
@@ -177,6 +179,16 @@ func (stats *LogStats) Logf(w io.Writer, params url.Values) error {
 	log.Strings(stats.TablesUsed)
 	log.Key("ActiveKeyspace")
 	log.String(stats.ActiveKeyspace)
+	// Only log the query attributes if some were sent, so that the
+	// format does not change for clients that do not use them.
+	if len(stats.QueryAttributes) > 0 {
+		log.Key("QueryAttributes")
+		if redacted {
+			log.Redacted()
+		} else {
+			log.BindVariables(stats.QueryAttributes, fullBindParams)
+		}
+	}
 
 	return log.Flush(w)
 }
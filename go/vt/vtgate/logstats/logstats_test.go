@@ -147,6 +147,34 @@ func TestLogStatsFormat(t *testing.T) {
 	}
 }
 
+func TestLogStatsQueryAttributes(t *testing.T) {
+	defer func() {
+		streamlog.SetRedactDebugUIQueries(false)
+		streamlog.SetQueryLogFormat("text")
+	}()
+	logStats := NewLogStats(context.Background(), "test", "sql1", "suuid", nil)
+	logStats.StartTime = time.Date(2017, time.January, 1, 1, 2, 3, 0, time.UTC)
+	logStats.EndTime = time.Date(2017, time.January, 1, 1, 2, 4, 1234, time.UTC)
+	logStats.QueryAttributes = map[string]*querypb.BindVariable{"app": sqltypes.StringBindVariable("billing")}
+	params := map[string][]string{"full": {}}
+
+	streamlog.SetQueryLogFormat("text")
+	got := testFormat(t, logStats, params)
+	assert.Equal(t, "test\t\t\t''\t''\t2017-01-01 01:02:03.000000\t2017-01-01 01:02:04.000001\t1.000001\t0.000000\t0.000000\t0.000000\t\t\"sql1\"\t{}\t0\t0\t\"\"\t\"\"\t\"suuid\"\tfalse\t[]\t\"\"\t{\"app\": {\"type\": \"VARCHAR\", \"value\": \"billing\"}}\n", got)
+
+	streamlog.SetRedactDebugUIQueries(true)
+	got = testFormat(t, logStats, params)
+	assert.Equal(t, "test\t\t\t''\t''\t2017-01-01 01:02:03.000000\t2017-01-01 01:02:04.000001\t1.000001\t0.000000\t0.000000\t0.000000\t\t\"sql1\"\t\"[REDACTED]\"\t0\t0\t\"\"\t\"\"\t\"suuid\"\tfalse\t[]\t\"\"\t\"[REDACTED]\"\n", got)
+
+	streamlog.SetRedactDebugUIQueries(false)
+	streamlog.SetQueryLogFormat("json")
+	got = testFormat(t, logStats, params)
+	var parsed map[string]any
+	err := json.Unmarshal([]byte(got), &parsed)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"app": map[string]any{"type": "VARCHAR", "value": "billing"}}, parsed["QueryAttributes"])
+}
+
 func TestLogStatsFilter(t *testing.T) {
 	defer func() { streamlog.SetQueryLogFilterTag("") }()
 
@@ -85,6 +85,15 @@ func (e *Executor) newExecute(
 		return err
 	}
 
+	// The query attributes sent by the client can be used instead of query directives.
+	if attributes := queryAttributesFromContext(ctx); len(attributes) > 0 {
+		logStats.QueryAttributes = queryAttributesBindVars(attributes)
+		if sqlparser.AddQueryAttributeDirectives(stmt, queryAttributesStrings(attributes)) {
+			// The plan is cached by query, so it must include the added directives.
+			query = sqlparser.String(stmt)
+		}
+	}
+
 	var lastVSchemaCreated time.Time
 	vs := e.VSchema()
 	lastVSchemaCreated = vs.GetCreated()
@@ -18,12 +18,20 @@ package planbuilder
 
 import (
 	"vitess.io/vitess/go/vt/key"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
 func buildCallProcPlan(stmt *sqlparser.CallProc, vschema plancontext.VSchema) (*planResult, error) {
+	if vschema.HasTableRestrictions() {
+		// the procedure runs on the tablets, so the column and row level ACLs could not be enforced on the tables it reads
+		return nil, vterrors.NewErrorf(vtrpcpb.Code_PERMISSION_DENIED, vterrors.AccessDeniedError, "CALL is not allowed for callers with column or row level ACLs")
+	}
 	var ks string
 	if stmt.Name.Qualifier.NotEmpty() {
 		ks = stmt.Name.Qualifier.String()
@@ -34,6 +42,16 @@ func buildCallProcPlan(stmt *sqlparser.CallProc, vschema plancontext.VSchema) (*
 		return nil, err
 	}
 
+	if dest == nil && keyspace.Sharded {
+		procedure, err := findProcedure(keyspace, stmt.Name.Name.String(), vschema)
+		if err != nil {
+			return nil, err
+		}
+		if procedure != nil {
+			return buildShardedCallProcPlan(stmt, procedure, vschema)
+		}
+	}
+
 	if dest == nil {
 		if err := vschema.ErrorIfShardedF(keyspace, "CALL", errNotAllowWhenSharded); err != nil {
 			return nil, err
@@ -50,4 +68,44 @@ func buildCallProcPlan(stmt *sqlparser.CallProc, vschema plancontext.VSchema) (*
 	}), nil
 }
 
+// findProcedure returns the routing of the procedure in the vschema of the keyspace, if it has one.
+func findProcedure(keyspace *vindexes.Keyspace, name string, vschema plancontext.VSchema) (*vindexes.Procedure, error) {
+	vs := vschema.GetVSchema()
+	if vs == nil {
+		return nil, nil
+	}
+	return vs.FindProcedure(keyspace.Name, name)
+}
+
+// buildShardedCallProcPlan builds the plan of a CALL to a procedure of a sharded keyspace,
+// which is routed as declared in the vschema. The results of the shards are concatenated.
+func buildShardedCallProcPlan(stmt *sqlparser.CallProc, procedure *vindexes.Procedure, vschema plancontext.VSchema) (*planResult, error) {
+	send := &engine.Send{
+		Keyspace: procedure.Keyspace,
+	}
+	switch procedure.Routing {
+	case vindexes.ProcedureRoutingVindex:
+		if procedure.Argument >= len(stmt.Params) {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "missing argument %d to route CALL %s", procedure.Argument, procedure.Name)
+		}
+		value, err := evalengine.Translate(stmt.Params[procedure.Argument], &evalengine.Config{
+			Collation:   vschema.ConnCollation(),
+			Environment: vschema.Environment(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		send.Vindex = procedure.Vindex
+		send.Values = []evalengine.Expr{value}
+	case vindexes.ProcedureRoutingAllShards:
+		send.TargetDestination = key.DestinationAllShards{}
+	case vindexes.ProcedureRoutingShard:
+		send.TargetDestination = key.DestinationShard(procedure.Shard)
+	}
+
+	stmt.Name.Qualifier = sqlparser.NewIdentifierCS("")
+	send.Query = sqlparser.String(stmt)
+	return newPlanResult(send), nil
+}
+
 const errNotAllowWhenSharded = "CALL is not supported for sharded keyspace"
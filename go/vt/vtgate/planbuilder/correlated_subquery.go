@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	popcode "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+)
+
+var _ logicalPlan = (*correlatedSubquery)(nil)
+
+// correlatedSubquery is the logicalPlan for engine.CorrelatedSubquery.
+// This gets built if a correlated subquery can't be merged with the
+// outer query, and has to be executed once for every outer row.
+type correlatedSubquery struct {
+	subquery  logicalPlan
+	outer     logicalPlan
+	eSubquery *engine.CorrelatedSubquery
+}
+
+// newCorrelatedSubquery builds a new correlatedSubquery.
+func newCorrelatedSubquery(opcode popcode.PulloutOpcode, sqName, hasValues string, vars map[string]int, subquery, outer logicalPlan) *correlatedSubquery {
+	return &correlatedSubquery{
+		subquery: subquery,
+		outer:    outer,
+		eSubquery: &engine.CorrelatedSubquery{
+			Opcode:         opcode,
+			SubqueryResult: sqName,
+			HasValues:      hasValues,
+			Vars:           vars,
+		},
+	}
+}
+
+// Primitive implements the logicalPlan interface
+func (cs *correlatedSubquery) Primitive() engine.Primitive {
+	cs.eSubquery.Subquery = cs.subquery.Primitive()
+	cs.eSubquery.Outer = cs.outer.Primitive()
+	return cs.eSubquery
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"strings"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/tableacl"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+)
+
+// enforceDataACL enforces the column and row level ACLs of the caller on the statement.
+// Referencing a denied column fails the query. The row filters are added to the predicates
+// of the statement, which is then analyzed again, in which case a new planning context is returned.
+func enforceDataACL(ctx *plancontext.PlanningContext, stmt sqlparser.Statement) (*plancontext.PlanningContext, error) {
+	restrictions := make(map[semantics.TableSet]*tableacl.TableRestrictions)
+	for idx, table := range ctx.SemTable.Tables {
+		vindexTable := table.GetVindexTable()
+		if vindexTable == nil {
+			continue
+		}
+		if r := ctx.VSchema.TableRestrictions(vindexTable); r != nil {
+			restrictions[semantics.SingleTableSet(idx)] = r
+		}
+	}
+	if len(restrictions) == 0 {
+		return ctx, nil
+	}
+
+	if err := checkDeniedColumns(ctx, stmt, restrictions); err != nil {
+		return nil, err
+	}
+	filtered, err := addRowFilters(ctx, stmt, restrictions)
+	if err != nil || !filtered {
+		return ctx, err
+	}
+	return plancontext.CreatePlanningContext(stmt, ctx.ReservedVars, ctx.VSchema, ctx.PlannerVersion)
+}
+
+// checkDeniedColumns fails if the statement references a denied column, or uses * on a table with denied columns.
+func checkDeniedColumns(ctx *plancontext.PlanningContext, stmt sqlparser.Statement, restrictions map[semantics.TableSet]*tableacl.TableRestrictions) error {
+	command := strings.ToUpper(sqlparser.ASTToStatementType(stmt).String())
+	deniedColumnError := func(column string, ts semantics.TableSet) error {
+		tableName := ""
+		if ti, err := ctx.SemTable.TableInfoFor(ts); err == nil {
+			tableName = ti.GetVindexTable().Name.String()
+		}
+		return vterrors.NewErrorf(vtrpcpb.Code_PERMISSION_DENIED, vterrors.ColumnAccessDeniedError, "%s command denied for column '%s' in table '%s'", command, column, tableName)
+	}
+
+	return sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.ColName:
+			ts := ctx.SemTable.DirectDeps(node)
+			if restrictions[ts].IsColumnDenied(node.Name.String()) {
+				return false, deniedColumnError(node.Name.String(), ts)
+			}
+		case *sqlparser.Select:
+			for _, selectExpr := range node.SelectExprs {
+				star, ok := selectExpr.(*sqlparser.StarExpr)
+				if !ok {
+					continue
+				}
+				for _, ate := range aliasedTableExprs(node.From) {
+					name, err := ate.TableName()
+					if err != nil {
+						continue
+					}
+					// an aliased table can only be referenced through its alias
+					qualifier := name.Name.String()
+					if !ate.As.IsEmpty() {
+						qualifier = ate.As.String()
+					}
+					if !star.TableName.IsEmpty() && star.TableName.Name.String() != qualifier {
+						continue
+					}
+					ts := ctx.SemTable.TableSetFor(ate)
+					if r := restrictions[ts]; r != nil && len(r.DeniedColumns) > 0 {
+						return false, deniedColumnError("*", ts)
+					}
+				}
+			}
+		}
+		return true, nil
+	}, stmt)
+}
+
+// addRowFilters adds the row filters of the restricted tables to the WHERE clause of the statement
+// that reads them, or to the ON condition of the outer join they are on the inner side of.
+func addRowFilters(ctx *plancontext.PlanningContext, stmt sqlparser.Statement, restrictions map[semantics.TableSet]*tableacl.TableRestrictions) (bool, error) {
+	filtered := false
+	rowFilter := func(ate *sqlparser.AliasedTableExpr) (sqlparser.Expr, error) {
+		r := restrictions[ctx.SemTable.TableSetFor(ate)]
+		if r == nil || len(r.RowFilters) == 0 {
+			return nil, nil
+		}
+		name, err := ate.TableName()
+		if err != nil {
+			return nil, err
+		}
+		var predicates []sqlparser.Expr
+		for _, rowFilter := range r.RowFilters {
+			predicate, err := ctx.VSchema.Environment().Parser().ParseExpr(rowFilter)
+			if err != nil {
+				return nil, vterrors.Wrapf(err, "invalid row filter on table %s", sqlparser.String(name))
+			}
+			qualifyColumns(predicate, name)
+			predicates = append(predicates, predicate)
+		}
+		filtered = true
+		return sqlparser.AndExpressions(predicates...), nil
+	}
+
+	err := sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.Select:
+			predicates, err := tableExprsRowFilters(node.From, rowFilter)
+			if err != nil {
+				return false, err
+			}
+			for _, predicate := range predicates {
+				node.AddWhere(predicate)
+			}
+		case *sqlparser.Update:
+			predicates, err := tableExprsRowFilters(node.TableExprs, rowFilter)
+			if err != nil {
+				return false, err
+			}
+			for _, predicate := range predicates {
+				node.AddWhere(predicate)
+			}
+		case *sqlparser.Delete:
+			predicates, err := tableExprsRowFilters(node.TableExprs, rowFilter)
+			if err != nil {
+				return false, err
+			}
+			for _, predicate := range predicates {
+				node.AddWhere(predicate)
+			}
+		}
+		return true, nil
+	}, stmt)
+	return filtered, err
+}
+
+// tableExprsRowFilters returns the row filters that belong to the WHERE clause of the given FROM clause.
+// The row filters of the tables on the inner side of outer joins are added to the join conditions instead.
+func tableExprsRowFilters(tableExprs sqlparser.TableExprs, rowFilter func(*sqlparser.AliasedTableExpr) (sqlparser.Expr, error)) ([]sqlparser.Expr, error) {
+	var where []sqlparser.Expr
+	for _, tableExpr := range tableExprs {
+		predicates, err := tableExprRowFilters(tableExpr, rowFilter)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, predicates...)
+	}
+	return where, nil
+}
+
+func tableExprRowFilters(tableExpr sqlparser.TableExpr, rowFilter func(*sqlparser.AliasedTableExpr) (sqlparser.Expr, error)) ([]sqlparser.Expr, error) {
+	switch tableExpr := tableExpr.(type) {
+	case *sqlparser.AliasedTableExpr:
+		predicate, err := rowFilter(tableExpr)
+		if err != nil || predicate == nil {
+			return nil, err
+		}
+		return []sqlparser.Expr{predicate}, nil
+	case *sqlparser.ParenTableExpr:
+		return tableExprsRowFilters(tableExpr.Exprs, rowFilter)
+	case *sqlparser.JoinTableExpr:
+		left, err := tableExprRowFilters(tableExpr.LeftExpr, rowFilter)
+		if err != nil {
+			return nil, err
+		}
+		right, err := tableExprRowFilters(tableExpr.RightExpr, rowFilter)
+		if err != nil {
+			return nil, err
+		}
+		var inner []sqlparser.Expr
+		switch tableExpr.Join {
+		case sqlparser.LeftJoinType:
+			inner, right = right, nil
+		case sqlparser.RightJoinType:
+			inner, left = left, nil
+		}
+		if len(inner) > 0 {
+			if tableExpr.Condition == nil || len(tableExpr.Condition.Using) > 0 {
+				return nil, vterrors.VT12001("row filter on the inner side of an outer join without an ON condition")
+			}
+			tableExpr.Condition.On = sqlparser.AndExpressions(append([]sqlparser.Expr{tableExpr.Condition.On}, inner...)...)
+		}
+		return append(left, right...), nil
+	}
+	return nil, nil
+}
+
+// aliasedTableExprs returns the tables of a FROM clause, without going into derived tables.
+func aliasedTableExprs(tableExprs sqlparser.TableExprs) []*sqlparser.AliasedTableExpr {
+	var tables []*sqlparser.AliasedTableExpr
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if ate, ok := node.(*sqlparser.AliasedTableExpr); ok {
+			tables = append(tables, ate)
+			return false, nil
+		}
+		return true, nil
+	}, tableExprs)
+	return tables
+}
+
+// qualifyColumns qualifies the unqualified columns of a row filter with the name of its table.
+// The columns of subqueries are left alone, as they belong to the tables of the subqueries.
+func qualifyColumns(predicate sqlparser.Expr, table sqlparser.TableName) {
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.Subquery:
+			return false, nil
+		case *sqlparser.ColName:
+			if node.Qualifier.IsEmpty() {
+				node.Qualifier = table
+			}
+		}
+		return true, nil
+	}, predicate)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/vschemawrapper"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tableaclpb "vitess.io/vitess/go/vt/proto/tableacl"
+	"vitess.io/vitess/go/vt/tableacl"
+	"vitess.io/vitess/go/vt/tableacl/simpleacl"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+)
+
+func TestDataACL(t *testing.T) {
+	dataACL, err := tableacl.NewDataACL(&tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			Name:                 "user",
+			TableNamesOrPrefixes: []string{"user", "authoritative"},
+			ColumnAcls: []*tableaclpb.ColumnACL{{
+				Columns:       []string{"costly", "col2"},
+				DeniedReaders: []string{"analysts"},
+			}},
+			RowFilters: []*tableaclpb.RowFilter{{
+				Predicate: "predef1 = 'eu'",
+				Readers:   []string{"analysts"},
+			}},
+		}},
+	}, &simpleacl.Factory{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		query  string
+		caller *querypb.VTGateCallerID
+		// queries are the queries sent to the tablets, in the order of the plan
+		queries []string
+		err     string
+	}{{
+		name:    "unrestricted caller",
+		query:   "select costly from user where id = 1",
+		caller:  &querypb.VTGateCallerID{Username: "admin"},
+		queries: []string{"select costly from `user` where id = 1"},
+	}, {
+		name:   "denied column",
+		query:  "select id, u.Costly from user as u where id = 1",
+		caller: &querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}},
+		err:    "SELECT command denied for column 'Costly' in table 'user'",
+	}, {
+		name:   "denied column in predicate",
+		query:  "select id from user where costly > 10",
+		caller: &querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}},
+		err:    "SELECT command denied for column 'costly' in table 'user'",
+	}, {
+		name:   "star on table with denied columns",
+		query:  "select * from user where id = 1",
+		caller: &querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}},
+		err:    "SELECT command denied for column '*' in table 'user'",
+	}, {
+		name:   "qualified star on aliased table with denied columns",
+		query:  "select u.* from user as u where id = 1",
+		caller: &querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}},
+		err:    "SELECT command denied for column '*' in table 'user'",
+	}, {
+		name:   "expanded star on authoritative table with denied columns",
+		query:  "select * from authoritative",
+		caller: &querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}},
+		err:    "SELECT command denied for column 'col2' in table 'authoritative'",
+	}, {
+		name:   "denied column in update",
+		query:  "update user set costly = 1 where id = 1",
+		caller: &querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}},
+		err:    "UPDATE command denied for column 'costly' in table 'user'",
+	}, {
+		name:    "row filter",
+		query:   "select id from user where id = 1",
+		caller:  &querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}},
+		queries: []string{"select id from `user` where id = 1 and `user`.predef1 = 'eu'"},
+	}, {
+		name:    "row filter on aliased table",
+		query:   "select u.id, e.col from user as u join user_extra as e on u.id = e.user_id",
+		caller:  &querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}},
+		queries: []string{"select u.id, e.col from `user` as u, user_extra as e where u.predef1 = 'eu' and u.id = e.user_id"},
+	}, {
+		name:    "row filter on the inner side of an outer join",
+		query:   "select e.id, u.id from user_extra as e left join user as u on u.id = e.user_id",
+		caller:  &querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}},
+		queries: []string{"select e.id, u.id from user_extra as e left join `user` as u on u.id = e.user_id and u.predef1 = 'eu'"},
+	}, {
+		name:   "row filter on the inner side of an outer join with using",
+		query:  "select e.id from user_extra as e left join user as u using (id)",
+		caller: &querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}},
+		err:    "VT12001: unsupported: row filter on the inner side of an outer join without an ON condition",
+	}, {
+		name:    "row filter in delete",
+		query:   "delete from user_extra where user_id in (select id from user)",
+		caller:  &querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}},
+		queries: []string{"delete from user_extra where user_id in (select id from `user` where `user`.predef1 = 'eu')"},
+	}, {
+		name:   "stream of restricted table",
+		query:  "stream * from user",
+		caller: &querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}},
+		err:    "STREAM of table user is not allowed for callers with column or row level ACLs",
+	}, {
+		name:   "vstream of restricted table",
+		query:  "vstream * from user",
+		caller: &querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}},
+		err:    "VSTREAM of table user is not allowed for callers with column or row level ACLs",
+	}, {
+		name:   "call by restricted caller",
+		query:  "call proc()",
+		caller: &querypb.VTGateCallerID{Username: "alice", Groups: []string{"analysts"}},
+		err:    "CALL is not allowed for callers with column or row level ACLs",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			vschemaWrapper := &vschemawrapper.VSchemaWrapper{
+				V:        loadSchema(t, "vschemas/schema.json", true),
+				Version:  Gen4,
+				Env:      vtenv.NewTestEnv(),
+				DataACL:  dataACL,
+				CallerID: tc.caller,
+			}
+			plan, err := TestBuilder(tc.query, vschemaWrapper, vschemaWrapper.CurrentDb())
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.queries, planQueries(plan.Instructions))
+		})
+	}
+}
+
+// planQueries returns the queries of the routes of a plan.
+func planQueries(primitive engine.Primitive) []string {
+	var queries []string
+	var collect func(description engine.PrimitiveDescription)
+	collect = func(description engine.PrimitiveDescription) {
+		if query, ok := description.Other["Query"].(string); ok {
+			queries = append(queries, query)
+		}
+		for _, input := range description.Inputs {
+			collect(input)
+		}
+	}
+	collect(engine.PrimitiveToPlanDescription(primitive))
+	return queries
+}
@@ -49,6 +49,11 @@ func gen4DeleteStmtPlanner(
 		return nil, err
 	}
 
+	ctx, err = enforceDataACL(ctx, deleteStmt)
+	if err != nil {
+		return nil, err
+	}
+
 	err = queryRewrite(ctx, deleteStmt)
 	if err != nil {
 		return nil, err
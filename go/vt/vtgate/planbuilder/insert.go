@@ -33,6 +33,11 @@ func gen4InsertStmtPlanner(version querypb.ExecuteOptions_PlannerVersion, insStm
 		return nil, err
 	}
 
+	ctx, err = enforceDataACL(ctx, insStmt)
+	if err != nil {
+		return nil, err
+	}
+
 	err = queryRewrite(ctx, insStmt)
 	if err != nil {
 		return nil, err
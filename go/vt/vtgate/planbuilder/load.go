@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+)
+
+func buildLoadPlan(stmt *sqlparser.Load, query string, vschema plancontext.VSchema) (*planResult, error) {
+	if stmt.Local {
+		return buildLoadLocalPlan(stmt, vschema)
+	}
+
+	keyspace, err := vschema.DefaultKeyspace()
+	if err != nil {
+		return nil, err
+	}
+
+	destination := vschema.Destination()
+	if destination == nil {
+		if err := vschema.ErrorIfShardedF(keyspace, "LOAD", "LOAD is not supported on sharded keyspace, unless it is LOAD DATA LOCAL INFILE"); err != nil {
+			return nil, err
+		}
+		destination = key.DestinationAnyShard{}
+	}
+
+	return newPlanResult(&engine.Send{
+		Keyspace:          keyspace,
+		TargetDestination: destination,
+		Query:             query,
+		IsDML:             true,
+		SingleShardOnly:   true,
+	}), nil
+}
+
+// buildLoadLocalPlan builds the plan of a LOAD DATA LOCAL INFILE statement. The file
+// is read from the client by vtgate, and its lines are inserted in batches of regular
+// inserts, which works on any keyspace.
+func buildLoadLocalPlan(stmt *sqlparser.Load, vschema plancontext.VSchema) (*planResult, error) {
+	if len(stmt.SetExprs) > 0 {
+		return nil, vterrors.VT12001("SET clause in LOAD DATA LOCAL INFILE")
+	}
+	switch strings.ToLower(stmt.Charset.Name) {
+	case "", "utf8", "utf8mb3", "utf8mb4":
+	default:
+		return nil, vterrors.VT12001("CHARACTER SET " + stmt.Charset.Name + " in LOAD DATA LOCAL INFILE")
+	}
+	format, err := loadFormat(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	vtable, _, _, _, err := vschema.FindTable(stmt.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns sqlparser.Columns
+	var fields []bool
+	for _, expr := range stmt.Columns {
+		col, isCol := expr.(*sqlparser.ColName)
+		if isCol {
+			columns = append(columns, col.Name)
+		}
+		fields = append(fields, isCol)
+	}
+	if len(fields) > 0 && len(columns) == 0 {
+		return nil, vterrors.VT12001("LOAD DATA LOCAL INFILE that only assigns user variables")
+	}
+
+	var insert strings.Builder
+	if stmt.Duplicate == sqlparser.LoadDuplicateReplace {
+		insert.WriteString("replace ")
+	} else {
+		insert.WriteString("insert ")
+	}
+	if stmt.Duplicate == sqlparser.LoadDuplicateIgnore {
+		insert.WriteString("ignore ")
+	}
+	insert.WriteString("into ")
+	insert.WriteString(sqlparser.String(stmt.Table))
+	insert.WriteString(sqlparser.String(stmt.Partitions))
+	insert.WriteString(sqlparser.String(columns))
+	insert.WriteString(" values ")
+
+	return newPlanResult(&engine.Load{
+		Keyspace:    vtable.Keyspace,
+		TableName:   vtable.Name.String(),
+		FileName:    stmt.FileName,
+		Format:      format,
+		IgnoreLines: stmt.IgnoreLines,
+		Duplicate:   stmt.Duplicate,
+		Insert:      insert.String(),
+		Fields:      fields,
+	}, singleTable(vtable.Keyspace.Name, vtable.Name.String())), nil
+}
+
+// loadFormat returns the format of the file of a LOAD DATA statement.
+func loadFormat(stmt *sqlparser.Load) (engine.LoadFormat, error) {
+	format := engine.DefaultLoadFormat()
+	if fields := stmt.Fields; fields != nil {
+		if fields.TerminatedBy != nil {
+			format.FieldsTerminatedBy = fields.TerminatedBy.Val
+		}
+		if fields.EnclosedBy != nil {
+			format.FieldsEnclosedBy = fields.EnclosedBy.Val
+		}
+		if fields.EscapedBy != nil {
+			format.FieldsEscapedBy = fields.EscapedBy.Val
+		}
+	}
+	if lines := stmt.Lines; lines != nil {
+		if lines.StartingBy != nil {
+			format.LinesStartingBy = lines.StartingBy.Val
+		}
+		if lines.TerminatedBy != nil {
+			format.LinesTerminatedBy = lines.TerminatedBy.Val
+		}
+	}
+
+	if format.FieldsTerminatedBy == "" || format.LinesTerminatedBy == "" {
+		return format, vterrors.VT12001("LOAD DATA LOCAL INFILE with empty field or line terminators")
+	}
+	if len(format.FieldsEnclosedBy) > 1 || len(format.FieldsEscapedBy) > 1 {
+		return format, vterrors.VT03012("FIELDS ENCLOSED BY and ESCAPED BY must be a single character")
+	}
+	return format, nil
+}
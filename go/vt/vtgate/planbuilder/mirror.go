@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/vt/key"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// buildSelectPlan builds the plan of a SELECT or UNION statement. If the tables it
+// reads have mirror rules, the plan also mirrors the query to the target tables.
+func buildSelectPlan(query string, stmt sqlparser.SelectStatement, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
+	configuredPlanner, err := getConfiguredPlanner(vschema, stmt, query)
+	if err != nil {
+		return nil, err
+	}
+	vs := vschema.GetVSchema()
+	if vschema.Destination() != nil || vs == nil || len(vs.MirrorRules) == 0 || stmt.GetLock() != sqlparser.NoLock {
+		return buildRoutePlan(stmt, reservedVars, vschema, configuredPlanner)
+	}
+
+	// The statement is rewritten while it is planned.
+	mirrorStmt := sqlparser.CloneSelectStatement(stmt)
+	plan, err := configuredPlanner(stmt, reservedVars, vschema)
+	if err != nil {
+		return nil, err
+	}
+
+	percent := mirrorPercent(plan.tables, vschema)
+	if percent == 0 {
+		return plan, nil
+	}
+	target, err := configuredPlanner(mirrorStmt, reservedVars, mirrorVSchema{VSchema: vschema})
+	if err != nil {
+		// The query is not mirrored if it cannot be planned against the target tables.
+		return plan, nil
+	}
+	return newPlanResult(&engine.PercentBasedMirror{
+		Percent:   percent,
+		Primitive: plan.primitive,
+		Target:    target.primitive,
+	}, plan.tables...), nil
+}
+
+// mirrorPercent returns the percentage of the queries reading the given tables that
+// are mirrored, which is the lowest percentage of the mirror rules of the tables.
+// It returns 0 if none of the tables has a mirror rule.
+func mirrorPercent(tables []string, vschema plancontext.VSchema) float32 {
+	var percent float32
+	found := false
+	for _, table := range tables {
+		keyspace, name, ok := strings.Cut(table, ".")
+		if !ok {
+			continue
+		}
+		mr, err := vschema.GetVSchema().FindMirrorRule(keyspace, name, vschema.TabletType())
+		if err != nil || mr == nil {
+			continue
+		}
+		if !found || mr.Percent < percent {
+			percent = mr.Percent
+		}
+		found = true
+	}
+	return percent
+}
+
+// mirrorVSchema is the VSchema the mirrored queries are planned with: the tables
+// that have a mirror rule are resolved to the target tables of their rules.
+type mirrorVSchema struct {
+	plancontext.VSchema
+}
+
+// FindTable implements the plancontext.VSchema interface
+func (vs mirrorVSchema) FindTable(name sqlparser.TableName) (*vindexes.Table, string, topodatapb.TabletType, key.Destination, error) {
+	table, keyspace, tabletType, dest, err := vs.VSchema.FindTable(name)
+	if err != nil {
+		return nil, "", tabletType, nil, err
+	}
+	if target := vs.mirrorTarget(table); target != nil {
+		return target, target.Keyspace.Name, tabletType, dest, nil
+	}
+	return table, keyspace, tabletType, dest, nil
+}
+
+// FindTableOrVindex implements the plancontext.VSchema interface
+func (vs mirrorVSchema) FindTableOrVindex(name sqlparser.TableName) (*vindexes.Table, vindexes.Vindex, string, topodatapb.TabletType, key.Destination, error) {
+	table, vindex, keyspace, tabletType, dest, err := vs.VSchema.FindTableOrVindex(name)
+	if err != nil {
+		return nil, nil, "", tabletType, nil, err
+	}
+	if target := vs.mirrorTarget(table); target != nil {
+		return target, nil, target.Keyspace.Name, tabletType, dest, nil
+	}
+	return table, vindex, keyspace, tabletType, dest, nil
+}
+
+// mirrorTarget returns the target table of the mirror rule of the table, if it has one.
+func (vs mirrorVSchema) mirrorTarget(table *vindexes.Table) *vindexes.Table {
+	if table == nil || table.Keyspace == nil {
+		return nil
+	}
+	mr, err := vs.GetVSchema().FindMirrorRule(table.Keyspace.Name, table.Name.String(), vs.TabletType())
+	if err != nil || mr == nil {
+		return nil
+	}
+	return mr.Table
+}
@@ -77,6 +77,12 @@ func transformToLogicalPlan(ctx *plancontext.PlanningContext, op operators.Opera
 		return transformSequential(ctx, op)
 	case *operators.DMLWithInput:
 		return transformDMLWithInput(ctx, op)
+	case *operators.Window:
+		return transformWindow(ctx, op)
+	case *operators.RecurseCTE:
+		return transformRecurseCTE(ctx, op)
+	case *operators.RecurseCTERef:
+		return transformRecurseCTERef(ctx, op)
 	}
 
 	return nil, vterrors.VT13001(fmt.Sprintf("unknown type encountered: %T (transformToLogicalPlan)", op))
@@ -241,10 +247,33 @@ func transformSubQuery(ctx *plancontext.PlanningContext, op *operators.SubQuery)
 		return newUncorrelatedSubquery(op.FilterType, op.SubqueryValueName, op.HasValuesName, inner, outer), nil
 	}
 
+	if op.PerRowPredicate != nil || op.PerRowValue {
+		return transformCorrelatedSubquery(ctx, op, outer, inner)
+	}
+
 	lhsCols := op.OuterExpressionsNeeded(ctx, op.Outer)
 	return newSemiJoin(outer, inner, op.Vars, lhsCols), nil
 }
 
+func transformCorrelatedSubquery(ctx *plancontext.PlanningContext, op *operators.SubQuery, outer, inner logicalPlan) (logicalPlan, error) {
+	plan := newCorrelatedSubquery(op.FilterType, op.SubqueryValueName, op.HasValuesName, op.Vars, inner, outer)
+	if op.PerRowPredicate == nil {
+		return plan, nil
+	}
+
+	predicate, err := evalengine.Translate(op.PerRowPredicate, &evalengine.Config{
+		ResolveType: ctx.SemTable.TypeForExpr,
+		Collation:   ctx.SemTable.Collation,
+		Environment: ctx.VSchema.Environment(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	plan.eSubquery.Predicate = predicate
+	plan.eSubquery.ASTPredicate = op.PerRowPredicate
+	return plan, nil
+}
+
 // transformFkVerify transforms a FkVerify operator into a logical plan.
 func transformFkVerify(ctx *plancontext.PlanningContext, fkv *operators.FkVerify) (logicalPlan, error) {
 	inputLP, err := transformToLogicalPlan(ctx, fkv.Input)
@@ -293,6 +322,18 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 		aggrParam.OrigOpcode = aggr.OriginalOpCode
 		aggrParam.WCol = aggr.WSOffset
 		aggrParam.Type = aggr.GetTypeCollation(ctx)
+		for idx, offsets := range aggr.ExtraOffsets {
+			typ, _ := ctx.SemTable.TypeForExpr(aggr.Func.GetArgs()[idx+1])
+			col := engine.CheckCol{
+				Col:          offsets.ColOffset,
+				Type:         typ,
+				CollationEnv: ctx.VSchema.Environment().CollationEnv(),
+			}
+			if offsets.WSOffset >= 0 {
+				col.WsCol = &offsets.WSOffset
+			}
+			aggrParam.ExtraCols = append(aggrParam.ExtraCols, col)
+		}
 		oa.aggregates = append(oa.aggregates, aggrParam)
 	}
 	for _, groupBy := range op.Grouping {
@@ -454,6 +495,100 @@ func transformFilter(ctx *plancontext.PlanningContext, op *operators.Filter) (lo
 	}, nil
 }
 
+func transformWindow(ctx *plancontext.PlanningContext, op *operators.Window) (logicalPlan, error) {
+	plan, err := transformToLogicalPlan(ctx, op.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	collationEnv := ctx.VSchema.Environment().CollationEnv()
+	comparison := func(order sqlparser.OrderBy, offsets, wsOffsets []int) evalengine.Comparison {
+		var cmp evalengine.Comparison
+		for idx, by := range order {
+			typ, _ := ctx.SemTable.TypeForExpr(by.Expr)
+			cmp = append(cmp, evalengine.OrderByParams{
+				Col:             offsets[idx],
+				WeightStringCol: wsOffsets[idx],
+				Desc:            by.Direction == sqlparser.DescOrder,
+				Type:            typ,
+				CollationEnv:    collationEnv,
+			})
+		}
+		return cmp
+	}
+
+	prim := &engine.Window{}
+	for _, fn := range op.Funcs {
+		// the rows of a partition only need to be next to each other, so any direction works
+		partitionBy := slice.Map(fn.Spec.PartitionClause, func(expr sqlparser.Expr) *sqlparser.Order {
+			return &sqlparser.Order{Expr: expr, Direction: sqlparser.AscOrder}
+		})
+		wf := &engine.WindowFunc{
+			Opcode:       fn.OpCode,
+			AggrOpcode:   fn.AggrOpCode,
+			Col:          fn.ColOffset,
+			N:            fn.NExpr,
+			Default:      fn.DefaultExpr,
+			Avg:          fn.AvgExpr,
+			PartitionBy:  comparison(partitionBy, fn.PartitionOffsets, fn.PartitionWSOffsets),
+			OrderBy:      comparison(fn.Spec.OrderClause, fn.OrderOffsets, fn.OrderWSOffsets),
+			Frame:        fn.Frame,
+			Alias:        fn.Original.ColumnName(),
+			Expr:         fn.Func,
+			CollationEnv: collationEnv,
+		}
+		if fn.Arg != nil {
+			wf.Type, _ = ctx.SemTable.TypeForExpr(fn.Arg)
+		}
+		prim.Functions = append(prim.Functions, wf)
+	}
+
+	return &window{
+		logicalPlanCommon: newBuilderCommon(plan),
+		ewindow:           prim,
+	}, nil
+}
+
+func transformRecurseCTE(ctx *plancontext.PlanningContext, op *operators.RecurseCTE) (logicalPlan, error) {
+	seed, err := transformToLogicalPlan(ctx, op.Seed)
+	if err != nil {
+		return nil, err
+	}
+	term, err := transformToLogicalPlan(ctx, op.Term)
+	if err != nil {
+		return nil, err
+	}
+	return &recurseCTE{
+		seed: seed,
+		term: term,
+		ecte: &engine.RecurseCTE{
+			Vars:      op.Vars,
+			Distinct:  op.Distinct,
+			CheckCols: op.CheckCols,
+		},
+	}, nil
+}
+
+// transformRecurseCTERef builds a projection over a single row that produces the
+// values of the row from the previous iteration, read from the bind variables
+func transformRecurseCTERef(ctx *plancontext.PlanningContext, op *operators.RecurseCTERef) (logicalPlan, error) {
+	cfg := &evalengine.Config{
+		ResolveType: ctx.SemTable.TypeForExpr,
+		Collation:   ctx.SemTable.Collation,
+		Environment: ctx.VSchema.Environment(),
+	}
+	prim := &engine.Projection{Input: &engine.SingleRow{}}
+	for _, col := range op.Columns {
+		expr, err := evalengine.Translate(op.ArgumentFor(ctx, col.Expr), cfg)
+		if err != nil {
+			return nil, err
+		}
+		prim.Exprs = append(prim.Exprs, expr)
+		prim.Cols = append(prim.Cols, col.ColumnName())
+	}
+	return &primitiveWrapper{prim: prim}, nil
+}
+
 func transformApplyJoinPlan(ctx *plancontext.PlanningContext, n *operators.ApplyJoin) (logicalPlan, error) {
 	lhs, err := transformToLogicalPlan(ctx, n.LHS)
 	if err != nil {
@@ -280,6 +280,11 @@ func (qb *queryBuilder) sortTables() {
 		if !isSel {
 			return true, nil
 		}
+		if hasLateralDerivedTable(sel) {
+			// LATERAL derived tables have to come after the tables they are using columns from,
+			// so we keep the tables in the order they were added to the query
+			return true, nil
+		}
 		ts := &tableSorter{
 			sel: sel,
 			tbl: qb.ctx.SemTable,
@@ -290,6 +295,19 @@ func (qb *queryBuilder) sortTables() {
 
 }
 
+func hasLateralDerivedTable(sel *sqlparser.Select) bool {
+	for _, tableExpr := range sel.From {
+		aliasedExpr, ok := tableExpr.(*sqlparser.AliasedTableExpr)
+		if !ok {
+			continue
+		}
+		if dt, ok := aliasedExpr.Expr.(*sqlparser.DerivedTable); ok && dt.Lateral {
+			return true
+		}
+	}
+	return false
+}
+
 type tableSorter struct {
 	sel *sqlparser.Select
 	tbl *semantics.SemTable
@@ -385,6 +403,8 @@ func buildQuery(op Operator, qb *queryBuilder) {
 		buildAggregation(op, qb)
 	case *Union:
 		buildUnion(op, qb)
+	case *RecurseCTE:
+		buildRecurseCTE(op, qb)
 	case *Distinct:
 		buildQuery(op.Source, qb)
 		qb.asSelectStatement().MakeDistinct()
@@ -533,9 +553,40 @@ func buildApplyJoin(op *ApplyJoin, qb *queryBuilder) {
 
 	qbR := &queryBuilder{ctx: qb.ctx}
 	buildQuery(op.RHS, qbR)
+	if op.Lateral {
+		restoreLateralColumns(qbR.stmt.(FromStatement), op.ExtraLHSVars)
+	}
 	qb.joinWith(qbR, pred, op.JoinType)
 }
 
+// restoreLateralColumns puts back the LHS columns in the derived tables that have been using arguments for them,
+// and marks these derived tables as LATERAL, so they can use the columns of the tables that come before them
+func restoreLateralColumns(stmt FromStatement, vars []BindVarExpr) {
+	for _, tableExpr := range stmt.GetFrom() {
+		aliasedExpr, ok := tableExpr.(*sqlparser.AliasedTableExpr)
+		if !ok {
+			continue
+		}
+		dt, ok := aliasedExpr.Expr.(*sqlparser.DerivedTable)
+		if !ok {
+			continue
+		}
+		dt.Select = sqlparser.Rewrite(dt.Select, nil, func(cursor *sqlparser.Cursor) bool {
+			arg, ok := cursor.Node().(*sqlparser.Argument)
+			if !ok {
+				return true
+			}
+			for _, bve := range vars {
+				if bve.Name == arg.Name {
+					cursor.Replace(sqlparser.CloneExpr(bve.Expr))
+					dt.Lateral = true
+				}
+			}
+			return true
+		}).(sqlparser.SelectStatement)
+	}
+}
+
 func buildUnion(op *Union, qb *queryBuilder) {
 	// the first input is built first
 	buildQuery(op.Sources[0], qb)
@@ -569,6 +620,10 @@ func buildDerived(op *Horizon, qb *queryBuilder) {
 	qb.stmt = nil
 	switch sel := stmt.(type) {
 	case *sqlparser.Select:
+		if _, isRecursive := op.Source.(*RecurseCTE); isRecursive {
+			buildDerivedRecurseCTE(op, qb, sel)
+			return
+		}
 		buildDerivedSelect(op, qb, sel)
 		return
 	case *sqlparser.Union:
@@ -612,6 +667,35 @@ func buildDerivedSelect(op *Horizon, qb *queryBuilder, sel *sqlparser.Select) {
 	}
 }
 
+// buildRecurseCTE builds a query selecting all the rows of a recursive CTE that has been merged
+// into a single route. The CTE is sent as is, and evaluated by MySQL.
+func buildRecurseCTE(op *RecurseCTE, qb *queryBuilder) {
+	union := sqlparser.CloneRefOfUnion(op.Query)
+	sqlparser.RemoveKeyspace(union)
+
+	qb.stmt = &sqlparser.Select{
+		With: &sqlparser.With{
+			Recursive: true,
+			CTEs: []*sqlparser.CommonTableExpr{{
+				ID:       op.CTE.ID,
+				Columns:  op.CTE.Columns,
+				Subquery: &sqlparser.Subquery{Select: union},
+			}},
+		},
+		SelectExprs: sqlparser.SelectExprs{&sqlparser.StarExpr{}},
+		From:        sqlparser.TableExprs{sqlparser.NewAliasedTableExpr(sqlparser.NewTableName(op.CTE.ID.String()), "")},
+	}
+}
+
+func buildDerivedRecurseCTE(op *Horizon, qb *queryBuilder, sel *sqlparser.Select) {
+	qb.addTableExpr(op.Alias, op.Alias, TableID(op), &sqlparser.DerivedTable{
+		Select: sel,
+	}, nil, op.ColumnAliases)
+	for _, col := range op.Columns {
+		qb.addProjection(&sqlparser.AliasedExpr{Expr: col})
+	}
+}
+
 func buildHorizon(op *Horizon, qb *queryBuilder) {
 	buildQuery(op.Source, qb)
 	stripDownQuery(op.Query, qb.asSelectStatement())
@@ -27,13 +27,6 @@ import (
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 )
 
-func errDistinctAggrWithMultiExpr(f sqlparser.AggrFunc) {
-	if f == nil {
-		panic(vterrors.VT12001("distinct aggregation function with multiple expressions"))
-	}
-	panic(vterrors.VT12001(fmt.Sprintf("distinct aggregation function with multiple expressions '%s'", sqlparser.String(f))))
-}
-
 func tryPushAggregator(ctx *plancontext.PlanningContext, aggregator *Aggregator) (output Operator, applyResult *ApplyResult) {
 	if aggregator.Pushed {
 		return aggregator, NoRewrite
@@ -55,6 +48,12 @@ func tryPushAggregator(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 		return splitAvgAggregations(ctx, aggregator)
 	}
 
+	// the values concatenated by the shards can't be merged in the order that a GROUP_CONCAT with ORDER BY asks for,
+	// so these aggregations are not pushed down. They are evaluated at the vtgate level over the sorted rows instead
+	if planGroupConcatOrder(ctx, aggregator) {
+		return aggregator, NoRewrite
+	}
+
 	switch src := aggregator.Source.(type) {
 	case *Route:
 		// if we have a single sharded route, we can push it down
@@ -80,6 +79,60 @@ func tryPushAggregator(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 	return
 }
 
+// planGroupConcatOrder sets the ordering needed to evaluate GROUP_CONCAT with ORDER BY at the vtgate level.
+// It returns false if there are no such aggregations.
+func planGroupConcatOrder(ctx *plancontext.PlanningContext, aggregator *Aggregator) bool {
+	equalOrder := func(a, b *sqlparser.Order) bool {
+		return a.Direction == b.Direction && ctx.SemTable.EqualsExpr(a.Expr, b.Expr)
+	}
+
+	var order sqlparser.OrderBy
+	for _, aggr := range aggregator.Aggregations {
+		gc, ok := aggr.Func.(*sqlparser.GroupConcatExpr)
+		if !ok || len(gc.OrderBy) == 0 {
+			continue
+		}
+		if order == nil {
+			order = gc.OrderBy
+			continue
+		}
+		if !slices.EqualFunc(order, gc.OrderBy, equalOrder) {
+			panic(vterrors.VT12001(fmt.Sprintf("GROUP_CONCAT with different ORDER BY in a cross-shard query: %s", sqlparser.String(aggr.Original))))
+		}
+	}
+	if order == nil {
+		return false
+	}
+
+	// all the rows are sent to the vtgate, so distinct aggregations have to be evaluated there as well
+	var distinctExprs sqlparser.Exprs
+	for _, aggr := range aggregator.Aggregations {
+		if !aggr.Distinct {
+			continue
+		}
+		args := aggr.Func.GetArgs()
+		if distinctExprs == nil {
+			distinctExprs = args
+			continue
+		}
+		if !slices.EqualFunc(distinctExprs, args, ctx.SemTable.EqualsExpr) {
+			panic(vterrors.VT12001(fmt.Sprintf("only one DISTINCT aggregation is allowed in a SELECT: %s", sqlparser.String(aggr.Original))))
+		}
+	}
+
+	// duplicates are found by comparing each row with the previous one,
+	// which only works if the GROUP_CONCAT ordering keeps them next to each other
+	for _, o := range order {
+		if distinctExprs != nil && !slices.ContainsFunc(distinctExprs, func(e sqlparser.Expr) bool { return ctx.SemTable.EqualsExpr(e, o.Expr) }) {
+			panic(vterrors.VT12001(fmt.Sprintf("GROUP_CONCAT ordering on an expression that is not part of the DISTINCT aggregation in a cross-shard query: %s", sqlparser.String(o.Expr))))
+		}
+	}
+
+	aggregator.GroupConcatOrder = order
+	aggregator.DistinctExprs = distinctExprs
+	return true
+}
+
 func reachedPhase(ctx *plancontext.PlanningContext, p Phase) bool {
 	b := ctx.CurrentPhase >= int(p)
 	return b
@@ -141,6 +194,11 @@ func aggregateTheAggregate(a *Aggregator, i int) {
 		// Think of it as we are SUMming together a bunch of distributed COUNTs.
 		aggr.OriginalOpCode, aggr.OpCode = aggr.OpCode, opcode.AggregateSum
 		a.Aggregations[i] = aggr
+	case opcode.AggregateGroupConcatDistinct:
+		// When pushed down, every shard returns the concatenation of its own distinct values,
+		// and these are concatenated above the Route.
+		aggr.OriginalOpCode, aggr.OpCode = aggr.OpCode, opcode.AggregateGroupConcat
+		a.Aggregations[i] = aggr
 	}
 }
 
@@ -180,10 +238,6 @@ func pushAggregations(ctx *plancontext.PlanningContext, aggregator *Aggregator,
 			continue
 		}
 
-		if len(distinctExprs) != 1 {
-			errDistinctAggrWithMultiExpr(aggr.Func)
-		}
-
 		// We handle a distinct aggregation by turning it into a group by and
 		// doing the aggregating on the vtgate level instead
 		aeDistinctExpr := aeWrap(distinctExprs[0])
@@ -196,12 +250,19 @@ func pushAggregations(ctx *plancontext.PlanningContext, aggregator *Aggregator,
 			groupBy := NewGroupBy(distinctExprs[0])
 			groupBy.ColOffset = aggr.ColOffset
 			aggrBelowRoute.Grouping = append(aggrBelowRoute.Grouping, groupBy)
+
+			// The remaining expressions of a distinct aggregation over more than one expression are grouped on as well.
+			// They are added to both aggregators, so they end up on the same offsets on both sides of the route.
+			for _, expr := range distinctExprs[1:] {
+				aggrBelowRoute.addColumnWithoutPushing(ctx, aeWrap(expr), true)
+				aggregator.Columns = append(aggregator.Columns, aeWrap(expr))
+			}
 			distinctAggrGroupByAdded = true
 		}
 	}
 
 	if !canPushDistinctAggr {
-		aggregator.DistinctExpr = distinctExprs[0]
+		aggregator.DistinctExprs = distinctExprs
 	}
 }
 
@@ -229,11 +290,8 @@ func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 		if len(distinctExprs) == 0 {
 			distinctExprs = args
 		}
-		for idx, expr := range distinctExprs {
-			if !ctx.SemTable.EqualsExpr(expr, args[idx]) {
-				differentExpr = aggr.Original
-				break
-			}
+		if !slices.EqualFunc(distinctExprs, args, ctx.SemTable.EqualsExpr) {
+			differentExpr = aggr.Original
 		}
 	}
 
@@ -551,10 +609,7 @@ func splitAggrColumnsToLeftAndRight(
 	// Distinct aggregation cannot be pushed down in the join.
 	// We keep node of the distinct aggregation expression to be used later for ordering.
 	if !canPushDistinctAggr {
-		if len(distinctExprs) != 1 {
-			errDistinctAggrWithMultiExpr(nil)
-		}
-		aggregator.DistinctExpr = distinctExprs[0]
+		aggregator.DistinctExprs = distinctExprs
 		return nil, errAbortAggrPushing
 	}
 
@@ -621,13 +676,9 @@ func splitAvgAggregations(ctx *plancontext.PlanningContext, aggr *Aggregator) (O
 			continue
 		}
 
-		if avg.Distinct {
-			panic(vterrors.VT12001("AVG(distinct <>)"))
-		}
-
-		// We have an AVG that we need to split
-		sumExpr := &sqlparser.Sum{Arg: avg.Arg}
-		countExpr := &sqlparser.Count{Args: []sqlparser.Expr{avg.Arg}}
+		// We have an AVG that we need to split. AVG(DISTINCT x) is split into SUM(DISTINCT x) and COUNT(DISTINCT x)
+		sumExpr := &sqlparser.Sum{Arg: avg.Arg, Distinct: avg.Distinct}
+		countExpr := &sqlparser.Count{Args: []sqlparser.Expr{avg.Arg}, Distinct: avg.Distinct}
 		calcExpr := &sqlparser.BinaryExpr{
 			Operator: sqlparser.DivOp,
 			Left:     sumExpr,
@@ -643,9 +694,12 @@ func splitAvgAggregations(ctx *plancontext.PlanningContext, aggr *Aggregator) (O
 			if offset == aggregation.ColOffset {
 				// We have found the AVG column. We'll change it to SUM, and then we add a COUNT as well
 				aggr.Aggregations[aggrOffset].OpCode = opcode.AggregateSum
+				if avg.Distinct {
+					aggr.Aggregations[aggrOffset].OpCode = opcode.AggregateSumDistinct
+				}
 
 				countExprAlias := aeWrap(countExpr)
-				countAggr := NewAggr(opcode.AggregateCount, countExpr, countExprAlias, sqlparser.String(countExpr))
+				countAggr := createAggrFromAggrFunc(countExpr, countExprAlias)
 				countAggr.ColOffset = len(aggr.Columns) + len(columns)
 				aggregations = append(aggregations, countAggr)
 				columns = append(columns, countExprAlias)
@@ -132,11 +132,7 @@ func (ab *aggBuilder) handleAggr(ctx *plancontext.PlanningContext, aggr Aggr) er
 		return ab.handleAggrWithCountStarMultiplier(ctx, aggr)
 	case opcode.AggregateMax, opcode.AggregateMin, opcode.AggregateAnyValue:
 		return ab.handlePushThroughAggregation(ctx, aggr)
-	case opcode.AggregateGroupConcat:
-		f := aggr.Func.(*sqlparser.GroupConcatExpr)
-		if f.Distinct || len(f.OrderBy) > 0 || f.Separator != "" {
-			panic("fail here")
-		}
+	case opcode.AggregateGroupConcat, opcode.AggregateGroupConcatDistinct:
 		// this needs special handling, currently aborting the push of function
 		// and later will try pushing the column instead.
 		// TODO: this should be handled better by pushing the function down.
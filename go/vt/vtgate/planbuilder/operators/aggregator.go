@@ -40,10 +40,15 @@ type (
 		Grouping     []GroupBy
 		Aggregations []Aggr
 
-		// We support a single distinct aggregation per aggregator. It is stored here.
+		// We support a single distinct aggregation per aggregator. Its expressions are stored here.
 		// When planning the ordering that the OrderedAggregate will require,
-		// this needs to be the last ORDER BY expression
-		DistinctExpr sqlparser.Expr
+		// these need to be the last ORDER BY expressions
+		DistinctExprs sqlparser.Exprs
+
+		// GroupConcatOrder is the ORDER BY of the GROUP_CONCAT aggregations that are evaluated
+		// at the vtgate level. The rows are sorted by it after the grouping columns,
+		// so the values are concatenated in the order the query asks for
+		GroupConcatOrder sqlparser.OrderBy
 
 		// Pushed will be set to true once this aggregation has been pushed deeper in the tree
 		Pushed        bool
@@ -288,6 +293,10 @@ func (a *Aggregator) planOffsets(ctx *plancontext.PlanningContext) Operator {
 	}
 
 	for idx, aggr := range a.Aggregations {
+		if aggr.OpCode.IsDistinct() {
+			// the extra arguments of distinct aggregations are grouped on below the route
+			a.Aggregations[idx].ExtraOffsets = a.addExtraArgs(ctx, aggr, true)
+		}
 		if !aggr.NeedsWeightString(ctx) {
 			continue
 		}
@@ -298,16 +307,31 @@ func (a *Aggregator) planOffsets(ctx *plancontext.PlanningContext) Operator {
 	return nil
 }
 
+// addExtraArgs adds the arguments after the first one of an aggregation over more than one expression,
+// and the weight strings needed to compare them, returning their offsets
+func (a *Aggregator) addExtraArgs(ctx *plancontext.PlanningContext, aggr Aggr, addToGroupBy bool) []ArgOffsets {
+	var offsets []ArgOffsets
+	for _, arg := range aggr.extraArgs() {
+		argOffsets := ArgOffsets{
+			ColOffset: a.internalAddColumn(ctx, aeWrap(arg), addToGroupBy),
+			WSOffset:  -1,
+		}
+		if aggr.OpCode.NeedsComparableValues() && ctx.SemTable.NeedsWeightString(arg) {
+			argOffsets.WSOffset = a.internalAddColumn(ctx, aeWrap(weightStringFor(arg)), addToGroupBy)
+		}
+		offsets = append(offsets, argOffsets)
+	}
+	return offsets
+}
+
 func (aggr Aggr) getPushColumn() sqlparser.Expr {
 	switch aggr.OpCode {
 	case opcode.AggregateAnyValue:
 		return aggr.Original.Expr
 	case opcode.AggregateCountStar:
 		return sqlparser.NewIntLiteral("1")
-	case opcode.AggregateGroupConcat:
-		if len(aggr.Func.GetArgs()) > 1 {
-			panic(vterrors.VT12001("group_concat with more than 1 column"))
-		}
+	case opcode.AggregateGroupConcat, opcode.AggregateGroupConcatDistinct, opcode.AggregateCountDistinct:
+		// the remaining arguments are added separately, see addExtraArgs
 		return aggr.Func.GetArg()
 	default:
 		if len(aggr.Func.GetArgs()) > 1 {
@@ -390,6 +414,9 @@ func (a *Aggregator) pushRemainingGroupingColumnsAndWeightStrings(ctx *planconte
 		a.Grouping[idx].WSOffset = offset
 	}
 	for idx, aggr := range a.Aggregations {
+		if aggr.ExtraOffsets == nil {
+			a.Aggregations[idx].ExtraOffsets = a.addExtraArgs(ctx, aggr, false)
+		}
 		if aggr.WSOffset != -1 || !aggr.NeedsWeightString(ctx) {
 			continue
 		}
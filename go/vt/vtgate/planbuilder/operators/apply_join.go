@@ -50,6 +50,9 @@ type (
 		// these are needed by other operators further down the right hand side of the join
 		ExtraLHSVars []BindVarExpr
 
+		// Lateral is set when the RHS is a LATERAL derived table that uses the ExtraLHSVars in place of LHS columns
+		Lateral bool
+
 		// After offset planning
 
 		// Columns stores the column indexes of the columns coming from the left and right side
@@ -18,6 +18,7 @@ package operators
 
 import (
 	"fmt"
+	"slices"
 
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -148,6 +149,9 @@ func createOperatorFromUnion(ctx *plancontext.PlanningContext, node *sqlparser.U
 	if isRHSUnion {
 		panic(vterrors.VT12001("nesting of UNIONs on the right-hand side"))
 	}
+	if recursiveCTERef(ctx, node) != nil {
+		return createRecurseCTE(ctx, node)
+	}
 	opLHS := translateQueryToOp(ctx, node.Left)
 	opRHS := translateQueryToOp(ctx, node.Right)
 	lexprs := ctx.SemTable.SelectExprs(node.Left)
@@ -223,6 +227,12 @@ func getOperatorFromTableExpr(ctx *plancontext.PlanningContext, tableExpr sqlpar
 
 func getOperatorFromJoinTableExpr(ctx *plancontext.PlanningContext, tableExpr *sqlparser.JoinTableExpr) Operator {
 	lhs := getOperatorFromTableExpr(ctx, tableExpr.LeftExpr, false)
+	if lateral := createLateralJoin(ctx, lhs, tableExpr.RightExpr, tableExpr.Join); lateral != nil {
+		if tableExpr.Join == sqlparser.LeftJoinType {
+			return setOuterJoinPredicate(lateral, tableExpr.Condition.On)
+		}
+		return addJoinPredicates(ctx, tableExpr.Condition.On, lateral)
+	}
 	rhs := getOperatorFromTableExpr(ctx, tableExpr.RightExpr, false)
 
 	switch tableExpr.Join {
@@ -246,6 +256,10 @@ func getOperatorFromAliasedTableExpr(ctx *plancontext.PlanningContext, tableExpr
 			panic(err)
 		}
 
+		if ref, isRecursiveRef := tableInfo.(*semantics.RecursiveCTETable); isRecursiveRef {
+			return newRecurseCTERef(ctx, tableID, ref)
+		}
+
 		if vt, isVindex := tableInfo.(*semantics.VindexTable); isVindex {
 			solves := tableID
 			return &Vindex{
@@ -269,24 +283,112 @@ func getOperatorFromAliasedTableExpr(ctx *plancontext.PlanningContext, tableExpr
 			tbl.Select.SetOrderBy(nil)
 		}
 
-		inner := translateQueryToOp(ctx, tbl.Select)
-		if horizon, ok := inner.(*Horizon); ok {
-			horizon.TableId = &tableID
-			horizon.Alias = tableExpr.As.String()
-			horizon.ColumnAliases = tableExpr.Columns
-			qp := CreateQPFromSelectStatement(ctx, tbl.Select)
-			horizon.QP = qp
+		return createDerivedTableOp(ctx, tableExpr, tbl.Select)
+	default:
+		panic(vterrors.VT13001(fmt.Sprintf("unable to use: %T", tbl)))
+	}
+}
+
+func createDerivedTableOp(ctx *plancontext.PlanningContext, tableExpr *sqlparser.AliasedTableExpr, sel sqlparser.SelectStatement) Operator {
+	tableID := ctx.SemTable.TableSetFor(tableExpr)
+	inner := translateQueryToOp(ctx, sel)
+	if horizon, ok := inner.(*Horizon); ok {
+		horizon.TableId = &tableID
+		horizon.Alias = tableExpr.As.String()
+		horizon.ColumnAliases = tableExpr.Columns
+		qp := CreateQPFromSelectStatement(ctx, sel)
+		horizon.QP = qp
+	}
+
+	return inner
+}
+
+// createLateralJoin joins a LATERAL derived table that uses columns from the LHS with the LHS.
+// The columns from the LHS are replaced with arguments in the derived table, so that it can be
+// evaluated once for every row of the LHS if the two sides can't be merged into a single route.
+// If the table expression is not such a derived table, nil is returned.
+func createLateralJoin(ctx *plancontext.PlanningContext, lhs Operator, tableExpr sqlparser.TableExpr, joinType sqlparser.JoinType) *Join {
+	aliasedExpr, ok := tableExpr.(*sqlparser.AliasedTableExpr)
+	if !ok {
+		return nil
+	}
+	dt, ok := aliasedExpr.Expr.(*sqlparser.DerivedTable)
+	if !ok || !dt.Lateral {
+		return nil
+	}
+
+	lhsID := TableID(lhs)
+	isLHSColumn := func(node sqlparser.SQLNode) bool {
+		col, ok := node.(*sqlparser.ColName)
+		if !ok {
+			return false
+		}
+		deps := ctx.SemTable.DirectDeps(col)
+		return deps.NotEmpty() && deps.IsSolvedBy(lhsID)
+	}
+	usesLHS := func(node sqlparser.SQLNode) (found bool) {
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			found = found || isLHSColumn(node)
+			return !found, nil
+		}, node)
+		return
+	}
+
+	var vars []BindVarExpr
+	sel := sqlparser.CopyOnRewrite(dt.Select, nil, func(cursor *sqlparser.CopyOnWriteCursor) {
+		if !isLHSColumn(cursor.Node()) {
+			return
 		}
+		col := cursor.Node().(*sqlparser.ColName)
+		name := ctx.GetReservedArgumentFor(col)
+		if !slices.ContainsFunc(vars, func(bve BindVarExpr) bool { return bve.Name == name }) {
+			vars = append(vars, BindVarExpr{Name: name, Expr: col})
+		}
+		cursor.Replace(sqlparser.NewArgument(name))
+	}, ctx.SemTable.CopySemanticInfo).(sqlparser.SelectStatement)
+	if len(vars) == 0 {
+		return nil
+	}
 
-		return inner
+	switch joinType {
+	case sqlparser.NormalJoinType, sqlparser.StraightJoinType, sqlparser.LeftJoinType:
 	default:
-		panic(vterrors.VT13001(fmt.Sprintf("unable to use: %T", tbl)))
+		panic(vterrors.VT12001(fmt.Sprintf("LATERAL derived table using columns from the other side of a %s", joinType.ToString())))
+	}
+
+	for _, s := range sqlparser.GetAllSelects(dt.Select) {
+		if usesLHS(s.SelectExprs) {
+			panic(vterrors.VT12001("LATERAL derived table using columns from the other side of the join in its SELECT expressions"))
+		}
+	}
+
+	var predicates []sqlparser.Expr
+	if s, isSel := dt.Select.(*sqlparser.Select); isSel && s.Where != nil {
+		for _, pred := range sqlparser.SplitAndExpression(nil, s.Where.Expr) {
+			if usesLHS(pred) {
+				predicates = append(predicates, pred)
+			}
+		}
+	}
+
+	return &Join{
+		LHS:               lhs,
+		RHS:               createDerivedTableOp(ctx, aliasedExpr, sel),
+		JoinType:          joinType,
+		LateralVars:       vars,
+		LateralPredicates: predicates,
 	}
 }
 
 func crossJoin(ctx *plancontext.PlanningContext, exprs sqlparser.TableExprs) Operator {
 	var output Operator
 	for _, tableExpr := range exprs {
+		if output != nil {
+			if lateral := createLateralJoin(ctx, output, tableExpr, sqlparser.NormalJoinType); lateral != nil {
+				output = lateral
+				continue
+			}
+		}
 		op := getOperatorFromTableExpr(ctx, tableExpr, len(exprs) == 1)
 		if output == nil {
 			output = op
@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"io"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+)
+
+// tryMergeRecurseCTE merges the Seed and the Term of a recursive CTE into a single route,
+// when they both go to the same single shard. MySQL then evaluates the whole recursion.
+func tryMergeRecurseCTE(ctx *plancontext.PlanningContext, in *RecurseCTE) (Operator, *ApplyResult) {
+	seed, ok := in.Seed.(*Route)
+	if !ok {
+		return in, NoRewrite
+	}
+
+	termRoutes, ok := recurseCTETermRoutes(in.Term)
+	if !ok {
+		return in, NoRewrite
+	}
+
+	recursiveVars := recurseCTEVars(in)
+	routing := seed.Routing
+	current := seed
+	for _, route := range termRoutes {
+		if usesVars(route.Routing, recursiveVars) {
+			// the route depends on the rows of the previous iteration, so it can't be decided up front
+			return in, NoRewrite
+		}
+		routing = mergeRecurseCTERouting(ctx, current, route)
+		if routing == nil {
+			return in, NoRewrite
+		}
+		current = &Route{Routing: routing}
+	}
+	if !isSingleShard(routing) {
+		return in, NoRewrite
+	}
+
+	term := in.Term
+	if len(termRoutes) > 0 {
+		term = unwrapRoutes(term)
+	}
+	rcte := in.Clone([]Operator{seed.Source, term}).(*RecurseCTE)
+	return &Route{
+		Source:     rcte,
+		MergedWith: termRoutes,
+		Routing:    routing,
+	}, Rewrote("merged recursive CTE into a single route")
+}
+
+// recurseCTETermRoutes returns the routes of the Term. It returns false if the Term has
+// operators other than routes, joins, filters and projections, which are evaluated by the vtgate.
+func recurseCTETermRoutes(op Operator) ([]*Route, bool) {
+	switch op := op.(type) {
+	case *Route:
+		return []*Route{op}, true
+	case *RecurseCTERef:
+		return nil, true
+	case *ApplyJoin, *Filter, *Projection:
+	case *Horizon:
+		if op.TableId != nil {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+	var routes []*Route
+	for _, input := range op.Inputs() {
+		inputRoutes, ok := recurseCTETermRoutes(input)
+		if !ok {
+			return nil, false
+		}
+		routes = append(routes, inputRoutes...)
+	}
+	return routes, true
+}
+
+// recurseCTEVars returns the names of the bind variables that hold values of the rows
+// of the previous iteration, in the Term.
+func recurseCTEVars(in *RecurseCTE) map[string]any {
+	vars := map[string]any{}
+	for name := range in.Vars {
+		vars[name] = nil
+	}
+	_ = Visit(in.Term, func(op Operator) error {
+		aj, ok := op.(*ApplyJoin)
+		if !ok {
+			return nil
+		}
+		for _, col := range append(aj.JoinPredicates.columns, aj.JoinColumns.columns...) {
+			for _, bve := range col.LHSExprs {
+				vars[bve.Name] = nil
+			}
+		}
+		for _, bve := range aj.ExtraLHSVars {
+			vars[bve.Name] = nil
+		}
+		return nil
+	})
+	return vars
+}
+
+// usesVars returns true if the shard of the routing is decided by one of the bind variables
+func usesVars(routing Routing, vars map[string]any) bool {
+	sr, ok := routing.(*ShardedRouting)
+	if !ok {
+		return false
+	}
+	found := false
+	for _, expr := range sr.VindexExpressions() {
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			if arg, ok := node.(*sqlparser.Argument); ok {
+				if _, isVar := vars[arg.Name]; isVar {
+					found = true
+					return false, io.EOF
+				}
+			}
+			return true, nil
+		}, expr)
+	}
+	return found
+}
+
+// mergeRecurseCTERouting returns the routing of the two routes merged together,
+// or nil if they might go to different shards.
+func mergeRecurseCTERouting(ctx *plancontext.PlanningContext, lhs, rhs *Route) Routing {
+	_, _, routingA, routingB, a, b, sameKeyspace := prepareInputRoutes(lhs, rhs)
+	switch {
+	case a == dual:
+		return routingB
+	case b == dual:
+		return routingA
+	case !sameKeyspace:
+		return nil
+	case a == anyShard:
+		return routingB
+	case b == anyShard:
+		return routingA
+	case a == sharded && b == sharded:
+		tblA := routingA.(*ShardedRouting)
+		tblB := routingB.(*ShardedRouting)
+		if tblA.RouteOpCode == engine.EqualUnique && tblB.RouteOpCode == engine.EqualUnique &&
+			tblA.SelectedVindex() == tblB.SelectedVindex() &&
+			gen4ValuesEqual(ctx, tblA.VindexExpressions(), tblB.VindexExpressions()) {
+			return routingA
+		}
+	}
+	return nil
+}
+
+func isSingleShard(routing Routing) bool {
+	switch r := routing.(type) {
+	case *DualRouting, *AnyShardRouting:
+		return true
+	case *ShardedRouting:
+		return r.RouteOpCode == engine.EqualUnique
+	}
+	return false
+}
+
+// unwrapRoutes replaces the routes of the Term with their sources
+func unwrapRoutes(term Operator) Operator {
+	return BottomUp(term, TableID, func(op Operator, _ semantics.TableSet, _ bool) (Operator, *ApplyResult) {
+		if route, ok := op.(*Route); ok {
+			return route.Source, Rewrote("removed route merged into the recursive CTE")
+		}
+		return op, NoRewrite
+	}, stopAtRoute)
+}
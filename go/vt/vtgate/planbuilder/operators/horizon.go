@@ -91,6 +91,10 @@ func (h *Horizon) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.
 		h.Source = h.Source.AddPredicate(ctx, expr)
 		return h
 	}
+	if _, isRecursive := h.Source.(*RecurseCTE); isRecursive {
+		// predicates can't be pushed into the recursion, so they are evaluated on the results of it
+		return newFilter(h, expr)
+	}
 	tableInfo, err := ctx.SemTable.TableInfoForExpr(expr)
 	if err != nil {
 		if errors.Is(err, semantics.ErrNotSingleTable) {
@@ -74,9 +74,17 @@ func expandUnionHorizon(ctx *plancontext.PlanningContext, horizon *Horizon, unio
 }
 
 func expandSelectHorizon(ctx *plancontext.PlanningContext, horizon *Horizon, sel *sqlparser.Select) (Operator, *ApplyResult) {
-	op := createProjectionFromSelect(ctx, horizon)
 	qp := horizon.getQP(ctx)
 	var extracted []string
+	if windowFuncs := getWindowFuncs(ctx, sel); len(windowFuncs) > 0 {
+		if qp.NeedsAggregation() {
+			panic(vterrors.VT12001("window functions together with aggregation in a cross-shard query"))
+		}
+		horizon.Source = newWindow(ctx, horizon.src(), sel, windowFuncs)
+		extracted = append(extracted, "Window")
+	}
+
+	op := createProjectionFromSelect(ctx, horizon)
 	if qp.HasAggr {
 		extracted = append(extracted, "Aggregation")
 	} else {
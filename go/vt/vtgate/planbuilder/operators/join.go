@@ -17,6 +17,8 @@ limitations under the License.
 package operators
 
 import (
+	"slices"
+
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
@@ -30,6 +32,13 @@ type Join struct {
 	// NormalJoinType, StraightJoinType and LeftJoinType.
 	JoinType sqlparser.JoinType
 
+	// LateralVars are set when the RHS is a LATERAL derived table that uses columns from the LHS.
+	// The RHS has been planned using these arguments in place of the LHS columns.
+	LateralVars []BindVarExpr
+	// LateralPredicates are the predicates of the LATERAL derived table that compare its columns with the LHS.
+	// They are only used to decide if the two sides can be merged into a single route.
+	LateralPredicates []sqlparser.Expr
+
 	noColumns
 }
 
@@ -41,10 +50,12 @@ func (j *Join) Clone(inputs []Operator) Operator {
 	clone.LHS = inputs[0]
 	clone.RHS = inputs[1]
 	return &Join{
-		LHS:       inputs[0],
-		RHS:       inputs[1],
-		Predicate: j.Predicate,
-		JoinType:  j.JoinType,
+		LHS:               inputs[0],
+		RHS:               inputs[1],
+		Predicate:         j.Predicate,
+		JoinType:          j.JoinType,
+		LateralVars:       slices.Clone(j.LateralVars),
+		LateralPredicates: slices.Clone(j.LateralPredicates),
 	}
 }
 
@@ -104,19 +115,19 @@ func createLeftOuterJoin(ctx *plancontext.PlanningContext, join *sqlparser.JoinT
 	}
 
 	joinOp := &Join{LHS: lhs, RHS: rhs, JoinType: join.Join}
+	return setOuterJoinPredicate(joinOp, join.Condition.On)
+}
 
+func setOuterJoinPredicate(joinOp *Join, predicate sqlparser.Expr) Operator {
 	// for outer joins we have to be careful with the predicates we use
-	var op Operator
-	subq, _ := getSubQuery(join.Condition.On)
+	subq, _ := getSubQuery(predicate)
 	if subq != nil {
 		panic(vterrors.VT12001("subquery in outer join predicate"))
 	}
-	predicate := join.Condition.On
 	sqlparser.RemoveKeyspaceInCol(predicate)
 	joinOp.Predicate = predicate
-	op = joinOp
 
-	return op
+	return joinOp
 }
 
 func createInnerJoin(ctx *plancontext.PlanningContext, tableExpr *sqlparser.JoinTableExpr, lhs, rhs Operator) Operator {
@@ -19,6 +19,7 @@ package operators
 import (
 	"fmt"
 	"reflect"
+	"slices"
 
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
@@ -97,6 +98,12 @@ type (
 		joinType sqlparser.JoinType
 	}
 
+	// lateralMerger merges a LATERAL derived table with the tables it uses columns from
+	lateralMerger struct {
+		joinMerger
+		vars []BindVarExpr
+	}
+
 	routingType int
 )
 
@@ -215,3 +222,28 @@ func (jm *joinMerger) merge(ctx *plancontext.PlanningContext, op1, op2 *Route, r
 		Routing:    r,
 	}
 }
+
+func (lm *lateralMerger) mergeShardedRouting(ctx *plancontext.PlanningContext, r1, _ *ShardedRouting, op1, op2 *Route) *Route {
+	// the RHS is routed using arguments that hold the values of the LHS row,
+	// so only the routing of the LHS can be used for the merged route
+	return lm.newRoute(ctx, op1, op2, r1)
+}
+
+func (lm *lateralMerger) merge(ctx *plancontext.PlanningContext, op1, op2 *Route, r Routing) *Route {
+	if _, isSharded := r.(*ShardedRouting); isSharded && r != op1.Routing {
+		// we can't use the routing of the RHS, since it might be using the arguments for the LHS columns
+		return nil
+	}
+	return lm.newRoute(ctx, op1, op2, r)
+}
+
+func (lm *lateralMerger) newRoute(ctx *plancontext.PlanningContext, op1, op2 *Route, r Routing) *Route {
+	join := lm.getApplyJoin(ctx, op1, op2)
+	join.ExtraLHSVars = slices.Clone(lm.vars)
+	join.Lateral = true
+	return &Route{
+		Source:     join,
+		MergedWith: []*Route{op2},
+		Routing:    r,
+	}
+}
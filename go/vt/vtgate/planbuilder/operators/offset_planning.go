@@ -59,6 +59,9 @@ func planOffsets(ctx *plancontext.PlanningContext, root Operator) Operator {
 
 // mustFetchFromInput returns true for expressions that have to be fetched from the input and cannot be evaluated
 func mustFetchFromInput(ctx *plancontext.PlanningContext, e sqlparser.SQLNode) bool {
+	if sqlparser.IsWindowFunc(e) {
+		return true
+	}
 	switch fun := e.(type) {
 	case *sqlparser.ColName, sqlparser.AggrFunc:
 		return true
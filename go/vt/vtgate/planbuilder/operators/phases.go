@@ -18,6 +18,7 @@ package operators
 
 import (
 	"io"
+	"slices"
 
 	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -221,7 +222,7 @@ func addOrderingForAllAggregations(ctx *plancontext.PlanningContext, root Operat
 		requireOrdering := needsOrdering(ctx, aggrOp)
 		var res *ApplyResult
 		if requireOrdering {
-			addOrderingFor(aggrOp)
+			addOrderingFor(ctx, aggrOp)
 			res = Rewrote("added ordering before aggregation")
 		}
 		return in, res
@@ -230,31 +231,42 @@ func addOrderingForAllAggregations(ctx *plancontext.PlanningContext, root Operat
 	return BottomUp(root, TableID, visitor, stopAtRoute)
 }
 
-func addOrderingFor(aggrOp *Aggregator) {
-	orderBys := slice.Map(aggrOp.Grouping, func(from GroupBy) OrderBy {
+func addOrderingFor(ctx *plancontext.PlanningContext, aggrOp *Aggregator) {
+	aggrOp.Source = &Ordering{
+		Source: aggrOp.Source,
+		Order:  aggrOp.requiredOrdering(ctx),
+	}
+}
+
+// requiredOrdering returns the ordering the aggregator needs on its input: first the grouping columns,
+// then the ORDER BY of the GROUP_CONCAT aggregations and last the expressions of the distinct aggregation
+func (a *Aggregator) requiredOrdering(ctx *plancontext.PlanningContext) []OrderBy {
+	orderBys := slice.Map(a.Grouping, func(from GroupBy) OrderBy {
 		return from.AsOrderBy()
 	})
-	if aggrOp.DistinctExpr != nil {
+	for _, order := range a.GroupConcatOrder {
+		orderBys = append(orderBys, OrderBy{
+			Inner:          order,
+			SimplifiedExpr: order.Expr,
+		})
+	}
+	for _, expr := range a.DistinctExprs {
+		if slices.ContainsFunc(a.GroupConcatOrder, func(o *sqlparser.Order) bool { return ctx.SemTable.EqualsExpr(o.Expr, expr) }) {
+			// already sorted on it
+			continue
+		}
 		orderBys = append(orderBys, OrderBy{
 			Inner: &sqlparser.Order{
-				Expr: aggrOp.DistinctExpr,
+				Expr: expr,
 			},
-			SimplifiedExpr: aggrOp.DistinctExpr,
+			SimplifiedExpr: expr,
 		})
 	}
-	aggrOp.Source = &Ordering{
-		Source: aggrOp.Source,
-		Order:  orderBys,
-	}
+	return orderBys
 }
 
 func needsOrdering(ctx *plancontext.PlanningContext, in *Aggregator) bool {
-	requiredOrder := slice.Map(in.Grouping, func(from GroupBy) sqlparser.Expr {
-		return from.Inner
-	})
-	if in.DistinctExpr != nil {
-		requiredOrder = append(requiredOrder, in.DistinctExpr)
-	}
+	requiredOrder := in.requiredOrdering(ctx)
 	if len(requiredOrder) == 0 {
 		return false
 	}
@@ -262,8 +274,13 @@ func needsOrdering(ctx *plancontext.PlanningContext, in *Aggregator) bool {
 	if len(srcOrdering) < len(requiredOrder) {
 		return true
 	}
-	for idx, gb := range requiredOrder {
-		if !ctx.SemTable.EqualsExprWithDeps(srcOrdering[idx].SimplifiedExpr, gb) {
+	for idx, order := range requiredOrder {
+		if !ctx.SemTable.EqualsExprWithDeps(srcOrdering[idx].SimplifiedExpr, order.SimplifiedExpr) {
+			return true
+		}
+		// only the GROUP_CONCAT ordering cares about the direction of the sorting
+		isGroupConcatOrder := idx >= len(in.Grouping) && idx < len(in.Grouping)+len(in.GroupConcatOrder)
+		if isGroupConcatOrder && srcOrdering[idx].Inner.Direction != order.Inner.Direction {
 			return true
 		}
 	}
@@ -284,16 +301,31 @@ func addGroupByOnRHSOfJoin(root Operator) Operator {
 }
 
 func addLiteralGroupingToRHS(in *ApplyJoin) (Operator, *ApplyResult) {
-	_ = Visit(in.RHS, func(op Operator) error {
-		aggr, isAggr := op.(*Aggregator)
-		if !isAggr {
-			return nil
+	var visit func(op Operator)
+	visit = func(op Operator) {
+		switch op := op.(type) {
+		case *Aggregator:
+			if op.isDerived() {
+				// the aggregation of a derived table, such as a LATERAL one, returns a row even without input
+				return
+			}
+			if len(op.Grouping) == 0 {
+				gb := sqlparser.NewIntLiteral(".0")
+				op.Grouping = append(op.Grouping, NewGroupBy(gb))
+			}
+		case *Projection:
+			if op.isDerived() {
+				return
+			}
+		case *Horizon:
+			if op.IsDerived() {
+				return
+			}
 		}
-		if len(aggr.Grouping) == 0 {
-			gb := sqlparser.NewIntLiteral(".0")
-			aggr.Grouping = append(aggr.Grouping, NewGroupBy(gb))
+		for _, input := range op.Inputs() {
+			visit(input)
 		}
-		return nil
-	})
+	}
+	visit(in.RHS)
 	return in, NoRewrite
 }
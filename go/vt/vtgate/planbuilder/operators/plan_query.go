@@ -143,6 +143,10 @@ func tryTruncateColumnsAt(op Operator, truncateAt int) bool {
 	case *Limit:
 		return tryTruncateColumnsAt(op.Source, truncateAt)
 	case *SubQuery:
+		if op.PerRowValue || op.PerRowPredicate != nil {
+			// the rows are evaluated in vtgate, so we need all the columns of the outer query
+			return false
+		}
 		for _, offset := range op.Vars {
 			if offset >= truncateAt {
 				return false
@@ -138,7 +138,8 @@ func pushProjectionToOuter(ctx *plancontext.PlanningContext, p *Projection, sq *
 		return p, NoRewrite
 	}
 
-	if !reachedPhase(ctx, subquerySettling) {
+	if !reachedPhase(ctx, subquerySettling) || sq.PerRowValue {
+		// when the subquery is evaluated per row, its value is not available to the outer query
 		return p, NoRewrite
 	}
 
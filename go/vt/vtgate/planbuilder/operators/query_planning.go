@@ -19,6 +19,7 @@ package operators
 import (
 	"fmt"
 	"io"
+	"slices"
 
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
@@ -88,6 +89,8 @@ func runRewriters(ctx *plancontext.PlanningContext, root Operator) Operator {
 			return tryPushDistinct(in)
 		case *Union:
 			return tryPushUnion(ctx, in)
+		case *RecurseCTE:
+			return tryMergeRecurseCTE(ctx, in)
 		case *SubQueryContainer:
 			return pushOrMergeSubQueryContainer(ctx, in)
 		case *QueryGraph:
@@ -206,7 +209,8 @@ func pushOrExpandHorizon(ctx *plancontext.PlanningContext, in *Horizon) (Operato
 		!needsOrdering &&
 		!qp.NeedsAggregation() &&
 		!in.selectStatement().IsDistinct() &&
-		in.selectStatement().GetLimit() == nil
+		in.selectStatement().GetLimit() == nil &&
+		(!isSel || canPushWindowFuncs(ctx, rb, sel))
 
 	if canPush {
 		return Swap(in, rb, "push horizon into route")
@@ -248,6 +252,9 @@ func setUpperLimit(in *Limit) (Operator, *ApplyResult) {
 		case *Join, *ApplyJoin, *SubQueryContainer, *SubQuery:
 			// we can't push limits down on either side
 			return SkipChildren
+		case *Window:
+			// window functions need to see all the rows of their partitions
+			return SkipChildren
 		case *Route:
 			newSrc := &Limit{
 				Source: op.Source,
@@ -270,6 +277,14 @@ func setUpperLimit(in *Limit) (Operator, *ApplyResult) {
 func tryPushOrdering(ctx *plancontext.PlanningContext, in *Ordering) (Operator, *ApplyResult) {
 	switch src := in.Source.(type) {
 	case *Route:
+		if proj, ok := src.Source.(*Projection); ok && proj.isDerived() {
+			// the ordering ends up outside the derived table, so it has to use its columns
+			order, ok := orderByDerivedColumns(ctx, in.Order, proj)
+			if !ok {
+				return in, NoRewrite
+			}
+			in.Order = order
+		}
 		return Swap(in, src, "push ordering under route")
 	case *Filter:
 		return Swap(in, src, "push ordering under filter")
@@ -322,6 +337,42 @@ func tryPushOrdering(ctx *plancontext.PlanningContext, in *Ordering) (Operator,
 	return in, NoRewrite
 }
 
+// orderByDerivedColumns rewrites the ordering expressions that are computed by the derived table
+// into its columns. It returns false if one of them is not a column of the derived table.
+func orderByDerivedColumns(ctx *plancontext.PlanningContext, order []OrderBy, proj *Projection) ([]OrderBy, bool) {
+	ap, err := proj.GetAliasedProjections()
+	if err != nil {
+		return nil, false
+	}
+	var newOrder []OrderBy
+	for _, by := range order {
+		if ctx.SemTable.DirectDeps(by.SimplifiedExpr).IsSolvedBy(proj.DT.TableID) {
+			// already using the columns of the derived table
+			newOrder = append(newOrder, by)
+			continue
+		}
+		offset := slices.IndexFunc(ap, func(pe *ProjExpr) bool {
+			return ctx.SemTable.EqualsExprWithDeps(pe.ColExpr, by.SimplifiedExpr)
+		})
+		if offset < 0 {
+			return nil, false
+		}
+		name := ap[offset].Original.ColumnName()
+		if offset < len(proj.DT.Columns) {
+			name = proj.DT.Columns[offset].String()
+		}
+		col := sqlparser.NewColNameWithQualifier(name, sqlparser.NewTableName(proj.DT.Alias))
+		ctx.SemTable.Direct[col] = proj.DT.TableID
+		ctx.SemTable.Recursive[col] = ctx.SemTable.RecursiveDeps(by.SimplifiedExpr)
+		ctx.SemTable.CopyExprInfo(by.SimplifiedExpr, col)
+		newOrder = append(newOrder, OrderBy{
+			Inner:          &sqlparser.Order{Expr: col, Direction: by.Inner.Direction},
+			SimplifiedExpr: col,
+		})
+	}
+	return newOrder, true
+}
+
 func overlaps(ctx *plancontext.PlanningContext, order []OrderBy, grouping []GroupBy) bool {
 ordering:
 	for _, orderBy := range order {
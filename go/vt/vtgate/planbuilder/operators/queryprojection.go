@@ -86,8 +86,18 @@ type (
 		ColOffset int
 		WSOffset  int
 
+		// ExtraOffsets point to the arguments after the first one, for aggregations
+		// over more than one expression that are evaluated at the vtgate level
+		ExtraOffsets []ArgOffsets
+
 		SubQueryExpression []*SubQuery
 	}
+
+	// ArgOffsets points to the column and weight string column of an aggregation argument
+	ArgOffsets struct {
+		ColOffset int
+		WSOffset  int
+	}
 )
 
 func (aggr Aggr) NeedsWeightString(ctx *plancontext.PlanningContext) bool {
@@ -99,7 +109,7 @@ func (aggr Aggr) GetTypeCollation(ctx *plancontext.PlanningContext) evalengine.T
 		return evalengine.Type{}
 	}
 	switch aggr.OpCode {
-	case opcode.AggregateMin, opcode.AggregateMax, opcode.AggregateSumDistinct, opcode.AggregateCountDistinct:
+	case opcode.AggregateMin, opcode.AggregateMax, opcode.AggregateSumDistinct, opcode.AggregateCountDistinct, opcode.AggregateGroupConcatDistinct:
 		typ, _ := ctx.SemTable.TypeForExpr(aggr.Func.GetArg())
 		return typ
 
@@ -116,6 +126,21 @@ func NewGroupBy(inner sqlparser.Expr) GroupBy {
 	}
 }
 
+// extraArgs returns the arguments after the first one, for aggregations that accept more than one expression
+func (aggr Aggr) extraArgs() sqlparser.Exprs {
+	switch aggr.OpCode {
+	case opcode.AggregateCountDistinct, opcode.AggregateGroupConcat, opcode.AggregateGroupConcatDistinct:
+		if aggr.Func == nil {
+			return nil
+		}
+		args := aggr.Func.GetArgs()
+		if len(args) > 1 {
+			return args[1:]
+		}
+	}
+	return nil
+}
+
 func NewAggr(opCode opcode.AggregateOpcode, f sqlparser.AggrFunc, original *sqlparser.AliasedExpr, alias string) Aggr {
 	return Aggr{
 		Original:  original,
@@ -204,7 +229,8 @@ func (qp *QueryProjection) addSelectExpressions(ctx *plancontext.PlanningContext
 func IsAggr(ctx *plancontext.PlanningContext, e sqlparser.SQLNode) bool {
 	switch node := e.(type) {
 	case sqlparser.AggrFunc:
-		return true
+		// aggregations with an OVER clause are window functions and don't group the rows
+		return !sqlparser.IsWindowFunc(node)
 	case *sqlparser.FuncExpr:
 		return node.Name.EqualsAnyString(ctx.VSchema.GetAggregateUDFs())
 	}
@@ -220,6 +246,10 @@ func ContainsAggr(ctx *plancontext.PlanningContext, e sqlparser.SQLNode) (hasAgg
 			// so we don't need to worry about aggregation in the original
 			return false, nil
 		case sqlparser.AggrFunc:
+			if sqlparser.IsWindowFunc(node) {
+				// the arguments of a window aggregation can still contain aggregations
+				return true, nil
+			}
 			hasAggr = true
 			return false, io.EOF
 		case *sqlparser.Subquery:
@@ -536,6 +566,8 @@ func createAggrFromAggrFunc(fnc sqlparser.AggrFunc, aliasedExpr *sqlparser.Alias
 			code = opcode.AggregateCountDistinct
 		case opcode.AggregateSum:
 			code = opcode.AggregateSumDistinct
+		case opcode.AggregateGroupConcat:
+			code = opcode.AggregateGroupConcatDistinct
 		}
 	}
 
@@ -0,0 +1,359 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+)
+
+type (
+	// RecurseCTE is used to evaluate a recursive CTE at the vtgate level.
+	// The Seed produces the initial rows, and the Term is evaluated once for every row
+	// produced by the previous iteration, until an iteration does not produce any rows.
+	RecurseCTE struct {
+		Seed, Term Operator
+
+		// Vars maps the bind variables used by the recursive reference in the Term
+		// to the columns of the rows produced by the previous iteration
+		Vars map[string]int
+
+		Distinct bool
+
+		// Columns are the columns produced by the CTE. They are named and typed after the Seed
+		Columns []*sqlparser.AliasedExpr
+
+		// termExprs are the select expressions of the Term, used when new columns are added
+		termExprs sqlparser.SelectExprs
+
+		// CTE is the definition of the CTE, and Query the UNION that it is made of.
+		// They are used to send the CTE as is when it is merged into a single route
+		CTE   *sqlparser.CommonTableExpr
+		Query *sqlparser.Union
+
+		// This is only filled in during offset planning
+		CheckCols []engine.CheckCol
+	}
+
+	// RecurseCTERef is the recursive reference to a CTE inside its recursive query block.
+	// It produces a single row, made from the bind variables that hold the row of
+	// the previous iteration the Term is being evaluated for.
+	RecurseCTERef struct {
+		TableID semantics.TableSet
+
+		// ColumnNames are the names of the columns of the CTE, and BindVars
+		// the names of the bind variables that hold the values for them
+		ColumnNames []string
+		BindVars    []string
+
+		// Columns are the expressions this operator has been asked to produce
+		Columns []*sqlparser.AliasedExpr
+
+		noInputs
+	}
+)
+
+func createRecurseCTE(ctx *plancontext.PlanningContext, node *sqlparser.Union) Operator {
+	seed := translateQueryToOp(ctx, node.Left)
+	term := translateQueryToOp(ctx, node.Right)
+
+	vars := map[string]int{}
+	_ = Visit(term, func(op Operator) error {
+		if ref, ok := op.(*RecurseCTERef); ok {
+			for i, bv := range ref.BindVars {
+				vars[bv] = i
+			}
+		}
+		return nil
+	})
+
+	// the columns are named after the CTE, which might use a column list different from the seed
+	ref := recursiveCTERef(ctx, node)
+	names := ref.ColumnNames()
+	var columns []*sqlparser.AliasedExpr
+	for idx, expr := range ctx.SemTable.SelectExprs(node.Left) {
+		ae, ok := expr.(*sqlparser.AliasedExpr)
+		if !ok {
+			panic(vterrors.VT09015())
+		}
+		columns = append(columns, sqlparser.NewAliasedExpr(ae.Expr, names[idx]))
+	}
+
+	rcte := &RecurseCTE{
+		Seed:      seed,
+		Term:      term,
+		Vars:      vars,
+		Distinct:  node.Distinct,
+		Columns:   columns,
+		termExprs: ctx.SemTable.SelectExprs(node.Right),
+		CTE:       ref.CTE,
+		Query:     node,
+	}
+	return newHorizon(rcte, node)
+}
+
+// recursiveCTERef returns the table info for the recursive reference in the UNION, if the UNION is a recursive CTE
+func recursiveCTERef(ctx *plancontext.PlanningContext, node *sqlparser.Union) (result *semantics.RecursiveCTETable) {
+	term, ok := node.Right.(*sqlparser.Select)
+	if !ok {
+		return nil
+	}
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		ate, ok := node.(*sqlparser.AliasedTableExpr)
+		if !ok {
+			return true, nil
+		}
+		tableInfo, err := ctx.SemTable.TableInfoFor(ctx.SemTable.TableSetFor(ate))
+		if err == nil {
+			if ref, isRef := tableInfo.(*semantics.RecursiveCTETable); isRef {
+				result = ref
+			}
+		}
+		return false, nil
+	}, sqlparser.TableExprs(term.From))
+	return
+}
+
+func newRecurseCTERef(ctx *plancontext.PlanningContext, id semantics.TableSet, tableInfo *semantics.RecursiveCTETable) *RecurseCTERef {
+	name, err := tableInfo.Name()
+	if err != nil {
+		panic(err)
+	}
+	ref := &RecurseCTERef{
+		TableID:     id,
+		ColumnNames: tableInfo.ColumnNames(),
+	}
+	for _, col := range ref.ColumnNames {
+		ref.BindVars = append(ref.BindVars, ctx.ReservedVars.ReserveColName(sqlparser.NewColNameWithQualifier(col, name)))
+	}
+	return ref
+}
+
+// Clone implements the Operator interface
+func (r *RecurseCTE) Clone(inputs []Operator) Operator {
+	return &RecurseCTE{
+		Seed:      inputs[0],
+		Term:      inputs[1],
+		Vars:      maps.Clone(r.Vars),
+		Distinct:  r.Distinct,
+		Columns:   slices.Clone(r.Columns),
+		termExprs: r.termExprs,
+		CTE:       r.CTE,
+		Query:     r.Query,
+		CheckCols: slices.Clone(r.CheckCols),
+	}
+}
+
+// Inputs implements the Operator interface
+func (r *RecurseCTE) Inputs() []Operator {
+	return []Operator{r.Seed, r.Term}
+}
+
+// SetInputs implements the Operator interface
+func (r *RecurseCTE) SetInputs(ops []Operator) {
+	r.Seed, r.Term = ops[0], ops[1]
+}
+
+// AddPredicate implements the Operator interface.
+// Predicates are not pushed into the Seed or the Term, since that would change what the recursion produces
+func (r *RecurseCTE) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	return newFilter(r, expr)
+}
+
+func (r *RecurseCTE) AddColumn(ctx *plancontext.PlanningContext, reuse bool, gb bool, expr *sqlparser.AliasedExpr) int {
+	if reuse {
+		offset := r.FindCol(ctx, expr.Expr, false)
+		if offset >= 0 {
+			return offset
+		}
+	}
+
+	switch e := expr.Expr.(type) {
+	case *sqlparser.ColName:
+		offset := slices.IndexFunc(r.Columns, func(col *sqlparser.AliasedExpr) bool {
+			return e.Name.EqualString(col.ColumnName())
+		})
+		if offset == -1 {
+			panic(vterrors.VT13001(fmt.Sprintf("could not find the column '%s' on the recursive CTE", sqlparser.String(e))))
+		}
+		return offset
+	case *sqlparser.WeightStringFuncExpr:
+		argIdx := slices.IndexFunc(r.Columns, func(col *sqlparser.AliasedExpr) bool {
+			return ctx.SemTable.EqualsExprWithDeps(e.Expr, col.Expr)
+		})
+		if argIdx == -1 {
+			panic(vterrors.VT13001(fmt.Sprintf("could not find the argument to the weight_string function: %s", sqlparser.String(e.Expr))))
+		}
+		return r.addWeightStringToOffset(ctx, argIdx, gb)
+	default:
+		panic(vterrors.VT13001(fmt.Sprintf("only weight_string function is expected - got %s", sqlparser.String(expr))))
+	}
+}
+
+func (r *RecurseCTE) addWeightStringToOffset(ctx *plancontext.PlanningContext, argIdx int, addToGroupBy bool) int {
+	seedOffset := r.Seed.AddColumn(ctx, false, addToGroupBy, aeWrap(weightStringFor(r.Columns[argIdx].Expr)))
+
+	termExpr, ok := r.termExprs[argIdx].(*sqlparser.AliasedExpr)
+	if !ok {
+		panic(vterrors.VT09015())
+	}
+	termOffset := r.Term.AddColumn(ctx, false, addToGroupBy, aeWrap(weightStringFor(termExpr.Expr)))
+	if seedOffset != termOffset || seedOffset != len(r.Columns) {
+		panic(vterrors.VT12001("weight_string offsets did not line up for the recursive CTE"))
+	}
+
+	r.Columns = append(r.Columns, aeWrap(weightStringFor(r.Columns[argIdx].Expr)))
+	return seedOffset
+}
+
+func (r *RecurseCTE) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, _ bool) int {
+	for idx, col := range r.Columns {
+		if ctx.SemTable.EqualsExprWithDeps(expr, col.Expr) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (r *RecurseCTE) GetColumns(*plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	return r.Columns
+}
+
+func (r *RecurseCTE) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
+	return transformColumnsToSelectExprs(ctx, r)
+}
+
+func (r *RecurseCTE) ShortDescription() string {
+	if r.Distinct {
+		return "DISTINCT"
+	}
+	return ""
+}
+
+func (r *RecurseCTE) GetOrdering(*plancontext.PlanningContext) []OrderBy {
+	return nil
+}
+
+func (r *RecurseCTE) planOffsets(ctx *plancontext.PlanningContext) Operator {
+	if !r.Distinct {
+		return nil
+	}
+	for idx, col := range r.Columns {
+		var wsCol *int
+		typ, _ := ctx.SemTable.TypeForExpr(col.Expr)
+		if ctx.SemTable.NeedsWeightString(col.Expr) {
+			offset := r.addWeightStringToOffset(ctx, idx, false)
+			wsCol = &offset
+		}
+
+		r.CheckCols = append(r.CheckCols, engine.CheckCol{
+			Col:          idx,
+			WsCol:        wsCol,
+			Type:         typ,
+			CollationEnv: ctx.VSchema.Environment().CollationEnv(),
+		})
+	}
+	return nil
+}
+
+// Clone implements the Operator interface
+func (r *RecurseCTERef) Clone([]Operator) Operator {
+	return &RecurseCTERef{
+		TableID:     r.TableID,
+		ColumnNames: r.ColumnNames,
+		BindVars:    r.BindVars,
+		Columns:     slices.Clone(r.Columns),
+	}
+}
+
+func (r *RecurseCTERef) introducesTableID() semantics.TableSet {
+	return r.TableID
+}
+
+// AddPredicate implements the Operator interface
+func (r *RecurseCTERef) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	return newFilter(r, expr)
+}
+
+func (r *RecurseCTERef) AddColumn(ctx *plancontext.PlanningContext, reuse bool, _ bool, expr *sqlparser.AliasedExpr) int {
+	if reuse {
+		offset := r.FindCol(ctx, expr.Expr, false)
+		if offset >= 0 {
+			return offset
+		}
+	}
+	if !ctx.SemTable.RecursiveDeps(expr.Expr).IsSolvedBy(r.TableID) {
+		panic(vterrors.VT13001(fmt.Sprintf("cannot add '%s' to the recursive reference of a CTE", sqlparser.String(expr))))
+	}
+	r.Columns = append(r.Columns, expr)
+	return len(r.Columns) - 1
+}
+
+func (r *RecurseCTERef) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, _ bool) int {
+	for idx, col := range r.Columns {
+		if ctx.SemTable.EqualsExprWithDeps(expr, col.Expr) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (r *RecurseCTERef) GetColumns(*plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	return r.Columns
+}
+
+func (r *RecurseCTERef) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
+	return transformColumnsToSelectExprs(ctx, r)
+}
+
+func (r *RecurseCTERef) ShortDescription() string {
+	return strings.Join(slice.Map(r.Columns, func(ae *sqlparser.AliasedExpr) string {
+		return sqlparser.String(ae)
+	}), ", ")
+}
+
+func (r *RecurseCTERef) GetOrdering(*plancontext.PlanningContext) []OrderBy {
+	return nil
+}
+
+// ArgumentFor returns the expression with the columns of the CTE replaced by the bind variables holding their values
+func (r *RecurseCTERef) ArgumentFor(ctx *plancontext.PlanningContext, expr sqlparser.Expr) sqlparser.Expr {
+	return sqlparser.CopyOnRewrite(expr, nil, func(cursor *sqlparser.CopyOnWriteCursor) {
+		col, ok := cursor.Node().(*sqlparser.ColName)
+		if !ok || ctx.SemTable.DirectDeps(col) != r.TableID {
+			return
+		}
+		idx := slices.IndexFunc(r.ColumnNames, func(name string) bool {
+			return col.Name.EqualString(name)
+		})
+		if idx == -1 {
+			panic(vterrors.VT13001(fmt.Sprintf("could not find the column '%s' on the recursive CTE", sqlparser.String(col))))
+		}
+		arg := sqlparser.NewArgument(r.BindVars[idx])
+		ctx.SemTable.CopyExprInfo(col, arg)
+		cursor.Replace(arg)
+	}, ctx.SemTable.CopySemanticInfo).(sqlparser.Expr)
+}
@@ -559,7 +559,11 @@ func (r *Route) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.Ex
 func createProjection(ctx *plancontext.PlanningContext, src Operator, derivedName string) *Projection {
 	proj := newAliasedProjection(src)
 	cols := src.GetColumns(ctx)
-	for _, col := range cols {
+	var columnAliases sqlparser.Columns
+	if horizon, ok := src.(*Horizon); ok {
+		columnAliases = horizon.ColumnAliases
+	}
+	for idx, col := range cols {
 		if derivedName == "" {
 			proj.addUnexploredExpr(col, col.Expr)
 			continue
@@ -568,6 +572,9 @@ func createProjection(ctx *plancontext.PlanningContext, src Operator, derivedNam
 		// for derived tables, we want to use the exposed colname
 		tableName := sqlparser.NewTableName(derivedName)
 		columnName := col.ColumnName()
+		if idx < len(columnAliases) {
+			columnName = columnAliases[idx].String()
+		}
 		colName := sqlparser.NewColNameWithQualifier(columnName, tableName)
 		ctx.SemTable.CopySemanticInfo(col.Expr, colName)
 		proj.addUnexploredExpr(aeWrap(colName), colName)
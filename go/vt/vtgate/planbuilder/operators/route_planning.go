@@ -19,6 +19,7 @@ package operators
 import (
 	"bytes"
 	"io"
+	"slices"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -53,9 +54,37 @@ func pushDerived(ctx *plancontext.PlanningContext, op *Horizon) (Operator, *Appl
 }
 
 func optimizeJoin(ctx *plancontext.PlanningContext, op *Join) (Operator, *ApplyResult) {
+	if len(op.LateralVars) > 0 {
+		return mergeOrLateralJoin(ctx, op)
+	}
 	return mergeOrJoin(ctx, op.LHS, op.RHS, sqlparser.SplitAndExpression(nil, op.Predicate), op.JoinType)
 }
 
+// mergeOrLateralJoin plans a join with a LATERAL derived table on the RHS. If the two sides can't be merged
+// into a single route, we use an apply join that sends the LHS columns the derived table needs to the RHS.
+// The LHS always has to be evaluated first, so unlike other joins, the sides are never switched.
+func mergeOrLateralJoin(ctx *plancontext.PlanningContext, op *Join) (Operator, *ApplyResult) {
+	joinPredicates := sqlparser.SplitAndExpression(nil, op.Predicate)
+	m := &lateralMerger{
+		joinMerger: joinMerger{predicates: joinPredicates, joinType: op.JoinType},
+		vars:       op.LateralVars,
+	}
+	newPlan := mergeJoinInputs(ctx, op.LHS, op.RHS, slices.Concat(joinPredicates, op.LateralPredicates), m)
+	if newPlan != nil {
+		return newPlan, Rewrote("merge lateral join into single operator")
+	}
+
+	if !reachedPhase(ctx, initialPlanning) {
+		// the derived table might not have been pushed under its route yet,
+		// so we wait before giving up on merging the two sides
+		return op, NoRewrite
+	}
+
+	join := NewApplyJoin(ctx, op.LHS, op.RHS, nil, op.JoinType)
+	join.ExtraLHSVars = slices.Clone(op.LateralVars)
+	return pushJoinPredicates(ctx, joinPredicates, join), Rewrote("logical lateral join to applyJoin")
+}
+
 func optimizeQueryGraph(ctx *plancontext.PlanningContext, op *QueryGraph) (result Operator, changed *ApplyResult) {
 
 	switch {
@@ -278,6 +307,12 @@ func requiresSwitchingSides(ctx *plancontext.PlanningContext, op Operator) (requ
 			return io.EOF
 		}
 
+		// the recursive reference of a CTE produces a single row, so it should drive the join
+		if _, isRecursiveRef := current.(*RecurseCTERef); isRecursiveRef {
+			required = true
+			return io.EOF
+		}
+
 		return nil
 	})
 	return
@@ -23,7 +23,6 @@ import (
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/vt/sqlparser"
-	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
@@ -670,7 +669,9 @@ func tryMergeJoinShardedRouting(
 		}
 
 		if !sameKeyspace {
-			panic(vterrors.VT12001("cross-shard correlated subquery"))
+			// routes in different keyspaces can't be merged - the caller will
+			// plan a join or a per-row subquery evaluation instead
+			return nil
 		}
 
 		canMerge := canMergeOnFilters(ctx, routeA, routeB, joinPredicates)
@@ -52,6 +52,16 @@ type SubQuery struct {
 	// correlated stores whether this subquery is correlated or not.
 	// We use this information to fail the planning if we are unable to merge the subquery with a route.
 	correlated bool
+	// outerRefs is set when the subquery uses the outer query outside the join predicates.
+	// Such subqueries have to be merged with the outer query, since they can't be executed once per outer row.
+	outerRefs bool
+
+	// Fields related to correlated subqueries that can't be merged, and are executed once per row of the outer query:
+	// PerRowPredicate is evaluated for every outer row when the subquery is used to filter the outer query.
+	// PerRowValue is set when the subquery is used in the SELECT expressions. The value of the
+	// subquery is then added to the outer rows as their first column.
+	PerRowPredicate sqlparser.Expr
+	PerRowValue     bool
 
 	IsProjection bool
 }
@@ -68,6 +78,9 @@ func (sq *SubQuery) planOffsets(ctx *plancontext.PlanningContext) Operator {
 			sq.Vars[lhsExpr.Name] = offset
 		}
 	}
+	if sq.PerRowPredicate != nil {
+		sq.PerRowPredicate = useOffsets(ctx, sq.PerRowPredicate, sq)
+	}
 	return nil
 }
 
@@ -176,19 +189,53 @@ func (sq *SubQuery) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparse
 }
 
 func (sq *SubQuery) AddColumn(ctx *plancontext.PlanningContext, reuseExisting bool, addToGroupBy bool, exprs *sqlparser.AliasedExpr) int {
-	return sq.Outer.AddColumn(ctx, reuseExisting, addToGroupBy, exprs)
+	if !sq.PerRowValue {
+		return sq.Outer.AddColumn(ctx, reuseExisting, addToGroupBy, exprs)
+	}
+	if sq.isValueColumn(exprs.Expr) {
+		return 0
+	}
+	// the value of the subquery is the first column, so all the outer columns are moved one step to the right
+	return sq.Outer.AddColumn(ctx, reuseExisting, addToGroupBy, exprs) + 1
 }
 
 func (sq *SubQuery) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, underRoute bool) int {
-	return sq.Outer.FindCol(ctx, expr, underRoute)
+	if !sq.PerRowValue {
+		return sq.Outer.FindCol(ctx, expr, underRoute)
+	}
+	if sq.isValueColumn(expr) {
+		return 0
+	}
+	offset := sq.Outer.FindCol(ctx, expr, underRoute)
+	if offset < 0 {
+		return offset
+	}
+	return offset + 1
+}
+
+// isValueColumn returns true if the expression is the column the outer query uses to access the value of the subquery
+func (sq *SubQuery) isValueColumn(expr sqlparser.Expr) bool {
+	switch expr := expr.(type) {
+	case *sqlparser.ColName:
+		return expr.Qualifier.IsEmpty() && expr.Name.EqualString(sq.ArgName)
+	case *sqlparser.Argument:
+		return expr.Name == sq.ArgName || (sq.HasValuesName != "" && expr.Name == sq.HasValuesName)
+	}
+	return false
 }
 
 func (sq *SubQuery) GetColumns(ctx *plancontext.PlanningContext) []*sqlparser.AliasedExpr {
-	return sq.Outer.GetColumns(ctx)
+	if !sq.PerRowValue {
+		return sq.Outer.GetColumns(ctx)
+	}
+	return append([]*sqlparser.AliasedExpr{aeWrap(sqlparser.NewColName(sq.ArgName))}, sq.Outer.GetColumns(ctx)...)
 }
 
 func (sq *SubQuery) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
-	return sq.Outer.GetSelectExprs(ctx)
+	if !sq.PerRowValue {
+		return sq.Outer.GetSelectExprs(ctx)
+	}
+	return transformColumnsToSelectExprs(ctx, sq)
 }
 
 // GetMergePredicates returns the predicates that we can use to try to merge this subquery with the outer query.
@@ -203,31 +250,53 @@ func (sq *SubQuery) settle(ctx *plancontext.PlanningContext, outer Operator) Ope
 	if !sq.TopLevel {
 		panic(subqueryNotAtTopErr)
 	}
-	if sq.correlated && sq.FilterType != opcode.PulloutExists {
-		panic(correlatedSubqueryErr)
+	if sq.correlated && (sq.IsProjection || sq.FilterType != opcode.PulloutExists) {
+		return sq.settlePerRow(ctx, outer)
 	}
 	if sq.IsProjection {
-		if len(sq.GetMergePredicates()) > 0 {
-			// this means that we have a correlated subquery on our hands
-			panic(correlatedSubqueryErr)
-		}
 		sq.SubqueryValueName = sq.ArgName
 		return outer
 	}
 	return sq.settleFilter(ctx, outer)
 }
 
-var correlatedSubqueryErr = vterrors.VT12001("correlated subquery is only supported for EXISTS")
+var correlatedSubqueryErr = vterrors.VT12001("correlated subquery using the outer query outside of its WHERE, HAVING and ON clauses")
 var subqueryNotAtTopErr = vterrors.VT12001("unmergable subquery can not be inside complex expression")
 
+// settlePerRow prepares a correlated subquery that could not be merged to be executed once for every outer row
+func (sq *SubQuery) settlePerRow(ctx *plancontext.PlanningContext, outer Operator) Operator {
+	if sq.outerRefs {
+		panic(correlatedSubqueryErr)
+	}
+	if !sq.IsProjection {
+		sq.PerRowPredicate = sqlparser.AndExpressions(sq.pulloutPredicates(ctx)...)
+		return outer
+	}
+
+	switch sq.FilterType {
+	case opcode.PulloutValue:
+		sq.SubqueryValueName = sq.ArgName
+	case opcode.PulloutExists:
+		if sq.HasValuesName == "" {
+			sq.HasValuesName = ctx.ReservedVars.ReserveHasValuesSubQuery()
+		}
+	default:
+		panic(vterrors.VT12001(fmt.Sprintf("correlated subquery with %s in the SELECT expressions", sq.FilterType.String())))
+	}
+	sq.PerRowValue = true
+	return outer
+}
+
 func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer Operator) Operator {
 	if len(sq.Predicates) > 0 {
-		if sq.FilterType != opcode.PulloutExists {
-			panic(correlatedSubqueryErr)
-		}
 		return outer
 	}
+	return newFilter(outer, sq.pulloutPredicates(ctx)...)
+}
 
+// pulloutPredicates returns the predicates that use the bind variables holding the result of the subquery
+// to filter the outer query
+func (sq *SubQuery) pulloutPredicates(ctx *plancontext.PlanningContext) []sqlparser.Expr {
 	hasValuesArg := func() string {
 		s := ctx.ReservedVars.ReserveVariable(string(sqlparser.HasValueSubQueryBaseName))
 		sq.HasValuesName = s
@@ -269,7 +338,7 @@ func (sq *SubQuery) settleFilter(ctx *plancontext.PlanningContext, outer Operato
 		predicates = append(predicates, rhsPred)
 		sq.SubqueryValueName = sq.ArgName
 	}
-	return newFilter(outer, predicates...)
+	return predicates
 }
 
 func dontEnterSubqueries(node, _ sqlparser.SQLNode) bool {
@@ -173,6 +173,8 @@ func createSubquery(
 
 	opInner := translateQueryToOp(ctx, subq.Select)
 
+	outerRefs := correlated && usesOuterOutsideJoinPredicates(ctx, subq.Select, predicates, sqc.Inner, outerID)
+
 	opInner = sqc.getRootOperator(opInner, nil)
 	return &SubQuery{
 		FilterType:       filterType,
@@ -185,7 +187,47 @@ func createSubquery(
 		TopLevel:         topLevel,
 		JoinColumns:      joinCols,
 		correlated:       correlated,
+		outerRefs:        outerRefs,
+	}
+}
+
+// usesOuterOutsideJoinPredicates returns true if the subquery uses values from the outer query anywhere
+// other than in the predicates that have been turned into join predicates, or if a join predicate uses an
+// aggregation over the outer query. The values needed by the join predicates can be sent to the subquery
+// as bind variables, but no other uses of the outer query can be.
+func usesOuterOutsideJoinPredicates(
+	ctx *plancontext.PlanningContext,
+	stmt sqlparser.SelectStatement,
+	joinPredicates sqlparser.Exprs,
+	inner []*SubQuery,
+	outerID semantics.TableSet,
+) bool {
+	for _, sq := range inner {
+		if ctx.SemTable.RecursiveDeps(sq.originalSubquery).IsOverlapping(outerID) {
+			return true
+		}
+	}
+
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if col, ok := node.(*sqlparser.ColName); ok && ctx.SemTable.RecursiveDeps(col).IsOverlapping(outerID) {
+			found = true
+		}
+		return !found, nil
+	}, stmt)
+	if found {
+		return true
+	}
+
+	for _, pred := range joinPredicates {
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			if aggr, ok := node.(sqlparser.AggrFunc); ok && ctx.SemTable.RecursiveDeps(aggr).IsOverlapping(outerID) {
+				found = true
+			}
+			return !found, nil
+		}, pred)
 	}
+	return found
 }
 
 func (sqb *SubQueryBuilder) inspectWhere(
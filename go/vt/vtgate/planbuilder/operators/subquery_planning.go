@@ -42,6 +42,11 @@ func isMergeable(ctx *plancontext.PlanningContext, query sqlparser.SelectStateme
 
 	switch node := query.(type) {
 	case *sqlparser.Select:
+		if !windowsPartitionedBy(ctx, node, validVindex) {
+			// window functions need to see all the rows of their partition
+			return false
+		}
+
 		if len(node.GroupBy) > 0 {
 			// iff we are grouping, we need to check that we can perform the grouping inside a single shard, and we check that
 			// by checking that one of the grouping expressions used is a unique single column vindex.
@@ -108,6 +108,17 @@ func createOperatorFromUpdate(ctx *plancontext.PlanningContext, updStmt *sqlpars
 	if updateWithInputPlanningRequired(ctx, childFks, parentFks, updStmt) {
 		return createUpdateWithInputOp(ctx, updStmt)
 	}
+	// Without ORDER BY, a LIMIT can pick any rows. The rows are selected up front, so
+	// the lookup vindexes and the table are guaranteed to be updated for the same rows.
+	// They are locked for update, like the other updates of vindex columns.
+	if updStmt.Limit != nil && len(updStmt.OrderBy) == 0 {
+		if vTbl := vindexColumnUpdateTable(ctx, updStmt); vTbl != nil {
+			if len(vTbl.PrimaryKey) == 0 {
+				panic(vterrors.VT12001(fmt.Sprintf("Vindex update with LIMIT and without ORDER BY on a table with no known primary key: %s", vTbl.Name.String())))
+			}
+			return createUpdateWithInputOp(ctx, updStmt)
+		}
+	}
 
 	var updClone *sqlparser.Update
 	var targetTbl TargetTable
@@ -155,6 +166,27 @@ func isMultiTargetUpdate(ctx *plancontext.PlanningContext, updateStmt *sqlparser
 	return targetTS.NumberOfTables() > 1
 }
 
+// vindexColumnUpdateTable returns the sharded table whose vindex column
+// is changed by the update, if any.
+func vindexColumnUpdateTable(ctx *plancontext.PlanningContext, updateStmt *sqlparser.Update) *vindexes.Table {
+	for _, ue := range updateStmt.Exprs {
+		ti, err := ctx.SemTable.TableInfoForExpr(ue.Name)
+		if err != nil {
+			continue
+		}
+		vTbl := ti.GetVindexTable()
+		if vTbl == nil || !vTbl.Keyspace.Sharded {
+			continue
+		}
+		for _, cv := range vTbl.ColumnVindexes {
+			if slices.ContainsFunc(cv.Columns, ue.Name.Name.Equal) {
+				return vTbl
+			}
+		}
+	}
+	return nil
+}
+
 func createUpdateWithInputOp(ctx *plancontext.PlanningContext, upd *sqlparser.Update) (op Operator) {
 	updClone := ctx.SemTable.Clone(upd).(*sqlparser.Update)
 	upd.Limit = nil
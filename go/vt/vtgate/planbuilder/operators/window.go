@@ -0,0 +1,434 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+)
+
+type (
+	// Window evaluates window functions at the vtgate level. It is used when we can't
+	// be sure that all the rows of a window partition are found on the same shard.
+	// The results of the window functions come first in the output, followed by the columns of the source.
+	Window struct {
+		Source Operator
+		Funcs  []*WindowFunc
+	}
+
+	// WindowFunc is a single window function evaluated by the Window operator
+	WindowFunc struct {
+		Original *sqlparser.AliasedExpr
+		Func     sqlparser.Expr
+		// Spec is the window specification, with any named window already resolved
+		Spec *sqlparser.WindowSpecification
+
+		OpCode     opcode.WindowOpcode
+		AggrOpCode opcode.AggregateOpcode
+		Arg        sqlparser.Expr
+		N          sqlparser.Expr
+		Default    sqlparser.Expr
+
+		// These fields are filled in during offset planning.
+		// All offsets point to columns of the source of the Window operator
+		ColOffset          int
+		PartitionOffsets   []int
+		PartitionWSOffsets []int
+		OrderOffsets       []int
+		OrderWSOffsets     []int
+		NExpr, DefaultExpr evalengine.Expr
+		AvgExpr            evalengine.Expr
+		Frame              *engine.WindowFrame
+	}
+)
+
+func newWindow(ctx *plancontext.PlanningContext, src Operator, sel *sqlparser.Select, funcs []sqlparser.Expr) *Window {
+	w := &Window{Source: src}
+	for _, fn := range funcs {
+		w.Funcs = append(w.Funcs, newWindowFunc(ctx, fn, sel))
+	}
+	return w
+}
+
+func newWindowFunc(ctx *plancontext.PlanningContext, fn sqlparser.Expr, sel *sqlparser.Select) *WindowFunc {
+	wf := &WindowFunc{
+		Original:  windowFuncAlias(ctx, fn, sel),
+		Func:      fn,
+		Spec:      resolveWindowSpec(sqlparser.GetOverClause(fn), sel.Windows),
+		ColOffset: -1,
+	}
+
+	unsupported := func() {
+		panic(vterrors.VT12001(fmt.Sprintf("window function '%s' in a cross-shard query", sqlparser.String(fn))))
+	}
+
+	switch fn := fn.(type) {
+	case *sqlparser.ArgumentLessWindowExpr:
+		switch fn.Type {
+		case sqlparser.RowNumberExprType:
+			wf.OpCode = opcode.WindowRowNumber
+		case sqlparser.RankExprType:
+			wf.OpCode = opcode.WindowRank
+		case sqlparser.DenseRankExprType:
+			wf.OpCode = opcode.WindowDenseRank
+		case sqlparser.PercentRankExprType:
+			wf.OpCode = opcode.WindowPercentRank
+		case sqlparser.CumeDistExprType:
+			wf.OpCode = opcode.WindowCumeDist
+		}
+	case *sqlparser.NtileExpr:
+		wf.OpCode, wf.N = opcode.WindowNtile, fn.N
+	case *sqlparser.LagLeadExpr:
+		wf.OpCode = opcode.WindowLag
+		if fn.Type == sqlparser.LeadExprType {
+			wf.OpCode = opcode.WindowLead
+		}
+		wf.Arg, wf.N, wf.Default = fn.Expr, fn.N, fn.Default
+	case *sqlparser.FirstOrLastValueExpr:
+		wf.OpCode = opcode.WindowFirstValue
+		if fn.Type == sqlparser.LastValueExprType {
+			wf.OpCode = opcode.WindowLastValue
+		}
+		wf.Arg = fn.Expr
+	case *sqlparser.NTHValueExpr:
+		if fn.FromFirstLastClause != nil && fn.FromFirstLastClause.Type == sqlparser.FromLastType {
+			unsupported()
+		}
+		wf.OpCode, wf.Arg, wf.N = opcode.WindowNthValue, fn.Expr, fn.N
+	case *sqlparser.CountStar:
+		wf.OpCode, wf.AggrOpCode = opcode.WindowAggregate, opcode.AggregateCountStar
+	case sqlparser.AggrFunc:
+		code, ok := opcode.SupportedAggregates[fn.AggrName()]
+		if !ok || sqlparser.IsDistinct(fn) || len(fn.GetArgs()) != 1 {
+			unsupported()
+		}
+		switch code {
+		case opcode.AggregateCount, opcode.AggregateSum, opcode.AggregateAvg, opcode.AggregateMin, opcode.AggregateMax:
+		default:
+			unsupported()
+		}
+		wf.OpCode, wf.AggrOpCode, wf.Arg = opcode.WindowAggregate, code, fn.GetArg()
+	default:
+		unsupported()
+	}
+
+	if frame := wf.Spec.FrameClause; frame != nil && frame.Unit == sqlparser.FrameRangeType {
+		// RANGE frames with offsets require arithmetic on the ORDER BY values
+		if frame.Start.Expr != nil || (frame.End != nil && frame.End.Expr != nil) {
+			unsupported()
+		}
+	}
+	return wf
+}
+
+// windowFuncAlias returns the column name used in the SELECT list for the window function, if there is one
+func windowFuncAlias(ctx *plancontext.PlanningContext, fn sqlparser.Expr, sel *sqlparser.Select) *sqlparser.AliasedExpr {
+	for _, expr := range sel.SelectExprs {
+		ae, ok := expr.(*sqlparser.AliasedExpr)
+		if ok && ctx.SemTable.EqualsExprWithDeps(ae.Expr, fn) {
+			return sqlparser.NewAliasedExpr(fn, ae.ColumnName())
+		}
+	}
+	return aeWrap(fn)
+}
+
+// resolveWindowSpec returns the window specification used by the OVER clause,
+// inlining the named window the clause refers to, if any
+func resolveWindowSpec(over *sqlparser.OverClause, windows sqlparser.NamedWindows) *sqlparser.WindowSpecification {
+	spec := over.WindowSpec
+	name := over.WindowName
+	if spec != nil && !spec.Name.IsEmpty() {
+		name = spec.Name
+	}
+	if name.IsEmpty() {
+		if spec == nil {
+			return &sqlparser.WindowSpecification{}
+		}
+		return spec
+	}
+
+	var definitions []*sqlparser.WindowDefinition
+	for _, nw := range windows {
+		definitions = append(definitions, nw.Windows...)
+	}
+	var base *sqlparser.WindowSpecification
+	// a named window can refer to another named window, so we follow the chain.
+	// it can't be longer than the number of definitions, unless there is a cycle
+	for i := 0; i <= len(definitions); i++ {
+		idx := slices.IndexFunc(definitions, func(def *sqlparser.WindowDefinition) bool {
+			return def.Name.Equal(name)
+		})
+		if idx < 0 {
+			panic(vterrors.VT03033(name.String()))
+		}
+		def := definitions[idx].WindowSpec
+		base = mergeWindowSpecs(def, base)
+		if def.Name.IsEmpty() {
+			return mergeWindowSpecs(base, spec)
+		}
+		name = def.Name
+	}
+	panic(vterrors.VT12001(fmt.Sprintf("circular window definition for '%s'", name.String())))
+}
+
+// mergeWindowSpecs returns a window specification that inherits from the base specification.
+// The inheriting specification can only add the parts that are missing from the base.
+func mergeWindowSpecs(base, spec *sqlparser.WindowSpecification) *sqlparser.WindowSpecification {
+	if base == nil {
+		return spec
+	}
+	if spec == nil {
+		return base
+	}
+	merged := &sqlparser.WindowSpecification{
+		PartitionClause: base.PartitionClause,
+		OrderClause:     base.OrderClause,
+		FrameClause:     base.FrameClause,
+	}
+	if len(merged.PartitionClause) == 0 {
+		merged.PartitionClause = spec.PartitionClause
+	}
+	if len(merged.OrderClause) == 0 {
+		merged.OrderClause = spec.OrderClause
+	}
+	if merged.FrameClause == nil {
+		merged.FrameClause = spec.FrameClause
+	}
+	return merged
+}
+
+// getWindowFuncs returns the window functions used in the SELECT expressions and the ORDER BY of the query
+func getWindowFuncs(ctx *plancontext.PlanningContext, sel *sqlparser.Select) []sqlparser.Expr {
+	var funcs []sqlparser.Expr
+	visit := func(node sqlparser.SQLNode) (bool, error) {
+		switch node.(type) {
+		case *sqlparser.Subquery:
+			return false, nil
+		}
+		if !sqlparser.IsWindowFunc(node) {
+			return true, nil
+		}
+		expr := node.(sqlparser.Expr)
+		if !slices.ContainsFunc(funcs, func(e sqlparser.Expr) bool { return ctx.SemTable.EqualsExprWithDeps(e, expr) }) {
+			funcs = append(funcs, expr)
+		}
+		return false, nil
+	}
+	_ = sqlparser.Walk(visit, sel.SelectExprs)
+	_ = sqlparser.Walk(visit, sel.OrderBy)
+	return funcs
+}
+
+// canPushWindowFuncs returns true if MySQL can evaluate all the window functions of the query.
+// This is the case when every window partition is guaranteed to live on a single shard,
+// which we know if the partition uses a column with a unique vindex.
+func canPushWindowFuncs(ctx *plancontext.PlanningContext, src Operator, sel *sqlparser.Select) bool {
+	route, isRoute := src.(*Route)
+	if !isRoute {
+		return len(getWindowFuncs(ctx, sel)) == 0
+	}
+	if route.IsSingleShard() {
+		return true
+	}
+	return windowsPartitionedBy(ctx, sel, func(expr sqlparser.Expr) bool {
+		return exprHasUniqueVindex(ctx, expr)
+	})
+}
+
+// windowsPartitionedBy returns true if every window function of the query partitions by an expression accepted by isUnique
+func windowsPartitionedBy(ctx *plancontext.PlanningContext, sel *sqlparser.Select, isUnique func(sqlparser.Expr) bool) bool {
+	for _, fn := range getWindowFuncs(ctx, sel) {
+		spec := resolveWindowSpec(sqlparser.GetOverClause(fn), sel.Windows)
+		if !slices.ContainsFunc(spec.PartitionClause, isUnique) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *Window) Clone(inputs []Operator) Operator {
+	return &Window{
+		Source: inputs[0],
+		Funcs: slice.Map(w.Funcs, func(from *WindowFunc) *WindowFunc {
+			clone := *from
+			return &clone
+		}),
+	}
+}
+
+func (w *Window) Inputs() []Operator {
+	return []Operator{w.Source}
+}
+
+func (w *Window) SetInputs(operators []Operator) {
+	w.Source = operators[0]
+}
+
+// AddPredicate can't push predicates to the source, since that would change the rows the window functions see
+func (w *Window) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	return newFilter(w, expr)
+}
+
+func (w *Window) AddColumn(ctx *plancontext.PlanningContext, reuse bool, gb bool, expr *sqlparser.AliasedExpr) int {
+	if offset := w.findFunc(ctx, expr.Expr); offset >= 0 {
+		return offset
+	}
+	return len(w.Funcs) + w.Source.AddColumn(ctx, reuse, gb, expr)
+}
+
+func (w *Window) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, underRoute bool) int {
+	if offset := w.findFunc(ctx, expr); offset >= 0 {
+		return offset
+	}
+	offset := w.Source.FindCol(ctx, expr, underRoute)
+	if offset < 0 {
+		return offset
+	}
+	return len(w.Funcs) + offset
+}
+
+func (w *Window) findFunc(ctx *plancontext.PlanningContext, expr sqlparser.Expr) int {
+	return slices.IndexFunc(w.Funcs, func(fn *WindowFunc) bool {
+		return ctx.SemTable.EqualsExprWithDeps(fn.Func, expr)
+	})
+}
+
+func (w *Window) GetColumns(ctx *plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	columns := slice.Map(w.Funcs, func(fn *WindowFunc) *sqlparser.AliasedExpr {
+		return fn.Original
+	})
+	return append(columns, w.Source.GetColumns(ctx)...)
+}
+
+func (w *Window) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
+	return transformColumnsToSelectExprs(ctx, w)
+}
+
+// GetOrdering returns nothing, since the rows are sorted by the window specifications
+func (w *Window) GetOrdering(*plancontext.PlanningContext) []OrderBy {
+	return nil
+}
+
+func (w *Window) ShortDescription() string {
+	funcs := slice.Map(w.Funcs, func(fn *WindowFunc) string {
+		return sqlparser.String(fn.Func)
+	})
+	return strings.Join(funcs, ", ")
+}
+
+func (w *Window) planOffsets(ctx *plancontext.PlanningContext) Operator {
+	cfg := &evalengine.Config{
+		ResolveType: ctx.SemTable.TypeForExpr,
+		Collation:   ctx.SemTable.Collation,
+		Environment: ctx.VSchema.Environment(),
+	}
+
+	for _, fn := range w.Funcs {
+		fn.planOffsets(ctx, w, cfg)
+	}
+	return nil
+}
+
+func (fn *WindowFunc) planOffsets(ctx *plancontext.PlanningContext, w *Window, cfg *evalengine.Config) {
+	addColumn := func(expr sqlparser.Expr) (offset, wsOffset int) {
+		offset = w.Source.AddColumn(ctx, true, false, aeWrap(expr))
+		if !ctx.SemTable.NeedsWeightString(expr) {
+			return offset, -1
+		}
+		return offset, w.Source.AddColumn(ctx, true, false, aeWrap(weightStringFor(expr)))
+	}
+
+	if fn.Arg != nil {
+		fn.ColOffset = w.Source.AddColumn(ctx, true, false, aeWrap(fn.Arg))
+	}
+	for _, expr := range fn.Spec.PartitionClause {
+		offset, wsOffset := addColumn(expr)
+		fn.PartitionOffsets = append(fn.PartitionOffsets, offset)
+		fn.PartitionWSOffsets = append(fn.PartitionWSOffsets, wsOffset)
+	}
+	for _, order := range fn.Spec.OrderClause {
+		offset, wsOffset := addColumn(order.Expr)
+		fn.OrderOffsets = append(fn.OrderOffsets, offset)
+		fn.OrderWSOffsets = append(fn.OrderWSOffsets, wsOffset)
+	}
+
+	if fn.N != nil {
+		fn.NExpr = translateWindowConstant(ctx, fn.N)
+	}
+	if fn.Default != nil {
+		fn.DefaultExpr = translateExpr(useOffsets(ctx, fn.Default, w), cfg)
+	}
+	if fn.AggrOpCode == opcode.AggregateAvg {
+		avg := &sqlparser.BinaryExpr{
+			Operator: sqlparser.DivOp,
+			Left:     sqlparser.NewOffset(0, fn.Arg),
+			Right:    sqlparser.NewOffset(1, fn.Arg),
+		}
+		fn.AvgExpr = translateExpr(avg, &evalengine.Config{
+			Collation:   ctx.SemTable.Collation,
+			Environment: ctx.VSchema.Environment(),
+		})
+	}
+	if fc := fn.Spec.FrameClause; fc != nil {
+		fn.Frame = &engine.WindowFrame{
+			Unit:  fc.Unit,
+			Start: newWindowFramePoint(ctx, fc.Start),
+			End:   engine.WindowFramePoint{Type: sqlparser.CurrentRowType},
+		}
+		if fc.End != nil {
+			fn.Frame.End = newWindowFramePoint(ctx, fc.End)
+		}
+	}
+}
+
+func newWindowFramePoint(ctx *plancontext.PlanningContext, fp *sqlparser.FramePoint) engine.WindowFramePoint {
+	point := engine.WindowFramePoint{Type: fp.Type}
+	if fp.Expr != nil {
+		point.Offset = translateWindowConstant(ctx, fp.Expr)
+	}
+	return point
+}
+
+// translateWindowConstant translates the arguments of window functions and frames that must be constants.
+// Columns can't be used in them, so we don't have to plan any offsets.
+func translateWindowConstant(ctx *plancontext.PlanningContext, expr sqlparser.Expr) evalengine.Expr {
+	eexpr, err := evalengine.Translate(expr, &evalengine.Config{
+		Collation:   ctx.SemTable.Collation,
+		Environment: ctx.VSchema.Environment(),
+	})
+	if err != nil {
+		panic(vterrors.VT12001(fmt.Sprintf("non-constant window function argument '%s'", sqlparser.String(expr))))
+	}
+	return eexpr
+}
+
+func translateExpr(expr sqlparser.Expr, cfg *evalengine.Config) evalengine.Expr {
+	eexpr, err := evalengine.Translate(expr, cfg)
+	if err != nil {
+		panic(err)
+	}
+	return eexpr
+}
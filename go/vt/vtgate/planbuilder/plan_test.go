@@ -48,7 +48,9 @@ import (
 )
 
 func makeTestOutput(t *testing.T) string {
-	testOutputTempDir := utils.MakeTestOutput(t, "testdata", "plan_test")
+	// Write the output to the system temp dir so failing runs never leave
+	// directories behind in testdata.
+	testOutputTempDir := utils.MakeTestOutput(t, os.TempDir(), "plan_test")
 
 	return testOutputTempDir
 }
@@ -102,6 +104,7 @@ func TestPlan(t *testing.T) {
 	testFile(t, "vexplain_cases.json", testOutputTempDir, vschemaWrapper, false)
 	testFile(t, "misc_cases.json", testOutputTempDir, vschemaWrapper, false)
 	testFile(t, "cte_cases.json", testOutputTempDir, vschemaWrapper, false)
+	testFile(t, "window_cases.json", testOutputTempDir, vschemaWrapper, false)
 }
 
 // TestForeignKeyPlanning tests the planning of foreign keys in a managed mode by Vitess.
@@ -407,6 +410,17 @@ func TestOLTP(t *testing.T) {
 	testFile(t, "oltp_cases.json", makeTestOutput(t), vschemaWrapper, false)
 }
 
+func TestMirrorPlanning(t *testing.T) {
+	vschemaWrapper := &vschemawrapper.VSchemaWrapper{
+		V:             loadSchema(t, "vschemas/mirror_schema.json", true),
+		TabletType_:   topodatapb.TabletType_PRIMARY,
+		SysVarEnabled: true,
+		Env:           vtenv.NewTestEnv(),
+	}
+
+	testFile(t, "mirror_cases.json", makeTestOutput(t), vschemaWrapper, false)
+}
+
 func TestTPCC(t *testing.T) {
 	vschemaWrapper := &vschemawrapper.VSchemaWrapper{
 		V:             loadSchema(t, "vschemas/tpcc_schema.json", true),
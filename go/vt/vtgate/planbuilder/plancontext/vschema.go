@@ -14,6 +14,7 @@ import (
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/tableacl"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
@@ -96,6 +97,13 @@ type VSchema interface {
 
 	// GetAggregateUDFs returns the list of aggregate UDFs.
 	GetAggregateUDFs() []string
+
+	// TableRestrictions returns the column and row level ACLs of the caller on the table,
+	// or nil if there are none.
+	TableRestrictions(table *vindexes.Table) *tableacl.TableRestrictions
+
+	// HasTableRestrictions returns true if the caller has column or row level ACLs on any table.
+	HasTableRestrictions() bool
 }
 
 // PlannerNameToVersion returns the numerical representation of the planner
@@ -197,6 +197,11 @@ func newBuildSelectPlan(
 		return nil, nil, err
 	}
 
+	ctx, err = enforceDataACL(ctx, selStmt)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if ks, _ := ctx.SemTable.SingleUnshardedKeyspace(); ks != nil {
 		plan, tablesUsed, err = selectUnshardedShortcut(ctx, selStmt, ks)
 		if err != nil {
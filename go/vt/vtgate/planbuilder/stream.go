@@ -19,6 +19,7 @@ package planbuilder
 import (
 	"vitess.io/vitess/go/vt/key"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
@@ -30,6 +31,10 @@ func buildStreamPlan(stmt *sqlparser.Stream, vschema plancontext.VSchema) (*plan
 	if err != nil {
 		return nil, err
 	}
+	if vschema.TableRestrictions(table) != nil {
+		// the rows are streamed as is, so the column and row level ACLs could not be enforced on them
+		return nil, vterrors.NewErrorf(vtrpcpb.Code_PERMISSION_DENIED, vterrors.AccessDeniedError, "STREAM of table %s is not allowed for callers with column or row level ACLs", table.Name.String())
+	}
 	if destTabletType != topodatapb.TabletType_PRIMARY {
 		return nil, vterrors.VT09009(destTabletType)
 	}
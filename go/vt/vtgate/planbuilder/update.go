@@ -41,6 +41,11 @@ func gen4UpdateStmtPlanner(
 		return nil, err
 	}
 
+	ctx, err = enforceDataACL(ctx, updStmt)
+	if err != nil {
+		return nil, err
+	}
+
 	err = queryRewrite(ctx, updStmt)
 	if err != nil {
 		return nil, err
@@ -24,6 +24,7 @@ import (
 
 	"vitess.io/vitess/go/vt/key"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
@@ -36,6 +37,10 @@ func buildVStreamPlan(stmt *sqlparser.VStream, vschema plancontext.VSchema) (*pl
 	if err != nil {
 		return nil, err
 	}
+	if vschema.TableRestrictions(table) != nil {
+		// the row events are streamed as is, so the column and row level ACLs could not be enforced on them
+		return nil, vterrors.NewErrorf(vtrpcpb.Code_PERMISSION_DENIED, vterrors.AccessDeniedError, "VSTREAM of table %s is not allowed for callers with column or row level ACLs", table.Name.String())
+	}
 	// TODO: do we need this restriction?
 	if destTabletType != topodatapb.TabletType_PRIMARY {
 		return nil, vterrors.VT09009(destTabletType)
@@ -72,6 +72,8 @@ var (
 	mysqlQueryTimeout             time.Duration
 	mysqlSlowConnectWarnThreshold time.Duration
 	mysqlConnBufferPooling        bool
+	mysqlServerEnableCompression  bool
+	mysqlServerEnableLocalInfile  bool
 
 	mysqlDefaultWorkloadName = "OLTP"
 	mysqlDefaultWorkload     int32
@@ -100,6 +102,8 @@ func registerPluginFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&mysqlQueryTimeout, "mysql_server_query_timeout", mysqlQueryTimeout, "mysql query timeout")
 	fs.BoolVar(&mysqlConnBufferPooling, "mysql-server-pool-conn-read-buffers", mysqlConnBufferPooling, "If set, the server will pool incoming connection read buffers")
 	fs.DurationVar(&mysqlKeepAlivePeriod, "mysql-server-keepalive-period", mysqlKeepAlivePeriod, "TCP period between keep-alives")
+	fs.BoolVar(&mysqlServerEnableCompression, "mysql-server-enable-compression", mysqlServerEnableCompression, "If set, the server will support zlib and zstd protocol compression for clients that ask for it")
+	fs.BoolVar(&mysqlServerEnableLocalInfile, "mysql-server-enable-local-infile", mysqlServerEnableLocalInfile, "If set, the server will read the files of LOAD DATA LOCAL INFILE statements from the clients that enable it")
 	fs.DurationVar(&mysqlServerFlushDelay, "mysql_server_flush_delay", mysqlServerFlushDelay, "Delay after which buffered response will be flushed to the client.")
 	fs.StringVar(&mysqlDefaultWorkloadName, "mysql_default_workload", mysqlDefaultWorkloadName, "Default session workload (OLTP, OLAP, DBA)")
 }
@@ -147,6 +151,14 @@ func (vh *vtgateHandler) ComResetConnection(c *mysql.Conn) {
 	}
 }
 
+// ComChangeUser closes the session of the previous user, and starts a new one,
+// as if the new user just connected. The immediate caller id is built from
+// the User and UserData of the connection for every query, so it is updated too.
+func (vh *vtgateHandler) ComChangeUser(c *mysql.Conn) {
+	vh.ComResetConnection(c)
+	c.ClientData = nil
+}
+
 func (vh *vtgateHandler) ConnectionClosed(c *mysql.Conn) {
 	// Rollback if there is an ongoing transaction. Ignore error.
 	defer func() {
@@ -204,6 +216,20 @@ func startSpan(ctx context.Context, query, label string) (trace.Span, context.Co
 	return startSpanTestable(ctx, query, label, trace.NewSpan, trace.NewFromString)
 }
 
+// startSpanWithQueryAttributes starts a span for the query. The parent span
+// can be sent as the VT_SPAN_CONTEXT query attribute, or in the query comments.
+func startSpanWithQueryAttributes(ctx context.Context, attributes []mysql.QueryAttribute, query, label string) (trace.Span, context.Context, error) {
+	for _, attr := range attributes {
+		if !strings.EqualFold(attr.Name, "VT_SPAN_CONTEXT") || attr.Value.IsNull() {
+			continue
+		}
+		span, ctx := getSpan(ctx, []string{attr.Name, attr.Value.ToString()}, trace.NewSpan, label, trace.NewFromString)
+		trace.AnnotateSQL(span, sqlparser.Preview(query))
+		return span, ctx, nil
+	}
+	return startSpan(ctx, query, label)
+}
+
 func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
 	session := vh.session(c)
 	if c.IsShuttingDown() && !session.InTransaction {
@@ -219,13 +245,15 @@ func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sq
 		defer cancel()
 	}
 
-	span, ctx, err := startSpan(ctx, query, "vtgateHandler.ComQuery")
+	span, ctx, err := startSpanWithQueryAttributes(ctx, c.QueryAttributes, query, "vtgateHandler.ComQuery")
 	if err != nil {
 		return vterrors.Wrap(err, "failed to extract span")
 	}
 	defer span.Finish()
 
 	ctx = callinfo.MysqlCallInfo(ctx, c)
+	ctx = withQueryAttributes(ctx, c.QueryAttributes)
+	ctx = withLocalInfile(ctx, c)
 
 	// Fill in the ImmediateCallerID with the UserData returned by
 	// the AuthServer plugin for that user. If nothing was
@@ -321,6 +349,45 @@ func (vh *vtgateHandler) ComPrepare(c *mysql.Conn, query string, bindVars map[st
 	return fld, nil
 }
 
+func (vh *vtgateHandler) ComFieldList(c *mysql.Conn, table, wildcard string) ([]*querypb.Field, error) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if mysqlQueryTimeout != 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), mysqlQueryTimeout)
+		defer cancel()
+	} else {
+		ctx = context.Background()
+	}
+
+	ctx = callinfo.MysqlCallInfo(ctx, c)
+
+	// Fill in the ImmediateCallerID as for ComQuery,
+	// the fields of a table are subject to table ACLs too.
+	im := c.UserData.Get()
+	ef := callerid.NewEffectiveCallerID(
+		c.User,                  /* principal: who */
+		c.RemoteAddr().String(), /* component: running client process */
+		"VTGate MySQL Connector" /* subcomponent: part of the client */)
+	ctx = callerid.NewContext(ctx, ef, im)
+
+	session := vh.session(c)
+	if !session.InTransaction {
+		vh.busyConnections.Add(1)
+	}
+	defer func() {
+		if !session.InTransaction {
+			vh.busyConnections.Add(-1)
+		}
+	}()
+
+	fld, err := vh.vtg.FieldList(ctx, session, table, wildcard)
+	err = sqlerror.NewSQLErrorFromError(err)
+	if err != nil {
+		return nil, err
+	}
+	return fld, nil
+}
+
 func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareData, callback func(*sqltypes.Result) error) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	c.UpdateCancelCtx(cancel)
@@ -330,7 +397,14 @@ func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareDat
 		defer cancel()
 	}
 
+	span, ctx, err := startSpanWithQueryAttributes(ctx, c.QueryAttributes, prepare.PrepareStmt, "vtgateHandler.ComStmtExecute")
+	if err != nil {
+		return vterrors.Wrap(err, "failed to extract span")
+	}
+	defer span.Finish()
+
 	ctx = callinfo.MysqlCallInfo(ctx, c)
+	ctx = withQueryAttributes(ctx, c.QueryAttributes)
 
 	// Fill in the ImmediateCallerID with the UserData returned by
 	// the AuthServer plugin for that user. If nothing was
@@ -548,6 +622,8 @@ func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 			_ = initTLSConfig(context.Background(), srv, mysqlSslCert, mysqlSslKey, mysqlSslCa, mysqlSslCrl, mysqlSslServerCA, mysqlServerRequireSecureTransport, tlsVersion)
 		}
 		srv.tcpListener.AllowClearTextWithoutTLS.Store(mysqlAllowClearTextWithoutTLS)
+		srv.tcpListener.EnableCompression = mysqlServerEnableCompression
+		srv.tcpListener.EnableLocalInfile = mysqlServerEnableLocalInfile
 		// Check for the connection threshold
 		if mysqlSlowConnectWarnThreshold != 0 {
 			log.Infof("setting mysql slow connection threshold to %v", mysqlSlowConnectWarnThreshold)
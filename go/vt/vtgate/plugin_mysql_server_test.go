@@ -422,3 +422,33 @@ func TestGracefulShutdownWithTransaction(t *testing.T) {
 
 	require.True(t, mysqlConn.IsMarkedForClose())
 }
+
+func TestComChangeUser(t *testing.T) {
+	executor, _, _, _, _ := createExecutorEnv(t)
+
+	vh := newVtgateHandler(&VTGate{executor: executor, timings: timings, rowsReturned: rowsReturned, rowsAffected: rowsAffected})
+	th := &testHandler{}
+	listener, err := mysql.NewListener("tcp", "127.0.0.1:", mysql.NewAuthServerNone(), th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	mysqlConn := mysql.GetTestServerConn(listener)
+	mysqlConn.ConnectionID = 1
+	mysqlConn.UserData = &mysql.StaticUserData{}
+	vh.connections[1] = mysqlConn
+
+	err = vh.ComQuery(mysqlConn, "BEGIN", func(result *sqltypes.Result) error {
+		return nil
+	})
+	require.NoError(t, err)
+	session := vh.session(mysqlConn)
+	require.True(t, session.InTransaction)
+	require.EqualValues(t, 1, vh.busyConnections.Load())
+
+	// The transaction of the previous user is rolled back, and the new user gets a new session.
+	vh.ComChangeUser(mysqlConn)
+	newSession := vh.session(mysqlConn)
+	assert.False(t, newSession.InTransaction)
+	assert.NotEqual(t, session.SessionUUID, newSession.SessionUUID)
+	assert.EqualValues(t, 0, vh.busyConnections.Load())
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// queryAttributesKey is the context key of the query attributes sent
+// by a MySQL client with the query being executed.
+type queryAttributesKey struct{}
+
+// withQueryAttributes returns a copy of ctx carrying the given query attributes.
+func withQueryAttributes(ctx context.Context, attributes []mysql.QueryAttribute) context.Context {
+	if len(attributes) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, queryAttributesKey{}, attributes)
+}
+
+// queryAttributesFromContext returns the query attributes carried by ctx, if any.
+func queryAttributesFromContext(ctx context.Context) []mysql.QueryAttribute {
+	attributes, _ := ctx.Value(queryAttributesKey{}).([]mysql.QueryAttribute)
+	return attributes
+}
+
+// queryAttributesBindVars returns the query attributes as bind variables, to be logged.
+func queryAttributesBindVars(attributes []mysql.QueryAttribute) map[string]*querypb.BindVariable {
+	bindVars := make(map[string]*querypb.BindVariable, len(attributes))
+	for _, attr := range attributes {
+		bindVars[attr.Name] = sqltypes.ValueBindVariable(attr.Value)
+	}
+	return bindVars
+}
+
+// queryAttributesStrings returns the non-NULL query attributes as strings,
+// so they can be used as query directives.
+func queryAttributesStrings(attributes []mysql.QueryAttribute) map[string]string {
+	values := make(map[string]string, len(attributes))
+	for _, attr := range attributes {
+		if attr.Value.IsNull() {
+			continue
+		}
+		values[attr.Name] = attr.Value.ToString()
+	}
+	return values
+}
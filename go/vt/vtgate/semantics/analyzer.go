@@ -453,6 +453,10 @@ func (a *analyzer) noteQuerySignature(node sqlparser.SQLNode) {
 			a.sig.Aggregation = true
 		}
 	case sqlparser.AggrFunc:
+		if sqlparser.IsWindowFunc(node) {
+			// aggregations with an OVER clause do not group the rows
+			break
+		}
 		a.sig.Aggregation = true
 	case *sqlparser.Delete, *sqlparser.Update, *sqlparser.Insert:
 		a.sig.DML = true
@@ -888,8 +888,20 @@ func TestInvalidQueries(t *testing.T) {
 		sql:  "select 1 from t1 where (id, id) in (select 1, 2, 3)",
 		serr: "Operand should contain 2 column(s)",
 	}, {
-		sql:  "WITH RECURSIVE cte (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM cte WHERE n < 5) SELECT * FROM cte",
-		serr: "VT12001: unsupported: recursive common table expression",
+		sql:  "WITH RECURSIVE cte (n) AS (SELECT n + 1 FROM cte WHERE n < 5) SELECT * FROM cte",
+		serr: "VT03034: recursive common table expression 'cte' should contain a UNION",
+	}, {
+		sql:  "WITH RECURSIVE cte (n) AS (SELECT n + 1 FROM cte UNION ALL SELECT 1) SELECT * FROM cte",
+		serr: "VT03035: recursive common table expression 'cte' should have one or more non-recursive query blocks followed by one or more recursive ones",
+	}, {
+		sql:  "WITH RECURSIVE cte (n) AS (SELECT 1 UNION ALL SELECT max(n) + 1 FROM cte WHERE n < 5) SELECT * FROM cte",
+		serr: "VT03036: recursive common table expression 'cte' can contain neither aggregation nor window functions in recursive query block",
+	}, {
+		sql:  "WITH RECURSIVE cte (n) AS (SELECT 1 UNION ALL SELECT a.n + 1 FROM cte a JOIN cte b ON a.n = b.n) SELECT * FROM cte",
+		serr: "VT12001: unsupported: recursive common table expression that is not referenced exactly once in the FROM clause of the recursive query block",
+	}, {
+		sql:  "WITH RECURSIVE cte (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM cte LEFT JOIN t1 ON t1.id = n) SELECT * FROM cte",
+		serr: "VT12001: unsupported: outer join with the recursive reference of a common table expression",
 	}, {
 		sql:  "with x as (select 1), x as (select 1) select * from x",
 		serr: "VT03013: not unique table/alias: 'x'",
@@ -1371,6 +1383,30 @@ func TestScopingSubQueryJoinClause(t *testing.T) {
 
 }
 
+// TestScopingLateralDerivedTable tests that a LATERAL derived table can use the columns
+// of the tables that come before it in the FROM clause, while a normal derived table can't.
+func TestScopingLateralDerivedTable(t *testing.T) {
+	query := "select x.uid from t1, lateral (select t2.uid from t2 where t2.uid = t1.id) x"
+
+	parse, err := sqlparser.NewTestParser().Parse(query)
+	require.NoError(t, err)
+
+	st, err := Analyze(parse, "d", fakeSchemaInfo())
+	require.NoError(t, err)
+
+	dt := parse.(*sqlparser.Select).From[1].(*sqlparser.AliasedTableExpr).Expr.(*sqlparser.DerivedTable)
+	cmp := dt.Select.(*sqlparser.Select).Where.Expr.(*sqlparser.ComparisonExpr)
+	assert.Equal(t, TS0, st.DirectDeps(cmp.Right))
+	assert.Equal(t, TS1, st.DirectDeps(cmp.Left))
+
+	parse, err = sqlparser.NewTestParser().Parse("select x.uid from t1, (select t2.uid from t2 where t2.uid = t1.id) x")
+	require.NoError(t, err)
+
+	st, err = Analyze(parse, "d", fakeSchemaInfo())
+	require.NoError(t, err)
+	require.EqualError(t, st.NotUnshardedErr, "column 't1.id' not found")
+}
+
 var unsharded = &vindexes.Keyspace{
 	Name:    "unsharded",
 	Sharded: false,
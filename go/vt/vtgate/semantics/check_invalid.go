@@ -38,22 +38,14 @@ func (a *analyzer) checkForInvalidConstructs(cursor *sqlparser.Cursor) error {
 		return checkUnion(node)
 	case *sqlparser.JSONTableExpr:
 		return &JSONTablesError{}
-	case *sqlparser.DerivedTable:
-		return checkDerived(node)
 	case *sqlparser.AssignmentExpr:
 		return vterrors.VT12001("Assignment expression")
 	case *sqlparser.Subquery:
 		return a.checkSubqueryColumns(cursor.Parent(), node)
-	case *sqlparser.With:
-		if node.Recursive {
-			return vterrors.VT12001("recursive common table expression")
-		}
 	case *sqlparser.Insert:
 		if node.Action == sqlparser.ReplaceAct {
 			return ShardedError{Inner: &UnsupportedConstruct{errString: "REPLACE INTO with sharded keyspace"}}
 		}
-	case *sqlparser.OverClause:
-		return ShardedError{Inner: &UnsupportedConstruct{errString: "OVER CLAUSE with sharded keyspace"}}
 	}
 
 	return nil
@@ -92,13 +84,6 @@ func (a *analyzer) checkSubqueryColumns(parent sqlparser.SQLNode, subq *sqlparse
 	return nil
 }
 
-func checkDerived(node *sqlparser.DerivedTable) error {
-	if node.Lateral {
-		return vterrors.VT12001("lateral derived tables")
-	}
-	return nil
-}
-
 func checkUnion(node *sqlparser.Union) error {
 	err := sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
 		switch node := node.(type) {
@@ -121,6 +121,10 @@ func (r *earlyRewriter) handleAliasedTable(node *sqlparser.AliasedTableExpr) err
 	if !ok || tbl.Qualifier.NotEmpty() {
 		return nil
 	}
+	if _, isRecursiveRef := r.tables.recursiveCTERefs[node]; isRecursiveRef {
+		// this is the recursive query block reading from the CTE it belongs to - it stays a table
+		return nil
+	}
 	scope := r.scoper.currentScope()
 	cte := scope.findCTE(tbl.Name.String())
 	if cte == nil {
@@ -129,8 +133,15 @@ func (r *earlyRewriter) handleAliasedTable(node *sqlparser.AliasedTableExpr) err
 	if node.As.IsEmpty() {
 		node.As = tbl.Name
 	}
+	sel := cte.Subquery.Select
+	if cte.recursive {
+		// every reference gets its own copy of the CTE, so the recursive
+		// reference inside it can be bound to this particular use of the CTE
+		sel = sqlparser.CloneSelectStatement(sel)
+		r.tables.addRecursiveCTERef(cte.CommonTableExpr, sel.(*sqlparser.Union))
+	}
 	node.Expr = &sqlparser.DerivedTable{
-		Select: cte.Subquery.Select,
+		Select: sel,
 	}
 	if len(cte.Columns) > 0 {
 		node.Columns = cte.Columns
@@ -141,7 +152,7 @@ func (r *earlyRewriter) handleAliasedTable(node *sqlparser.AliasedTableExpr) err
 func (r *earlyRewriter) handleWith(node *sqlparser.With) error {
 	scope := r.scoper.currentScope()
 	for _, cte := range node.CTEs {
-		err := scope.addCTE(cte)
+		err := scope.addCTE(cte, node.Recursive)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"strings"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// RecursiveCTETable is the table the recursive query block of a recursive CTE reads from.
+// It contains the rows produced by the previous iteration of the recursion. Column names
+// come from the CTE column list or the anchor query, and the types from the anchor query.
+type RecursiveCTETable struct {
+	tableName string
+	ASTNode   *sqlparser.AliasedTableExpr
+	CTE       *sqlparser.CommonTableExpr
+	columns   []ColumnInfo
+}
+
+var _ TableInfo = (*RecursiveCTETable)(nil)
+
+// dependencies implements the TableInfo interface
+func (r *RecursiveCTETable) dependencies(colName string, org originable) (dependencies, error) {
+	ts := org.tableSetFor(r.ASTNode)
+	for _, info := range r.columns {
+		if strings.EqualFold(info.Name, colName) {
+			return createCertain(ts, ts, info.Type), nil
+		}
+	}
+	return &nothing{}, nil
+}
+
+// getTableSet implements the TableInfo interface
+func (r *RecursiveCTETable) getTableSet(org originable) TableSet {
+	return org.tableSetFor(r.ASTNode)
+}
+
+// getExprFor implements the TableInfo interface
+func (r *RecursiveCTETable) getExprFor(s string) (sqlparser.Expr, error) {
+	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "Unknown column '%s' in 'field list'", s)
+}
+
+// IsInfSchema implements the TableInfo interface
+func (r *RecursiveCTETable) IsInfSchema() bool {
+	return false
+}
+
+// getColumns implements the TableInfo interface
+func (r *RecursiveCTETable) getColumns() []ColumnInfo {
+	return r.columns
+}
+
+// GetAliasedTableExpr implements the TableInfo interface
+func (r *RecursiveCTETable) GetAliasedTableExpr() *sqlparser.AliasedTableExpr {
+	return r.ASTNode
+}
+
+func (r *RecursiveCTETable) canShortCut() shortCut {
+	return canShortCut
+}
+
+// GetVindexTable implements the TableInfo interface
+func (r *RecursiveCTETable) GetVindexTable() *vindexes.Table {
+	return nil
+}
+
+// Name implements the TableInfo interface
+func (r *RecursiveCTETable) Name() (sqlparser.TableName, error) {
+	return r.ASTNode.TableName()
+}
+
+// authoritative implements the TableInfo interface
+func (r *RecursiveCTETable) authoritative() bool {
+	return true
+}
+
+// matches implements the TableInfo interface
+func (r *RecursiveCTETable) matches(name sqlparser.TableName) bool {
+	return name.Qualifier.IsEmpty() && r.tableName == name.Name.String()
+}
+
+// ColumnNames returns the names of the columns of the CTE, in the order the rows produce them
+func (r *RecursiveCTETable) ColumnNames() []string {
+	names := make([]string, 0, len(r.columns))
+	for _, col := range r.columns {
+		names = append(names, col.Name)
+	}
+	return names
+}
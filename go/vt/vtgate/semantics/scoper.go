@@ -47,11 +47,17 @@ type (
 		isUnion      bool
 		joinUsing    map[string]TableSet
 		stmtScope    bool
-		ctes         map[string]*sqlparser.CommonTableExpr
+		ctes         map[string]*cteInfo
 		inGroupBy    bool
 		inHaving     bool
 		inHavingAggr bool
 	}
+
+	// cteInfo is a common table expression that is in scope, and whether it refers to itself
+	cteInfo struct {
+		*sqlparser.CommonTableExpr
+		recursive bool
+	}
 )
 
 func newScoper(si SchemaInformation) *scoper {
@@ -169,6 +175,10 @@ func (s *scoper) enterJoinScope(cursor *sqlparser.Cursor) {
 		// To create this special context, we will find the parent scope of the select statement involved.
 		currScope := s.currentScope()
 		stmtScope := currScope.findParentScopeOfStatement()
+		if isLateralDerivedTable(cursor.Node()) {
+			// a LATERAL derived table can also see the tables that come before it in the FROM clause
+			stmtScope = currScope
+		}
 		nScope := newScope(stmtScope)
 		if stmtScope == nil {
 			// TODO: this feels hacky. revisit with a better plan
@@ -179,6 +189,16 @@ func (s *scoper) enterJoinScope(cursor *sqlparser.Cursor) {
 	}
 }
 
+// isLateralDerivedTable returns true if the table expression is a derived table using the LATERAL keyword
+func isLateralDerivedTable(node sqlparser.SQLNode) bool {
+	ate, ok := node.(*sqlparser.AliasedTableExpr)
+	if !ok {
+		return false
+	}
+	dt, ok := ate.Expr.(*sqlparser.DerivedTable)
+	return ok && dt.Lateral
+}
+
 func (s *scoper) pushSelectScope(node *sqlparser.Select) {
 	currScope := newScope(s.currentScope())
 	currScope.stmtScope = true
@@ -331,20 +351,26 @@ func newScope(parent *scope) *scope {
 	return &scope{
 		parent:    parent,
 		joinUsing: map[string]TableSet{},
-		ctes:      map[string]*sqlparser.CommonTableExpr{},
+		ctes:      map[string]*cteInfo{},
 	}
 }
 
-func (s *scope) addCTE(cte *sqlparser.CommonTableExpr) error {
+func (s *scope) addCTE(cte *sqlparser.CommonTableExpr, recursive bool) error {
 	name := cte.ID.String()
 	_, exists := s.ctes[name]
 	if exists {
 		return vterrors.VT03013(name)
 	}
-	if err := checkForInvalidAliasUse(cte, name); err != nil {
+	info := &cteInfo{CommonTableExpr: cte}
+	if recursive && countCTEReferences(cte.Subquery.Select, name) > 0 {
+		if err := checkRecursiveCTE(cte, name); err != nil {
+			return err
+		}
+		info.recursive = true
+	} else if err := checkForInvalidAliasUse(cte, name); err != nil {
 		return err
 	}
-	s.ctes[name] = cte
+	s.ctes[name] = info
 	return nil
 }
 
@@ -364,6 +390,93 @@ func checkForInvalidAliasUse(cte *sqlparser.CommonTableExpr, name string) (err e
 	return err
 }
 
+// checkRecursiveCTE makes sure that a CTE that refers to itself has the shape MySQL requires:
+// one or more anchor query blocks, followed by a single recursive query block that reads the
+// CTE exactly once in its FROM clause
+func checkRecursiveCTE(cte *sqlparser.CommonTableExpr, name string) error {
+	union, ok := cte.Subquery.Select.(*sqlparser.Union)
+	if !ok {
+		return vterrors.VT03034(name)
+	}
+	if countCTEReferences(union.Left, name) > 0 {
+		return vterrors.VT03035(name)
+	}
+	term, ok := union.Right.(*sqlparser.Select)
+	if !ok {
+		return vterrors.VT12001("multiple recursive query blocks in a recursive common table expression")
+	}
+	if len(cteReferencesInFrom(term.From, name)) != 1 || countCTEReferences(term, name) != 1 {
+		return vterrors.VT12001("recursive common table expression that is not referenced exactly once in the FROM clause of the recursive query block")
+	}
+	if union.OrderBy != nil || union.Limit != nil || term.OrderBy != nil || term.Limit != nil || term.Distinct {
+		return vterrors.VT12001("ORDER BY / LIMIT / SELECT DISTINCT in recursive common table expression")
+	}
+	if term.GroupBy != nil || term.Having != nil || len(term.Windows) > 0 || containsAggrOrWindowFunc(term.SelectExprs) {
+		return vterrors.VT03036(name)
+	}
+	return checkRecursiveCTEJoins(term.From, name)
+}
+
+// checkRecursiveCTEJoins fails for outer joins that involve the recursive reference to the CTE.
+// The recursive query block is evaluated once for every row produced by the previous iteration,
+// which means the reference has to be joined using inner join semantics.
+func checkRecursiveCTEJoins(exprs sqlparser.TableExprs, name string) error {
+	return sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		join, ok := node.(*sqlparser.JoinTableExpr)
+		if !ok || join.Join.IsInner() || countCTEReferences(join, name) == 0 {
+			return true, nil
+		}
+		return false, vterrors.VT12001("outer join with the recursive reference of a common table expression")
+	}, exprs)
+}
+
+// countCTEReferences returns the number of table expressions that read from the CTE with the given name
+func countCTEReferences(node sqlparser.SQLNode, name string) (count int) {
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		ate, ok := node.(*sqlparser.AliasedTableExpr)
+		if !ok {
+			return true, nil
+		}
+		tbl, ok := ate.Expr.(sqlparser.TableName)
+		if ok && tbl.Qualifier.IsEmpty() && tbl.Name.String() == name {
+			count++
+		}
+		return true, nil
+	}, node)
+	return
+}
+
+// cteReferencesInFrom returns the table expressions of the FROM clause that read from the CTE with the given name.
+// Derived tables are not searched, since they are not part of the FROM clause itself
+func cteReferencesInFrom(from sqlparser.TableExprs, name string) (refs []*sqlparser.AliasedTableExpr) {
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		ate, ok := node.(*sqlparser.AliasedTableExpr)
+		if !ok {
+			return true, nil
+		}
+		tbl, ok := ate.Expr.(sqlparser.TableName)
+		if ok && tbl.Qualifier.IsEmpty() && tbl.Name.String() == name {
+			refs = append(refs, ate)
+		}
+		return false, nil
+	}, from)
+	return
+}
+
+func containsAggrOrWindowFunc(exprs sqlparser.SelectExprs) (found bool) {
+	if sqlparser.ContainsAggregation(exprs) {
+		return true
+	}
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if _, isSubq := node.(*sqlparser.Subquery); isSubq {
+			return false, nil
+		}
+		found = found || sqlparser.IsWindowFunc(node)
+		return !found, nil
+	}, exprs)
+	return
+}
+
 func (s *scope) addTable(info TableInfo) error {
 	name, err := info.Name()
 	if err != nil {
@@ -411,7 +524,7 @@ func (s *scope) findParentScopeOfStatement() *scope {
 }
 
 // findCTE will search in this scope, and then recursively search the parents
-func (s *scope) findCTE(name string) *sqlparser.CommonTableExpr {
+func (s *scope) findCTE(name string) *cteInfo {
 	cte, found := s.ctes[name]
 	if found || s.parent == nil {
 		// if we don't have a parent, we'll return
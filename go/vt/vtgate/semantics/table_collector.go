@@ -36,6 +36,10 @@ type tableCollector struct {
 	org       originable
 	unionInfo map[*sqlparser.Union]unionInfo
 	done      map[*sqlparser.AliasedTableExpr]TableInfo
+
+	// recursiveCTERefs keeps track of the table expressions that
+	// the recursive query block of a CTE uses to read from itself
+	recursiveCTERefs map[*sqlparser.AliasedTableExpr]*sqlparser.CommonTableExpr
 }
 
 type earlyTableCollector struct {
@@ -83,6 +87,8 @@ func (etc *earlyTableCollector) newTableCollector(scoper *scoper, org originable
 		unionInfo: map[*sqlparser.Union]unionInfo{},
 		done:      etc.done,
 		org:       org,
+
+		recursiveCTERefs: map[*sqlparser.AliasedTableExpr]*sqlparser.CommonTableExpr{},
 	}
 }
 
@@ -174,7 +180,11 @@ func (tc *tableCollector) handleTableName(node *sqlparser.AliasedTableExpr, t sq
 
 	tableInfo, found = tc.done[node]
 	if !found {
-		tableInfo, err = getTableInfo(node, t, tc.si, tc.currentDb)
+		if cte, isRecursiveRef := tc.recursiveCTERefs[node]; isRecursiveRef {
+			tableInfo, err = tc.newRecursiveCTETable(node, cte)
+		} else {
+			tableInfo, err = getTableInfo(node, t, tc.si, tc.currentDb)
+		}
 		if err != nil {
 			return err
 		}
@@ -185,6 +195,50 @@ func (tc *tableCollector) handleTableName(node *sqlparser.AliasedTableExpr, t sq
 	return scope.addTable(tableInfo)
 }
 
+// addRecursiveCTERef records the table expression the recursive query block of
+// the given union uses to read from the CTE, so it is not expanded again
+func (tc *tableCollector) addRecursiveCTERef(cte *sqlparser.CommonTableExpr, union *sqlparser.Union) {
+	def := &sqlparser.CommonTableExpr{
+		ID:       cte.ID,
+		Columns:  cte.Columns,
+		Subquery: &sqlparser.Subquery{Select: union},
+	}
+	term := union.Right.(*sqlparser.Select)
+	for _, ref := range cteReferencesInFrom(term.From, cte.ID.String()) {
+		tc.recursiveCTERefs[ref] = def
+	}
+}
+
+func (tc *tableCollector) newRecursiveCTETable(node *sqlparser.AliasedTableExpr, cte *sqlparser.CommonTableExpr) (TableInfo, error) {
+	union := cte.Subquery.Select.(*sqlparser.Union)
+	anchor := sqlparser.GetFirstSelect(union.Left)
+	if len(cte.Columns) > 0 && len(cte.Columns) != len(anchor.SelectExprs) {
+		return nil, vterrors.VT03006()
+	}
+
+	tbl := &RecursiveCTETable{
+		tableName: node.As.String(),
+		ASTNode:   node,
+		CTE:       cte,
+	}
+	if tbl.tableName == "" {
+		tbl.tableName = cte.ID.String()
+	}
+	for i, expr := range anchor.SelectExprs {
+		ae, ok := expr.(*sqlparser.AliasedExpr)
+		if !ok {
+			return nil, vterrors.VT09015()
+		}
+		name := ae.ColumnName()
+		if len(cte.Columns) > 0 {
+			name = cte.Columns[i].String()
+		}
+		_, _, typ := tc.org.depsForExpr(ae.Expr)
+		tbl.columns = append(tbl.columns, ColumnInfo{Name: name, Type: typ})
+	}
+	return tbl, nil
+}
+
 func getTableInfo(node *sqlparser.AliasedTableExpr, t sqlparser.TableName, si SchemaInformation, currentDb string) (TableInfo, error) {
 	var tbl *vindexes.Table
 	var vindex vindexes.Vindex
@@ -18,6 +18,7 @@ package semantics
 
 import (
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
@@ -61,10 +62,32 @@ func (t *typer) up(cursor *sqlparser.Cursor) error {
 			}
 		}
 		t.m[node] = code.ResolveType(inputType, t.collationEnv)
+	case *sqlparser.ArgumentLessWindowExpr:
+		sqltype := sqltypes.Uint64
+		if node.Type == sqlparser.PercentRankExprType || node.Type == sqlparser.CumeDistExprType {
+			sqltype = sqltypes.Float64
+		}
+		t.m[node] = evalengine.NewType(sqltype, collations.CollationBinaryID)
+	case *sqlparser.NtileExpr:
+		t.m[node] = evalengine.NewType(sqltypes.Uint64, collations.CollationBinaryID)
+	case *sqlparser.LagLeadExpr:
+		t.setWindowValueType(node, node.Expr)
+	case *sqlparser.FirstOrLastValueExpr:
+		t.setWindowValueType(node, node.Expr)
+	case *sqlparser.NTHValueExpr:
+		t.setWindowValueType(node, node.Expr)
 	}
 	return nil
 }
 
+// setWindowValueType sets the type of window functions that return the value of their argument
+// for some row of the partition. They return NULL when that row does not exist.
+func (t *typer) setWindowValueType(node, arg sqlparser.Expr) {
+	if tt, ok := t.m[arg]; ok {
+		t.m[node] = evalengine.NewTypeEx(tt.Type(), tt.Collation(), true, tt.Size(), tt.Scale())
+	}
+}
+
 func (t *typer) setTypeFor(node *sqlparser.ColName, typ evalengine.Type) {
 	t.m[node] = typ
 }
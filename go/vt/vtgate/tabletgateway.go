@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math/rand/v2"
 	"runtime/debug"
+	"slices"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -37,6 +38,7 @@ import (
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/balancer"
 	"vitess.io/vitess/go/vt/vtgate/buffer"
 	"vitess.io/vitess/go/vt/vttablet/queryservice"
 
@@ -55,6 +57,23 @@ var (
 	retryCount = 2
 
 	logCollations = logutil.NewThrottledLogger("CollationInconsistent", 1*time.Minute)
+
+	// balancerEnabled enables the tablet balancer for the tablet types other than PRIMARY.
+	balancerEnabled bool
+	// balancerVtgateCells is the list of cells that have vtgates, required by the balancer.
+	balancerVtgateCells []string
+	// balancerKeyspaces is the list of keyspaces the balancer is used for, or all of them if empty.
+	balancerKeyspaces []string
+	// balancerLoadAware makes the balancer account for the queries in flight to the tablets.
+	balancerLoadAware bool
+
+	// hedgeReplicaReads enables the hedging of the non-transactional REPLICA and RDONLY reads.
+	hedgeReplicaReads bool
+	// hedgeReplicaReadsDelay is the delay after which a read is also sent to a second tablet.
+	hedgeReplicaReadsDelay = 50 * time.Millisecond
+	// hedgeReplicaReadsPercentile, if set, replaces the delay by this percentile of the
+	// latencies of the recent reads of the target.
+	hedgeReplicaReadsPercentile float64
 )
 
 func init() {
@@ -62,6 +81,13 @@ func init() {
 		fs.StringVar(&CellsToWatch, "cells_to_watch", "", "comma-separated list of cells for watching tablets")
 		fs.DurationVar(&initialTabletTimeout, "gateway_initial_tablet_timeout", 30*time.Second, "At startup, the tabletGateway will wait up to this duration to get at least one tablet per keyspace/shard/tablet type")
 		fs.IntVar(&retryCount, "retry-count", 2, "retry count")
+		fs.BoolVar(&balancerEnabled, "enable-balancer", false, "Enable the tablet balancer to spread the load of the non-primary tablet types evenly across the tablets of all the cells, while preferring the local cell")
+		fs.StringSliceVar(&balancerVtgateCells, "balancer-vtgate-cells", []string{}, "When the tablet balancer is enabled, a comma-separated list of the cells that contain vtgates (required)")
+		fs.StringSliceVar(&balancerKeyspaces, "balancer-keyspaces", []string{}, "When the tablet balancer is enabled, a comma-separated list of the keyspaces to use it for (optional, defaults to all of them)")
+		fs.BoolVar(&balancerLoadAware, "balancer-load-aware", false, "When the tablet balancer is enabled, pick the tablets that have fewer queries in flight from this vtgate more often")
+		fs.BoolVar(&hedgeReplicaReads, "hedge-replica-reads", false, "Send the non-transactional REPLICA and RDONLY reads that are slow to respond to a second tablet too, and return the first response")
+		fs.DurationVar(&hedgeReplicaReadsDelay, "hedge-replica-reads-delay", 50*time.Millisecond, "When hedging replica reads, the delay after which a read is sent to a second tablet")
+		fs.Float64Var(&hedgeReplicaReadsPercentile, "hedge-replica-reads-percentile", 0, "When hedging replica reads, if set, use this percentile of the latencies of the recent reads of the target as the delay instead, e.g. 95")
 	})
 }
 
@@ -81,9 +107,15 @@ type TabletGateway struct {
 	// statusAggregators is a map indexed by the key
 	// keyspace/shard/tablet_type.
 	statusAggregators map[string]*TabletStatusAggregator
+	// hedgeLatencies is a map of the latencies of the recent hedgeable
+	// reads, indexed by the key keyspace/shard/tablet_type.
+	hedgeLatencies map[string]*latencyWindow
 
 	// buffer, if enabled, buffers requests during a detected PRIMARY failover.
 	buffer *buffer.Buffer
+
+	// balancer, if enabled, orders the tablets of the non-primary tablet types.
+	balancer balancer.TabletBalancer
 }
 
 func createHealthCheck(ctx context.Context, retryDelay, timeout time.Duration, ts *topo.Server, cell, cellsToWatch string) discovery.HealthCheck {
@@ -110,8 +142,12 @@ func NewTabletGateway(ctx context.Context, hc discovery.HealthCheck, serv srvtop
 		localCell:         localCell,
 		retryCount:        retryCount,
 		statusAggregators: make(map[string]*TabletStatusAggregator),
+		hedgeLatencies:    make(map[string]*latencyWindow),
 	}
 	gw.setupBuffering(ctx)
+	if balancerEnabled {
+		gw.setupBalancer()
+	}
 	gw.QueryService = queryservice.Wrap(nil, gw.withRetry)
 	return gw
 }
@@ -145,6 +181,21 @@ func (gw *TabletGateway) setupBuffering(ctx context.Context) {
 	}(bufferCtx, ksChan, gw.buffer)
 }
 
+func (gw *TabletGateway) setupBalancer() {
+	if len(balancerVtgateCells) == 0 {
+		log.Exitf("balancer-vtgate-cells is required when the tablet balancer is enabled")
+	}
+	gw.balancer = balancer.NewTabletBalancer(gw.localCell, balancerVtgateCells, balancerLoadAware)
+}
+
+// useBalancer returns whether the tablets of the target are ordered by the tablet balancer.
+func (gw *TabletGateway) useBalancer(target *querypb.Target) bool {
+	if gw.balancer == nil || target.TabletType == topodatapb.TabletType_PRIMARY {
+		return false
+	}
+	return len(balancerKeyspaces) == 0 || slices.Contains(balancerKeyspaces, target.Keyspace)
+}
+
 // QueryServiceByAlias satisfies the Gateway interface
 func (gw *TabletGateway) QueryServiceByAlias(alias *topodatapb.TabletAlias, target *querypb.Target) (queryservice.QueryService, error) {
 	qs, err := gw.hc.TabletConnection(alias, target)
@@ -228,8 +279,11 @@ func (gw *TabletGateway) CacheStatus() TabletCacheStatusList {
 //
 // withRetry also adds shard information to errors returned from the inner QueryService, so
 // withShardError should not be combined with withRetry.
+//
+// If hedging is enabled, the reads that can be hedged are also sent to the next tablet when
+// they are slow to respond, see executeHedged.
 func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target, _ queryservice.QueryService,
-	_ string, inTransaction bool, inner func(ctx context.Context, target *querypb.Target, conn queryservice.QueryService) (bool, error)) error {
+	name string, inTransaction bool, inner func(ctx context.Context, target *querypb.Target, conn queryservice.QueryService) (bool, error)) error {
 
 	// for transactions, we connect to a specific tablet instead of letting gateway choose one
 	if inTransaction && target.TabletType != topodatapb.TabletType_PRIMARY {
@@ -306,7 +360,12 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 			break
 		}
 
-		gw.shuffleTablets(gw.localCell, tablets)
+		useBalancer := gw.useBalancer(target)
+		if useBalancer {
+			gw.balancer.ShuffleTablets(target, tablets)
+		} else {
+			gw.shuffleTablets(gw.localCell, tablets)
+		}
 
 		var th *discovery.TabletHealth
 		// skip tablets we tried before
@@ -334,9 +393,18 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 
 		gw.updateDefaultConnCollation(tabletLastUsed)
 
+		if canHedge(target, name, inTransaction) {
+			var canRetry bool
+			canRetry, err = gw.executeHedged(ctx, target, th, hedgeTablet(tablets, th, invalidTablets), useBalancer, invalidTablets, inner)
+			if canRetry {
+				continue
+			}
+			break
+		}
+
 		startTime := time.Now()
 		var canRetry bool
-		canRetry, err = inner(ctx, target, th.Conn)
+		canRetry, err = gw.execute(ctx, target, th, useBalancer, inner)
 		gw.updateStats(target, startTime, err)
 		if canRetry {
 			invalidTablets[topoproto.TabletAliasString(tabletLastUsed.Alias)] = true
@@ -347,6 +415,17 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 	return NewShardError(err, target)
 }
 
+// execute runs inner on the tablet.
+func (gw *TabletGateway) execute(ctx context.Context, target *querypb.Target, th *discovery.TabletHealth, useBalancer bool,
+	inner func(ctx context.Context, target *querypb.Target, conn queryservice.QueryService) (bool, error)) (bool, error) {
+	queryDone := func() {}
+	if useBalancer {
+		queryDone = gw.balancer.QueryStarted(th.Tablet.Alias)
+	}
+	defer queryDone()
+	return inner(ctx, target, th.Conn)
+}
+
 // withShardError adds shard information to errors returned from the inner QueryService.
 func (gw *TabletGateway) withShardError(ctx context.Context, target *querypb.Target, conn queryservice.QueryService,
 	_ string, _ bool, inner func(ctx context.Context, target *querypb.Target, conn queryservice.QueryService) (bool, error)) error {
@@ -0,0 +1,219 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const (
+	// hedgeLatencySamples is the number of latencies of the recent reads of a
+	// target that the hedging percentile is computed from.
+	hedgeLatencySamples = 1000
+	// hedgeLatencyRefresh is the number of reads after which the hedging delay
+	// of a target is computed again.
+	hedgeLatencyRefresh = 100
+)
+
+var (
+	// hedgedReadsFired counts the reads that were also sent to a second tablet.
+	hedgedReadsFired = stats.NewCountersWithMultiLabels(
+		"HedgedReadsFired",
+		"Reads also sent to a second tablet because the first one did not respond within the hedging delay, by keyspace and tablet type",
+		[]string{"Keyspace", "TabletType"})
+	// hedgedReadsWon counts the hedged reads to which the second tablet responded first.
+	hedgedReadsWon = stats.NewCountersWithMultiLabels(
+		"HedgedReadsWon",
+		"Hedged reads to which the second tablet responded first, by keyspace and tablet type",
+		[]string{"Keyspace", "TabletType"})
+)
+
+// hedgeResult is the outcome of a read on one of the tablets of a hedged read.
+type hedgeResult struct {
+	th        *discovery.TabletHealth
+	startTime time.Time
+	canRetry  bool
+	err       error
+}
+
+// canHedge returns whether the call can be hedged. Only the non-transactional
+// Execute calls to REPLICA and RDONLY tablets are: the results of the streaming
+// calls are sent back while they are read, so they cannot be read twice.
+func canHedge(target *querypb.Target, name string, inTransaction bool) bool {
+	if !hedgeReplicaReads || inTransaction || name != "Execute" {
+		return false
+	}
+	return target.TabletType == topodatapb.TabletType_REPLICA || target.TabletType == topodatapb.TabletType_RDONLY
+}
+
+// hedgeTablet returns the tablet a read sent to th is hedged to, which is the next
+// one of the tablets that was not tried before, or nil if there is none.
+func hedgeTablet(tablets []*discovery.TabletHealth, th *discovery.TabletHealth, invalidTablets map[string]bool) *discovery.TabletHealth {
+	for _, t := range tablets[slices.Index(tablets, th)+1:] {
+		if t.Conn != nil && !invalidTablets[topoproto.TabletAliasString(t.Tablet.Alias)] {
+			return t
+		}
+	}
+	return nil
+}
+
+// executeHedged runs inner on the tablet th and, if it does not complete within the
+// hedging delay, on the tablet backup too. It returns the outcome of the first successful
+// read and cancels the other one, or the error of the last one if they both fail. The
+// tablets whose read fails with a retryable error are added to invalidTablets.
+func (gw *TabletGateway) executeHedged(ctx context.Context, target *querypb.Target, th, backup *discovery.TabletHealth, useBalancer bool, invalidTablets map[string]bool,
+	inner func(ctx context.Context, target *querypb.Target, conn queryservice.QueryService) (bool, error)) (bool, error) {
+	results := make(chan hedgeResult, 2)
+	var cancels []context.CancelFunc
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+	start := func(th *discovery.TabletHealth) {
+		readCtx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+		go func() {
+			startTime := time.Now()
+			canRetry, err := gw.execute(readCtx, target, th, useBalancer, inner)
+			results <- hedgeResult{th: th, startTime: startTime, canRetry: canRetry, err: err}
+		}()
+	}
+
+	var hedge <-chan time.Time
+	if backup != nil {
+		timer := time.NewTimer(gw.hedgeDelay(target))
+		defer timer.Stop()
+		hedge = timer.C
+	}
+	statsKey := []string{target.Keyspace, topoproto.TabletTypeLString(target.TabletType)}
+
+	start(th)
+	pending := 1
+	var canRetry bool
+	var err error
+	for pending > 0 {
+		select {
+		case <-hedge:
+			hedge = nil
+			hedgedReadsFired.Add(statsKey, 1)
+			gw.updateDefaultConnCollation(backup.Tablet)
+			start(backup)
+			pending++
+		case res := <-results:
+			pending--
+			gw.updateStats(target, res.startTime, res.err)
+			if res.err == nil {
+				if res.th == backup {
+					hedgedReadsWon.Add(statsKey, 1)
+				}
+				gw.recordHedgeLatency(target, time.Since(res.startTime))
+				return false, nil
+			}
+			if res.canRetry {
+				invalidTablets[topoproto.TabletAliasString(res.th.Tablet.Alias)] = true
+			}
+			canRetry, err = res.canRetry, res.err
+		}
+	}
+	return canRetry, err
+}
+
+// hedgeDelay returns the delay after which a read of the target is hedged.
+func (gw *TabletGateway) hedgeDelay(target *querypb.Target) time.Duration {
+	if hedgeReplicaReadsPercentile > 0 {
+		if delay := gw.getLatencyWindow(target).getDelay(); delay > 0 {
+			return delay
+		}
+	}
+	return hedgeReplicaReadsDelay
+}
+
+// recordHedgeLatency records the latency of a successful read of the target, if the
+// hedging delay is computed from them.
+func (gw *TabletGateway) recordHedgeLatency(target *querypb.Target, latency time.Duration) {
+	if hedgeReplicaReadsPercentile > 0 {
+		gw.getLatencyWindow(target).record(latency, hedgeReplicaReadsPercentile)
+	}
+}
+
+func (gw *TabletGateway) getLatencyWindow(target *querypb.Target) *latencyWindow {
+	key := fmt.Sprintf("%v/%v/%v", target.Keyspace, target.Shard, target.TabletType.String())
+
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+	window, ok := gw.hedgeLatencies[key]
+	if !ok {
+		window = &latencyWindow{}
+		gw.hedgeLatencies[key] = window
+	}
+	return window
+}
+
+// latencyWindow holds the latencies of the recent reads of a target, and the
+// hedging delay computed from them.
+type latencyWindow struct {
+	mu sync.Mutex
+	// latencies is a ring buffer of the last hedgeLatencySamples latencies.
+	latencies []time.Duration
+	next      int
+	// reads is the number of reads since the delay was computed.
+	reads int
+	delay time.Duration
+}
+
+// record adds a latency to the window, and computes the delay again as the given
+// percentile of the latencies every hedgeLatencyRefresh reads.
+func (w *latencyWindow) record(latency time.Duration, percentile float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.latencies) < hedgeLatencySamples {
+		w.latencies = append(w.latencies, latency)
+	} else {
+		w.latencies[w.next] = latency
+		w.next = (w.next + 1) % hedgeLatencySamples
+	}
+	w.reads++
+	if w.reads < hedgeLatencyRefresh {
+		return
+	}
+	w.reads = 0
+	sorted := slices.Clone(w.latencies)
+	slices.Sort(sorted)
+	i := int(float64(len(sorted)-1) * min(percentile, 100) / 100)
+	w.delay = sorted[i]
+}
+
+// getDelay returns the delay computed from the latencies, or 0 if there were not
+// enough reads yet.
+func (w *latencyWindow) getDelay() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.delay
+}
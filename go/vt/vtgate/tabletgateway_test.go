@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -152,6 +153,115 @@ func TestTabletGatewayShuffleTablets(t *testing.T) {
 	}
 }
 
+func TestTabletGatewayBalancer(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	oldEnabled, oldCells, oldKeyspaces := balancerEnabled, balancerVtgateCells, balancerKeyspaces
+	defer func() {
+		balancerEnabled, balancerVtgateCells, balancerKeyspaces = oldEnabled, oldCells, oldKeyspaces
+	}()
+	balancerEnabled = true
+	balancerVtgateCells = []string{"cell1", "cell2"}
+
+	target := &querypb.Target{
+		Keyspace:   "ks",
+		Shard:      "0",
+		TabletType: topodatapb.TabletType_REPLICA,
+	}
+	hc := discovery.NewFakeHealthCheck(nil)
+	tg := NewTabletGateway(ctx, hc, &fakeTopoServer{}, "cell1")
+	defer tg.Close(ctx)
+
+	// cell1 has one replica and cell2 has three: half of the queries of cell1 go to cell2.
+	local := hc.AddTestTablet("cell1", "1.1.1.1", 1001, "ks", "0", topodatapb.TabletType_REPLICA, true, 10, nil)
+	for i := int32(0); i < 3; i++ {
+		hc.AddTestTablet("cell2", "2.2.2.2", 1001+i, "ks", "0", topodatapb.TabletType_REPLICA, true, 10, nil)
+	}
+	const n = 1000
+	for i := 0; i < n; i++ {
+		_, err := tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+		require.NoError(t, err)
+	}
+	assert.InDelta(t, n/2, local.ExecCount.Load(), n/10)
+
+	// The balancer is not used for the other keyspaces, whose queries stay in the local cell.
+	balancerKeyspaces = []string{"other"}
+	local.ExecCount.Store(0)
+	for i := 0; i < n; i++ {
+		_, err := tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, n, local.ExecCount.Load())
+}
+
+func TestTabletGatewayHedgedReads(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	oldHedge, oldDelay := hedgeReplicaReads, hedgeReplicaReadsDelay
+	defer func() {
+		hedgeReplicaReads, hedgeReplicaReadsDelay = oldHedge, oldDelay
+	}()
+	hedgeReplicaReads = true
+	hedgeReplicaReadsDelay = 10 * time.Millisecond
+
+	target := &querypb.Target{
+		Keyspace:   "hedged",
+		Shard:      "0",
+		TabletType: topodatapb.TabletType_REPLICA,
+	}
+	hc := discovery.NewFakeHealthCheck(nil)
+	tg := NewTabletGateway(ctx, hc, &fakeTopoServer{}, "cell")
+	defer tg.Close(ctx)
+
+	slow := hc.AddTestTablet("cell", "1.1.1.1", 1001, "hedged", "0", topodatapb.TabletType_REPLICA, true, 10, nil)
+	slow.ExecuteDelay = 10 * time.Second
+	fast := hc.AddTestTablet("cell", "1.1.1.1", 1002, "hedged", "0", topodatapb.TabletType_REPLICA, true, 10, nil)
+
+	// The reads sent to the slow tablet first are hedged to the fast one, which responds first.
+	const n = 20
+	for i := 0; i < n; i++ {
+		start := time.Now()
+		qr, err := tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, len(qr.Rows))
+		assert.Less(t, time.Since(start), time.Second)
+	}
+	fired := hedgedReadsFired.Counts()["hedged.replica"]
+	assert.Greater(t, fired, int64(0))
+	assert.Equal(t, fired, hedgedReadsWon.Counts()["hedged.replica"])
+	// The slow tablet never completes a read: the fast one served all of them.
+	assert.EqualValues(t, n, fast.ExecCount.Load())
+	assert.Zero(t, slow.ExecCount.Load())
+
+	// The reads in a transaction and the streaming reads are not hedged.
+	_, err := tg.Execute(ctx, target, "query", nil, 1, 0, nil)
+	require.Error(t, err)
+	slow.ExecuteDelay = 0
+	err = tg.StreamExecute(ctx, target, "query", nil, 0, 0, nil, func(qr *sqltypes.Result) error {
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, fired, hedgedReadsFired.Counts()["hedged.replica"])
+}
+
+func TestLatencyWindow(t *testing.T) {
+	w := &latencyWindow{}
+	for i := 1; i < hedgeLatencyRefresh; i++ {
+		w.record(time.Duration(i)*time.Millisecond, 90)
+	}
+	// The delay is only computed once there are enough reads.
+	assert.Zero(t, w.getDelay())
+	w.record(hedgeLatencyRefresh*time.Millisecond, 90)
+	assert.Equal(t, 90*time.Millisecond, w.getDelay())
+
+	// Only the last hedgeLatencySamples latencies are kept.
+	for i := 0; i < hedgeLatencySamples; i++ {
+		w.record(time.Millisecond, 90)
+	}
+	assert.Len(t, w.latencies, hedgeLatencySamples)
+	assert.Equal(t, time.Millisecond, w.getDelay())
+}
+
 func TestTabletGatewayReplicaTransactionError(t *testing.T) {
 	ctx := utils.LeakCheckContext(t)
 
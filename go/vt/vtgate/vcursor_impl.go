@@ -43,6 +43,7 @@ import (
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/tableacl"
 	"vitess.io/vitess/go/vt/topo"
 	topoprotopb "vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
@@ -124,6 +125,14 @@ type vcursorImpl struct {
 
 	warmingReadsPercent int
 	warmingReadsChannel chan bool
+	mirrorChannel       chan bool
+
+	// tracer collects the execution statistics of the primitives for VEXPLAIN TRACE, it is nil otherwise.
+	tracer *engine.PrimitiveTracer
+
+	// dataACL holds the column and row level ACLs of callerID enforced by the planner, if any.
+	dataACL  *tableacl.DataACL
+	callerID *querypb.VTGateCallerID
 }
 
 // newVcursorImpl creates a vcursorImpl. Before creating this object, you have to separate out any marginComments that came with
@@ -170,9 +179,18 @@ func newVCursorImpl(
 
 	warmingReadsPct := 0
 	var warmingReadsChan chan bool
+	var mirrorChan chan bool
+	var dataACL *tableacl.DataACL
 	if executor != nil {
 		warmingReadsPct = executor.warmingReadsPercent
 		warmingReadsChan = executor.warmingReadsChannel
+		mirrorChan = executor.mirrorChannel
+		dataACL = executor.DataACL()
+	}
+
+	var callerID *querypb.VTGateCallerID
+	if logStats != nil && logStats.Ctx != nil {
+		callerID = callerid.ImmediateCallerIDFromContext(logStats.Ctx)
 	}
 	return &vcursorImpl{
 		safeSession:         safeSession,
@@ -191,6 +209,9 @@ func newVCursorImpl(
 		pv:                  pv,
 		warmingReadsPercent: warmingReadsPct,
 		warmingReadsChannel: warmingReadsChan,
+		mirrorChannel:       mirrorChan,
+		dataACL:             dataACL,
+		callerID:            callerID,
 	}, nil
 }
 
@@ -235,6 +256,11 @@ func (vc *vcursorImpl) ExceedsMaxMemoryRows(numRows int) bool {
 	return !vc.ignoreMaxMemoryRows && numRows > maxMemoryRows
 }
 
+// CTEMaxRecursionDepth returns the cteMaxRecursionDepth flag value.
+func (vc *vcursorImpl) CTEMaxRecursionDepth() int {
+	return cteMaxRecursionDepth
+}
+
 // SetIgnoreMaxMemoryRows sets the ignoreMaxMemoryRows value.
 func (vc *vcursorImpl) SetIgnoreMaxMemoryRows(ignoreMaxMemoryRows bool) {
 	vc.ignoreMaxMemoryRows = ignoreMaxMemoryRows
@@ -478,51 +504,59 @@ func (vc *vcursorImpl) TargetString() string {
 const MaxBufferingRetries = 3
 
 func (vc *vcursorImpl) ExecutePrimitive(ctx context.Context, primitive engine.Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
-	for try := 0; try < MaxBufferingRetries; try++ {
-		res, err := primitive.TryExecute(ctx, vc, bindVars, wantfields)
-		if err != nil && vterrors.RootCause(err) == buffer.ShardMissingError {
-			continue
+	return vc.tracer.TraceExecute(primitive, func() (*sqltypes.Result, error) {
+		for try := 0; try < MaxBufferingRetries; try++ {
+			res, err := primitive.TryExecute(ctx, vc, bindVars, wantfields)
+			if err != nil && vterrors.RootCause(err) == buffer.ShardMissingError {
+				continue
+			}
+			return res, err
 		}
-		return res, err
-	}
-	return nil, vterrors.New(vtrpcpb.Code_UNAVAILABLE, "upstream shards are not available")
+		return nil, vterrors.New(vtrpcpb.Code_UNAVAILABLE, "upstream shards are not available")
+	})
 }
 
 func (vc *vcursorImpl) ExecutePrimitiveStandalone(ctx context.Context, primitive engine.Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	// clone the vcursorImpl with a new session.
 	newVC := vc.cloneWithAutocommitSession()
-	for try := 0; try < MaxBufferingRetries; try++ {
-		res, err := primitive.TryExecute(ctx, newVC, bindVars, wantfields)
-		if err != nil && vterrors.RootCause(err) == buffer.ShardMissingError {
-			continue
+	return vc.tracer.TraceExecute(primitive, func() (*sqltypes.Result, error) {
+		for try := 0; try < MaxBufferingRetries; try++ {
+			res, err := primitive.TryExecute(ctx, newVC, bindVars, wantfields)
+			if err != nil && vterrors.RootCause(err) == buffer.ShardMissingError {
+				continue
+			}
+			return res, err
 		}
-		return res, err
-	}
-	return nil, vterrors.New(vtrpcpb.Code_UNAVAILABLE, "upstream shards are not available")
+		return nil, vterrors.New(vtrpcpb.Code_UNAVAILABLE, "upstream shards are not available")
+	})
 }
 
 func (vc *vcursorImpl) StreamExecutePrimitive(ctx context.Context, primitive engine.Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
-	for try := 0; try < MaxBufferingRetries; try++ {
-		err := primitive.TryStreamExecute(ctx, vc, bindVars, wantfields, callback)
-		if err != nil && vterrors.RootCause(err) == buffer.ShardMissingError {
-			continue
+	return vc.tracer.TraceStreamExecute(primitive, callback, func(callback func(*sqltypes.Result) error) error {
+		for try := 0; try < MaxBufferingRetries; try++ {
+			err := primitive.TryStreamExecute(ctx, vc, bindVars, wantfields, callback)
+			if err != nil && vterrors.RootCause(err) == buffer.ShardMissingError {
+				continue
+			}
+			return err
 		}
-		return err
-	}
-	return vterrors.New(vtrpcpb.Code_UNAVAILABLE, "upstream shards are not available")
+		return vterrors.New(vtrpcpb.Code_UNAVAILABLE, "upstream shards are not available")
+	})
 }
 
 func (vc *vcursorImpl) StreamExecutePrimitiveStandalone(ctx context.Context, primitive engine.Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(result *sqltypes.Result) error) error {
 	// clone the vcursorImpl with a new session.
 	newVC := vc.cloneWithAutocommitSession()
-	for try := 0; try < MaxBufferingRetries; try++ {
-		err := primitive.TryStreamExecute(ctx, newVC, bindVars, wantfields, callback)
-		if err != nil && vterrors.RootCause(err) == buffer.ShardMissingError {
-			continue
+	return vc.tracer.TraceStreamExecute(primitive, callback, func(callback func(*sqltypes.Result) error) error {
+		for try := 0; try < MaxBufferingRetries; try++ {
+			err := primitive.TryStreamExecute(ctx, newVC, bindVars, wantfields, callback)
+			if err != nil && vterrors.RootCause(err) == buffer.ShardMissingError {
+				continue
+			}
+			return err
 		}
-		return err
-	}
-	return vterrors.New(vtrpcpb.Code_UNAVAILABLE, "upstream shards are not available")
+		return vterrors.New(vtrpcpb.Code_UNAVAILABLE, "upstream shards are not available")
+	})
 }
 
 // Execute is part of the engine.VCursor interface.
@@ -572,6 +606,7 @@ const txRollback = "Rollback Transaction"
 func (vc *vcursorImpl) ExecuteMultiShard(ctx context.Context, primitive engine.Primitive, rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, rollbackOnError, canAutocommit bool) (*sqltypes.Result, []error) {
 	noOfShards := len(rss)
 	atomic.AddUint64(&vc.logStats.ShardQueries, uint64(noOfShards))
+	vc.tracer.RecordShardCalls(primitive, noOfShards)
 	err := vc.markSavepoint(ctx, rollbackOnError && (noOfShards > 1), map[string]*querypb.BindVariable{})
 	if err != nil {
 		return nil, []error{err}
@@ -587,6 +622,7 @@ func (vc *vcursorImpl) ExecuteMultiShard(ctx context.Context, primitive engine.P
 func (vc *vcursorImpl) StreamExecuteMulti(ctx context.Context, primitive engine.Primitive, query string, rss []*srvtopo.ResolvedShard, bindVars []map[string]*querypb.BindVariable, rollbackOnError bool, autocommit bool, callback func(reply *sqltypes.Result) error) []error {
 	noOfShards := len(rss)
 	atomic.AddUint64(&vc.logStats.ShardQueries, uint64(noOfShards))
+	vc.tracer.RecordShardCalls(primitive, noOfShards)
 	err := vc.markSavepoint(ctx, rollbackOnError && (noOfShards > 1), map[string]*querypb.BindVariable{})
 	if err != nil {
 		return []error{err}
@@ -613,6 +649,7 @@ func (vc *vcursorImpl) ExecuteStandalone(ctx context.Context, primitive engine.P
 			BindVariables: bindVars,
 		},
 	}
+	vc.tracer.RecordShardCalls(primitive, 1)
 	// The autocommit flag is always set to false because we currently don't
 	// execute DMLs through ExecuteStandalone.
 	qr, errs := vc.executor.ExecuteMultiShard(ctx, primitive, rss, bqs, NewAutocommitSession(vc.safeSession.Session), false /* autocommit */, vc.ignoreMaxMemoryRows)
@@ -670,6 +707,15 @@ func (vc *vcursorImpl) AutocommitApproval() bool {
 	return vc.safeSession.AutocommitApproval()
 }
 
+// ReadLocalInfile implements the VCursor interface
+func (vc *vcursorImpl) ReadLocalInfile(ctx context.Context, fileName string) (io.ReadCloser, error) {
+	requester := localInfileFromContext(ctx)
+	if requester == nil {
+		return nil, vterrors.VT12001("LOAD DATA LOCAL INFILE outside of the MySQL protocol")
+	}
+	return requester.RequestLocalInfile(fileName)
+}
+
 // setRollbackOnPartialExecIfRequired sets the value on SafeSession.rollbackOnPartialExec
 // when the query gets successfully executed on at least one shard,
 // there does not exist any old savepoint for which rollback is already set
@@ -1121,10 +1167,28 @@ func (vc *vcursorImpl) keyForPlan(ctx context.Context, query string, buf io.Stri
 			_, _ = buf.WriteString(vc.destination.String())
 		}
 	}
+	if key := vc.dataACL.CallerKey(vc.callerID); key != "" {
+		_, _ = buf.WriteString("+DataACL:")
+		_, _ = buf.WriteString(key)
+	}
 	_, _ = buf.WriteString("+Query:")
 	_, _ = buf.WriteString(query)
 }
 
+// TableRestrictions is part of the plancontext.VSchema interface.
+func (vc *vcursorImpl) TableRestrictions(table *vindexes.Table) *tableacl.TableRestrictions {
+	var keyspace string
+	if table.Keyspace != nil {
+		keyspace = table.Keyspace.Name
+	}
+	return vc.dataACL.Restrictions(keyspace, table.Name.String(), vc.callerID)
+}
+
+// HasTableRestrictions is part of the plancontext.VSchema interface.
+func (vc *vcursorImpl) HasTableRestrictions() bool {
+	return vc.dataACL.IsRestricted(vc.callerID)
+}
+
 func (vc *vcursorImpl) GetKeyspace() string {
 	return vc.keyspace
 }
@@ -1278,6 +1342,17 @@ func (vc *vcursorImpl) cloneWithAutocommitSession() *vcursorImpl {
 		topoServer:      vc.topoServer,
 		warnShardedOnly: vc.warnShardedOnly,
 		pv:              vc.pv,
+		tracer:          vc.tracer,
+	}
+}
+
+// StartPrimitiveTrace is part of the engine.VCursor interface.
+func (vc *vcursorImpl) StartPrimitiveTrace() func() map[engine.Primitive]engine.PrimitiveStats {
+	vc.tracer = engine.NewPrimitiveTracer()
+	return func() map[engine.Primitive]engine.PrimitiveStats {
+		stats := vc.tracer.Stats()
+		vc.tracer = nil
+		return stats
 	}
 }
 
@@ -1356,6 +1431,63 @@ func (vc *vcursorImpl) CloneForReplicaWarming(ctx context.Context) engine.VCurso
 	return v
 }
 
+func (vc *vcursorImpl) GetMirrorChannel() chan bool {
+	return vc.mirrorChannel
+}
+
+func (vc *vcursorImpl) CloneForMirroring(ctx context.Context) (context.Context, context.CancelFunc, engine.VCursor) {
+	callerId := callerid.EffectiveCallerIDFromContext(ctx)
+	immediateCallerId := callerid.ImmediateCallerIDFromContext(ctx)
+
+	// The mirrored query must not be canceled when the query completes.
+	timedCtx, cancel := context.WithTimeout(context.Background(), mirrorQueryTimeout)
+	clonedCtx := callerid.NewContext(timedCtx, callerId, immediateCallerId)
+
+	v := &vcursorImpl{
+		safeSession:         NewAutocommitSession(vc.safeSession.Session),
+		keyspace:            vc.keyspace,
+		tabletType:          vc.tabletType,
+		destination:         vc.destination,
+		marginComments:      vc.marginComments,
+		executor:            vc.executor,
+		resolver:            vc.resolver,
+		topoServer:          vc.topoServer,
+		logStats:            &logstats.LogStats{Ctx: clonedCtx},
+		collation:           vc.collation,
+		ignoreMaxMemoryRows: vc.ignoreMaxMemoryRows,
+		vschema:             vc.vschema,
+		vm:                  vc.vm,
+		warnShardedOnly:     vc.warnShardedOnly,
+		pv:                  vc.pv,
+		dataACL:             vc.dataACL,
+		callerID:            vc.callerID,
+	}
+
+	v.marginComments.Trailing += "/* mirror query */"
+
+	return clonedCtx, cancel, v
+}
+
+func (vc *vcursorImpl) RecordMirrorStats(sourceKeyspace, targetKeyspace string, sourceTime, targetTime time.Duration, sourceErr, targetErr error) {
+	outcome := "Ok"
+	switch {
+	case sourceErr != nil && targetErr != nil:
+		outcome = "BothErrors"
+	case sourceErr != nil:
+		outcome = "SourceError"
+	case vterrors.Code(targetErr) == vtrpcpb.Code_DEADLINE_EXCEEDED:
+		outcome = "TargetTimeout"
+	case targetErr != nil:
+		outcome = "TargetError"
+	}
+	mirrorQueries.Add([]string{sourceKeyspace, targetKeyspace, outcome}, 1)
+	mirrorLatencies.Add([]string{sourceKeyspace, targetKeyspace, "Source"}, sourceTime)
+	mirrorLatencies.Add([]string{sourceKeyspace, targetKeyspace, "Target"}, targetTime)
+	if targetTime > sourceTime {
+		mirrorTargetLatencyExcess.Add([]string{sourceKeyspace, targetKeyspace}, int64(targetTime-sourceTime))
+	}
+}
+
 // UpdateForeignKeyChecksState updates the foreign key checks state of the vcursor.
 func (vc *vcursorImpl) UpdateForeignKeyChecksState(fkStateFromQuery *bool) {
 	// Initialize the state to unspecified.
@@ -60,6 +60,13 @@ const (
 	TypeReference = "reference"
 )
 
+// The following constants represent the routings of procedures.
+const (
+	ProcedureRoutingVindex    = "vindex"
+	ProcedureRoutingAllShards = "all_shards"
+	ProcedureRoutingShard     = "shard"
+)
+
 // VSchema represents the denormalized version of SrvVSchema,
 // used for building routing plans.
 type VSchema struct {
@@ -74,6 +81,7 @@ type VSchema struct {
 	Keyspaces            map[string]*KeyspaceSchema `json:"keyspaces"`
 	ShardRoutingRules    map[string]string          `json:"shard_routing_rules"`
 	KeyspaceRoutingRules map[string]string          `json:"keyspace_routing_rules"`
+	MirrorRules          map[string]*MirrorRule     `json:"mirror_rules,omitempty"`
 	// created is the time when the VSchema object was created. Used to detect if a cached
 	// copy of the vschema is stale.
 	created time.Time
@@ -98,6 +106,42 @@ func (rr *RoutingRule) MarshalJSON() ([]byte, error) {
 	return json.Marshal(tables)
 }
 
+// MirrorRule represents one mirror rule: a percentage of the read queries
+// of a table are also sent to a table of another keyspace.
+type MirrorRule struct {
+	Percent float32
+	Table   *Table
+	Error   error
+}
+
+// MarshalJSON returns a JSON representation of MirrorRule.
+func (mr *MirrorRule) MarshalJSON() ([]byte, error) {
+	if mr.Error != nil {
+		return json.Marshal(mr.Error.Error())
+	}
+	return json.Marshal(struct {
+		Percent float32 `json:"percent"`
+		Table   string  `json:"table"`
+	}{
+		Percent: mr.Percent,
+		Table:   mr.Table.String(),
+	})
+}
+
+// Procedure describes how the CALL statements of a stored procedure of a
+// sharded keyspace are routed.
+type Procedure struct {
+	Name     string    `json:"name"`
+	Keyspace *Keyspace `json:"-"`
+	Routing  string    `json:"routing"`
+	// Vindex maps the argument at position Argument to the shard the CALL
+	// is sent to, for the vindex routing.
+	Vindex   SingleColumn `json:"vindex,omitempty"`
+	Argument int          `json:"argument,omitempty"`
+	// Shard is the shard the CALL is sent to, for the shard routing.
+	Shard string `json:"shard,omitempty"`
+}
+
 // Table represents a table in VSchema.
 type Table struct {
 	Type                    string                 `json:"type,omitempty"`
@@ -245,6 +289,9 @@ type KeyspaceSchema struct {
 	Views           map[string]sqlparser.SelectStatement
 	Error           error
 	MultiTenantSpec *vschemapb.MultiTenantSpec
+	// Procedures are the procedures of a sharded keyspace that can be called,
+	// by lowercase name.
+	Procedures map[string]*Procedure
 
 	// These are the UDFs that exist in the schema and are aggregations
 	AggregateUDFs []string
@@ -258,6 +305,7 @@ type ksJSON struct {
 	Views           map[string]string          `json:"views,omitempty"`
 	Error           string                     `json:"error,omitempty"`
 	MultiTenantSpec *vschemapb.MultiTenantSpec `json:"multi_tenant_spec,omitempty"`
+	Procedures      map[string]*Procedure      `json:"procedures,omitempty"`
 }
 
 // findTable looks for the table with the requested tablename in the keyspace.
@@ -291,6 +339,7 @@ func (ks *KeyspaceSchema) MarshalJSON() ([]byte, error) {
 		ForeignKeyMode:  ks.ForeignKeyMode.String(),
 		Vindexes:        ks.Vindexes,
 		MultiTenantSpec: ks.MultiTenantSpec,
+		Procedures:      ks.Procedures,
 	}
 	if ks.Error != nil {
 		ksJ.Error = ks.Error.Error()
@@ -338,6 +387,7 @@ func BuildVSchema(source *vschemapb.SrvVSchema, parser *sqlparser.Parser) (vsche
 	buildRoutingRule(source, vschema, parser)
 	buildShardRoutingRule(source, vschema)
 	buildKeyspaceRoutingRule(source, vschema)
+	buildMirrorRules(source, vschema, parser)
 	// Resolve auto-increments after routing rules are built since sequence tables also obey routing rules.
 	resolveAutoIncrement(source, vschema, parser)
 	return vschema
@@ -385,6 +435,9 @@ func buildKeyspaces(source *vschemapb.SrvVSchema, vschema *VSchema, parser *sqlp
 		}
 		vschema.Keyspaces[ksname] = ksvschema
 		ksvschema.Error = buildTables(ks, vschema, ksvschema, parser)
+		if ksvschema.Error == nil {
+			ksvschema.Error = buildProcedures(ks, ksvschema)
+		}
 	}
 }
 
@@ -829,6 +882,72 @@ func buildTables(ks *vschemapb.Keyspace, vschema *VSchema, ksvschema *KeyspaceSc
 	return nil
 }
 
+// buildProcedures builds the procedures of a sharded keyspace. They are ignored
+// for unsharded keyspaces, whose CALL statements are sent to their only shard.
+func buildProcedures(ks *vschemapb.Keyspace, ksvschema *KeyspaceSchema) error {
+	if !ks.Sharded || len(ks.Procedures) == 0 {
+		return nil
+	}
+	ksvschema.Procedures = make(map[string]*Procedure, len(ks.Procedures))
+	for pname, procedure := range ks.Procedures {
+		p := &Procedure{
+			Name:     pname,
+			Keyspace: ksvschema.Keyspace,
+			Routing:  procedure.Routing,
+		}
+		switch procedure.Routing {
+		case ProcedureRoutingVindex:
+			vindex, ok := ksvschema.Vindexes[procedure.Vindex]
+			if !ok {
+				return vterrors.Errorf(
+					vtrpcpb.Code_NOT_FOUND,
+					"vindex %s not found for procedure %s",
+					procedure.Vindex,
+					pname,
+				)
+			}
+			single, ok := vindex.(SingleColumn)
+			if !ok {
+				return vterrors.Errorf(
+					vtrpcpb.Code_INVALID_ARGUMENT,
+					"multi-column vindex %s cannot route procedure %s",
+					procedure.Vindex,
+					pname,
+				)
+			}
+			if procedure.Argument < 0 {
+				return vterrors.Errorf(
+					vtrpcpb.Code_INVALID_ARGUMENT,
+					"invalid argument %d for procedure %s",
+					procedure.Argument,
+					pname,
+				)
+			}
+			p.Vindex = single
+			p.Argument = int(procedure.Argument)
+		case ProcedureRoutingAllShards:
+		case ProcedureRoutingShard:
+			if procedure.Shard == "" {
+				return vterrors.Errorf(
+					vtrpcpb.Code_INVALID_ARGUMENT,
+					"missing shard for procedure %s",
+					pname,
+				)
+			}
+			p.Shard = procedure.Shard
+		default:
+			return vterrors.Errorf(
+				vtrpcpb.Code_NOT_FOUND,
+				"unidentified routing %s for procedure %s",
+				procedure.Routing,
+				pname,
+			)
+		}
+		ksvschema.Procedures[strings.ToLower(pname)] = p
+	}
+	return nil
+}
+
 func (vschema *VSchema) addTableName(t *Table) {
 	tname := t.Name.String()
 	if _, ok := vschema.globalTables[tname]; ok {
@@ -1021,6 +1140,99 @@ func buildKeyspaceRoutingRule(source *vschemapb.SrvVSchema, vschema *VSchema) {
 	vschema.KeyspaceRoutingRules = rulesMap
 }
 
+func buildMirrorRules(source *vschemapb.SrvVSchema, vschema *VSchema, parser *sqlparser.Parser) {
+	vschema.MirrorRules = nil
+	if len(source.GetMirrorRules().GetRules()) == 0 {
+		return
+	}
+	vschema.MirrorRules = make(map[string]*MirrorRule)
+	for _, rule := range source.MirrorRules.Rules {
+		if _, ok := vschema.MirrorRules[rule.FromTable]; ok {
+			vschema.MirrorRules[rule.FromTable] = &MirrorRule{
+				Error: vterrors.Errorf(
+					vtrpcpb.Code_ALREADY_EXISTS,
+					"duplicate mirror rule for entry %s",
+					rule.FromTable,
+				),
+			}
+			continue
+		}
+		vschema.MirrorRules[rule.FromTable] = buildMirrorRule(rule, vschema, parser)
+	}
+
+	// The queries mirrored to a table are not mirrored again.
+	for fromTable, mr := range vschema.MirrorRules {
+		if mr.Table == nil {
+			continue
+		}
+		toTable := mr.Table.Keyspace.Name + "." + mr.Table.Name.String()
+		for otherTable := range vschema.MirrorRules {
+			if otherTable == toTable || strings.HasPrefix(otherTable, toTable+"@") {
+				vschema.MirrorRules[fromTable] = &MirrorRule{
+					Error: vterrors.Errorf(
+						vtrpcpb.Code_INVALID_ARGUMENT,
+						"table %s is mirrored to %s, which has a mirror rule too",
+						fromTable,
+						toTable,
+					),
+				}
+				break
+			}
+		}
+	}
+}
+
+func buildMirrorRule(rule *vschemapb.MirrorRule, vschema *VSchema, parser *sqlparser.Parser) *MirrorRule {
+	if rule.Percent < 0 || rule.Percent > 100 {
+		return &MirrorRule{
+			Error: vterrors.Errorf(
+				vtrpcpb.Code_INVALID_ARGUMENT,
+				"mirror rule percent must be between 0 and 100, got %v for %s",
+				rule.Percent,
+				rule.FromTable,
+			),
+		}
+	}
+
+	fromTable, _, _ := strings.Cut(rule.FromTable, "@")
+	fromKeyspace, _, err := parseMirrorRuleTable(fromTable, parser)
+	if err != nil {
+		return &MirrorRule{Error: err}
+	}
+	toKeyspace, toTableName, err := parseMirrorRuleTable(rule.ToTable, parser)
+	if err != nil {
+		return &MirrorRule{Error: err}
+	}
+	if toKeyspace == fromKeyspace {
+		return &MirrorRule{
+			Error: vterrors.Errorf(
+				vtrpcpb.Code_INVALID_ARGUMENT,
+				"table %s must be mirrored to another keyspace, not %s",
+				rule.FromTable,
+				rule.ToTable,
+			),
+		}
+	}
+	t, err := vschema.FindTable(toKeyspace, toTableName)
+	if err != nil {
+		return &MirrorRule{Error: err}
+	}
+	return &MirrorRule{
+		Percent: rule.Percent,
+		Table:   t,
+	}
+}
+
+// parseMirrorRuleTable parses a table name of a mirror rule, which must be qualified.
+func parseMirrorRuleTable(table string, parser *sqlparser.Parser) (string, string, error) {
+	// we need to backtick the keyspace and table name before calling ParseTable
+	escaped, err := escapeQualifiedTable(table)
+	if err != nil {
+		return "", "", vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, err.Error())
+	}
+	return parser.ParseTable(escaped)
+}
+
 // FindTable returns a pointer to the Table. If a keyspace is specified, only tables
 // from that keyspace are searched. If the specified keyspace is unsharded
 // and no tables matched, it's considered valid: FindTable will construct a table
@@ -1168,6 +1380,36 @@ func (vschema *VSchema) FindRoutedTable(keyspace, tablename string, tabletType t
 	)
 }
 
+// FindMirrorRule finds the mirror rule of a table for the given tablet type.
+// It returns nil if the table has none.
+func (vschema *VSchema) FindMirrorRule(keyspace, tablename string, tabletType topodatapb.TabletType) (*MirrorRule, error) {
+	if len(vschema.MirrorRules) == 0 {
+		return nil, nil
+	}
+	qualified := keyspace + "." + tablename
+	// First look for a rule for the tablet type: keyspace.table@tablet_type.
+	// Then look for one without tablet type: keyspace.table.
+	for _, name := range []string{qualified + TabletTypeSuffix[tabletType], qualified} {
+		if mr, ok := vschema.MirrorRules[name]; ok {
+			if mr.Error != nil {
+				return nil, mr.Error
+			}
+			return mr, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindProcedure finds the procedure of a keyspace by name. It returns nil if the
+// keyspace has no routing for it.
+func (vschema *VSchema) FindProcedure(keyspace, name string) (*Procedure, error) {
+	ks, ok := vschema.Keyspaces[keyspace]
+	if !ok {
+		return nil, vterrors.VT05003(keyspace)
+	}
+	return ks.Procedures[strings.ToLower(name)], nil
+}
+
 // FindTableOrVindex finds a table or a Vindex by name using Find and FindVindex.
 func (vschema *VSchema) FindTableOrVindex(keyspace, name string, tabletType topodatapb.TabletType) (*Table, Vindex, error) {
 	tables, err := vschema.FindRoutedTable(keyspace, name, tabletType)
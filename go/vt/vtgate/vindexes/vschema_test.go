@@ -897,6 +897,184 @@ func TestVSchemaRoutingRules(t *testing.T) {
 	assert.Equal(t, string(wantb), string(gotb), string(gotb))
 }
 
+func TestVSchemaMirrorRules(t *testing.T) {
+	input := vschemapb.SrvVSchema{
+		MirrorRules: &vschemapb.MirrorRules{
+			Rules: []*vschemapb.MirrorRule{{
+				FromTable: "ks1.t1",
+				ToTable:   "ks2.t1",
+				Percent:   50,
+			}, {
+				FromTable: "ks1.t2@replica",
+				ToTable:   "`ks2`.`t2`",
+				Percent:   10,
+			}, {
+				FromTable: "ks1.dup",
+				ToTable:   "ks2.t1",
+				Percent:   10,
+			}, {
+				FromTable: "ks1.dup",
+				ToTable:   "ks2.t1",
+				Percent:   10,
+			}, {
+				FromTable: "ks1.percent",
+				ToTable:   "ks2.t1",
+				Percent:   101,
+			}, {
+				FromTable: "ks1.unqualified",
+				ToTable:   "t1",
+				Percent:   10,
+			}, {
+				FromTable: "ks1.samekeyspace",
+				ToTable:   "ks1.t1",
+				Percent:   10,
+			}, {
+				FromTable: "ks1.badkeyspace",
+				ToTable:   "ks4.t1",
+				Percent:   10,
+			}, {
+				FromTable: "ks2.t2",
+				ToTable:   "ks3.t2",
+				Percent:   10,
+			}},
+		},
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"ks1": {
+				Tables: map[string]*vschemapb.Table{
+					"t1": {},
+					"t2": {},
+				},
+			},
+			"ks2": {
+				Tables: map[string]*vschemapb.Table{
+					"t1": {},
+					"t2": {},
+				},
+			},
+			"ks3": {
+				Tables: map[string]*vschemapb.Table{
+					"t2": {},
+				},
+			},
+		},
+	}
+	vschema := BuildVSchema(&input, sqlparser.NewTestParser())
+
+	wantErrors := map[string]string{
+		"ks1.t2@replica":   "table ks1.t2@replica is mirrored to ks2.t2, which has a mirror rule too",
+		"ks1.dup":          "duplicate mirror rule for entry ks1.dup",
+		"ks1.percent":      "mirror rule percent must be between 0 and 100, got 101 for ks1.percent",
+		"ks1.unqualified":  "invalid table name: t1, it must be of the qualified form <keyspace_name>.<table_name> (dots are not allowed in either name)",
+		"ks1.samekeyspace": "table ks1.samekeyspace must be mirrored to another keyspace, not ks1.t1",
+		"ks1.badkeyspace":  "VT05003: unknown database 'ks4' in vschema",
+	}
+	require.Len(t, vschema.MirrorRules, len(wantErrors)+2)
+	for fromTable, wantErr := range wantErrors {
+		assert.EqualError(t, vschema.MirrorRules[fromTable].Error, wantErr, fromTable)
+	}
+
+	mr, err := vschema.FindMirrorRule("ks1", "t1", topodatapb.TabletType_PRIMARY)
+	require.NoError(t, err)
+	assert.EqualValues(t, 50, mr.Percent)
+	assert.Equal(t, "ks2.t1", mr.Table.String())
+
+	mr, err = vschema.FindMirrorRule("ks2", "t2", topodatapb.TabletType_REPLICA)
+	require.NoError(t, err)
+	assert.Equal(t, "ks3.t2", mr.Table.String())
+
+	// The rule for replicas does not apply to the primary.
+	mr, err = vschema.FindMirrorRule("ks1", "t2", topodatapb.TabletType_PRIMARY)
+	require.NoError(t, err)
+	assert.Nil(t, mr)
+
+	_, err = vschema.FindMirrorRule("ks1", "t2", topodatapb.TabletType_REPLICA)
+	assert.EqualError(t, err, wantErrors["ks1.t2@replica"])
+}
+
+func TestVSchemaProcedures(t *testing.T) {
+	vindexes := map[string]*vschemapb.Vindex{
+		"hash":     {Type: "hash"},
+		"multicol": {Type: "multicol", Params: map[string]string{"column_count": "2"}},
+	}
+	input := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"sharded": {
+				Sharded:  true,
+				Vindexes: vindexes,
+				Procedures: map[string]*vschemapb.Procedure{
+					"ByUser":  {Routing: "vindex", Vindex: "hash", Argument: 1},
+					"cleanup": {Routing: "all_shards"},
+					"rebuild": {Routing: "shard", Shard: "-80"},
+				},
+			},
+			"unsharded": {
+				Procedures: map[string]*vschemapb.Procedure{
+					"cleanup": {Routing: "all_shards"},
+				},
+			},
+		},
+	}
+	vschema := BuildVSchema(&input, sqlparser.NewTestParser())
+	require.NoError(t, vschema.Keyspaces["sharded"].Error)
+
+	// The procedures are found regardless of the case of their name.
+	p, err := vschema.FindProcedure("sharded", "byuser")
+	require.NoError(t, err)
+	assert.Equal(t, ProcedureRoutingVindex, p.Routing)
+	assert.Equal(t, "hash", p.Vindex.String())
+	assert.Equal(t, 1, p.Argument)
+
+	p, err = vschema.FindProcedure("sharded", "cleanup")
+	require.NoError(t, err)
+	assert.Equal(t, ProcedureRoutingAllShards, p.Routing)
+
+	p, err = vschema.FindProcedure("sharded", "rebuild")
+	require.NoError(t, err)
+	assert.Equal(t, "-80", p.Shard)
+
+	p, err = vschema.FindProcedure("sharded", "unknown")
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	// The procedures of the unsharded keyspaces are ignored.
+	p, err = vschema.FindProcedure("unsharded", "cleanup")
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	_, err = vschema.FindProcedure("unknown", "cleanup")
+	assert.EqualError(t, err, "VT05003: unknown database 'unknown' in vschema")
+
+	tcases := []struct {
+		procedure *vschemapb.Procedure
+		wantErr   string
+	}{{
+		procedure: &vschemapb.Procedure{Routing: "vindex", Vindex: "unknown"},
+		wantErr:   "vindex unknown not found for procedure p",
+	}, {
+		procedure: &vschemapb.Procedure{Routing: "vindex", Vindex: "multicol"},
+		wantErr:   "multi-column vindex multicol cannot route procedure p",
+	}, {
+		procedure: &vschemapb.Procedure{Routing: "vindex", Vindex: "hash", Argument: -1},
+		wantErr:   "invalid argument -1 for procedure p",
+	}, {
+		procedure: &vschemapb.Procedure{Routing: "shard"},
+		wantErr:   "missing shard for procedure p",
+	}, {
+		procedure: &vschemapb.Procedure{Routing: "any_shard"},
+		wantErr:   "unidentified routing any_shard for procedure p",
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.wantErr, func(t *testing.T) {
+			_, err := BuildKeyspace(&vschemapb.Keyspace{
+				Sharded:    true,
+				Vindexes:   vindexes,
+				Procedures: map[string]*vschemapb.Procedure{"p": tcase.procedure},
+			}, sqlparser.NewTestParser())
+			assert.EqualError(t, err, tcase.wantErr)
+		})
+	}
+}
+
 func TestChooseVindexForType(t *testing.T) {
 	testcases := []struct {
 		in  querypb.Type
@@ -73,6 +73,9 @@ var (
 	maxPayloadSize  int
 	warnPayloadSize int
 
+	// cteMaxRecursionDepth mirrors MySQL's cte_max_recursion_depth for recursive CTEs evaluated by vtgate
+	cteMaxRecursionDepth = 1000
+
 	noScatter          bool
 	enableShardRouting bool
 
@@ -117,6 +120,13 @@ var (
 	warmingReadsPercent      = 0
 	warmingReadsQueryTimeout = 5 * time.Second
 	warmingReadsConcurrency  = 500
+
+	mirrorQueryTimeout = 5 * time.Second
+	mirrorConcurrency  = 500
+
+	// dataACLConfig is a table ACL config whose column and row level ACLs are enforced by vtgate
+	dataACLConfig               string
+	dataACLConfigReloadInterval time.Duration
 )
 
 func registerFlags(fs *pflag.FlagSet) {
@@ -127,6 +137,7 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&streamBufferSize, "stream_buffer_size", streamBufferSize, "the number of bytes sent from vtgate for each stream call. It's recommended to keep this value in sync with vttablet's query-server-config-stream-buffer-size.")
 	fs.Int64Var(&queryPlanCacheMemory, "gate_query_cache_memory", queryPlanCacheMemory, "gate server query cache size in bytes, maximum amount of memory to be cached. vtgate analyzes every incoming query and generate a query plan, these plans are being cached in a lru cache. This config controls the capacity of the lru cache.")
 	fs.IntVar(&maxMemoryRows, "max_memory_rows", maxMemoryRows, "Maximum number of rows that will be held in memory for intermediate results as well as the final result.")
+	fs.IntVar(&cteMaxRecursionDepth, "cte-max-recursion-depth", cteMaxRecursionDepth, "Maximum number of iterations a recursive common table expression evaluated by vtgate can run before it is aborted.")
 	fs.IntVar(&warnMemoryRows, "warn_memory_rows", warnMemoryRows, "Warning threshold for in-memory results. A row count higher than this amount will cause the VtGateWarnings.ResultsExceeded counter to be incremented.")
 	fs.StringVar(&defaultDDLStrategy, "ddl_strategy", defaultDDLStrategy, "Set default strategy for DDL statements. Override with @@ddl_strategy session variable")
 	fs.StringVar(&dbDDLPlugin, "dbddl_plugin", dbDDLPlugin, "controls how to handle CREATE/DROP DATABASE. use it if you are using your own database provisioning service")
@@ -154,6 +165,10 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&warmingReadsPercent, "warming-reads-percent", 0, "Percentage of reads on the primary to forward to replicas. Useful for keeping buffer pools warm")
 	fs.IntVar(&warmingReadsConcurrency, "warming-reads-concurrency", 500, "Number of concurrent warming reads allowed")
 	fs.DurationVar(&warmingReadsQueryTimeout, "warming-reads-query-timeout", 5*time.Second, "Timeout of warming read queries")
+	fs.IntVar(&mirrorConcurrency, "mirror-concurrency", mirrorConcurrency, "Number of concurrent queries mirrored to target keyspaces by mirror rules. Queries are not mirrored while this limit is reached")
+	fs.DurationVar(&mirrorQueryTimeout, "mirror-query-timeout", mirrorQueryTimeout, "Timeout of the queries mirrored to target keyspaces by mirror rules")
+	fs.StringVar(&dataACLConfig, "data-acl-config", dataACLConfig, "path to a table ACL config file (json or proto) whose column_acls and row_filters are enforced by vtgate")
+	fs.DurationVar(&dataACLConfigReloadInterval, "data-acl-config-reload-interval", dataACLConfigReloadInterval, "Ticker to reload the data ACL config file. Default is not to reload.")
 }
 
 func init() {
@@ -233,6 +248,7 @@ type VTGate struct {
 	logExecute       *logutil.ThrottledLogger
 	logPrepare       *logutil.ThrottledLogger
 	logStreamExecute *logutil.ThrottledLogger
+	logFieldList     *logutil.ThrottledLogger
 }
 
 // RegisterVTGate defines the type of registration mechanism.
@@ -257,6 +273,9 @@ func Init(
 	// TabletGateway can create it's own healthcheck
 	gw := NewTabletGateway(ctx, hc, serv, cell)
 	gw.RegisterStats()
+	if gw.balancer != nil {
+		servenv.HTTPHandleFunc("/debug/balancer", gw.balancer.DebugHandler)
+	}
 	if err := gw.WaitForTablets(ctx, tabletTypesToWait); err != nil {
 		log.Fatalf("tabletGateway.WaitForTablets failed: %v", err)
 	}
@@ -330,6 +349,28 @@ func Init(
 		log.Fatalf("error initializing query logger: %v", err)
 	}
 
+	if dataACLConfig != "" {
+		if err := executor.loadDataACL(dataACLConfig); err != nil {
+			log.Fatalf("error initializing data ACL: %v", err)
+		}
+		if dataACLConfigReloadInterval > 0 {
+			go func() {
+				ticker := time.NewTicker(dataACLConfigReloadInterval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						if err := executor.loadDataACL(dataACLConfig); err != nil {
+							log.Errorf("error reloading data ACL, keeping the previous one: %v", err)
+						}
+					}
+				}
+			}()
+		}
+	}
+
 	// connect the schema tracker with the vschema manager
 	if enableSchemaChangeSignal {
 		st.RegisterSignalReceiver(executor.vm.Rebuild)
@@ -577,6 +618,25 @@ handleError:
 	return session, nil, err
 }
 
+// FieldList returns the columns of a table whose names match the wildcard.
+func (vtg *VTGate) FieldList(ctx context.Context, session *vtgatepb.Session, table, wildcard string) ([]*querypb.Field, error) {
+	destKeyspace, destTabletType, _, _ := vtg.executor.ParseDestinationTarget(session.TargetString)
+	statsKey := []string{"FieldList", destKeyspace, topoproto.TabletTypeLString(destTabletType)}
+	defer vtg.timings.Record(statsKey, time.Now())
+
+	fld, err := vtg.executor.FieldList(ctx, NewSafeSession(session), table, wildcard)
+	if err == nil {
+		return fld, nil
+	}
+
+	query := map[string]any{
+		"Table":    table,
+		"Wildcard": wildcard,
+		"Session":  session,
+	}
+	return nil, recordAndAnnotateError(err, statsKey, query, vtg.logFieldList, vtg.executor.vm.parser)
+}
+
 // VStream streams binlog events.
 func (vtg *VTGate) VStream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid, filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func([]*binlogdatapb.VEvent) error) error {
 	return vtg.vsm.VStream(ctx, tabletType, vgtid, filter, flags, send)
@@ -681,5 +741,6 @@ func newVTGate(executor *Executor, resolver *Resolver, vsm *vstreamManager, tc *
 		logExecute:       logutil.NewThrottledLogger("Execute", 5*time.Second),
 		logPrepare:       logutil.NewThrottledLogger("Prepare", 5*time.Second),
 		logStreamExecute: logutil.NewThrottledLogger("StreamExecute", 5*time.Second),
+		logFieldList:     logutil.NewThrottledLogger("FieldList", 5*time.Second),
 	}
 }
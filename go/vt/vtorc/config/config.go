@@ -24,6 +24,7 @@ import (
 
 	"github.com/spf13/pflag"
 
+	"vitess.io/vitess/go/flagutil"
 	"vitess.io/vitess/go/vt/log"
 )
 
@@ -41,6 +42,18 @@ const (
 	UnseenInstanceForgetHours             = 240 // Number of hours after which an unseen instance is forgotten
 )
 
+// The recoveries VTOrc can run on replicas whose replication SQL thread stopped with an error.
+const (
+	// ReplicationSQLErrorRecoveryRestart restarts the replication, like for any stopped replication.
+	ReplicationSQLErrorRecoveryRestart = "restart"
+	// ReplicationSQLErrorRecoveryDrain changes the tablet type of the replica to DRAINED, so that it stops serving.
+	ReplicationSQLErrorRecoveryDrain = "drain"
+	// ReplicationSQLErrorRecoveryRestore restores the replica from the latest backup.
+	ReplicationSQLErrorRecoveryRestore = "restore"
+	// ReplicationSQLErrorRecoveryNone only reports the error.
+	ReplicationSQLErrorRecoveryNone = "none"
+)
+
 var (
 	sqliteDataFile                 = "file::memory:?mode=memory&cache=shared"
 	instancePollTime               = 5 * time.Second
@@ -58,6 +71,15 @@ var (
 	recoveryPollDuration           = 1 * time.Second
 	ersEnabled                     = true
 	convertTabletsWithErrantGTIDs  = false
+	primaryDiskHealthReparent      = false
+	primaryDiskWriteLatency        time.Duration
+	primaryDiskUsedPercent         float64
+	replicationSQLErrorRecovery    = flagutil.NewStringEnum("replication-sql-error-recovery", ReplicationSQLErrorRecoveryRestart, []string{
+		ReplicationSQLErrorRecoveryRestart,
+		ReplicationSQLErrorRecoveryDrain,
+		ReplicationSQLErrorRecoveryRestore,
+		ReplicationSQLErrorRecoveryNone,
+	})
 )
 
 // RegisterFlags registers the flags required by VTOrc
@@ -79,6 +101,10 @@ func RegisterFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&recoveryPollDuration, "recovery-poll-duration", recoveryPollDuration, "Timer duration on which VTOrc polls its database to run a recovery")
 	fs.BoolVar(&ersEnabled, "allow-emergency-reparent", ersEnabled, "Whether VTOrc should be allowed to run emergency reparent operation when it detects a dead primary")
 	fs.BoolVar(&convertTabletsWithErrantGTIDs, "change-tablets-with-errant-gtid-to-drained", convertTabletsWithErrantGTIDs, "Whether VTOrc should be changing the type of tablets with errant GTIDs to DRAINED")
+	fs.BoolVar(&primaryDiskHealthReparent, "allow-primary-disk-health-reparent", primaryDiskHealthReparent, "Whether VTOrc should be allowed to run planned reparent operation when it detects that the disk of a primary is unhealthy")
+	fs.DurationVar(&primaryDiskWriteLatency, "primary-disk-write-latency-threshold", primaryDiskWriteLatency, "Latency of the disk writes reported by a primary above which VTOrc considers its disk unhealthy. 0 disables the check, and the one of stalled disks")
+	fs.Float64Var(&primaryDiskUsedPercent, "primary-disk-used-percent-threshold", primaryDiskUsedPercent, "Percentage of the disk of a primary in use above which VTOrc considers its disk unhealthy. 0 disables the check")
+	fs.Var(replicationSQLErrorRecovery, "replication-sql-error-recovery", "Recovery VTOrc runs on replicas whose replication SQL thread stopped with an error, like a duplicate key or a missing row. Valid values are: restart (restart the replication), drain (change the tablet type to DRAINED), restore (restore the tablet from the latest backup) and none (only report the error)")
 }
 
 // Configuration makes for vtorc configuration input, which can be provided by user via JSON formatted file.
@@ -100,6 +126,15 @@ type Configuration struct {
 	TolerableReplicationLagSeconds        int    // Amount of replication lag that is considered acceptable for a tablet to be eligible for promotion when Vitess makes the choice of a new primary in PRS.
 	TopoInformationRefreshSeconds         int    // Timer duration on which VTOrc refreshes the keyspace and vttablet records from the topo-server.
 	RecoveryPollSeconds                   int    // Timer duration on which VTOrc recovery analysis runs
+
+	KeyspacePrimaryDiskHealthThresholds map[string]PrimaryDiskHealthThresholds // Per keyspace thresholds of the disk health of the primaries, replacing the ones set with flags
+}
+
+// PrimaryDiskHealthThresholds are the thresholds above which VTOrc considers the disk of a primary unhealthy.
+// A disk that the primary reports as stalled is unhealthy when the check of the write latency is enabled.
+type PrimaryDiskHealthThresholds struct {
+	DiskWriteLatencyMilliseconds int64   // Latency of the disk writes. 0 disables the check, including the one of stalled disks.
+	DiskUsedPercent              float64 // Percentage of the disk in use. 0 disables the check.
 }
 
 // ToJSONString will marshal this configuration as JSON
@@ -152,6 +187,37 @@ func SetConvertTabletWithErrantGTIDs(val bool) {
 	convertTabletsWithErrantGTIDs = val
 }
 
+// PrimaryDiskHealthReparentEnabled reports whether VTOrc is allowed to run PRS when the disk of a primary is unhealthy.
+func PrimaryDiskHealthReparentEnabled() bool {
+	return primaryDiskHealthReparent
+}
+
+// SetPrimaryDiskHealthReparentEnabled sets the value for the primaryDiskHealthReparent variable. This should only be used from tests.
+func SetPrimaryDiskHealthReparentEnabled(val bool) {
+	primaryDiskHealthReparent = val
+}
+
+// GetPrimaryDiskHealthThresholds returns the thresholds of the disk health of the primaries of the given keyspace.
+func GetPrimaryDiskHealthThresholds(keyspace string) PrimaryDiskHealthThresholds {
+	if thresholds, ok := Config.KeyspacePrimaryDiskHealthThresholds[keyspace]; ok {
+		return thresholds
+	}
+	return PrimaryDiskHealthThresholds{
+		DiskWriteLatencyMilliseconds: primaryDiskWriteLatency.Milliseconds(),
+		DiskUsedPercent:              primaryDiskUsedPercent,
+	}
+}
+
+// ReplicationSQLErrorRecovery returns the recovery VTOrc runs on replicas whose replication SQL thread stopped with an error.
+func ReplicationSQLErrorRecovery() string {
+	return replicationSQLErrorRecovery.String()
+}
+
+// SetReplicationSQLErrorRecovery sets the value for the replicationSQLErrorRecovery variable. This should only be used from tests.
+func SetReplicationSQLErrorRecovery(val string) error {
+	return replicationSQLErrorRecovery.Set(val)
+}
+
 // LogConfigValues is used to log the config values.
 func LogConfigValues() {
 	b, _ := json.MarshalIndent(Config, "", "\t")
@@ -105,6 +105,11 @@ CREATE TABLE database_instance (
 	semi_sync_primary_status TINYint NOT NULL DEFAULT 0,
 	semi_sync_replica_status TINYint NOT NULL DEFAULT 0,
 	semi_sync_primary_clients int NOT NULL DEFAULT 0,
+	last_sql_errno int unsigned not null default 0,
+	last_io_errno int unsigned not null default 0,
+	disk_stalled TINYint NOT NULL DEFAULT 0,
+	disk_write_latency_ms bigint NOT NULL DEFAULT 0,
+	disk_used_percent double NOT NULL DEFAULT 0,
 	PRIMARY KEY (alias)
 )`,
 	`
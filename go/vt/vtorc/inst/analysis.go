@@ -40,10 +40,13 @@ const (
 	PrimaryIsReadOnly                      AnalysisCode = "PrimaryIsReadOnly"
 	PrimarySemiSyncMustBeSet               AnalysisCode = "PrimarySemiSyncMustBeSet"
 	PrimarySemiSyncMustNotBeSet            AnalysisCode = "PrimarySemiSyncMustNotBeSet"
+	PrimaryDiskUnhealthy                   AnalysisCode = "PrimaryDiskUnhealthy"
 	ReplicaIsWritable                      AnalysisCode = "ReplicaIsWritable"
 	NotConnectedToPrimary                  AnalysisCode = "NotConnectedToPrimary"
 	ConnectedToWrongPrimary                AnalysisCode = "ConnectedToWrongPrimary"
 	ReplicationStopped                     AnalysisCode = "ReplicationStopped"
+	ReplicationSQLThreadError              AnalysisCode = "ReplicationSQLThreadError"
+	ReplicationIOThreadError               AnalysisCode = "ReplicationIOThreadError"
 	ReplicaSemiSyncMustBeSet               AnalysisCode = "ReplicaSemiSyncMustBeSet"
 	ReplicaSemiSyncMustNotBeSet            AnalysisCode = "ReplicaSemiSyncMustNotBeSet"
 	UnreachablePrimaryWithLaggingReplicas  AnalysisCode = "UnreachablePrimaryWithLaggingReplicas"
@@ -104,7 +107,14 @@ type ReplicationAnalysis struct {
 	ReplicationDepth                          uint
 	IsFailingToConnectToPrimary               bool
 	ReplicationStopped                        bool
+	LastSQLErrno                              uint32
+	LastSQLError                              string
+	LastIOErrno                               uint32
+	LastIOError                               string
 	ErrantGTID                                string
+	DiskStalled                               bool
+	DiskWriteLatency                          time.Duration
+	DiskUsedPercent                           float64
 	Analysis                                  AnalysisCode
 	Description                               string
 	StructureAnalysis                         []StructureAnalysisCode
@@ -84,6 +84,9 @@ func GetReplicationAnalysis(keyspace string, shard string, hints *ReplicationAna
 		vitess_shard.primary_timestamp AS shard_primary_term_timestamp,
 		primary_instance.read_only AS read_only,
 		MIN(primary_instance.gtid_errant) AS gtid_errant, 
+		MIN(primary_instance.disk_stalled) AS disk_stalled,
+		MIN(primary_instance.disk_write_latency_ms) AS disk_write_latency_ms,
+		MIN(primary_instance.disk_used_percent) AS disk_used_percent,
 		MIN(primary_instance.alias) IS NULL AS is_invalid,
 		MIN(primary_instance.binary_log_file) AS binary_log_file,
 		MIN(primary_instance.binary_log_pos) AS binary_log_pos,
@@ -145,6 +148,18 @@ func GetReplicationAnalysis(keyspace string, shard string, hints *ReplicationAna
 			primary_instance.replica_sql_running = 0
 			OR primary_instance.replica_io_running = 0
 		) AS replication_stopped,
+		MIN(
+			CASE WHEN primary_instance.replica_sql_running = 0 THEN primary_instance.last_sql_errno ELSE 0 END
+		) AS last_sql_errno,
+		MIN(
+			CASE WHEN primary_instance.replica_sql_running = 0 THEN primary_instance.last_sql_error ELSE '' END
+		) AS last_sql_error,
+		MIN(
+			CASE WHEN primary_instance.replica_io_running = 0 THEN primary_instance.last_io_errno ELSE 0 END
+		) AS last_io_errno,
+		MIN(
+			CASE WHEN primary_instance.replica_io_running = 0 THEN primary_instance.last_io_error ELSE '' END
+		) AS last_io_error,
 		MIN(
 			primary_instance.binlog_server
 		) AS is_binlog_server,
@@ -344,8 +359,15 @@ func GetReplicationAnalysis(keyspace string, shard string, hints *ReplicationAna
 		a.ReplicationDepth = m.GetUint("replication_depth")
 		a.IsFailingToConnectToPrimary = m.GetBool("is_failing_to_connect_to_primary")
 		a.ReplicationStopped = m.GetBool("replication_stopped")
+		a.LastSQLErrno = m.GetUint32("last_sql_errno")
+		a.LastSQLError = m.GetString("last_sql_error")
+		a.LastIOErrno = m.GetUint32("last_io_errno")
+		a.LastIOError = m.GetString("last_io_error")
 		a.IsBinlogServer = m.GetBool("is_binlog_server")
 		a.ErrantGTID = m.GetString("gtid_errant")
+		a.DiskStalled = m.GetBool("disk_stalled")
+		a.DiskWriteLatency = time.Duration(m.GetInt64("disk_write_latency_ms")) * time.Millisecond
+		a.DiskUsedPercent = m.GetFloat64("disk_used_percent")
 
 		countValidOracleGTIDReplicas := m.GetUint("count_valid_oracle_gtid_replicas")
 		a.OracleGTIDImmediateTopology = countValidOracleGTIDReplicas == a.CountValidReplicas && a.CountValidReplicas > 0
@@ -416,6 +438,12 @@ func GetReplicationAnalysis(keyspace string, shard string, hints *ReplicationAna
 			return nil
 		}
 		isInvalid := m.GetBool("is_invalid")
+		var diskProblem string
+		if a.IsClusterPrimary && a.LastCheckValid && config.PrimaryDiskHealthReparentEnabled() {
+			// The problem is a cluster-wide one, which holds off the recoveries of the replicas,
+			// so it is only reported when VTOrc is going to reparent away from the primary.
+			diskProblem = primaryDiskProblem(a, config.GetPrimaryDiskHealthThresholds(a.ClusterDetails.Keyspace))
+		}
 		if a.IsClusterPrimary && isInvalid {
 			a.Analysis = InvalidPrimary
 			a.Description = "VTOrc hasn't been able to reach the primary even once since restart/shutdown"
@@ -459,6 +487,11 @@ func GetReplicationAnalysis(keyspace string, shard string, hints *ReplicationAna
 			a.Analysis = PrimarySemiSyncMustNotBeSet
 			a.Description = "Primary semi-sync must not be set"
 			//
+		} else if a.IsClusterPrimary && diskProblem != "" {
+			a.Analysis = PrimaryDiskUnhealthy
+			a.Description = "Primary disk is unhealthy: " + diskProblem
+			ca.hasClusterwideAction = true
+			//
 		} else if topo.IsReplicaType(a.TabletType) && a.ErrantGTID != "" {
 			a.Analysis = ErrantGTIDDetected
 			a.Description = "Tablet has errant GTIDs"
@@ -485,6 +518,14 @@ func GetReplicationAnalysis(keyspace string, shard string, hints *ReplicationAna
 			a.Analysis = ConnectedToWrongPrimary
 			a.Description = "Connected to wrong primary"
 			//
+		} else if topo.IsReplicaType(a.TabletType) && !a.IsPrimary && a.ReplicationStopped && a.LastSQLErrno != 0 {
+			a.Analysis = ReplicationSQLThreadError
+			a.Description = fmt.Sprintf("Replication SQL thread stopped with error %d: %s", a.LastSQLErrno, a.LastSQLError)
+			//
+		} else if topo.IsReplicaType(a.TabletType) && !a.IsPrimary && a.ReplicationStopped && a.LastIOErrno != 0 {
+			a.Analysis = ReplicationIOThreadError
+			a.Description = fmt.Sprintf("Replication IO thread stopped with error %d: %s", a.LastIOErrno, a.LastIOError)
+			//
 		} else if topo.IsReplicaType(a.TabletType) && !a.IsPrimary && a.ReplicationStopped {
 			a.Analysis = ReplicationStopped
 			a.Description = "Replication is stopped"
@@ -606,6 +647,33 @@ func GetReplicationAnalysis(keyspace string, shard string, hints *ReplicationAna
 	return result, err
 }
 
+// primaryDiskProblem returns why the disk of the analyzed primary is unhealthy according to the given
+// thresholds, or an empty string if it is healthy.
+func primaryDiskProblem(a *ReplicationAnalysis, thresholds config.PrimaryDiskHealthThresholds) string {
+	return diskProblem(a.DiskStalled, a.DiskWriteLatency, a.DiskUsedPercent, thresholds)
+}
+
+// DiskProblem returns why the disk of the instance is unhealthy according to the given thresholds,
+// or an empty string if it is healthy.
+func (instance *Instance) DiskProblem(thresholds config.PrimaryDiskHealthThresholds) string {
+	return diskProblem(instance.DiskStalled, instance.DiskWriteLatency, instance.DiskUsedPercent, thresholds)
+}
+
+// diskProblem returns why a disk is unhealthy according to the given thresholds, or an empty string
+// if it is healthy. A stalled disk is checked along with the write latency.
+func diskProblem(stalled bool, writeLatency time.Duration, usedPercent float64, thresholds config.PrimaryDiskHealthThresholds) string {
+	if thresholds.DiskWriteLatencyMilliseconds > 0 && stalled {
+		return "disk writes are stalled"
+	}
+	if thresholds.DiskWriteLatencyMilliseconds > 0 && writeLatency.Milliseconds() > thresholds.DiskWriteLatencyMilliseconds {
+		return fmt.Sprintf("disk write latency %v exceeds %vms", writeLatency, thresholds.DiskWriteLatencyMilliseconds)
+	}
+	if thresholds.DiskUsedPercent > 0 && usedPercent > thresholds.DiskUsedPercent {
+		return fmt.Sprintf("disk used %.1f%% exceeds %.1f%%", usedPercent, thresholds.DiskUsedPercent)
+	}
+	return ""
+}
+
 // postProcessAnalyses is used to update different analyses based on the information gleaned from looking at all the analyses together instead of individual data.
 func postProcessAnalyses(result []*ReplicationAnalysis, clusters map[string]*clusterAnalysis) []*ReplicationAnalysis {
 	for {
@@ -60,7 +60,9 @@ type Instance struct {
 	ExecBinlogCoordinates BinlogCoordinates
 	IsDetached            bool
 	RelaylogCoordinates   BinlogCoordinates
+	LastSQLErrno          uint32
 	LastSQLError          string
+	LastIOErrno           uint32
 	LastIOError           string
 	SecondsBehindPrimary  sql.NullInt64
 	SQLDelay              uint32
@@ -86,6 +88,10 @@ type Instance struct {
 	SemiSyncPrimaryClients             uint
 	SemiSyncReplicaStatus              bool
 
+	DiskStalled      bool
+	DiskWriteLatency time.Duration
+	DiskUsedPercent  float64
+
 	LastSeenTimestamp    string
 	IsLastCheckValid     bool
 	IsUpToDate           bool
@@ -236,6 +236,9 @@ func ReadTopologyInstanceBufferable(tabletAlias string, latency *stopwatch.Named
 		instance.SemiSyncPrimaryClients = uint(fs.SemiSyncPrimaryClients)
 		instance.SemiSyncPrimaryStatus = fs.SemiSyncPrimaryStatus
 		instance.SemiSyncReplicaStatus = fs.SemiSyncReplicaStatus
+		instance.DiskStalled = fs.DiskStalled
+		instance.DiskWriteLatency = time.Duration(fs.DiskWriteLatencyMs) * time.Millisecond
+		instance.DiskUsedPercent = fs.DiskUsedPercent
 
 		if instance.IsOracleMySQL() || instance.IsPercona() {
 			// Stuff only supported on Oracle / Percona MySQL
@@ -287,6 +290,8 @@ func ReadTopologyInstanceBufferable(tabletAlias string, latency *stopwatch.Named
 		instance.RelaylogCoordinates.Type = RelayLog
 		errorChan <- err
 
+		instance.LastSQLErrno = fs.ReplicationStatus.LastSqlErrno
+		instance.LastIOErrno = fs.ReplicationStatus.LastIoErrno
 		instance.LastSQLError = emptyQuotesRegexp.ReplaceAllString(strconv.QuoteToASCII(fs.ReplicationStatus.LastSqlError), "")
 		instance.LastIOError = emptyQuotesRegexp.ReplaceAllString(strconv.QuoteToASCII(fs.ReplicationStatus.LastIoError), "")
 
@@ -521,6 +526,8 @@ func readInstanceRow(m sqlutils.RowMap) *Instance {
 	instance.RelaylogCoordinates.Type = RelayLog
 	instance.LastSQLError = m.GetString("last_sql_error")
 	instance.LastIOError = m.GetString("last_io_error")
+	instance.LastSQLErrno = m.GetUint32("last_sql_errno")
+	instance.LastIOErrno = m.GetUint32("last_io_errno")
 	instance.SecondsBehindPrimary = m.GetNullInt64("replication_lag_seconds")
 	instance.ReplicationLagSeconds = m.GetNullInt64("replica_lag_seconds")
 	instance.SQLDelay = m.GetUint32("sql_delay")
@@ -535,6 +542,9 @@ func readInstanceRow(m sqlutils.RowMap) *Instance {
 	instance.SemiSyncPrimaryStatus = m.GetBool("semi_sync_primary_status")
 	instance.SemiSyncPrimaryClients = m.GetUint("semi_sync_primary_clients")
 	instance.SemiSyncReplicaStatus = m.GetBool("semi_sync_replica_status")
+	instance.DiskStalled = m.GetBool("disk_stalled")
+	instance.DiskWriteLatency = time.Duration(m.GetInt64("disk_write_latency_ms")) * time.Millisecond
+	instance.DiskUsedPercent = m.GetFloat64("disk_used_percent")
 	instance.ReplicationDepth = m.GetUint("replication_depth")
 	instance.IsCoPrimary = m.GetBool("is_co_primary")
 	instance.HasReplicationCredentials = m.GetBool("has_replication_credentials")
@@ -825,6 +835,8 @@ func mkInsertOdkuForInstances(instances []*Instance, instanceWasActuallyFound bo
 		"relay_log_pos",
 		"last_sql_error",
 		"last_io_error",
+		"last_sql_errno",
+		"last_io_errno",
 		"replication_lag_seconds",
 		"replica_lag_seconds",
 		"sql_delay",
@@ -843,6 +855,9 @@ func mkInsertOdkuForInstances(instances []*Instance, instanceWasActuallyFound bo
 		"semi_sync_primary_status",
 		"semi_sync_primary_clients",
 		"semi_sync_replica_status",
+		"disk_stalled",
+		"disk_write_latency_ms",
+		"disk_used_percent",
 		"last_discovery_latency",
 	}
 
@@ -904,6 +919,8 @@ func mkInsertOdkuForInstances(instances []*Instance, instanceWasActuallyFound bo
 		args = append(args, instance.RelaylogCoordinates.LogPos)
 		args = append(args, instance.LastSQLError)
 		args = append(args, instance.LastIOError)
+		args = append(args, instance.LastSQLErrno)
+		args = append(args, instance.LastIOErrno)
 		args = append(args, instance.SecondsBehindPrimary)
 		args = append(args, instance.ReplicationLagSeconds)
 		args = append(args, instance.SQLDelay)
@@ -922,6 +939,9 @@ func mkInsertOdkuForInstances(instances []*Instance, instanceWasActuallyFound bo
 		args = append(args, instance.SemiSyncPrimaryStatus)
 		args = append(args, instance.SemiSyncPrimaryClients)
 		args = append(args, instance.SemiSyncReplicaStatus)
+		args = append(args, instance.DiskStalled)
+		args = append(args, instance.DiskWriteLatency.Milliseconds())
+		args = append(args, instance.DiskUsedPercent)
 		args = append(args, instance.LastDiscoveryLatency.Nanoseconds())
 	}
 
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"slices"
 	"strings"
 	"sync"
@@ -42,6 +43,7 @@ import (
 	"vitess.io/vitess/go/vt/vtorc/process"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
@@ -298,6 +300,30 @@ func changeTabletType(ctx context.Context, tablet *topodatapb.Tablet, tabletType
 	return tmc.ChangeType(ctx, tablet, tabletType, semiSync)
 }
 
+// restoreFromBackup starts restoring the given tablet from its latest backup. The restore runs in the background,
+// as it can take much longer than a recovery is allowed to hold the shard lock, and done is called with its result.
+func restoreFromBackup(tablet *topodatapb.Tablet, done func(error)) error {
+	stream, err := tmc.RestoreFromBackup(context.Background(), tablet, &tabletmanagerdatapb.RestoreFromBackupRequest{})
+	if err != nil {
+		return err
+	}
+	go func() {
+		for {
+			event, err := stream.Recv()
+			if err == io.EOF {
+				done(nil)
+				return
+			}
+			if err != nil {
+				done(err)
+				return
+			}
+			log.Infof("Restore of %v: %v", topoproto.TabletAliasString(tablet.Alias), event.GetValue())
+		}
+	}()
+	return nil
+}
+
 // resetReplicationParameters resets the replication parameters on the given tablet.
 func resetReplicationParameters(ctx context.Context, tablet *topodatapb.Tablet) error {
 	return tmc.ResetReplicationParameters(ctx, tablet)
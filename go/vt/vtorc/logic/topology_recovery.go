@@ -17,10 +17,13 @@
 package logic
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/rand/v2"
+	"slices"
 	"time"
 
 	"vitess.io/vitess/go/stats"
@@ -45,6 +48,8 @@ const (
 	FixPrimaryRecoveryName                           string = "FixPrimary"
 	FixReplicaRecoveryName                           string = "FixReplica"
 	RecoverErrantGTIDDetectedName                    string = "RecoverErrantGTIDDetected"
+	RecoverReplicationSQLThreadErrorRecoveryName     string = "RecoverReplicationSQLThreadError"
+	RecoverPrimaryDiskUnhealthyRecoveryName          string = "RecoverPrimaryDiskUnhealthy"
 )
 
 var (
@@ -54,6 +59,8 @@ var (
 		ElectNewPrimaryRecoveryName,
 		FixPrimaryRecoveryName,
 		FixReplicaRecoveryName,
+		RecoverReplicationSQLThreadErrorRecoveryName,
+		RecoverPrimaryDiskUnhealthyRecoveryName,
 	}
 
 	countPendingRecoveries = stats.NewGauge("PendingRecoveries", "Count of the number of pending recoveries")
@@ -95,6 +102,8 @@ const (
 	fixPrimaryFunc
 	fixReplicaFunc
 	recoverErrantGTIDDetectedFunc
+	recoverReplicationSQLThreadErrorFunc
+	recoverPrimaryDiskUnhealthyFunc
 )
 
 // TopologyRecovery represents an entry in the topology_recovery table
@@ -305,6 +314,22 @@ func getCheckAndRecoverFunctionCode(analysisCode inst.AnalysisCode, tabletAlias
 			return noRecoveryFunc
 		}
 		return recoverErrantGTIDDetectedFunc
+	case inst.ReplicationSQLThreadError:
+		switch config.ReplicationSQLErrorRecovery() {
+		case config.ReplicationSQLErrorRecoveryRestart:
+			return fixReplicaFunc
+		case config.ReplicationSQLErrorRecoveryNone:
+			// The problem is only recorded, the replica has to be repaired by hand.
+			return recoverGenericProblemFunc
+		}
+		return recoverReplicationSQLThreadErrorFunc
+	case inst.PrimaryDiskUnhealthy:
+		if !config.PrimaryDiskHealthReparentEnabled() {
+			// The problem is only recorded, the primary has to be moved away by hand.
+			log.Infof("VTOrc not configured to reparent away from primaries with an unhealthy disk, skipping recovering %v", analysisCode)
+			return recoverGenericProblemFunc
+		}
+		return recoverPrimaryDiskUnhealthyFunc
 	case inst.PrimaryHasPrimary:
 		return recoverPrimaryHasPrimaryFunc
 	case inst.LockedSemiSyncPrimary:
@@ -314,8 +339,8 @@ func getCheckAndRecoverFunctionCode(analysisCode inst.AnalysisCode, tabletAlias
 	case inst.PrimaryIsReadOnly, inst.PrimarySemiSyncMustBeSet, inst.PrimarySemiSyncMustNotBeSet:
 		return fixPrimaryFunc
 	// replica
-	case inst.NotConnectedToPrimary, inst.ConnectedToWrongPrimary, inst.ReplicationStopped, inst.ReplicaIsWritable,
-		inst.ReplicaSemiSyncMustBeSet, inst.ReplicaSemiSyncMustNotBeSet:
+	case inst.NotConnectedToPrimary, inst.ConnectedToWrongPrimary, inst.ReplicationStopped, inst.ReplicationIOThreadError,
+		inst.ReplicaIsWritable, inst.ReplicaSemiSyncMustBeSet, inst.ReplicaSemiSyncMustNotBeSet:
 		return fixReplicaFunc
 	// primary, non actionable
 	case inst.DeadPrimaryAndReplicas:
@@ -360,6 +385,10 @@ func hasActionableRecovery(recoveryFunctionCode recoveryFunction) bool {
 		return true
 	case recoverErrantGTIDDetectedFunc:
 		return true
+	case recoverReplicationSQLThreadErrorFunc:
+		return true
+	case recoverPrimaryDiskUnhealthyFunc:
+		return true
 	default:
 		return false
 	}
@@ -390,6 +419,10 @@ func getCheckAndRecoverFunction(recoveryFunctionCode recoveryFunction) (
 		return fixReplica
 	case recoverErrantGTIDDetectedFunc:
 		return recoverErrantGTIDDetected
+	case recoverReplicationSQLThreadErrorFunc:
+		return recoverReplicationSQLThreadError
+	case recoverPrimaryDiskUnhealthyFunc:
+		return recoverPrimaryDiskUnhealthy
 	default:
 		return nil
 	}
@@ -419,6 +452,10 @@ func getRecoverFunctionName(recoveryFunctionCode recoveryFunction) string {
 		return FixReplicaRecoveryName
 	case recoverErrantGTIDDetectedFunc:
 		return RecoverErrantGTIDDetectedName
+	case recoverReplicationSQLThreadErrorFunc:
+		return RecoverReplicationSQLThreadErrorRecoveryName
+	case recoverPrimaryDiskUnhealthyFunc:
+		return RecoverPrimaryDiskUnhealthyRecoveryName
 	default:
 		return ""
 	}
@@ -427,7 +464,7 @@ func getRecoverFunctionName(recoveryFunctionCode recoveryFunction) string {
 // isClusterWideRecovery returns whether the given recovery is a cluster-wide recovery or not
 func isClusterWideRecovery(recoveryFunctionCode recoveryFunction) bool {
 	switch recoveryFunctionCode {
-	case recoverDeadPrimaryFunc, electNewPrimaryFunc, recoverPrimaryTabletDeletedFunc:
+	case recoverDeadPrimaryFunc, electNewPrimaryFunc, recoverPrimaryTabletDeletedFunc, recoverPrimaryDiskUnhealthyFunc:
 		return true
 	default:
 		return false
@@ -715,6 +752,114 @@ func electNewPrimary(ctx context.Context, analysisEntry *inst.ReplicationAnalysi
 	return true, topologyRecovery, err
 }
 
+// recoverPrimaryDiskUnhealthy runs a PlannedReparentShard away from a primary whose disk is unhealthy,
+// before the primary fails outright.
+func recoverPrimaryDiskUnhealthy(ctx context.Context, analysisEntry *inst.ReplicationAnalysis) (recoveryAttempted bool, topologyRecovery *TopologyRecovery, err error) {
+	topologyRecovery, err = AttemptRecoveryRegistration(analysisEntry)
+	if topologyRecovery == nil || err != nil {
+		_ = AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("found an active or recent recovery on %+v. Will not issue another recoverPrimaryDiskUnhealthy.", analysisEntry.AnalyzedInstanceAlias))
+		return false, nil, err
+	}
+	log.Infof("Analysis: %v, will reparent away from the primary %v of %v:%v", analysisEntry.Analysis, analysisEntry.AnalyzedInstanceAlias, analysisEntry.ClusterDetails.Keyspace, analysisEntry.ClusterDetails.Shard)
+
+	var promotedReplica *inst.Instance
+	// This has to be done in the end; whether successful or not, we should mark that the recovery is done.
+	// So that after the active period passes, we are able to run other recoveries.
+	defer func() {
+		_ = resolveRecovery(topologyRecovery, promotedReplica)
+	}()
+
+	analyzedTablet, err := inst.ReadTablet(analysisEntry.AnalyzedInstanceAlias)
+	if err != nil {
+		return false, topologyRecovery, err
+	}
+	_ = AuditTopologyRecovery(topologyRecovery, analysisEntry.Description)
+	_ = inst.AuditOperation(RecoverPrimaryDiskUnhealthyRecoveryName, analysisEntry.AnalyzedInstanceAlias, analysisEntry.Description)
+
+	newPrimaryAlias, err := diskHealthyPrimaryCandidate(ctx, analyzedTablet)
+	if err != nil {
+		// Reparenting to a replica whose disk is unhealthy too would only move the problem around.
+		_ = AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("will not reparent away from the primary with an unhealthy disk: %v", err))
+		return false, topologyRecovery, nil
+	}
+	_ = AuditTopologyRecovery(topologyRecovery, "starting PlannedReparentShard away from the primary with an unhealthy disk.")
+
+	ev, err := reparentutil.NewPlannedReparenter(ts, tmc, logutil.NewCallbackLogger(func(event *logutilpb.Event) {
+		level := event.GetLevel()
+		value := event.GetValue()
+		// we only log the warnings and errors explicitly, everything gets logged as an information message anyways in auditing topology recovery
+		switch level {
+		case logutilpb.Level_WARNING:
+			log.Warningf("PRS - %s", value)
+		case logutilpb.Level_ERROR:
+			log.Errorf("PRS - %s", value)
+		}
+		_ = AuditTopologyRecovery(topologyRecovery, value)
+	})).ReparentShard(ctx,
+		analyzedTablet.Keyspace,
+		analyzedTablet.Shard,
+		reparentutil.PlannedReparentOptions{
+			NewPrimaryAlias:     newPrimaryAlias,
+			AvoidPrimaryAlias:   analyzedTablet.Alias,
+			WaitReplicasTimeout: time.Duration(config.Config.WaitReplicasTimeoutSeconds) * time.Second,
+			TolerableReplLag:    time.Duration(config.Config.TolerableReplicationLagSeconds) * time.Second,
+		},
+	)
+
+	if ev != nil && ev.NewPrimary != nil {
+		promotedReplica, _, _ = inst.ReadInstance(topoproto.TabletAliasString(ev.NewPrimary.Alias))
+	}
+	postPrsCompletion(topologyRecovery, analysisEntry, promotedReplica)
+	return true, topologyRecovery, err
+}
+
+// diskHealthyPrimaryCandidate returns the replica to promote instead of the given primary, when some of
+// the replicas PlannedReparentShard could promote have an unhealthy disk too. It returns nil if they all
+// have a healthy disk, so that PlannedReparentShard chooses the best one, and an error if none has.
+func diskHealthyPrimaryCandidate(ctx context.Context, primary *topodatapb.Tablet) (*topodatapb.TabletAlias, error) {
+	tablets, err := ts.GetTabletMapForShard(ctx, primary.Keyspace, primary.Shard)
+	if err != nil {
+		return nil, err
+	}
+	thresholds := config.GetPrimaryDiskHealthThresholds(primary.Keyspace)
+	var healthy []*inst.Instance
+	unhealthy := 0
+	for _, tablet := range tablets {
+		// PlannedReparentShard only promotes the replicas of the cell of the primary.
+		if tablet.Type != topodatapb.TabletType_REPLICA || tablet.Alias.Cell != primary.Alias.Cell {
+			continue
+		}
+		instance, found, err := inst.ReadInstance(topoproto.TabletAliasString(tablet.Alias))
+		if err != nil || !found || instance.DiskProblem(thresholds) != "" {
+			unhealthy++
+			continue
+		}
+		healthy = append(healthy, instance)
+	}
+	if len(healthy) == 0 {
+		return nil, fmt.Errorf("none of the replicas of %v/%v has a healthy disk", primary.Keyspace, primary.Shard)
+	}
+	if unhealthy == 0 {
+		return nil, nil
+	}
+	// The replica with the least replication lag is the one that catches up with the primary the fastest.
+	slices.SortFunc(healthy, func(a, b *inst.Instance) int {
+		return cmp.Or(
+			cmp.Compare(replicationLag(a), replicationLag(b)),
+			cmp.Compare(a.InstanceAlias, b.InstanceAlias),
+		)
+	})
+	return topoproto.ParseTabletAlias(healthy[0].InstanceAlias)
+}
+
+// replicationLag returns the replication lag of the instance, or the maximum lag if it is unknown.
+func replicationLag(instance *inst.Instance) int64 {
+	if !instance.ReplicationLagSeconds.Valid {
+		return math.MaxInt64
+	}
+	return instance.ReplicationLagSeconds.Int64
+}
+
 // fixPrimary sets the primary as read-write.
 func fixPrimary(ctx context.Context, analysisEntry *inst.ReplicationAnalysis) (recoveryAttempted bool, topologyRecovery *TopologyRecovery, err error) {
 	topologyRecovery, err = AttemptRecoveryRegistration(analysisEntry)
@@ -821,3 +966,57 @@ func recoverErrantGTIDDetected(ctx context.Context, analysisEntry *inst.Replicat
 	err = changeTabletType(ctx, analyzedTablet, topodatapb.TabletType_DRAINED, reparentutil.IsReplicaSemiSync(durabilityPolicy, primaryTablet, analyzedTablet))
 	return true, topologyRecovery, err
 }
+
+// recoverReplicationSQLThreadError takes a replica whose replication SQL thread stopped with an error out of service,
+// by either changing its tablet type to DRAINED or restoring it from the latest backup, as configured.
+func recoverReplicationSQLThreadError(ctx context.Context, analysisEntry *inst.ReplicationAnalysis) (recoveryAttempted bool, topologyRecovery *TopologyRecovery, err error) {
+	topologyRecovery, err = AttemptRecoveryRegistration(analysisEntry)
+	if topologyRecovery == nil {
+		_ = AuditTopologyRecovery(topologyRecovery, fmt.Sprintf("found an active or recent recovery on %+v. Will not issue another recoverReplicationSQLThreadError.", analysisEntry.AnalyzedInstanceAlias))
+		return false, nil, err
+	}
+	log.Infof("Analysis: %v, will fix tablet %+v", analysisEntry.Analysis, analysisEntry.AnalyzedInstanceAlias)
+	// This has to be done in the end; whether successful or not, we should mark that the recovery is done.
+	// So that after the active period passes, we are able to run other recoveries.
+	defer func() {
+		_ = resolveRecovery(topologyRecovery, nil)
+	}()
+
+	analyzedTablet, err := inst.ReadTablet(analysisEntry.AnalyzedInstanceAlias)
+	if err != nil {
+		return false, topologyRecovery, err
+	}
+
+	primaryTablet, err := shardPrimary(analyzedTablet.Keyspace, analyzedTablet.Shard)
+	if err != nil {
+		log.Info("Could not compute primary for %v/%v", analyzedTablet.Keyspace, analyzedTablet.Shard)
+		return false, topologyRecovery, err
+	}
+
+	durabilityPolicy, err := inst.GetDurabilityPolicy(analyzedTablet.Keyspace)
+	if err != nil {
+		log.Info("Could not read the durability policy for %v/%v", analyzedTablet.Keyspace, analyzedTablet.Shard)
+		return false, topologyRecovery, err
+	}
+
+	recovery := config.ReplicationSQLErrorRecovery()
+	message := fmt.Sprintf("replication SQL thread stopped with error %d: %s; running the %s recovery", analysisEntry.LastSQLErrno, analysisEntry.LastSQLError, recovery)
+	_ = AuditTopologyRecovery(topologyRecovery, message)
+	_ = inst.AuditOperation(RecoverReplicationSQLThreadErrorRecoveryName, analysisEntry.AnalyzedInstanceAlias, message)
+
+	switch recovery {
+	case config.ReplicationSQLErrorRecoveryDrain:
+		err = changeTabletType(ctx, analyzedTablet, topodatapb.TabletType_DRAINED, reparentutil.IsReplicaSemiSync(durabilityPolicy, primaryTablet, analyzedTablet))
+		return true, topologyRecovery, err
+	case config.ReplicationSQLErrorRecoveryRestore:
+		err = restoreFromBackup(analyzedTablet, func(err error) {
+			message := "successfully restored the tablet from backup"
+			if err != nil {
+				message = fmt.Sprintf("failed to restore the tablet from backup: %v", err)
+			}
+			_ = inst.AuditOperation(RecoverReplicationSQLThreadErrorRecoveryName, analysisEntry.AnalyzedInstanceAlias, message)
+		})
+		return true, topologyRecovery, err
+	}
+	return false, topologyRecovery, fmt.Errorf("unknown replication SQL error recovery %q", recovery)
+}
@@ -18,6 +18,7 @@ package logic
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -202,6 +203,8 @@ func TestGetCheckAndRecoverFunctionCode(t *testing.T) {
 		name                         string
 		ersEnabled                   bool
 		convertTabletWithErrantGTIDs bool
+		replicationSQLErrorRecovery  string
+		primaryDiskHealthReparent    bool
 		analysisCode                 inst.AnalysisCode
 		wantRecoveryFunction         recoveryFunction
 	}{
@@ -257,6 +260,39 @@ func TestGetCheckAndRecoverFunctionCode(t *testing.T) {
 			convertTabletWithErrantGTIDs: false,
 			analysisCode:                 inst.ErrantGTIDDetected,
 			wantRecoveryFunction:         noRecoveryFunc,
+		}, {
+			name:                 "ReplicationIOThreadError",
+			analysisCode:         inst.ReplicationIOThreadError,
+			wantRecoveryFunction: fixReplicaFunc,
+		}, {
+			name:                        "ReplicationSQLThreadError with --replication-sql-error-recovery restart",
+			replicationSQLErrorRecovery: config.ReplicationSQLErrorRecoveryRestart,
+			analysisCode:                inst.ReplicationSQLThreadError,
+			wantRecoveryFunction:        fixReplicaFunc,
+		}, {
+			name:                        "ReplicationSQLThreadError with --replication-sql-error-recovery drain",
+			replicationSQLErrorRecovery: config.ReplicationSQLErrorRecoveryDrain,
+			analysisCode:                inst.ReplicationSQLThreadError,
+			wantRecoveryFunction:        recoverReplicationSQLThreadErrorFunc,
+		}, {
+			name:                        "ReplicationSQLThreadError with --replication-sql-error-recovery restore",
+			replicationSQLErrorRecovery: config.ReplicationSQLErrorRecoveryRestore,
+			analysisCode:                inst.ReplicationSQLThreadError,
+			wantRecoveryFunction:        recoverReplicationSQLThreadErrorFunc,
+		}, {
+			name:                        "ReplicationSQLThreadError with --replication-sql-error-recovery none",
+			replicationSQLErrorRecovery: config.ReplicationSQLErrorRecoveryNone,
+			analysisCode:                inst.ReplicationSQLThreadError,
+			wantRecoveryFunction:        recoverGenericProblemFunc,
+		}, {
+			name:                      "PrimaryDiskUnhealthy with --allow-primary-disk-health-reparent",
+			primaryDiskHealthReparent: true,
+			analysisCode:              inst.PrimaryDiskUnhealthy,
+			wantRecoveryFunction:      recoverPrimaryDiskUnhealthyFunc,
+		}, {
+			name:                 "PrimaryDiskUnhealthy without --allow-primary-disk-health-reparent",
+			analysisCode:         inst.PrimaryDiskUnhealthy,
+			wantRecoveryFunction: recoverGenericProblemFunc,
 		},
 	}
 
@@ -270,8 +306,100 @@ func TestGetCheckAndRecoverFunctionCode(t *testing.T) {
 			config.SetConvertTabletWithErrantGTIDs(tt.convertTabletWithErrantGTIDs)
 			defer config.SetConvertTabletWithErrantGTIDs(convertErrantVal)
 
+			diskHealthReparentVal := config.PrimaryDiskHealthReparentEnabled()
+			config.SetPrimaryDiskHealthReparentEnabled(tt.primaryDiskHealthReparent)
+			defer config.SetPrimaryDiskHealthReparentEnabled(diskHealthReparentVal)
+
+			if tt.replicationSQLErrorRecovery != "" {
+				sqlErrorRecoveryVal := config.ReplicationSQLErrorRecovery()
+				require.NoError(t, config.SetReplicationSQLErrorRecovery(tt.replicationSQLErrorRecovery))
+				defer func() {
+					_ = config.SetReplicationSQLErrorRecovery(sqlErrorRecoveryVal)
+				}()
+			}
+
 			gotFunc := getCheckAndRecoverFunctionCode(tt.analysisCode, "")
 			require.EqualValues(t, tt.wantRecoveryFunction, gotFunc)
 		})
 	}
 }
+
+func TestDiskHealthyPrimaryCandidate(t *testing.T) {
+	orcDb, err := db.OpenVTOrc()
+	require.NoError(t, err)
+	oldTs := ts
+	oldThresholds := config.Config.KeyspacePrimaryDiskHealthThresholds
+	defer func() {
+		ts = oldTs
+		config.Config.KeyspacePrimaryDiskHealthThresholds = oldThresholds
+		_, err = orcDb.Exec("delete from vitess_tablet")
+		require.NoError(t, err)
+		_, err = orcDb.Exec("delete from database_instance")
+		require.NoError(t, err)
+	}()
+	config.Config.KeyspacePrimaryDiskHealthThresholds = map[string]config.PrimaryDiskHealthThresholds{
+		"ks": {DiskWriteLatencyMilliseconds: 100, DiskUsedPercent: 90},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts = memorytopo.NewServer(ctx, "zone1", "zone2")
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "0"))
+
+	newTablet := func(cell string, uid uint32, tabletType topodatapb.TabletType) *topodatapb.Tablet {
+		return &topodatapb.Tablet{
+			Alias:         &topodatapb.TabletAlias{Cell: cell, Uid: uid},
+			Hostname:      fmt.Sprintf("localhost%d", uid),
+			MysqlHostname: fmt.Sprintf("localhost%d", uid),
+			MysqlPort:     1200,
+			Keyspace:      "ks",
+			Shard:         "0",
+			Type:          tabletType,
+		}
+	}
+	writeTablet := func(tablet *topodatapb.Tablet, usedPercent float64, lagSeconds int64) {
+		require.NoError(t, ts.CreateTablet(ctx, tablet))
+		require.NoError(t, inst.SaveTablet(tablet))
+		_, err := orcDb.Exec(`insert into database_instance (alias, hostname, port, server_id, version, binlog_format, log_bin, log_replica_updates,
+			binary_log_file, binary_log_pos, source_host, source_port, replica_sql_running, replica_io_running, source_log_file, read_source_log_pos,
+			relay_source_log_file, exec_source_log_pos, replica_lag_seconds, disk_used_percent)
+			values (?, ?, ?, ?, '8.0.34', 'ROW', 1, 1, '', 0, '', 0, 1, 1, '', 0, '', 0, ?, ?)`,
+			topoproto.TabletAliasString(tablet.Alias), tablet.MysqlHostname, tablet.MysqlPort, tablet.Alias.Uid, lagSeconds, usedPercent)
+		require.NoError(t, err)
+	}
+
+	primary := newTablet("zone1", 1, topodatapb.TabletType_PRIMARY)
+	writeTablet(primary, 95, 0)
+	writeTablet(newTablet("zone1", 2, topodatapb.TabletType_REPLICA), 50, 10)
+	writeTablet(newTablet("zone1", 3, topodatapb.TabletType_REPLICA), 50, 5)
+	// Replicas of other cells and RDONLY tablets are not promoted, whatever the health of their disk.
+	writeTablet(newTablet("zone2", 4, topodatapb.TabletType_REPLICA), 50, 0)
+	writeTablet(newTablet("zone1", 5, topodatapb.TabletType_RDONLY), 95, 0)
+
+	// When all the candidates are healthy, PlannedReparentShard picks one itself.
+	alias, err := diskHealthyPrimaryCandidate(ctx, primary)
+	require.NoError(t, err)
+	require.Nil(t, alias)
+
+	// The healthy replica with the least replication lag is picked.
+	writeTablet(newTablet("zone1", 6, topodatapb.TabletType_REPLICA), 95, 0)
+	alias, err = diskHealthyPrimaryCandidate(ctx, primary)
+	require.NoError(t, err)
+	require.Equal(t, "zone1-0000000003", topoproto.TabletAliasString(alias))
+
+	// A replica that VTOrc has no information about is not picked.
+	replica := newTablet("zone1", 7, topodatapb.TabletType_REPLICA)
+	require.NoError(t, ts.CreateTablet(ctx, replica))
+	alias, err = diskHealthyPrimaryCandidate(ctx, primary)
+	require.NoError(t, err)
+	require.Equal(t, "zone1-0000000003", topoproto.TabletAliasString(alias))
+
+	// When none of the candidates is healthy, there is no reparent.
+	_, err = orcDb.Exec("update database_instance set disk_used_percent = 95 where alias = 'zone1-0000000002'")
+	require.NoError(t, err)
+	_, err = orcDb.Exec("update database_instance set disk_stalled = 1 where alias = 'zone1-0000000003'")
+	require.NoError(t, err)
+	_, err = diskHealthyPrimaryCandidate(ctx, primary)
+	require.EqualError(t, err, "none of the replicas of ks/0 has a healthy disk")
+}
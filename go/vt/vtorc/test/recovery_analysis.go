@@ -59,6 +59,13 @@ type InfoForRecoveryAnalysis struct {
 	ReplicationDepth                          uint
 	IsFailingToConnectToPrimary               int
 	ReplicationStopped                        int
+	LastSQLErrno                              uint32
+	LastSQLError                              string
+	LastIOErrno                               uint32
+	LastIOError                               string
+	DiskStalled                               int
+	DiskWriteLatencyMs                        int64
+	DiskUsedPercent                           float64
 	IsDowntimed                               int
 	DowntimeEndTimestamp                      string
 	DowntimeRemainingSeconds                  int
@@ -110,6 +117,9 @@ func (info *InfoForRecoveryAnalysis) ConvertToRowMap() sqlutils.RowMap {
 	rowMap["count_valid_replicas"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.CountValidReplicas), Valid: true}
 	rowMap["count_valid_replicating_replicas"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.CountValidReplicatingReplicas), Valid: true}
 	rowMap["data_center"] = sqlutils.CellData{String: info.DataCenter, Valid: true}
+	rowMap["disk_stalled"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.DiskStalled), Valid: true}
+	rowMap["disk_used_percent"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.DiskUsedPercent), Valid: true}
+	rowMap["disk_write_latency_ms"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.DiskWriteLatencyMs), Valid: true}
 	rowMap["downtime_end_timestamp"] = sqlutils.CellData{String: info.DowntimeEndTimestamp, Valid: true}
 	rowMap["downtime_remaining_seconds"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.DowntimeRemainingSeconds), Valid: true}
 	rowMap["durability_policy"] = sqlutils.CellData{String: info.DurabilityPolicy, Valid: true}
@@ -124,6 +134,10 @@ func (info *InfoForRecoveryAnalysis) ConvertToRowMap() sqlutils.RowMap {
 	rowMap["is_last_check_valid"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.LastCheckValid), Valid: true}
 	rowMap["is_primary"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.IsPrimary), Valid: true}
 	rowMap["is_stale_binlog_coordinates"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.IsStaleBinlogCoordinates), Valid: true}
+	rowMap["last_io_errno"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.LastIOErrno), Valid: true}
+	rowMap["last_io_error"] = sqlutils.CellData{String: info.LastIOError, Valid: true}
+	rowMap["last_sql_errno"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.LastSQLErrno), Valid: true}
+	rowMap["last_sql_error"] = sqlutils.CellData{String: info.LastSQLError, Valid: true}
 	rowMap["keyspace_type"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.KeyspaceType), Valid: true}
 	rowMap["keyspace"] = sqlutils.CellData{String: info.Keyspace, Valid: true}
 	rowMap["shard"] = sqlutils.CellData{String: info.Shard, Valid: true}
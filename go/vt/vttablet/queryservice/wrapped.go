@@ -18,6 +18,7 @@ package queryservice
 
 import (
 	"context"
+	"sync"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -178,9 +179,17 @@ func (ws *wrappedService) ReadTransaction(ctx context.Context, target *querypb.T
 
 func (ws *wrappedService) Execute(ctx context.Context, target *querypb.Target, query string, bindVars map[string]*querypb.BindVariable, transactionID, reservedID int64, options *querypb.ExecuteOptions) (qr *sqltypes.Result, err error) {
 	inDedicatedConn := transactionID != 0 || reservedID != 0
+	// The wrapper may execute the query on several tablets at once to hedge it, in which
+	// case the result of the first successful execution is kept.
+	var mu sync.Mutex
+	var done bool
 	err = ws.wrapper(ctx, target, ws.impl, "Execute", inDedicatedConn, func(ctx context.Context, target *querypb.Target, conn QueryService) (bool, error) {
-		var innerErr error
-		qr, innerErr = conn.Execute(ctx, target, query, bindVars, transactionID, reservedID, options)
+		res, innerErr := conn.Execute(ctx, target, query, bindVars, transactionID, reservedID, options)
+		mu.Lock()
+		if !done {
+			qr, done = res, innerErr == nil
+		}
+		mu.Unlock()
 		// You cannot retry if you're in a transaction.
 		retryable := canRetry(ctx, innerErr) && (!inDedicatedConn)
 		return retryable, innerErr
@@ -63,6 +63,10 @@ type SandboxConn struct {
 	// Once, exhausted it will start returning non-error response.
 	MustFailExecute map[sqlparser.StatementType]int
 
+	// ExecuteDelay, if set, makes Execute wait for this long, or until
+	// its context is done, before it runs.
+	ExecuteDelay time.Duration
+
 	// These Count vars report how often the corresponding
 	// functions were called.
 	ExecCount                atomic.Int64
@@ -215,6 +219,13 @@ func (sbc *SandboxConn) SetSchemaResult(r []SchemaResult) {
 // Execute is part of the QueryService interface.
 func (sbc *SandboxConn) Execute(ctx context.Context, target *querypb.Target, query string, bindVars map[string]*querypb.BindVariable, transactionID, reservedID int64, options *querypb.ExecuteOptions) (*sqltypes.Result, error) {
 	sbc.panicIfNeeded()
+	if sbc.ExecuteDelay > 0 {
+		select {
+		case <-time.After(sbc.ExecuteDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
 	sbc.execMu.Lock()
 	defer sbc.execMu.Unlock()
 	sbc.ExecCount.Add(1)
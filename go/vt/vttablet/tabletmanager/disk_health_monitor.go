@@ -0,0 +1,196 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+// diskHealthCheckFile is the file the disk health monitor writes to.
+const diskHealthCheckFile = "vt_disk_health_check"
+
+var (
+	diskHealthCheckInterval time.Duration
+	diskHealthCheckTimeout  = 30 * time.Second
+	diskHealthCheckDir      string
+)
+
+func registerDiskHealthFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&diskHealthCheckInterval, "disk-health-check-interval", diskHealthCheckInterval, "Interval between the writes to the MySQL data directory used to report the health of its disk in FullStatus. 0 disables the disk health checks.")
+	fs.DurationVar(&diskHealthCheckTimeout, "disk-health-check-timeout", diskHealthCheckTimeout, "Time after which a disk health check write that has not completed reports the disk as stalled.")
+	fs.StringVar(&diskHealthCheckDir, "disk-health-check-dir", diskHealthCheckDir, "Directory written to by the disk health checks. Defaults to the MySQL data directory.")
+}
+
+func init() {
+	servenv.OnParseFor("vtcombo", registerDiskHealthFlags)
+	servenv.OnParseFor("vttablet", registerDiskHealthFlags)
+}
+
+// startDiskHealthMonitor starts the disk health checks of the MySQL data directory, if enabled.
+func (tm *TabletManager) startDiskHealthMonitor() {
+	dir := diskHealthCheckDir
+	if dir == "" && tm.Cnf != nil {
+		dir = tm.Cnf.DataDir
+	}
+	tm.diskHealthMonitor = newDiskHealthMonitor(dir, diskHealthCheckInterval, diskHealthCheckTimeout)
+	tm.diskHealthMonitor.Open()
+}
+
+// diskHealthMonitor periodically writes and syncs a small file in a directory,
+// usually the MySQL data directory. It reports the latency of the last write,
+// whether a write is stalled and how full the disk is, so that VTOrc can move
+// the primary away before its disk fails outright.
+type diskHealthMonitor struct {
+	dir      string
+	interval time.Duration
+	timeout  time.Duration
+
+	mu sync.Mutex
+	// writeStart is the start time of the write in flight, if any.
+	writeStart time.Time
+	// writeErr is the error of the last write.
+	writeErr     error
+	writeLatency time.Duration
+	usedPercent  float64
+
+	cancel context.CancelFunc
+}
+
+// newDiskHealthMonitor returns a monitor of the given directory, or nil if the
+// disk health checks are disabled.
+func newDiskHealthMonitor(dir string, interval, timeout time.Duration) *diskHealthMonitor {
+	if dir == "" || interval <= 0 {
+		return nil
+	}
+	return &diskHealthMonitor{
+		dir:      dir,
+		interval: interval,
+		timeout:  timeout,
+	}
+}
+
+// Open starts the disk health checks.
+func (m *diskHealthMonitor) Open() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	go m.run(ctx)
+}
+
+// Close stops the disk health checks. It does not wait for a stalled write.
+func (m *diskHealthMonitor) Close() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+}
+
+func (m *diskHealthMonitor) run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.check()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check writes to the disk and measures how full it is. A write that stalls
+// blocks the check, which is then reported by Status once it exceeds the timeout.
+func (m *diskHealthMonitor) check() {
+	start := time.Now()
+	m.mu.Lock()
+	m.writeStart = start
+	m.mu.Unlock()
+
+	writeErr := writeAndSync(filepath.Join(m.dir, diskHealthCheckFile))
+	latency := time.Since(start)
+	usedPercent, err := diskUsedPercent(m.dir)
+	if err != nil {
+		log.Warningf("Could not read the disk usage of %v: %v", m.dir, err)
+	}
+	if writeErr != nil {
+		log.Warningf("Disk health check write to %v failed: %v", m.dir, writeErr)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writeStart = time.Time{}
+	m.writeErr = writeErr
+	m.writeLatency = latency
+	if err == nil {
+		m.usedPercent = usedPercent
+	}
+}
+
+// Status returns whether the disk is stalled, the latency of the last write and
+// the percentage of the disk in use. A disk is stalled if the write in flight
+// exceeded the timeout, or if the last write failed.
+func (m *diskHealthMonitor) Status() (stalled bool, writeLatency time.Duration, usedPercent float64) {
+	if m == nil {
+		return false, 0, 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	writeLatency = m.writeLatency
+	if !m.writeStart.IsZero() {
+		inFlight := time.Since(m.writeStart)
+		stalled = inFlight > m.timeout
+		writeLatency = max(writeLatency, inFlight)
+	}
+	return stalled || m.writeErr != nil, writeLatency, m.usedPercent
+}
+
+func writeAndSync(name string) error {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(time.Now().UTC().Format(time.RFC3339Nano)); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskHealthMonitorDisabled(t *testing.T) {
+	assert.Nil(t, newDiskHealthMonitor("", time.Second, time.Second))
+	assert.Nil(t, newDiskHealthMonitor(t.TempDir(), 0, time.Second))
+
+	// A disabled monitor reports a healthy disk.
+	var m *diskHealthMonitor
+	m.Open()
+	defer m.Close()
+	stalled, writeLatency, usedPercent := m.Status()
+	assert.False(t, stalled)
+	assert.Zero(t, writeLatency)
+	assert.Zero(t, usedPercent)
+}
+
+func TestDiskHealthMonitorCheck(t *testing.T) {
+	dir := t.TempDir()
+	m := newDiskHealthMonitor(dir, time.Hour, time.Minute)
+	require.NotNil(t, m)
+
+	m.check()
+	stalled, _, usedPercent := m.Status()
+	assert.False(t, stalled)
+	assert.FileExists(t, filepath.Join(dir, diskHealthCheckFile))
+	assert.GreaterOrEqual(t, usedPercent, float64(0))
+	assert.LessOrEqual(t, usedPercent, float64(100))
+
+	// A write in flight for longer than the timeout stalls the disk.
+	m.mu.Lock()
+	m.writeStart = time.Now().Add(-2 * time.Minute)
+	m.mu.Unlock()
+	stalled, writeLatency, _ := m.Status()
+	assert.True(t, stalled)
+	assert.GreaterOrEqual(t, writeLatency, 2*time.Minute)
+
+	// A write that fails stalls the disk too.
+	m = newDiskHealthMonitor(filepath.Join(dir, "missing"), time.Hour, time.Minute)
+	m.check()
+	stalled, _, _ = m.Status()
+	assert.True(t, stalled)
+}
@@ -145,6 +145,9 @@ func (tm *TabletManager) FullStatus(ctx context.Context) (*replicationdatapb.Ful
 	// Semi sync settings - "show status like 'rpl_semi_sync_%'
 	semiSyncTimeout, semiSyncNumReplicas := tm.MysqlDaemon.SemiSyncSettings()
 
+	// Disk health - reported by the disk health monitor, if enabled
+	diskStalled, diskWriteLatency, diskUsedPercent := tm.diskHealthMonitor.Status()
+
 	return &replicationdatapb.FullStatus{
 		ServerId:                    serverID,
 		ServerUuid:                  serverUUID,
@@ -167,6 +170,9 @@ func (tm *TabletManager) FullStatus(ctx context.Context) (*replicationdatapb.Ful
 		SemiSyncPrimaryTimeout:      semiSyncTimeout,
 		SemiSyncWaitForReplicaCount: semiSyncNumReplicas,
 		SuperReadOnly:               superReadOnly,
+		DiskStalled:                 diskStalled,
+		DiskWriteLatencyMs:          uint64(diskWriteLatency.Milliseconds()),
+		DiskUsedPercent:             diskUsedPercent,
 	}, nil
 }
 
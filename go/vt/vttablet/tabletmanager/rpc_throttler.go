@@ -47,9 +47,23 @@ func (tm *TabletManager) CheckThrottler(ctx context.Context, req *tabletmanagerd
 		Threshold:       checkResult.Threshold,
 		Message:         checkResult.Message,
 		RecentlyChecked: checkResult.RecentlyChecked,
+		MetricName:      checkResult.MetricName,
+		Metrics:         make(map[string]*tabletmanagerdatapb.CheckThrottlerResponse_Metric, len(checkResult.Metrics)),
 	}
 	if checkResult.Error != nil {
 		resp.Error = checkResult.Error.Error()
 	}
+	for metricName, metric := range checkResult.Metrics {
+		respMetric := &tabletmanagerdatapb.CheckThrottlerResponse_Metric{
+			StatusCode: int32(metric.StatusCode),
+			Value:      metric.Value,
+			Threshold:  metric.Threshold,
+			Message:    metric.Message,
+		}
+		if metric.Error != nil {
+			respMetric.Error = metric.Error.Error()
+		}
+		resp.Metrics[metricName] = respMetric
+	}
 	return resp, nil
 }
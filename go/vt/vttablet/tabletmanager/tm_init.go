@@ -173,6 +173,10 @@ type TabletManager struct {
 	// first before other mutexes.
 	actionSema *semaphore.Weighted
 
+	// diskHealthMonitor reports the health of the disk of MySQL in FullStatus.
+	// It is nil if the disk health checks are disabled.
+	diskHealthMonitor *diskHealthMonitor
+
 	// mutex protects all the following fields (that start with '_'),
 	// only hold the mutex to update the fields, nothing else.
 	mutex sync.Mutex
@@ -402,6 +406,7 @@ func (tm *TabletManager) Start(tablet *topodatapb.Tablet, config *tabletenv.Tabl
 	// The following initializations don't need to be done
 	// in any specific order.
 	tm.startShardSync()
+	tm.startDiskHealthMonitor()
 	tm.exportStats()
 	servenv.OnRun(tm.registerTabletManager)
 
@@ -440,6 +445,7 @@ func (tm *TabletManager) Close() {
 	// running during lame duck.
 	tm.stopShardSync()
 	tm.stopRebuildKeyspace()
+	tm.diskHealthMonitor.Close()
 
 	// cleanup initialized fields in the tablet entry
 	f := func(tablet *topodatapb.Tablet) error {
@@ -471,6 +477,7 @@ func (tm *TabletManager) Stop() {
 	// here in addition to in Close() because tests do not call Close().
 	tm.stopShardSync()
 	tm.stopRebuildKeyspace()
+	tm.diskHealthMonitor.Close()
 
 	if tm.QueryServiceControl != nil {
 		tm.QueryServiceControl.Stats().Stop()
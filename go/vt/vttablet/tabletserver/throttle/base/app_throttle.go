@@ -22,20 +22,23 @@ import (
 
 // AppThrottle is the definition for an app throttling instruction
 // - Ratio: [0..1], 0 == no throttle, 1 == fully throttle
+// - DryRun: checks are evaluated and recorded, but always respond with OK
 type AppThrottle struct {
 	AppName  string
 	ExpireAt time.Time
 	Ratio    float64
 	Exempt   bool
+	DryRun   bool
 }
 
 // NewAppThrottle creates an AppThrottle struct
-func NewAppThrottle(appName string, expireAt time.Time, ratio float64, exempt bool) *AppThrottle {
+func NewAppThrottle(appName string, expireAt time.Time, ratio float64, exempt bool, dryRun bool) *AppThrottle {
 	result := &AppThrottle{
 		AppName:  appName,
 		ExpireAt: expireAt,
 		Ratio:    ratio,
 		Exempt:   exempt,
+		DryRun:   dryRun,
 	}
 	return result
 }
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MetricName is the name of a metric collected and checked by the throttler
+type MetricName string
+
+// MetricNames is a list of metric names
+type MetricNames []MetricName
+
+const (
+	// LagMetricName is the replication lag, measured with heartbeats, in seconds
+	LagMetricName MetricName = "lag"
+	// ThreadsRunningMetricName is the number of threads running in MySQL
+	ThreadsRunningMetricName MetricName = "threads_running"
+	// HistoryListLengthMetricName is the length of the InnoDB history list
+	HistoryListLengthMetricName MetricName = "history_list_length"
+	// LoadAvgMetricName is the 1 minute load average of the host, per CPU
+	LoadAvgMetricName MetricName = "loadavg"
+	// CustomMetricName is the result of the custom query of the throttler config
+	CustomMetricName MetricName = "custom"
+)
+
+// KnownMetricNames are all the metrics the throttler knows how to collect
+var KnownMetricNames = MetricNames{
+	LagMetricName,
+	ThreadsRunningMetricName,
+	HistoryListLengthMetricName,
+	LoadAvgMetricName,
+	CustomMetricName,
+}
+
+// defaultThresholds are the thresholds of the metrics that have no threshold in the throttler config.
+// The custom metric has no default threshold, as it depends on the custom query.
+var defaultThresholds = map[MetricName]float64{
+	LagMetricName:               5,
+	ThreadsRunningMetricName:    100,
+	HistoryListLengthMetricName: 5000,
+	LoadAvgMetricName:           1,
+}
+
+func (metric MetricName) String() string {
+	return string(metric)
+}
+
+// DefaultThreshold returns the threshold of the metric when none is configured
+func (metric MetricName) DefaultThreshold() float64 {
+	return defaultThresholds[metric]
+}
+
+// Contains returns true if the given metric is in the list
+func (names MetricNames) Contains(name MetricName) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a comma separated list of the metric names
+func (names MetricNames) String() string {
+	s := make([]string, len(names))
+	for i, name := range names {
+		s[i] = name.String()
+	}
+	return strings.Join(s, ",")
+}
+
+// ParseMetricNames validates and returns the given metric names
+func ParseMetricNames(names []string) (MetricNames, error) {
+	metricNames := make(MetricNames, 0, len(names))
+	for _, name := range names {
+		metricName := MetricName(strings.TrimSpace(name))
+		if !KnownMetricNames.Contains(metricName) {
+			return nil, fmt.Errorf("unknown throttler metric %q, expected one of: %v", name, KnownMetricNames)
+		}
+		if !metricNames.Contains(metricName) {
+			metricNames = append(metricNames, metricName)
+		}
+	}
+	return metricNames, nil
+}
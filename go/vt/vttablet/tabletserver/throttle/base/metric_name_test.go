@@ -0,0 +1,48 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMetricNames(t *testing.T) {
+	metricNames, err := ParseMetricNames([]string{"lag", " loadavg", "lag", "history_list_length"})
+	require.NoError(t, err)
+	assert.Equal(t, MetricNames{LagMetricName, LoadAvgMetricName, HistoryListLengthMetricName}, metricNames)
+	assert.Equal(t, "lag,loadavg,history_list_length", metricNames.String())
+	assert.True(t, metricNames.Contains(LoadAvgMetricName))
+	assert.False(t, metricNames.Contains(CustomMetricName))
+
+	metricNames, err = ParseMetricNames(nil)
+	require.NoError(t, err)
+	assert.Empty(t, metricNames)
+
+	_, err = ParseMetricNames([]string{"lag", "no_such_metric"})
+	assert.ErrorContains(t, err, `unknown throttler metric "no_such_metric"`)
+}
+
+func TestDefaultThreshold(t *testing.T) {
+	assert.Equal(t, float64(5), LagMetricName.DefaultThreshold())
+	assert.Equal(t, float64(100), ThreadsRunningMetricName.DefaultThreshold())
+	assert.Equal(t, float64(5000), HistoryListLengthMetricName.DefaultThreshold())
+	assert.Equal(t, float64(1), LoadAvgMetricName.DefaultThreshold())
+	assert.Zero(t, CustomMetricName.DefaultThreshold())
+}
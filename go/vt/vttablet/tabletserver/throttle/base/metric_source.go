@@ -0,0 +1,242 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// maxMetricSourceResponseSize limits how much of an HTTP metric source response is read
+const maxMetricSourceResponseSize = 16 * 1024 * 1024
+
+// MetricSource reads the current value of a metric
+type MetricSource interface {
+	Read(ctx context.Context) (float64, error)
+}
+
+// MetricSourceFunc is a function that reads the current value of a metric
+type MetricSourceFunc func(ctx context.Context) (float64, error)
+
+// Read implements MetricSource
+func (f MetricSourceFunc) Read(ctx context.Context) (float64, error) {
+	return f(ctx)
+}
+
+// NewMetricSourceFromURL returns the HTTP metric source of the given URL. A URL with a fragment reads the
+// metric named by the fragment from a Prometheus text exposition, e.g. "http://localhost:9100/metrics#node_load1"
+// or "http://localhost:9100/metrics#node_disk_io_now{device="sda"}". A URL with no fragment reads a response
+// body that is the value of the metric, e.g. "0.42".
+// Metric sources are local to the tablet, so the host of the URL must be a loopback address or "localhost".
+func NewMetricSourceFromURL(sourceURL string, client *http.Client) (MetricSource, error) {
+	u, err := url.Parse(strings.TrimSpace(sourceURL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid metric source URL %q: %w", sourceURL, err)
+	}
+	if err := validateMetricSourceURL(u); err != nil {
+		return nil, fmt.Errorf("invalid metric source URL %q: %w", sourceURL, err)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	// Redirects are only followed to local endpoints too.
+	localClient := *client
+	localClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after %d redirects", len(via))
+		}
+		return validateMetricSourceURL(req.URL)
+	}
+	client = &localClient
+	selector := u.Fragment
+	u.Fragment = ""
+	if selector == "" {
+		return &HTTPMetricSource{URL: u.String(), Client: client}, nil
+	}
+	metric, labels, err := parsePrometheusSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metric source URL %q: %w", sourceURL, err)
+	}
+	return &PrometheusMetricSource{URL: u.String(), Metric: metric, Labels: labels, Client: client}, nil
+}
+
+// validateMetricSourceURL returns an error if the URL is not an HTTP URL of a local endpoint
+func validateMetricSourceURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("missing host")
+	}
+	if strings.EqualFold(host, "localhost") {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("host %s is not a loopback address", host)
+}
+
+// HTTPMetricSource reads a metric from an HTTP endpoint whose response body is the value of the metric
+type HTTPMetricSource struct {
+	URL    string
+	Client *http.Client
+}
+
+// Read implements MetricSource
+func (source *HTTPMetricSource) Read(ctx context.Context) (float64, error) {
+	body, err := httpGet(ctx, source.Client, source.URL)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(body, maxMetricSourceResponseSize))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(strings.TrimSpace(string(content)), 64)
+}
+
+// PrometheusMetricSource reads a metric from an HTTP endpoint in the Prometheus text exposition format,
+// like those of node-level agents. The value is the highest of the samples of the metric whose labels
+// include the given labels. Gauge, counter and untyped metrics are supported.
+type PrometheusMetricSource struct {
+	URL    string
+	Metric string
+	Labels map[string]string
+	Client *http.Client
+}
+
+// Read implements MetricSource
+func (source *PrometheusMetricSource) Read(ctx context.Context) (float64, error) {
+	body, err := httpGet(ctx, source.Client, source.URL)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(io.LimitReader(body, maxMetricSourceResponseSize))
+	if err != nil {
+		return 0, err
+	}
+	family, ok := families[source.Metric]
+	if !ok {
+		return 0, fmt.Errorf("metric %s not found in %s", source.Metric, source.URL)
+	}
+	found := false
+	var value float64
+	for _, metric := range family.GetMetric() {
+		if !source.matchesLabels(metric) {
+			continue
+		}
+		sampleValue, ok := prometheusSampleValue(metric)
+		if !ok {
+			continue
+		}
+		if !found || sampleValue > value {
+			value = sampleValue
+			found = true
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("no sample of metric %s matches labels %v in %s", source.Metric, source.Labels, source.URL)
+	}
+	return value, nil
+}
+
+func (source *PrometheusMetricSource) matchesLabels(metric *dto.Metric) bool {
+	matched := 0
+	for _, label := range metric.GetLabel() {
+		if value, ok := source.Labels[label.GetName()]; ok {
+			if value != label.GetValue() {
+				return false
+			}
+			matched++
+		}
+	}
+	return matched == len(source.Labels)
+}
+
+func prometheusSampleValue(metric *dto.Metric) (float64, bool) {
+	switch {
+	case metric.Gauge != nil:
+		return metric.GetGauge().GetValue(), true
+	case metric.Counter != nil:
+		return metric.GetCounter().GetValue(), true
+	case metric.Untyped != nil:
+		return metric.GetUntyped().GetValue(), true
+	}
+	return 0, false
+}
+
+// parsePrometheusSelector parses a metric name with optional labels, e.g. `node_disk_io_now{device="sda"}`
+func parsePrometheusSelector(selector string) (metric string, labels map[string]string, err error) {
+	metric, rest, hasLabels := strings.Cut(selector, "{")
+	metric = strings.TrimSpace(metric)
+	if metric == "" {
+		return "", nil, fmt.Errorf("missing metric name in %q", selector)
+	}
+	if !hasLabels {
+		return metric, nil, nil
+	}
+	labels = make(map[string]string)
+	for {
+		rest = strings.TrimLeft(rest, " ,")
+		if rest == "}" {
+			return metric, labels, nil
+		}
+		name, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			return "", nil, fmt.Errorf("invalid labels in %q", selector)
+		}
+		value = strings.TrimSpace(value)
+		quoted, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid value of label %s in %q", strings.TrimSpace(name), selector)
+		}
+		labels[strings.TrimSpace(name)], _ = strconv.Unquote(quoted)
+		rest = value[len(quoted):]
+	}
+}
+
+func httpGet(ctx context.Context, client *http.Client, sourceURL string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, sourceURL)
+	}
+	return resp.Body, nil
+}
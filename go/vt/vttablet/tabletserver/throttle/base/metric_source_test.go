@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPrometheusExposition = `# HELP node_load1 1m load average.
+# TYPE node_load1 gauge
+node_load1 2.5
+# HELP node_disk_io_now The number of I/Os currently in progress.
+# TYPE node_disk_io_now gauge
+node_disk_io_now{device="sda"} 3
+node_disk_io_now{device="sdb"} 7
+node_disk_io_now{device="sdc"} 1
+# HELP node_disk_reads_completed_total The total number of reads completed successfully.
+# TYPE node_disk_reads_completed_total counter
+node_disk_reads_completed_total{device="sda"} 1234
+`
+
+func newTestMetricSourceServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/value", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "0.42")
+	})
+	mux.HandleFunc("/invalid", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "not a number")
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testPrometheusExposition)
+	})
+	mux.HandleFunc("/local-redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/value", http.StatusFound)
+	})
+	mux.HandleFunc("/remote-redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://metrics.example.com/value", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewMetricSourceFromURL(t *testing.T) {
+	source, err := NewMetricSourceFromURL("http://localhost:9100/value", nil)
+	require.NoError(t, err)
+	assert.IsType(t, &HTTPMetricSource{}, source)
+
+	source, err = NewMetricSourceFromURL(`http://localhost:9100/metrics#node_disk_io_now{device="sda", mode="x,y"}`, nil)
+	require.NoError(t, err)
+	require.IsType(t, &PrometheusMetricSource{}, source)
+	prometheusSource := source.(*PrometheusMetricSource)
+	assert.Equal(t, "http://localhost:9100/metrics", prometheusSource.URL)
+	assert.Equal(t, "node_disk_io_now", prometheusSource.Metric)
+	assert.Equal(t, map[string]string{"device": "sda", "mode": "x,y"}, prometheusSource.Labels)
+
+	for _, sourceURL := range []string{
+		"http://127.0.0.1:9100/value",
+		"https://LOCALHOST/value",
+		"http://[::1]:9100/value",
+	} {
+		_, err := NewMetricSourceFromURL(sourceURL, nil)
+		assert.NoError(t, err, sourceURL)
+	}
+
+	for _, sourceURL := range []string{
+		"ftp://localhost/value",
+		"http:///value",
+		"http://10.0.0.1:9100/metrics",
+		"http://metrics.example.com/value",
+		"http://localhost.example.com/value",
+		"http://[::2]:9100/value",
+		"http://localhost:9100/metrics#{device=\"sda\"}",
+		"http://localhost:9100/metrics#node_disk_io_now{device=sda}",
+		"http://localhost:9100/metrics#node_disk_io_now{device=\"sda\"",
+	} {
+		_, err := NewMetricSourceFromURL(sourceURL, nil)
+		assert.Error(t, err, sourceURL)
+	}
+}
+
+func TestMetricSourceRead(t *testing.T) {
+	server := newTestMetricSourceServer(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		sourceURL string
+		value     float64
+		wantErr   bool
+	}{
+		{
+			name:      "plain value",
+			sourceURL: server.URL + "/value",
+			value:     0.42,
+		}, {
+			name:      "invalid plain value",
+			sourceURL: server.URL + "/invalid",
+			wantErr:   true,
+		}, {
+			name:      "missing endpoint",
+			sourceURL: server.URL + "/missing",
+			wantErr:   true,
+		}, {
+			name:      "redirect to a local endpoint",
+			sourceURL: server.URL + "/local-redirect",
+			value:     0.42,
+		}, {
+			name:      "redirect to a remote endpoint",
+			sourceURL: server.URL + "/remote-redirect",
+			wantErr:   true,
+		}, {
+			name:      "prometheus gauge",
+			sourceURL: server.URL + "/metrics#node_load1",
+			value:     2.5,
+		}, {
+			name:      "prometheus highest sample",
+			sourceURL: server.URL + "/metrics#node_disk_io_now",
+			value:     7,
+		}, {
+			name:      "prometheus sample by labels",
+			sourceURL: server.URL + `/metrics#node_disk_io_now{device="sda"}`,
+			value:     3,
+		}, {
+			name:      "prometheus counter",
+			sourceURL: server.URL + "/metrics#node_disk_reads_completed_total",
+			value:     1234,
+		}, {
+			name:      "prometheus unknown labels",
+			sourceURL: server.URL + `/metrics#node_disk_io_now{device="sdz"}`,
+			wantErr:   true,
+		}, {
+			name:      "prometheus unknown metric",
+			sourceURL: server.URL + "/metrics#node_load5",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := NewMetricSourceFromURL(tt.sourceURL, server.Client())
+			require.NoError(t, err)
+			value, err := source.Read(ctx)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.value, value)
+		})
+	}
+}
@@ -44,17 +44,22 @@ import (
 	"time"
 )
 
-// RecentApp indicates when an app was last checked
+// RecentApp indicates when an app was last checked, and the result of that check.
+// For an app in dry-run mode, StatusCode is the status the check would have responded with.
 type RecentApp struct {
 	CheckedAtEpoch      int64
 	MinutesSinceChecked int64
+	StatusCode          int
+	DryRun              bool
 }
 
 // NewRecentApp creates a RecentApp
-func NewRecentApp(checkedAt time.Time) *RecentApp {
+func NewRecentApp(checkedAt time.Time, statusCode int, dryRun bool) *RecentApp {
 	result := &RecentApp{
 		CheckedAtEpoch:      checkedAt.Unix(),
 		MinutesSinceChecked: int64(time.Since(checkedAt).Minutes()),
+		StatusCode:          statusCode,
+		DryRun:              dryRun,
 	}
 	return result
 }
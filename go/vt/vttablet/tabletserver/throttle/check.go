@@ -59,8 +59,10 @@ const (
 )
 
 var (
-	statsThrottlerCheckAnyTotal = stats.NewCounter("ThrottlerCheckAnyTotal", "total number of checks")
-	statsThrottlerCheckAnyError = stats.GetOrNewCounter("ThrottlerCheckAnyError", "total number of failed checks")
+	statsThrottlerCheckAnyTotal    = stats.NewCounter("ThrottlerCheckAnyTotal", "total number of checks")
+	statsThrottlerCheckAnyError    = stats.GetOrNewCounter("ThrottlerCheckAnyError", "total number of failed checks")
+	statsThrottlerCheckDryRunTotal = stats.NewCountersWithSingleLabel("ThrottlerCheckDryRunTotal", "total number of checks of apps in dry-run mode", "App")
+	statsThrottlerCheckDryRunError = stats.NewCountersWithSingleLabel("ThrottlerCheckDryRunError", "number of checks of apps in dry-run mode that would have failed", "App")
 )
 
 // CheckFlags provide hints for a check
@@ -88,12 +90,12 @@ func NewThrottlerCheck(throttler *Throttler) *ThrottlerCheck {
 }
 
 // checkAppMetricResult allows an app to check on a metric
-func (check *ThrottlerCheck) checkAppMetricResult(ctx context.Context, appName string, storeType string, storeName string, metricResultFunc base.MetricResultFunc, flags *CheckFlags) (checkResult *CheckResult) {
+func (check *ThrottlerCheck) checkAppMetricResult(ctx context.Context, appName string, storeType string, storeName string, metricName base.MetricName, metricResultFunc base.MetricResultFunc, flags *CheckFlags) (checkResult *CheckResult) {
 	// Handle deprioritized app logic
 	denyApp := false
-	metricName := fmt.Sprintf("%s/%s", storeType, storeName)
+	nonLowPriorityKey := fmt.Sprintf("%s/%s/%s", storeType, storeName, metricName)
 	if flags.LowPriority {
-		if _, exists := check.throttler.nonLowPriorityAppRequestsThrottled.Get(metricName); exists {
+		if _, exists := check.throttler.nonLowPriorityAppRequestsThrottled.Get(nonLowPriorityKey); exists {
 			// a non-deprioritized app, ie a "normal" app, has recently been throttled.
 			// This is now a deprioritized app. Deny access to this request.
 			denyApp = true
@@ -128,7 +130,7 @@ func (check *ThrottlerCheck) checkAppMetricResult(ctx context.Context, appName s
 
 		if !flags.LowPriority && !flags.ReadCheck && throttlerapp.VitessName.Equals(appName) {
 			// low priority requests will henceforth be denied
-			go check.throttler.nonLowPriorityAppRequestsThrottled.SetDefault(metricName, true)
+			go check.throttler.nonLowPriorityAppRequestsThrottled.SetDefault(nonLowPriorityKey, true)
 		}
 	default:
 		// all good!
@@ -137,23 +139,51 @@ func (check *ThrottlerCheck) checkAppMetricResult(ctx context.Context, appName s
 	return NewCheckResult(statusCode, value, threshold, err)
 }
 
-// Check is the core function that runs when a user wants to check a metric
-func (check *ThrottlerCheck) Check(ctx context.Context, appName string, storeType string, storeName string, remoteAddr string, flags *CheckFlags) (checkResult *CheckResult) {
-	var metricResultFunc base.MetricResultFunc
-	switch storeType {
-	case "mysql":
-		{
-			metricResultFunc = func() (metricResult base.MetricResult, threshold float64) {
-				return check.throttler.getMySQLClusterMetrics(ctx, storeName)
-			}
-		}
-	}
-	if metricResultFunc == nil {
+// Check is the core function that runs when a user wants to check a metric. Each of the given metrics
+// is checked, and the result is that of the first metric to fail the check, or of the first metric
+// if all pass. The results of all metrics are found in the result's Metrics.
+func (check *ThrottlerCheck) Check(ctx context.Context, appName string, storeType string, storeName string, metricNames base.MetricNames, remoteAddr string, flags *CheckFlags) (checkResult *CheckResult) {
+	if storeType != "mysql" {
 		return NoSuchMetricCheckResult
 	}
+	if len(metricNames) == 0 {
+		metricNames = base.MetricNames{check.throttler.defaultMetricName()}
+	}
 
-	checkResult = check.checkAppMetricResult(ctx, appName, storeType, storeName, metricResultFunc, flags)
-	check.throttler.markRecentApp(appName, remoteAddr)
+	metrics := make(map[string]*CheckResult, len(metricNames))
+	for _, metricName := range metricNames {
+		metricResultFunc := func() (metricResult base.MetricResult, threshold float64) {
+			return check.throttler.getMySQLClusterMetrics(ctx, storeName, metricName)
+		}
+		metricCheckResult := check.checkAppMetricResult(ctx, appName, storeType, storeName, metricName, metricResultFunc, flags)
+		metricCheckResult.MetricName = metricName.String()
+		metrics[metricName.String()] = metricCheckResult
+
+		switch {
+		case checkResult == nil:
+			checkResult = metricCheckResult
+		case throttlerapp.VitessName.Equals(appName):
+			// The primary throttler reads the value of the first (default) metric from replicas which do
+			// not report metrics by name, so the result of the "vitess" app is always the first metric's.
+		case checkResult.StatusCode == http.StatusOK && metricCheckResult.StatusCode != http.StatusOK:
+			checkResult = metricCheckResult
+		}
+	}
+	checkResult = checkResult.withMetrics(metrics)
+	// An app in dry-run mode is recorded with the status it would have had, but is responded with OK
+	dryRun := check.throttler.IsAppDryRun(appName)
+	check.throttler.markRecentApp(appName, remoteAddr, checkResult.StatusCode, dryRun)
+	if dryRun {
+		go func(statusCode int) {
+			statsThrottlerCheckDryRunTotal.Add(appName, 1)
+			if statusCode != http.StatusOK {
+				statsThrottlerCheckDryRunError.Add(appName, 1)
+			}
+		}(checkResult.StatusCode)
+		if checkResult.StatusCode != http.StatusOK {
+			checkResult = checkResult.asDryRun()
+		}
+	}
 	if !throttlerapp.VitessName.Equals(appName) {
 		go func(statusCode int) {
 			statsThrottlerCheckAnyTotal.Add(1)
@@ -168,42 +198,66 @@ func (check *ThrottlerCheck) Check(ctx context.Context, appName string, storeTyp
 	return checkResult
 }
 
-func (check *ThrottlerCheck) splitMetricTokens(metricName string) (storeType string, storeName string, err error) {
-	metricTokens := strings.Split(metricName, "/")
-	if len(metricTokens) != 2 {
-		return storeType, storeName, base.ErrNoSuchMetric
+// splitMetricTokens splits an aggregated metric name, e.g. "mysql/self" or "mysql/shard/threads_running",
+// into its tokens. A name with no metric token is that of the default metric.
+func (check *ThrottlerCheck) splitMetricTokens(aggregatedMetricName string) (storeType string, storeName string, metricName base.MetricName, err error) {
+	metricTokens := strings.Split(aggregatedMetricName, "/")
+	switch len(metricTokens) {
+	case 2:
+		metricName = check.throttler.defaultMetricName()
+	case 3:
+		metricName = base.MetricName(metricTokens[2])
+	default:
+		return storeType, storeName, metricName, base.ErrNoSuchMetric
 	}
 	storeType = metricTokens[0]
 	storeName = metricTokens[1]
 
-	return storeType, storeName, nil
+	return storeType, storeName, metricName, nil
+}
+
+// metricStatsName returns the name by which an aggregated metric is reported in stats. The default metric
+// is reported as e.g. "MysqlSelf", and other metrics as e.g. "MysqlSelfThreadsRunning".
+func (check *ThrottlerCheck) metricStatsName(aggregatedMetricName string) (string, error) {
+	storeType, storeName, metricName, err := check.splitMetricTokens(aggregatedMetricName)
+	if err != nil {
+		return "", err
+	}
+	statsName := textutil.SingleWordCamel(storeType) + textutil.SingleWordCamel(storeName)
+	if metricName != check.throttler.defaultMetricName() {
+		for _, token := range strings.Split(metricName.String(), "_") {
+			statsName += textutil.SingleWordCamel(token)
+		}
+	}
+	return statsName, nil
 }
 
 // localCheck
-func (check *ThrottlerCheck) localCheck(ctx context.Context, metricName string) (checkResult *CheckResult) {
-	storeType, storeName, err := check.splitMetricTokens(metricName)
+func (check *ThrottlerCheck) localCheck(ctx context.Context, aggregatedMetricName string) (checkResult *CheckResult) {
+	storeType, storeName, metricName, err := check.splitMetricTokens(aggregatedMetricName)
 	if err != nil {
 		return NoSuchMetricCheckResult
 	}
-	checkResult = check.Check(ctx, throttlerapp.VitessName.String(), storeType, storeName, "local", StandardCheckFlags)
+	checkResult = check.Check(ctx, throttlerapp.VitessName.String(), storeType, storeName, base.MetricNames{metricName}, "local", StandardCheckFlags)
 
 	if checkResult.StatusCode == http.StatusOK {
-		check.throttler.markMetricHealthy(metricName)
+		check.throttler.markMetricHealthy(aggregatedMetricName)
 	}
-	if timeSinceHealthy, found := check.throttler.timeSinceMetricHealthy(metricName); found {
-		stats.GetOrNewGauge(fmt.Sprintf("ThrottlerCheck%s%sSecondsSinceHealthy", textutil.SingleWordCamel(storeType), textutil.SingleWordCamel(storeName)), fmt.Sprintf("seconds since last healthy cehck for %s.%s", storeType, storeName)).Set(int64(timeSinceHealthy.Seconds()))
+	if timeSinceHealthy, found := check.throttler.timeSinceMetricHealthy(aggregatedMetricName); found {
+		statsName, _ := check.metricStatsName(aggregatedMetricName)
+		stats.GetOrNewGauge(fmt.Sprintf("ThrottlerCheck%sSecondsSinceHealthy", statsName), fmt.Sprintf("seconds since last healthy cehck for %s", aggregatedMetricName)).Set(int64(timeSinceHealthy.Seconds()))
 	}
 
 	return checkResult
 }
 
-func (check *ThrottlerCheck) reportAggregated(metricName string, metricResult base.MetricResult) {
-	storeType, storeName, err := check.splitMetricTokens(metricName)
+func (check *ThrottlerCheck) reportAggregated(aggregatedMetricName string, metricResult base.MetricResult) {
+	statsName, err := check.metricStatsName(aggregatedMetricName)
 	if err != nil {
 		return
 	}
 	if value, err := metricResult.Get(); err == nil {
-		stats.GetOrNewGaugeFloat64(fmt.Sprintf("ThrottlerAggregated%s%s", textutil.SingleWordCamel(storeType), textutil.SingleWordCamel(storeName)), fmt.Sprintf("aggregated value for %s.%s", storeType, storeName)).Set(value)
+		stats.GetOrNewGaugeFloat64(fmt.Sprintf("ThrottlerAggregated%s", statsName), fmt.Sprintf("aggregated value for %s", aggregatedMetricName)).Set(value)
 	}
 }
 
@@ -55,6 +55,13 @@ type CheckResult struct {
 	Error           error   `json:"-"`
 	Message         string  `json:"Message"`
 	RecentlyChecked bool    `json:"RecentlyChecked"`
+	// MetricName is the metric this result is for
+	MetricName string `json:"MetricName,omitempty"`
+	// Metrics has the results of all checked metrics, by metric name
+	Metrics map[string]*CheckResult `json:"Metrics,omitempty"`
+	// DryRunStatusCode is the status code the check would have responded with, had the app not been
+	// in dry-run mode. It is only set for apps in dry-run mode.
+	DryRunStatusCode int `json:"DryRunStatusCode,omitempty"`
 }
 
 // NewCheckResult returns a CheckResult
@@ -71,6 +78,23 @@ func NewCheckResult(statusCode int, value float64, threshold float64, err error)
 	return result
 }
 
+// withMetrics returns a copy of this result, with the results of all checked metrics
+func (result *CheckResult) withMetrics(metrics map[string]*CheckResult) *CheckResult {
+	resultWithMetrics := *result
+	resultWithMetrics.Metrics = metrics
+	return &resultWithMetrics
+}
+
+// asDryRun returns a copy of this result that responds with OK, keeping the status code it would have
+// responded with in DryRunStatusCode
+func (result *CheckResult) asDryRun() *CheckResult {
+	dryRunResult := *result
+	dryRunResult.DryRunStatusCode = result.StatusCode
+	dryRunResult.StatusCode = http.StatusOK
+	dryRunResult.Error = nil
+	return &dryRunResult
+}
+
 // NewErrorCheckResult returns a check result that indicates an error
 func NewErrorCheckResult(statusCode int, err error) *CheckResult {
 	return NewCheckResult(statusCode, 0, 0, err)
@@ -49,6 +49,7 @@ import (
 	"github.com/patrickmn/go-cache"
 
 	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/base"
 )
 
 // MetricsQueryType indicates the type of metrics query on MySQL backend. See following.
@@ -67,29 +68,41 @@ const (
 
 var mysqlMetricCache = cache.New(cache.NoExpiration, 10*time.Second)
 
-func getMySQLMetricCacheKey(probe *Probe) string {
-	return fmt.Sprintf("%s:%s", probe.Alias, probe.MetricQuery)
+func getMySQLMetricCacheKey(probe *Probe, metricName base.MetricName) string {
+	return fmt.Sprintf("%s:%s:%s", probe.Alias, probe.MetricQuery, metricName)
 }
 
-func cacheMySQLThrottleMetric(probe *Probe, mySQLThrottleMetric *MySQLThrottleMetric) *MySQLThrottleMetric {
-	if mySQLThrottleMetric.Err != nil {
-		return mySQLThrottleMetric
+// cacheMySQLThrottleMetrics caches each of the metrics that were read without error. A metric
+// that failed does not prevent the others from being cached.
+func cacheMySQLThrottleMetrics(probe *Probe, mySQLThrottleMetrics MySQLThrottleMetrics) MySQLThrottleMetrics {
+	if probe.CacheMillis == 0 {
+		return mySQLThrottleMetrics
 	}
-	if probe.CacheMillis > 0 {
-		mysqlMetricCache.Set(getMySQLMetricCacheKey(probe), mySQLThrottleMetric, time.Duration(probe.CacheMillis)*time.Millisecond)
+	for metricName, metric := range mySQLThrottleMetrics {
+		if metric.Err != nil {
+			continue
+		}
+		mysqlMetricCache.Set(getMySQLMetricCacheKey(probe, metricName), metric, time.Duration(probe.CacheMillis)*time.Millisecond)
 	}
-	return mySQLThrottleMetric
+	return mySQLThrottleMetrics
 }
 
-func getCachedMySQLThrottleMetric(probe *Probe) *MySQLThrottleMetric {
+// getCachedMySQLThrottleMetrics returns the cached metrics among the given ones, and the names of those
+// that are not cached.
+func getCachedMySQLThrottleMetrics(probe *Probe, metricNames base.MetricNames) (MySQLThrottleMetrics, base.MetricNames) {
 	if probe.CacheMillis == 0 {
-		return nil
+		return nil, metricNames
 	}
-	if metric, found := mysqlMetricCache.Get(getMySQLMetricCacheKey(probe)); found {
-		mySQLThrottleMetric, _ := metric.(*MySQLThrottleMetric)
-		return mySQLThrottleMetric
+	cachedMetrics := make(MySQLThrottleMetrics)
+	var uncachedMetricNames base.MetricNames
+	for _, metricName := range metricNames {
+		if metric, found := mysqlMetricCache.Get(getMySQLMetricCacheKey(probe, metricName)); found {
+			cachedMetrics[metricName], _ = metric.(*MySQLThrottleMetric)
+			continue
+		}
+		uncachedMetricNames = append(uncachedMetricNames, metricName)
 	}
-	return nil
+	return cachedMetrics, uncachedMetricNames
 }
 
 // GetMetricsQueryType analyzes the type of a metrics query
@@ -108,12 +121,16 @@ func GetMetricsQueryType(query string) MetricsQueryType {
 
 // MySQLThrottleMetric has the probed metric for a tablet
 type MySQLThrottleMetric struct { // nolint:revive
+	Name        base.MetricName
 	ClusterName string
 	Alias       string
 	Value       float64
 	Err         error
 }
 
+// MySQLThrottleMetrics has the probed metrics for a tablet, by metric name
+type MySQLThrottleMetrics map[base.MetricName]*MySQLThrottleMetric // nolint:revive
+
 // NewMySQLThrottleMetric creates a new MySQLThrottleMetric
 func NewMySQLThrottleMetric() *MySQLThrottleMetric {
 	return &MySQLThrottleMetric{Value: 0}
@@ -129,29 +146,43 @@ func (metric *MySQLThrottleMetric) Get() (float64, error) {
 	return metric.Value, metric.Err
 }
 
-// ReadThrottleMetric returns a metric for the given probe. Either by explicit query
-// or via SHOW REPLICA STATUS
-func ReadThrottleMetric(probe *Probe, clusterName string, overrideGetMetricFunc func() *MySQLThrottleMetric) (mySQLThrottleMetric *MySQLThrottleMetric) {
-	if mySQLThrottleMetric := getCachedMySQLThrottleMetric(probe); mySQLThrottleMetric != nil {
-		return mySQLThrottleMetric
+// ReadThrottleMetrics returns the given metrics for the given probe. Either by explicit queries
+// or via a CheckThrottler request to the probed tablet. Only the metrics that are not cached are read.
+func ReadThrottleMetrics(probe *Probe, clusterName string, metricNames base.MetricNames, overrideGetMetricsFunc func(base.MetricNames) MySQLThrottleMetrics) (mySQLThrottleMetrics MySQLThrottleMetrics) {
+	cachedMetrics, uncachedMetricNames := getCachedMySQLThrottleMetrics(probe, metricNames)
+	if len(uncachedMetricNames) == 0 {
+		return cachedMetrics
 		// On cached results we avoid taking latency metrics
 	}
 
 	started := time.Now()
-	mySQLThrottleMetric = NewMySQLThrottleMetric()
-	mySQLThrottleMetric.ClusterName = clusterName
-	mySQLThrottleMetric.Alias = probe.Alias
-
-	defer func(metric *MySQLThrottleMetric, started time.Time) {
+	defer func(started time.Time) {
 		go func() {
 			stats.GetOrNewGauge("ThrottlerProbesLatency", "probes latency").Set(time.Since(started).Nanoseconds())
 			stats.GetOrNewCounter("ThrottlerProbesTotal", "total probes").Add(1)
-			if metric.Err != nil {
-				stats.GetOrNewCounter("ThrottlerProbesError", "total probes errors").Add(1)
+			for _, metric := range mySQLThrottleMetrics {
+				if metric.Err != nil {
+					stats.GetOrNewCounter("ThrottlerProbesError", "total probes errors").Add(1)
+					break
+				}
 			}
 		}()
-	}(mySQLThrottleMetric, started)
+	}(started)
 
-	mySQLThrottleMetric = overrideGetMetricFunc()
-	return cacheMySQLThrottleMetric(probe, mySQLThrottleMetric)
+	mySQLThrottleMetrics = overrideGetMetricsFunc(uncachedMetricNames)
+	for name, metric := range mySQLThrottleMetrics {
+		metric.Name = name
+		metric.ClusterName = clusterName
+		metric.Alias = probe.Alias
+	}
+	cacheMySQLThrottleMetrics(probe, mySQLThrottleMetrics)
+	if mySQLThrottleMetrics == nil {
+		mySQLThrottleMetrics = make(MySQLThrottleMetrics)
+	}
+	for name, metric := range cachedMetrics {
+		if _, ok := mySQLThrottleMetrics[name]; !ok {
+			mySQLThrottleMetrics[name] = metric
+		}
+	}
+	return mySQLThrottleMetrics
 }
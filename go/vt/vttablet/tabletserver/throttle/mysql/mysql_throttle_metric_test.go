@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/base"
+)
+
+func TestReadThrottleMetricsCache(t *testing.T) {
+	probe := &Probe{Alias: "zone1-0000000100", CacheMillis: 60000}
+	metricNames := base.MetricNames{base.LagMetricName, base.ThreadsRunningMetricName}
+
+	var probed []base.MetricNames
+	failLag := true
+	readMetrics := func(metricNames base.MetricNames) MySQLThrottleMetrics {
+		probed = append(probed, metricNames)
+		metrics := make(MySQLThrottleMetrics)
+		for _, metricName := range metricNames {
+			metric := &MySQLThrottleMetric{Value: 1}
+			if metricName == base.LagMetricName && failLag {
+				metric.Err = errors.New("lag failure")
+			}
+			metrics[metricName] = metric
+		}
+		return metrics
+	}
+
+	metrics := ReadThrottleMetrics(probe, "cluster", metricNames, readMetrics)
+	require.Len(t, metrics, 2)
+	assert.Error(t, metrics[base.LagMetricName].Err)
+	assert.NoError(t, metrics[base.ThreadsRunningMetricName].Err)
+	assert.Equal(t, "cluster", metrics[base.ThreadsRunningMetricName].ClusterName)
+	assert.Equal(t, probe.Alias, metrics[base.ThreadsRunningMetricName].Alias)
+
+	// The failed metric is probed again, while the other one is read from the cache:
+	failLag = false
+	metrics = ReadThrottleMetrics(probe, "cluster", metricNames, readMetrics)
+	require.Len(t, metrics, 2)
+	assert.NoError(t, metrics[base.LagMetricName].Err)
+	assert.NoError(t, metrics[base.ThreadsRunningMetricName].Err)
+
+	// Both metrics are now cached:
+	metrics = ReadThrottleMetrics(probe, "cluster", metricNames, readMetrics)
+	require.Len(t, metrics, 2)
+
+	assert.Equal(t, []base.MetricNames{
+		{base.LagMetricName, base.ThreadsRunningMetricName},
+		{base.LagMetricName},
+	}, probed)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/constants/sidecar"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/base"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/mysql"
+)
+
+const (
+	threadsRunningQuery    = "show global status like 'threads_running'"
+	historyListLengthQuery = "select count as history_len from information_schema.innodb_metrics where name = 'trx_rseg_history_len'"
+
+	loadAvgFile = "/proc/loadavg"
+)
+
+// defaultMetricName is the metric checked when an app has no assigned metrics. It is the custom
+// metric if a custom query or a custom metric URL is configured, and replication lag otherwise. It is also the metric
+// whose threshold is the throttler config's threshold.
+func (throttler *Throttler) defaultMetricName() base.MetricName {
+	if throttler.hasCustomMetric() {
+		return base.CustomMetricName
+	}
+	return base.LagMetricName
+}
+
+// collectedMetricNames returns the metrics collected by this throttler, starting with the default metric.
+// The custom metric is only collected when a custom query or a custom metric URL is configured.
+func (throttler *Throttler) collectedMetricNames() base.MetricNames {
+	metricNames := base.MetricNames{throttler.defaultMetricName()}
+	for _, metricName := range base.KnownMetricNames {
+		if metricName == base.CustomMetricName && !throttler.hasCustomMetric() {
+			continue
+		}
+		if !metricNames.Contains(metricName) {
+			metricNames = append(metricNames, metricName)
+		}
+	}
+	return metricNames
+}
+
+// inUseMetricNames returns the collected metrics that are checked by some app, starting with the default
+// metric, which apps with no assigned metrics check. Only these metrics are probed and aggregated.
+func (throttler *Throttler) inUseMetricNames() base.MetricNames {
+	assignedMetricNames := throttler.appCheckedMetricsSnapshot()
+	isAssigned := func(metricName base.MetricName) bool {
+		for _, metricNames := range assignedMetricNames {
+			if metricNames.Contains(metricName) {
+				return true
+			}
+		}
+		return false
+	}
+	metricNames := base.MetricNames{throttler.defaultMetricName()}
+	for _, metricName := range throttler.collectedMetricNames() {
+		if isAssigned(metricName) && !metricNames.Contains(metricName) {
+			metricNames = append(metricNames, metricName)
+		}
+	}
+	return metricNames
+}
+
+// readSelfMySQLThrottleMetrics reads the given metrics from this very tablet's backend mysql and host.
+func (throttler *Throttler) readSelfMySQLThrottleMetrics(ctx context.Context, probe *mysql.Probe, metricNames base.MetricNames) mysql.MySQLThrottleMetrics {
+	metrics := make(mysql.MySQLThrottleMetrics)
+	for _, metricName := range metricNames {
+		metric := &mysql.MySQLThrottleMetric{
+			Name:        metricName,
+			ClusterName: selfStoreName,
+		}
+		source, err := throttler.selfMetricSource(metricName)
+		if err != nil {
+			metric.Err = err
+		} else {
+			metric.Value, metric.Err = source.Read(ctx)
+		}
+		metrics[metricName] = metric
+	}
+	return metrics
+}
+
+// selfMetricSource returns the source of a metric collected from this very tablet's backend mysql and host.
+func (throttler *Throttler) selfMetricSource(metricName base.MetricName) (base.MetricSource, error) {
+	switch metricName {
+	case base.LagMetricName:
+		return throttler.selfMySQLQueryMetricSource(sqlparser.BuildParsedQuery(defaultReplicationLagQuery, sidecar.GetIdentifier()).Query), nil
+	case base.ThreadsRunningMetricName:
+		return throttler.selfMySQLQueryMetricSource(threadsRunningQuery), nil
+	case base.HistoryListLengthMetricName:
+		return throttler.selfMySQLQueryMetricSource(historyListLengthQuery), nil
+	case base.LoadAvgMetricName:
+		return base.MetricSourceFunc(func(ctx context.Context) (float64, error) {
+			return readSelfLoadAvgPerCPU()
+		}), nil
+	case base.CustomMetricName:
+		// The custom metric is read from a local HTTP endpoint, e.g. that of a node-level agent, when a
+		// custom metric URL is configured, and from mysql otherwise.
+		if customMetricURL := throttler.GetCustomMetricURL(); customMetricURL != "" {
+			return base.NewMetricSourceFromURL(customMetricURL, throttler.httpClient)
+		}
+		return throttler.selfMySQLQueryMetricSource(throttler.GetCustomQuery()), nil
+	}
+	return nil, base.ErrNoSuchMetric
+}
+
+// selfMySQLQueryMetricSource returns a source that runs the given metric query on this very tablet's backend mysql.
+func (throttler *Throttler) selfMySQLQueryMetricSource(query string) base.MetricSource {
+	return base.MetricSourceFunc(func(ctx context.Context) (float64, error) {
+		return throttler.readSelfMySQLQueryMetric(ctx, query)
+	})
+}
+
+// readSelfMySQLQueryMetric runs a metric query on this very tablet's backend mysql. The query is either
+// a single row, single column SELECT, or a SHOW GLOBAL STATUS/VARIABLES query.
+func (throttler *Throttler) readSelfMySQLQueryMetric(ctx context.Context, query string) (float64, error) {
+	conn, err := throttler.pool.Get(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Recycle()
+
+	tm, err := conn.Conn.Exec(ctx, query, 1, true)
+	if err != nil {
+		return 0, err
+	}
+	row := tm.Named().Row()
+	if row == nil {
+		return 0, fmt.Errorf("no results for readSelfMySQLQueryMetric")
+	}
+
+	switch mysql.GetMetricsQueryType(query) {
+	case mysql.MetricsQueryTypeSelect:
+		// We expect a single row, single column result.
+		// The "for" iteration below is just a way to get first result without knowing column name
+		for k := range row {
+			return row.ToFloat64(k)
+		}
+		return 0, fmt.Errorf("no columns for readSelfMySQLQueryMetric")
+	case mysql.MetricsQueryTypeShowGlobal:
+		return strconv.ParseFloat(row["Value"].ToString(), 64)
+	default:
+		return 0, fmt.Errorf("Unsupported metrics query type for query: %s", query)
+	}
+}
+
+// readSelfLoadAvgPerCPU returns the 1 minute load average of this host, divided by the number of CPUs.
+func readSelfLoadAvgPerCPU() (float64, error) {
+	content, err := os.ReadFile(loadAvgFile)
+	if err != nil {
+		return 0, err
+	}
+	return parseLoadAvgPerCPU(string(content), runtime.NumCPU())
+}
+
+// parseLoadAvgPerCPU parses the content of /proc/loadavg, e.g. "0.55 0.42 0.35 1/543 12345"
+func parseLoadAvgPerCPU(content string, numCPU int) (float64, error) {
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("unexpected content in %s: %q", loadAvgFile, content)
+	}
+	loadAvg, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, err
+	}
+	return loadAvg / float64(max(numCPU, 1)), nil
+}
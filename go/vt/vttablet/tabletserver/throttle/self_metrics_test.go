@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/base"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestParseLoadAvgPerCPU(t *testing.T) {
+	loadAvg, err := parseLoadAvgPerCPU("3.20 0.42 0.35 1/543 12345\n", 4)
+	require.NoError(t, err)
+	assert.Equal(t, 0.8, loadAvg)
+
+	loadAvg, err = parseLoadAvgPerCPU("0.5 0.42 0.35 1/543 12345\n", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, loadAvg)
+
+	_, err = parseLoadAvgPerCPU("", 4)
+	assert.Error(t, err)
+	_, err = parseLoadAvgPerCPU("abc 0.42", 4)
+	assert.Error(t, err)
+}
+
+func TestCollectedMetricNames(t *testing.T) {
+	throttler := newTestThrottler()
+	assert.Equal(t, "lag,threads_running,history_list_length,loadavg", throttler.collectedMetricNames().String())
+	assert.Equal(t, "mysql/self", throttler.aggregatedMetricName(selfStoreName, "lag"))
+	assert.Equal(t, "mysql/shard/loadavg", throttler.aggregatedMetricName(shardStoreName, "loadavg"))
+
+	throttler.customQuery.Store("select 1")
+	assert.Equal(t, "custom,lag,threads_running,history_list_length,loadavg", throttler.collectedMetricNames().String())
+	assert.Equal(t, "mysql/self", throttler.aggregatedMetricName(selfStoreName, "custom"))
+	assert.Equal(t, "mysql/self/lag", throttler.aggregatedMetricName(selfStoreName, "lag"))
+
+	throttler.customQuery.Store("")
+	throttler.customMetricURL.Store("http://localhost:9100/metrics#node_load1")
+	assert.Equal(t, "custom,lag,threads_running,history_list_length,loadavg", throttler.collectedMetricNames().String())
+}
+
+func TestInUseMetricNames(t *testing.T) {
+	throttler := newTestThrottler()
+	assert.Equal(t, "lag", throttler.inUseMetricNames().String())
+
+	throttler.applyAppCheckedMetrics(&topodatapb.ThrottlerConfig{
+		AppCheckedMetrics: map[string]*topodatapb.ThrottlerConfig_MetricNames{
+			"app1": {Names: []string{"loadavg", "lag"}},
+			"app2": {Names: []string{"history_list_length", "custom"}},
+		},
+	})
+	// The custom metric is not collected, and so is not in use:
+	assert.Equal(t, "lag,history_list_length,loadavg", throttler.inUseMetricNames().String())
+
+	throttler.customQuery.Store("select 1")
+	assert.Equal(t, "custom,lag,history_list_length,loadavg", throttler.inUseMetricNames().String())
+}
+
+func TestSelfCustomMetricSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "# TYPE node_load1 gauge")
+		fmt.Fprintln(w, "node_load1 1.5")
+	}))
+	defer server.Close()
+
+	throttler := newTestThrottler()
+	throttler.customMetricURL.Store(server.URL + "/metrics#node_load1")
+	source, err := throttler.selfMetricSource(base.CustomMetricName)
+	require.NoError(t, err)
+	value, err := source.Read(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, value)
+
+	throttler.customMetricURL.Store("http://localhost:9100/metrics#{}")
+	_, err = throttler.selfMetricSource(base.CustomMetricName)
+	assert.Error(t, err)
+
+	throttler.customMetricURL.Store("http://10.0.0.1:9100/metrics#node_load1")
+	_, err = throttler.selfMetricSource(base.CustomMetricName)
+	assert.ErrorContains(t, err, "not a loopback address")
+
+	_, err = throttler.selfMetricSource("unknown")
+	assert.ErrorIs(t, err, base.ErrNoSuchMetric)
+}
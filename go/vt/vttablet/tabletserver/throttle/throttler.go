@@ -48,7 +48,6 @@ import (
 	"math"
 	"math/rand/v2"
 	"net/http"
-	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -182,7 +181,7 @@ type Throttler struct {
 
 	throttleTabletTypesMap map[topodatapb.TabletType]bool
 
-	mysqlThrottleMetricChan chan *mysql.MySQLThrottleMetric
+	mysqlThrottleMetricChan chan mysql.MySQLThrottleMetrics
 	mysqlInventoryChan      chan *mysql.Inventory
 	mysqlClusterProbesChan  chan *mysql.ClusterProbes
 	throttlerConfigChan     chan *topodatapb.ThrottlerConfig
@@ -190,10 +189,14 @@ type Throttler struct {
 	mysqlInventory *mysql.Inventory
 
 	metricsQuery     atomic.Value
+	customQuery      atomic.Value
+	customMetricURL  atomic.Value
 	MetricsThreshold atomic.Uint64
 	checkAsCheckSelf atomic.Bool
 
 	mysqlClusterThresholds *cache.Cache
+	metricThresholds       *cache.Cache
+	appCheckedMetrics      *cache.Cache
 	aggregatedMetrics      *cache.Cache
 	throttledApps          *cache.Cache
 	recentApps             *cache.Cache
@@ -205,7 +208,7 @@ type Throttler struct {
 	cancelEnableContext context.CancelFunc
 	throttledAppsMutex  sync.Mutex
 
-	readSelfThrottleMetric func(context.Context, *mysql.Probe) *mysql.MySQLThrottleMetric // overwritten by unit test
+	readSelfThrottleMetrics func(context.Context, *mysql.Probe, base.MetricNames) mysql.MySQLThrottleMetrics // overwritten by unit test
 
 	nonLowPriorityAppRequestsThrottled *cache.Cache
 	httpClient                         *http.Client
@@ -224,8 +227,13 @@ type ThrottlerStatus struct {
 	Query     string
 	Threshold float64
 
+	MetricsThresholds map[base.MetricName]float64
+	AppCheckedMetrics map[string]base.MetricNames
+
 	AggregatedMetrics map[string]base.MetricResult
 	MetricsHealth     base.MetricHealthMap
+
+	RecentApps map[string]*base.RecentApp
 }
 
 // NewThrottler creates a Throttler
@@ -243,7 +251,7 @@ func NewThrottler(env tabletenv.Env, srvTopoServer srvtopo.Server, ts *topo.Serv
 		}),
 	}
 
-	throttler.mysqlThrottleMetricChan = make(chan *mysql.MySQLThrottleMetric)
+	throttler.mysqlThrottleMetricChan = make(chan mysql.MySQLThrottleMetrics)
 	throttler.mysqlInventoryChan = make(chan *mysql.Inventory, 1)
 	throttler.mysqlClusterProbesChan = make(chan *mysql.ClusterProbes)
 	throttler.throttlerConfigChan = make(chan *topodatapb.ThrottlerConfig)
@@ -251,6 +259,8 @@ func NewThrottler(env tabletenv.Env, srvTopoServer srvtopo.Server, ts *topo.Serv
 
 	throttler.throttledApps = cache.New(cache.NoExpiration, 0)
 	throttler.mysqlClusterThresholds = cache.New(cache.NoExpiration, 0)
+	throttler.metricThresholds = cache.New(cache.NoExpiration, 0)
+	throttler.appCheckedMetrics = cache.New(cache.NoExpiration, 0)
 	throttler.aggregatedMetrics = cache.New(aggregatedMetricsExpiration, 0)
 	throttler.recentApps = cache.New(recentAppsExpiration, 0)
 	throttler.metricsHealth = cache.New(cache.NoExpiration, 0)
@@ -270,8 +280,8 @@ func NewThrottler(env tabletenv.Env, srvTopoServer srvtopo.Server, ts *topo.Serv
 	throttler.recentCheckDormantDiff = int64(throttler.dormantPeriod / recentCheckRateLimiterInterval)
 
 	throttler.StoreMetricsThreshold(defaultThrottleLagThreshold.Seconds()) //default
-	throttler.readSelfThrottleMetric = func(ctx context.Context, p *mysql.Probe) *mysql.MySQLThrottleMetric {
-		return throttler.readSelfMySQLThrottleMetric(ctx, p)
+	throttler.readSelfThrottleMetrics = func(ctx context.Context, p *mysql.Probe, metricNames base.MetricNames) mysql.MySQLThrottleMetrics {
+		return throttler.readSelfMySQLThrottleMetrics(ctx, p, metricNames)
 	}
 
 	return throttler
@@ -307,10 +317,37 @@ func (throttler *Throttler) GetMetricsQuery() string {
 	return throttler.metricsQuery.Load().(string)
 }
 
+// GetCustomQuery returns the custom metric query of the throttler config, or an empty string if there is none
+func (throttler *Throttler) GetCustomQuery() string {
+	customQuery, _ := throttler.customQuery.Load().(string)
+	return customQuery
+}
+
+// GetCustomMetricURL returns the URL of the local HTTP endpoint the custom metric is read from, or an empty
+// string if the custom metric is not read over HTTP
+func (throttler *Throttler) GetCustomMetricURL() string {
+	customMetricURL, _ := throttler.customMetricURL.Load().(string)
+	return customMetricURL
+}
+
+// hasCustomMetric returns true if the throttler config has a custom metric, read either with a query or from a URL
+func (throttler *Throttler) hasCustomMetric() bool {
+	return throttler.GetCustomQuery() != "" || throttler.GetCustomMetricURL() != ""
+}
+
 func (throttler *Throttler) GetMetricsThreshold() float64 {
 	return math.Float64frombits(throttler.MetricsThreshold.Load())
 }
 
+// getMetricThreshold returns the threshold of the given metric
+func (throttler *Throttler) getMetricThreshold(metricName base.MetricName) float64 {
+	if thresholdVal, found := throttler.metricThresholds.Get(metricName.String()); found {
+		threshold, _ := thresholdVal.(float64)
+		return threshold
+	}
+	return metricName.DefaultThreshold()
+}
+
 // initThrottler initializes config
 func (throttler *Throttler) initConfig() {
 	log.Infof("Throttler: initializing config")
@@ -352,8 +389,8 @@ func (throttler *Throttler) normalizeThrottlerConfig(throttlerConfig *topodatapb
 	if throttlerConfig.ThrottledApps == nil {
 		throttlerConfig.ThrottledApps = make(map[string]*topodatapb.ThrottledAppRule)
 	}
-	if throttlerConfig.CustomQuery == "" {
-		// no custom query; we check replication lag
+	if throttlerConfig.CustomQuery == "" && throttlerConfig.CustomMetricUrl == "" {
+		// no custom metric; we check replication lag
 		if throttlerConfig.Threshold == 0 {
 			throttlerConfig.Threshold = defaultThrottleLagThreshold.Seconds()
 		}
@@ -396,10 +433,14 @@ func (throttler *Throttler) applyThrottlerConfig(ctx context.Context, throttlerC
 	} else {
 		throttler.metricsQuery.Store(throttlerConfig.CustomQuery)
 	}
-	throttler.StoreMetricsThreshold(throttlerConfig.Threshold)
+	throttler.customQuery.Store(throttlerConfig.CustomQuery)
+	throttler.customMetricURL.Store(throttlerConfig.CustomMetricUrl)
+	throttler.applyMetricThresholds(throttlerConfig)
+	throttler.StoreMetricsThreshold(throttler.getMetricThreshold(throttler.defaultMetricName()))
+	throttler.applyAppCheckedMetrics(throttlerConfig)
 	throttler.checkAsCheckSelf.Store(throttlerConfig.CheckAsCheckSelf)
 	for _, appRule := range throttlerConfig.ThrottledApps {
-		throttler.ThrottleApp(appRule.Name, protoutil.TimeFromProto(appRule.ExpiresAt).UTC(), appRule.Ratio, appRule.Exempt)
+		throttler.ThrottleApp(appRule.Name, protoutil.TimeFromProto(appRule.ExpiresAt).UTC(), appRule.Ratio, appRule.Exempt, appRule.DryRun)
 	}
 	if throttlerConfig.Enabled {
 		go throttler.Enable()
@@ -408,6 +449,96 @@ func (throttler *Throttler) applyThrottlerConfig(ctx context.Context, throttlerC
 	}
 }
 
+// applyMetricThresholds sets the threshold of each known metric. The default metric's threshold is the
+// config's threshold, and other metrics use their default threshold, unless explicitly configured.
+func (throttler *Throttler) applyMetricThresholds(throttlerConfig *topodatapb.ThrottlerConfig) {
+	defaultMetricName := throttler.defaultMetricName()
+	for _, metricName := range base.KnownMetricNames {
+		threshold := metricName.DefaultThreshold()
+		if metricName == defaultMetricName {
+			threshold = throttlerConfig.Threshold
+		}
+		if configThreshold, ok := throttlerConfig.MetricThresholds[metricName.String()]; ok {
+			threshold = configThreshold
+		}
+		throttler.metricThresholds.Set(metricName.String(), threshold, cache.DefaultExpiration)
+	}
+}
+
+// applyAppCheckedMetrics sets the metrics checked by each app, and removes the apps that are
+// no longer in the config.
+func (throttler *Throttler) applyAppCheckedMetrics(throttlerConfig *topodatapb.ThrottlerConfig) {
+	for appName, appMetrics := range throttlerConfig.AppCheckedMetrics {
+		metricNames, err := base.ParseMetricNames(appMetrics.GetNames())
+		if err != nil {
+			log.Errorf("Throttler: invalid metrics for app %v: %v", appName, err)
+			continue
+		}
+		throttler.appCheckedMetrics.Set(appName, metricNames, cache.DefaultExpiration)
+	}
+	for appName := range throttler.appCheckedMetrics.Items() {
+		if _, ok := throttlerConfig.AppCheckedMetrics[appName]; !ok {
+			throttler.appCheckedMetrics.Delete(appName)
+		}
+	}
+}
+
+// appCheckedMetricNames returns the metrics checked for the given app. These are the metrics assigned
+// to the app, or to any of the ':' separated parts of its name. An app with no assigned metrics checks
+// the metrics assigned to the "all" app, or else the default metric. The "vitess" app, used by the
+// throttler itself, checks all the metrics in use. Metrics that are not collected are never checked.
+func (throttler *Throttler) appCheckedMetricNames(appName string) base.MetricNames {
+	if throttlerapp.VitessName.Equals(appName) {
+		return throttler.inUseMetricNames()
+	}
+	collectedMetricNames := throttler.collectedMetricNames()
+	var metricNames base.MetricNames
+	addAppMetricNames := func(singleAppName string) {
+		object, found := throttler.appCheckedMetrics.Get(singleAppName)
+		if !found {
+			return
+		}
+		for _, metricName := range object.(base.MetricNames) {
+			if collectedMetricNames.Contains(metricName) && !metricNames.Contains(metricName) {
+				metricNames = append(metricNames, metricName)
+			}
+		}
+	}
+	addAppMetricNames(appName)
+	for _, singleAppName := range strings.Split(appName, ":") {
+		if singleAppName == "" {
+			continue
+		}
+		addAppMetricNames(singleAppName)
+	}
+	if len(metricNames) == 0 {
+		addAppMetricNames(throttlerapp.AllName.String())
+	}
+	if len(metricNames) == 0 {
+		metricNames = base.MetricNames{throttler.defaultMetricName()}
+	}
+	return metricNames
+}
+
+// appCheckedMetricsSnapshot returns a (copy) map of the metrics assigned to apps
+func (throttler *Throttler) appCheckedMetricsSnapshot() map[string]base.MetricNames {
+	snapshot := make(map[string]base.MetricNames)
+	for appName, item := range throttler.appCheckedMetrics.Items() {
+		metricNames, _ := item.Object.(base.MetricNames)
+		snapshot[appName] = metricNames
+	}
+	return snapshot
+}
+
+// metricThresholdsSnapshot returns a (copy) map of the thresholds of the known metrics
+func (throttler *Throttler) metricThresholdsSnapshot() map[base.MetricName]float64 {
+	snapshot := make(map[base.MetricName]float64)
+	for _, metricName := range base.KnownMetricNames {
+		snapshot[metricName] = throttler.getMetricThreshold(metricName)
+	}
+	return snapshot
+}
+
 func (throttler *Throttler) IsEnabled() bool {
 	return throttler.isEnabled.Load()
 }
@@ -543,7 +674,7 @@ func (throttler *Throttler) Open() error {
 	throttler.initConfig()
 	throttler.pool.Open(throttler.env.Config().DB.AppWithDB(), throttler.env.Config().DB.DbaWithDB(), throttler.env.Config().DB.AppDebugWithDB())
 
-	throttler.ThrottleApp("always-throttled-app", time.Now().Add(time.Hour*24*365*10), DefaultThrottleRatio, false)
+	throttler.ThrottleApp("always-throttled-app", time.Now().Add(time.Hour*24*365*10), DefaultThrottleRatio, false, false)
 
 	go throttler.retryReadAndApplyThrottlerConfig(ctx)
 
@@ -615,56 +746,13 @@ func (throttler *Throttler) stimulatePrimaryThrottler(ctx context.Context, tmCli
 	return nil
 }
 
-func (throttler *Throttler) generateSelfMySQLThrottleMetricFunc(ctx context.Context, probe *mysql.Probe) func() *mysql.MySQLThrottleMetric {
-	f := func() *mysql.MySQLThrottleMetric {
-		return throttler.readSelfThrottleMetric(ctx, probe)
+func (throttler *Throttler) generateSelfMySQLThrottleMetricsFunc(ctx context.Context, probe *mysql.Probe) func(base.MetricNames) mysql.MySQLThrottleMetrics {
+	f := func(metricNames base.MetricNames) mysql.MySQLThrottleMetrics {
+		return throttler.readSelfThrottleMetrics(ctx, probe, metricNames)
 	}
 	return f
 }
 
-// readSelfMySQLThrottleMetric reads the mysql metric from thi very tablet's backend mysql.
-func (throttler *Throttler) readSelfMySQLThrottleMetric(ctx context.Context, probe *mysql.Probe) *mysql.MySQLThrottleMetric {
-	metric := &mysql.MySQLThrottleMetric{
-		ClusterName: selfStoreName,
-		Alias:       "",
-		Value:       0,
-		Err:         nil,
-	}
-	conn, err := throttler.pool.Get(ctx, nil)
-	if err != nil {
-		metric.Err = err
-		return metric
-	}
-	defer conn.Recycle()
-
-	tm, err := conn.Conn.Exec(ctx, probe.MetricQuery, 1, true)
-	if err != nil {
-		metric.Err = err
-		return metric
-	}
-	row := tm.Named().Row()
-	if row == nil {
-		metric.Err = fmt.Errorf("no results for readSelfMySQLThrottleMetric")
-		return metric
-	}
-
-	metricsQueryType := mysql.GetMetricsQueryType(throttler.GetMetricsQuery())
-	switch metricsQueryType {
-	case mysql.MetricsQueryTypeSelect:
-		// We expect a single row, single column result.
-		// The "for" iteration below is just a way to get first result without knowing column name
-		for k := range row {
-			metric.Value, metric.Err = row.ToFloat64(k)
-		}
-	case mysql.MetricsQueryTypeShowGlobal:
-		metric.Value, metric.Err = strconv.ParseFloat(row["Value"].ToString(), 64)
-	default:
-		metric.Err = fmt.Errorf("Unsupported metrics query type for query: %s", throttler.GetMetricsQuery())
-	}
-
-	return metric
-}
-
 // throttledAppsSnapshot returns a snapshot (a copy) of current throttled apps
 func (throttler *Throttler) throttledAppsSnapshot() map[string]cache.Item {
 	return throttler.throttledApps.Items()
@@ -803,9 +891,9 @@ func (throttler *Throttler) Operate(ctx context.Context, wg *sync.WaitGroup) {
 						})
 					}
 				}
-			case metric := <-throttler.mysqlThrottleMetricChan:
-				// incoming MySQL metric, frequent, as result of collectMySQLMetrics()
-				throttler.mysqlInventory.TabletMetrics[metric.GetClusterTablet()] = metric
+			case metrics := <-throttler.mysqlThrottleMetricChan:
+				// incoming MySQL metrics, frequent, as result of collectMySQLMetrics()
+				throttler.updateMySQLTabletMetrics(metrics)
 			case <-mysqlRefreshTicker.C:
 				// sparse
 				if throttler.IsOpen() {
@@ -829,30 +917,53 @@ func (throttler *Throttler) Operate(ctx context.Context, wg *sync.WaitGroup) {
 	}()
 }
 
-func (throttler *Throttler) generateTabletProbeFunction(ctx context.Context, clusterName string, tmClient tmclient.TabletManagerClient, probe *mysql.Probe) (probeFunc func() *mysql.MySQLThrottleMetric) {
-	return func() *mysql.MySQLThrottleMetric {
+// generateTabletProbeFunction returns a function that probes the metrics of another tablet. The tablet reports
+// all the metrics it checks in a single response, so the function reads them all, whatever the given metrics.
+func (throttler *Throttler) generateTabletProbeFunction(ctx context.Context, clusterName string, tmClient tmclient.TabletManagerClient, probe *mysql.Probe) (probeFunc func(base.MetricNames) mysql.MySQLThrottleMetrics) {
+	return func(base.MetricNames) mysql.MySQLThrottleMetrics {
 		// Some reasonable timeout, to ensure we release connections even if they're hanging (otherwise grpc-go keeps polling those connections forever)
 		ctx, cancel := context.WithTimeout(ctx, 4*mysqlCollectInterval)
 		defer cancel()
 
-		// Hit a tablet's `check-self` via HTTP, and convert its CheckResult JSON output into a MySQLThrottleMetric
-		mySQLThrottleMetric := mysql.NewMySQLThrottleMetric()
-		mySQLThrottleMetric.ClusterName = clusterName
-		mySQLThrottleMetric.Alias = probe.Alias
+		// Hit a tablet's `check-self` via gRPC, and convert its CheckResult output into MySQLThrottleMetrics
+		metrics := make(mysql.MySQLThrottleMetrics)
+		newMetric := func(metricName base.MetricName) *mysql.MySQLThrottleMetric {
+			mySQLThrottleMetric := mysql.NewMySQLThrottleMetric()
+			mySQLThrottleMetric.Name = metricName
+			mySQLThrottleMetric.ClusterName = clusterName
+			mySQLThrottleMetric.Alias = probe.Alias
+			metrics[metricName] = mySQLThrottleMetric
+			return mySQLThrottleMetric
+		}
 
 		if probe.Tablet == nil {
-			mySQLThrottleMetric.Err = fmt.Errorf("found nil tablet reference for alias %v, hostname %v", probe.Alias, probe.Tablet.Hostname)
-			return mySQLThrottleMetric
+			newMetric(throttler.defaultMetricName()).Err = fmt.Errorf("found nil tablet reference for alias %v", probe.Alias)
+			return metrics
 		}
 		req := &tabletmanagerdatapb.CheckThrottlerRequest{} // We leave AppName empty; it will default to VitessName anyway, and we can save some proto space
 		resp, gRPCErr := tmClient.CheckThrottler(ctx, probe.Tablet, req)
 		if gRPCErr != nil {
-			mySQLThrottleMetric.Err = fmt.Errorf("gRPC error accessing tablet %v. Err=%v", probe.Alias, gRPCErr)
-			return mySQLThrottleMetric
+			newMetric(throttler.defaultMetricName()).Err = fmt.Errorf("gRPC error accessing tablet %v. Err=%v", probe.Alias, gRPCErr)
+			return metrics
 		}
-		mySQLThrottleMetric.Value = resp.Value
-		if resp.StatusCode == http.StatusInternalServerError {
-			mySQLThrottleMetric.Err = fmt.Errorf("Status code: %d", resp.StatusCode)
+		if len(resp.Metrics) == 0 {
+			// The tablet does not report metrics by name. Its value is that of the default metric.
+			mySQLThrottleMetric := newMetric(throttler.defaultMetricName())
+			mySQLThrottleMetric.Value = resp.Value
+			if resp.StatusCode == http.StatusInternalServerError {
+				mySQLThrottleMetric.Err = fmt.Errorf("Status code: %d", resp.StatusCode)
+			}
+		}
+		for name, metric := range resp.Metrics {
+			metricName := base.MetricName(name)
+			if !base.KnownMetricNames.Contains(metricName) {
+				continue
+			}
+			mySQLThrottleMetric := newMetric(metricName)
+			mySQLThrottleMetric.Value = metric.Value
+			if metric.StatusCode == http.StatusInternalServerError {
+				mySQLThrottleMetric.Err = fmt.Errorf("Status code: %d", metric.StatusCode)
+			}
 		}
 		if resp.RecentlyChecked {
 			// We have just probed a tablet, and it reported back that someone just recently "check"ed it.
@@ -860,11 +971,13 @@ func (throttler *Throttler) generateTabletProbeFunction(ctx context.Context, clu
 			throttler.requestHeartbeats()
 			statsThrottlerProbeRecentlyChecked.Add(1)
 		}
-		return mySQLThrottleMetric
+		return metrics
 	}
 }
 
 func (throttler *Throttler) collectMySQLMetrics(ctx context.Context, tmClient tmclient.TabletManagerClient, includeCluster func(clusterName string) bool) error {
+	// Only the metrics that some app checks are probed
+	metricNames := throttler.inUseMetricNames()
 	// synchronously, get lists of probes
 	for clusterName, probes := range throttler.mysqlInventory.ClustersProbes {
 		if !includeCluster(clusterName) {
@@ -882,15 +995,15 @@ func (throttler *Throttler) collectMySQLMetrics(ctx context.Context, tmClient tm
 				}
 				defer atomic.StoreInt64(&probe.QueryInProgress, 0)
 
-				var throttleMetricFunc func() *mysql.MySQLThrottleMetric
+				var throttleMetricsFunc func(base.MetricNames) mysql.MySQLThrottleMetrics
 				if clusterName == selfStoreName {
 					// Throttler is probing its own tablet's metrics:
-					throttleMetricFunc = throttler.generateSelfMySQLThrottleMetricFunc(ctx, probe)
+					throttleMetricsFunc = throttler.generateSelfMySQLThrottleMetricsFunc(ctx, probe)
 				} else {
 					// Throttler probing other tablets:
-					throttleMetricFunc = throttler.generateTabletProbeFunction(ctx, clusterName, tmClient, probe)
+					throttleMetricsFunc = throttler.generateTabletProbeFunction(ctx, clusterName, tmClient, probe)
 				}
-				throttleMetrics := mysql.ReadThrottleMetric(probe, clusterName, throttleMetricFunc)
+				throttleMetrics := mysql.ReadThrottleMetrics(probe, clusterName, metricNames, throttleMetricsFunc)
 				select {
 				case <-ctx.Done():
 					return
@@ -1012,14 +1125,49 @@ func (throttler *Throttler) updateMySQLClusterProbes(ctx context.Context, cluste
 	return nil
 }
 
+// synchronous update of a tablet's metrics in the inventory. Metrics which the tablet did not report are removed.
+func (throttler *Throttler) updateMySQLTabletMetrics(metrics mysql.MySQLThrottleMetrics) {
+	if len(metrics) == 0 {
+		return
+	}
+	var clusterTablet mysql.ClusterTablet
+	for _, metric := range metrics {
+		clusterTablet = metric.GetClusterTablet()
+		break
+	}
+	for _, metricName := range base.KnownMetricNames {
+		metric, ok := metrics[metricName]
+		if !ok {
+			delete(throttler.mysqlInventory.TabletMetrics[metricName], clusterTablet)
+			continue
+		}
+		tabletMetrics, ok := throttler.mysqlInventory.TabletMetrics[metricName]
+		if !ok {
+			tabletMetrics = make(mysql.TabletResultMap)
+			throttler.mysqlInventory.TabletMetrics[metricName] = tabletMetrics
+		}
+		tabletMetrics[clusterTablet] = metric
+	}
+}
+
+// aggregatedMetricName returns the name of a cluster's aggregated metric. The default metric
+// is named "mysql/<cluster>", and other metrics are named "mysql/<cluster>/<metric>".
+func (throttler *Throttler) aggregatedMetricName(clusterName string, metricName base.MetricName) string {
+	if metricName == throttler.defaultMetricName() {
+		return fmt.Sprintf("mysql/%s", clusterName)
+	}
+	return fmt.Sprintf("mysql/%s/%s", clusterName, metricName)
+}
+
 // synchronous aggregation of collected data
 func (throttler *Throttler) aggregateMySQLMetrics(ctx context.Context) error {
 	for clusterName, probes := range throttler.mysqlInventory.ClustersProbes {
-		metricName := fmt.Sprintf("mysql/%s", clusterName)
 		ignoreHostsCount := throttler.mysqlInventory.IgnoreHostsCount[clusterName]
 		ignoreHostsThreshold := throttler.mysqlInventory.IgnoreHostsThreshold[clusterName]
-		aggregatedMetric := aggregateMySQLProbes(ctx, probes, clusterName, throttler.mysqlInventory.TabletMetrics, ignoreHostsCount, throttler.configSettings.Stores.MySQL.IgnoreDialTCPErrors, ignoreHostsThreshold)
-		throttler.aggregatedMetrics.Set(metricName, aggregatedMetric, cache.DefaultExpiration)
+		for _, metricName := range throttler.inUseMetricNames() {
+			aggregatedMetric := aggregateMySQLProbes(ctx, probes, clusterName, throttler.mysqlInventory.TabletMetrics[metricName], ignoreHostsCount, throttler.configSettings.Stores.MySQL.IgnoreDialTCPErrors, ignoreHostsThreshold)
+			throttler.aggregatedMetrics.Set(throttler.aggregatedMetricName(clusterName, metricName), aggregatedMetric, cache.DefaultExpiration)
+		}
 	}
 	return nil
 }
@@ -1031,11 +1179,13 @@ func (throttler *Throttler) getNamedMetric(metricName string) base.MetricResult
 	return base.NoSuchMetric
 }
 
-func (throttler *Throttler) getMySQLClusterMetrics(ctx context.Context, clusterName string) (base.MetricResult, float64) {
+func (throttler *Throttler) getMySQLClusterMetrics(ctx context.Context, clusterName string, metricName base.MetricName) (base.MetricResult, float64) {
 	if thresholdVal, found := throttler.mysqlClusterThresholds.Get(clusterName); found {
 		threshold, _ := thresholdVal.(float64)
-		metricName := fmt.Sprintf("mysql/%s", clusterName)
-		return throttler.getNamedMetric(metricName), threshold
+		if metricName != throttler.defaultMetricName() {
+			threshold = throttler.getMetricThreshold(metricName)
+		}
+		return throttler.getNamedMetric(throttler.aggregatedMetricName(clusterName, metricName)), threshold
 	}
 
 	return base.NoSuchMetric, 0
@@ -1061,7 +1211,8 @@ func (throttler *Throttler) expireThrottledApps() {
 }
 
 // ThrottleApp instructs the throttler to begin throttling an app, to some period and with some ratio.
-func (throttler *Throttler) ThrottleApp(appName string, expireAt time.Time, ratio float64, exempt bool) (appThrottle *base.AppThrottle) {
+// An app in dry-run mode has its checks evaluated and recorded, but they always respond with OK.
+func (throttler *Throttler) ThrottleApp(appName string, expireAt time.Time, ratio float64, exempt bool, dryRun bool) (appThrottle *base.AppThrottle) {
 	throttler.throttledAppsMutex.Lock()
 	defer throttler.throttledAppsMutex.Unlock()
 
@@ -1075,6 +1226,7 @@ func (throttler *Throttler) ThrottleApp(appName string, expireAt time.Time, rati
 			appThrottle.Ratio = ratio
 		}
 		appThrottle.Exempt = exempt
+		appThrottle.DryRun = dryRun
 	} else {
 		if expireAt.IsZero() {
 			expireAt = now.Add(DefaultAppThrottleDuration)
@@ -1082,7 +1234,7 @@ func (throttler *Throttler) ThrottleApp(appName string, expireAt time.Time, rati
 		if ratio < 0 {
 			ratio = DefaultThrottleRatio
 		}
-		appThrottle = base.NewAppThrottle(appName, expireAt, ratio, exempt)
+		appThrottle = base.NewAppThrottle(appName, expireAt, ratio, exempt, dryRun)
 	}
 	if now.Before(appThrottle.ExpireAt) {
 		throttler.throttledApps.Set(appName, appThrottle, cache.DefaultExpiration)
@@ -1097,7 +1249,7 @@ func (throttler *Throttler) UnthrottleApp(appName string) (appThrottle *base.App
 	throttler.throttledApps.Delete(appName)
 	// the app is likely to check
 	throttler.requestHeartbeats()
-	return base.NewAppThrottle(appName, time.Now(), 0, false)
+	return base.NewAppThrottle(appName, time.Now(), 0, false, false)
 }
 
 // IsAppThrottled tells whether some app should be throttled.
@@ -1171,6 +1323,39 @@ func (throttler *Throttler) IsAppExempted(appName string) bool {
 	return false
 }
 
+// IsAppDryRun tells whether the checks of some app are in dry-run mode, in which case they are evaluated
+// and recorded, but always respond with OK. A dry-run rule on the "all" app applies to all apps. The
+// "vitess" app, which the throttler uses to collect metrics from its own tablets, is never in dry-run mode.
+func (throttler *Throttler) IsAppDryRun(appName string) bool {
+	if throttlerapp.VitessName.Equals(appName) {
+		return false
+	}
+	isSingleAppNameDryRun := func(singleAppName string) bool {
+		object, found := throttler.throttledApps.Get(singleAppName)
+		if !found {
+			return false
+		}
+		appThrottle := object.(*base.AppThrottle)
+		if !appThrottle.ExpireAt.After(time.Now()) {
+			// throttling cleanup hasn't purged yet, but it is expired
+			return false
+		}
+		return appThrottle.DryRun
+	}
+	if isSingleAppNameDryRun(throttlerapp.AllName.String()) || isSingleAppNameDryRun(appName) {
+		return true
+	}
+	for _, singleAppName := range strings.Split(appName, ":") {
+		if singleAppName == "" {
+			continue
+		}
+		if isSingleAppNameDryRun(singleAppName) {
+			return true
+		}
+	}
+	return false
+}
+
 // ThrottledAppsMap returns a (copy) map of currently throttled apps
 func (throttler *Throttler) ThrottledAppsMap() (result map[string](*base.AppThrottle)) {
 	result = make(map[string](*base.AppThrottle))
@@ -1182,10 +1367,10 @@ func (throttler *Throttler) ThrottledAppsMap() (result map[string](*base.AppThro
 	return result
 }
 
-// markRecentApp takes note that an app has just asked about throttling, making it "recent"
-func (throttler *Throttler) markRecentApp(appName string, remoteAddr string) {
+// markRecentApp takes note that an app has just asked about throttling, making it "recent", along with the result of the check
+func (throttler *Throttler) markRecentApp(appName string, remoteAddr string, statusCode int, dryRun bool) {
 	recentAppKey := fmt.Sprintf("%s/%s", appName, remoteAddr)
-	throttler.recentApps.Set(recentAppKey, time.Now(), cache.DefaultExpiration)
+	throttler.recentApps.Set(recentAppKey, base.NewRecentApp(time.Now(), statusCode, dryRun), cache.DefaultExpiration)
 }
 
 // RecentAppsMap returns a (copy) map of apps which checked for throttling recently
@@ -1193,8 +1378,9 @@ func (throttler *Throttler) RecentAppsMap() (result map[string](*base.RecentApp)
 	result = make(map[string](*base.RecentApp))
 
 	for recentAppKey, item := range throttler.recentApps.Items() {
-		recentApp := base.NewRecentApp(item.Object.(time.Time))
-		result[recentAppKey] = recentApp
+		recentApp := *item.Object.(*base.RecentApp)
+		recentApp.MinutesSinceChecked = int64(time.Since(time.Unix(recentApp.CheckedAtEpoch, 0)).Minutes())
+		result[recentAppKey] = &recentApp
 	}
 	return result
 }
@@ -1243,7 +1429,7 @@ func (throttler *Throttler) checkStore(ctx context.Context, appName string, stor
 		return okMetricCheckResult
 	}
 
-	checkResult = throttler.check.Check(ctx, appName, "mysql", storeName, remoteAddr, flags)
+	checkResult = throttler.check.Check(ctx, appName, "mysql", storeName, throttler.appCheckedMetricNames(appName), remoteAddr, flags)
 
 	shouldRequestHeartbeats := !flags.SkipRequestHeartbeats
 	if throttlerapp.VitessName.Equals(appName) {
@@ -1307,7 +1493,12 @@ func (throttler *Throttler) Status() *ThrottlerStatus {
 		Query:     throttler.GetMetricsQuery(),
 		Threshold: throttler.GetMetricsThreshold(),
 
+		MetricsThresholds: throttler.metricThresholdsSnapshot(),
+		AppCheckedMetrics: throttler.appCheckedMetricsSnapshot(),
+
 		AggregatedMetrics: throttler.aggregatedMetricsSnapshot(),
 		MetricsHealth:     throttler.metricsHealthSnapshot(),
+
+		RecentApps: throttler.RecentAppsMap(),
 	}
 }
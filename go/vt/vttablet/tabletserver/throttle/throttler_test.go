@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -33,6 +34,7 @@ import (
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/connpool"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/base"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/config"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/mysql"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
@@ -49,6 +51,8 @@ const (
 type fakeTMClient struct {
 	tmclient.TabletManagerClient
 	appNames []string
+	// noMetrics emulates tablets which do not report metrics by name
+	noMetrics bool
 
 	mu sync.Mutex
 }
@@ -62,6 +66,21 @@ func (c *fakeTMClient) CheckThrottler(ctx context.Context, tablet *topodatapb.Ta
 		Value:           0,
 		Threshold:       1,
 		RecentlyChecked: false,
+		Metrics:         map[string]*tabletmanagerdatapb.CheckThrottlerResponse_Metric{},
+	}
+	for _, metricName := range base.KnownMetricNames {
+		if c.noMetrics {
+			break
+		}
+		if metricName == base.CustomMetricName {
+			// no custom query
+			continue
+		}
+		resp.Metrics[metricName.String()] = &tabletmanagerdatapb.CheckThrottlerResponse_Metric{
+			StatusCode: http.StatusOK,
+			Value:      0,
+			Threshold:  1,
+		}
 	}
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -138,6 +157,8 @@ func newTestThrottler() *Throttler {
 	throttler := &Throttler{
 		mysqlClusterProbesChan: make(chan *mysql.ClusterProbes),
 		mysqlClusterThresholds: cache.New(cache.NoExpiration, 0),
+		metricThresholds:       cache.New(cache.NoExpiration, 0),
+		appCheckedMetrics:      cache.New(cache.NoExpiration, 0),
 		heartbeatWriter:        &FakeHeartbeatWriter{},
 		ts:                     &FakeTopoServer{},
 		mysqlInventory:         mysql.NewInventory(),
@@ -146,7 +167,7 @@ func newTestThrottler() *Throttler {
 		overrideTmClient:       &fakeTMClient{},
 	}
 	throttler.configSettings = configSettings
-	throttler.mysqlThrottleMetricChan = make(chan *mysql.MySQLThrottleMetric)
+	throttler.mysqlThrottleMetricChan = make(chan mysql.MySQLThrottleMetrics)
 	throttler.mysqlInventoryChan = make(chan *mysql.Inventory, 1)
 	throttler.mysqlClusterProbesChan = make(chan *mysql.ClusterProbes)
 	throttler.throttlerConfigChan = make(chan *topodatapb.ThrottlerConfig)
@@ -172,13 +193,18 @@ func newTestThrottler() *Throttler {
 	throttler.dormantPeriod = 5 * time.Second
 	throttler.recentCheckDormantDiff = int64(throttler.dormantPeriod / recentCheckRateLimiterInterval)
 
-	throttler.readSelfThrottleMetric = func(ctx context.Context, p *mysql.Probe) *mysql.MySQLThrottleMetric {
-		return &mysql.MySQLThrottleMetric{
-			ClusterName: selfStoreName,
-			Alias:       "",
-			Value:       1,
-			Err:         nil,
+	throttler.readSelfThrottleMetrics = func(ctx context.Context, p *mysql.Probe, metricNames base.MetricNames) mysql.MySQLThrottleMetrics {
+		metrics := make(mysql.MySQLThrottleMetrics)
+		for _, metricName := range metricNames {
+			metrics[metricName] = &mysql.MySQLThrottleMetric{
+				Name:        metricName,
+				ClusterName: selfStoreName,
+				Alias:       "",
+				Value:       1,
+				Err:         nil,
+			}
 		}
+		return metrics
 	}
 
 	return throttler
@@ -194,10 +220,10 @@ func TestIsAppThrottled(t *testing.T) {
 	assert.False(t, throttler.IsAppThrottled("app3"))
 	assert.False(t, throttler.IsAppThrottled("app4"))
 	//
-	throttler.ThrottleApp("app1", time.Now().Add(time.Hour), DefaultThrottleRatio, true)
-	throttler.ThrottleApp("app2", time.Now(), DefaultThrottleRatio, false)
-	throttler.ThrottleApp("app3", time.Now().Add(time.Hour), DefaultThrottleRatio, false)
-	throttler.ThrottleApp("app4", time.Now().Add(time.Hour), 0, false)
+	throttler.ThrottleApp("app1", time.Now().Add(time.Hour), DefaultThrottleRatio, true, false)
+	throttler.ThrottleApp("app2", time.Now(), DefaultThrottleRatio, false, false)
+	throttler.ThrottleApp("app3", time.Now().Add(time.Hour), DefaultThrottleRatio, false, false)
+	throttler.ThrottleApp("app4", time.Now().Add(time.Hour), 0, false, false)
 	assert.False(t, throttler.IsAppThrottled("app1")) // exempted
 	assert.False(t, throttler.IsAppThrottled("app2")) // expired
 	assert.True(t, throttler.IsAppThrottled("app3"))
@@ -223,15 +249,15 @@ func TestIsAppExempted(t *testing.T) {
 	assert.False(t, throttler.IsAppExempted("app2"))
 	assert.False(t, throttler.IsAppExempted("app3"))
 	//
-	throttler.ThrottleApp("app1", time.Now().Add(time.Hour), DefaultThrottleRatio, true)
-	throttler.ThrottleApp("app2", time.Now(), DefaultThrottleRatio, true) // instantly expire
+	throttler.ThrottleApp("app1", time.Now().Add(time.Hour), DefaultThrottleRatio, true, false)
+	throttler.ThrottleApp("app2", time.Now(), DefaultThrottleRatio, true, false) // instantly expire
 	assert.True(t, throttler.IsAppExempted("app1"))
 	assert.True(t, throttler.IsAppExempted("app1:other-tag"))
 	assert.False(t, throttler.IsAppExempted("app2")) // expired
 	assert.False(t, throttler.IsAppExempted("app3"))
 	//
 	throttler.UnthrottleApp("app1")
-	throttler.ThrottleApp("app2", time.Now().Add(time.Hour), DefaultThrottleRatio, false)
+	throttler.ThrottleApp("app2", time.Now().Add(time.Hour), DefaultThrottleRatio, false, false)
 	assert.False(t, throttler.IsAppExempted("app1"))
 	assert.False(t, throttler.IsAppExempted("app2"))
 	assert.False(t, throttler.IsAppExempted("app3"))
@@ -241,6 +267,115 @@ func TestIsAppExempted(t *testing.T) {
 	assert.True(t, throttler.IsAppExempted("schema-tracker"))
 }
 
+func TestIsAppDryRun(t *testing.T) {
+	throttler := Throttler{
+		throttledApps:   cache.New(cache.NoExpiration, 0),
+		heartbeatWriter: &FakeHeartbeatWriter{},
+	}
+	assert.False(t, throttler.IsAppDryRun("app1"))
+	assert.False(t, throttler.IsAppDryRun("app2"))
+	//
+	throttler.ThrottleApp("app1", time.Now().Add(time.Hour), 0, false, true)
+	throttler.ThrottleApp("app2", time.Now(), 0, false, true) // instantly expire
+	assert.True(t, throttler.IsAppDryRun("app1"))
+	assert.True(t, throttler.IsAppDryRun("app1:other-tag"))
+	assert.False(t, throttler.IsAppDryRun("app2")) // expired
+	assert.False(t, throttler.IsAppDryRun("app3"))
+	assert.False(t, throttler.IsAppThrottled("app1")) // ratio is zero
+	//
+	throttler.ThrottleApp(throttlerapp.AllName.String(), time.Now().Add(time.Hour), 0, false, true)
+	assert.True(t, throttler.IsAppDryRun("app3"))
+	assert.False(t, throttler.IsAppDryRun(throttlerapp.VitessName.String())) // never in dry-run mode
+	//
+	throttler.UnthrottleApp("app1")
+	throttler.UnthrottleApp(throttlerapp.AllName.String())
+	assert.False(t, throttler.IsAppDryRun("app1"))
+	assert.False(t, throttler.IsAppDryRun("app3"))
+}
+
+func TestAppCheckedMetricNames(t *testing.T) {
+	throttler := newTestThrottler()
+	throttler.applyAppCheckedMetrics(&topodatapb.ThrottlerConfig{
+		AppCheckedMetrics: map[string]*topodatapb.ThrottlerConfig_MetricNames{
+			throttlerapp.OnlineDDLName.String():    {Names: []string{"history_list_length", "lag"}},
+			throttlerapp.VReplicationName.String(): {Names: []string{"lag"}},
+			throttlerapp.AllName.String():          {Names: []string{"threads_running"}},
+			"app1":                                 {Names: []string{"custom"}},
+			"app2":                                 {Names: []string{"no_such_metric"}},
+		},
+	})
+	assert.Equal(t, base.MetricNames{base.HistoryListLengthMetricName, base.LagMetricName}, throttler.appCheckedMetricNames("online-ddl"))
+	assert.Equal(t, base.MetricNames{base.LagMetricName}, throttler.appCheckedMetricNames("vreplication"))
+	// Metrics of all the parts of an app name are checked:
+	assert.ElementsMatch(t, base.MetricNames{base.HistoryListLengthMetricName, base.LagMetricName}, throttler.appCheckedMetricNames("vcopier:wf:vreplication:online-ddl"))
+	// Apps with no metrics check the metrics of the "all" app:
+	assert.Equal(t, base.MetricNames{base.ThreadsRunningMetricName}, throttler.appCheckedMetricNames("app3"))
+	// The custom metric is not collected without a custom query:
+	assert.Equal(t, base.MetricNames{base.ThreadsRunningMetricName}, throttler.appCheckedMetricNames("app1"))
+	// Invalid metrics are ignored:
+	assert.Equal(t, base.MetricNames{base.ThreadsRunningMetricName}, throttler.appCheckedMetricNames("app2"))
+	// The throttler itself checks all the metrics in use, the default metric first:
+	assert.Equal(t, base.MetricNames{base.LagMetricName, base.ThreadsRunningMetricName, base.HistoryListLengthMetricName}, throttler.appCheckedMetricNames("vitess"))
+
+	throttler.customQuery.Store("select 1")
+	assert.Equal(t, base.MetricNames{base.CustomMetricName}, throttler.appCheckedMetricNames("app1"))
+	assert.Equal(t, base.MetricNames{base.CustomMetricName, base.LagMetricName, base.ThreadsRunningMetricName, base.HistoryListLengthMetricName}, throttler.appCheckedMetricNames("vitess"))
+
+	// Apps removed from the config are forgotten, and the default metric is checked when no "all" app is configured:
+	throttler.applyAppCheckedMetrics(&topodatapb.ThrottlerConfig{
+		AppCheckedMetrics: map[string]*topodatapb.ThrottlerConfig_MetricNames{
+			throttlerapp.OnlineDDLName.String(): {Names: []string{"history_list_length"}},
+		},
+	})
+	assert.Equal(t, base.MetricNames{base.HistoryListLengthMetricName}, throttler.appCheckedMetricNames("online-ddl"))
+	assert.Equal(t, base.MetricNames{base.CustomMetricName}, throttler.appCheckedMetricNames("vreplication"))
+	assert.Equal(t, map[string]base.MetricNames{"online-ddl": {base.HistoryListLengthMetricName}}, throttler.appCheckedMetricsSnapshot())
+}
+
+func TestApplyMetricThresholds(t *testing.T) {
+	throttler := newTestThrottler()
+	throttler.applyMetricThresholds(&topodatapb.ThrottlerConfig{
+		Threshold:        3,
+		MetricThresholds: map[string]float64{"loadavg": 2.5},
+	})
+	assert.Equal(t, map[base.MetricName]float64{
+		base.LagMetricName:               3,
+		base.ThreadsRunningMetricName:    100,
+		base.HistoryListLengthMetricName: 5000,
+		base.LoadAvgMetricName:           2.5,
+		base.CustomMetricName:            0,
+	}, throttler.metricThresholdsSnapshot())
+
+	// With a custom query, the config's threshold is that of the custom metric:
+	throttler.customQuery.Store("select 1")
+	throttler.applyMetricThresholds(&topodatapb.ThrottlerConfig{
+		Threshold:        10,
+		MetricThresholds: map[string]float64{"lag": 2},
+	})
+	assert.Equal(t, float64(10), throttler.getMetricThreshold(base.CustomMetricName))
+	assert.Equal(t, float64(2), throttler.getMetricThreshold(base.LagMetricName))
+	assert.Equal(t, float64(1), throttler.getMetricThreshold(base.LoadAvgMetricName))
+}
+
+func TestProbeTabletWithoutMetrics(t *testing.T) {
+	throttler := newTestThrottler()
+	probe := &mysql.Probe{Alias: "fakezone2-0000000101", Tablet: &topodatapb.Tablet{}}
+
+	metrics := throttler.generateTabletProbeFunction(context.Background(), shardStoreName, &fakeTMClient{}, probe)(nil)
+	assert.Len(t, metrics, len(base.KnownMetricNames)-1) // no custom metric
+	for metricName, metric := range metrics {
+		assert.Equal(t, metricName, metric.Name)
+		assert.Equal(t, shardStoreName, metric.ClusterName)
+		assert.Equal(t, probe.Alias, metric.Alias)
+	}
+
+	// The value of a tablet which does not report metrics by name is that of the default metric:
+	metrics = throttler.generateTabletProbeFunction(context.Background(), shardStoreName, &fakeTMClient{noMetrics: true}, probe)(nil)
+	require.Len(t, metrics, 1)
+	require.Contains(t, metrics, base.LagMetricName)
+	assert.NoError(t, metrics[base.LagMetricName].Err)
+}
+
 // TestRefreshMySQLInventory tests the behavior of the throttler's RefreshMySQLInventory() function, which
 // is called periodically in actual throttler. For a given cluster name, it generates a list of probes
 // the throttler will use to check metrics.
@@ -400,6 +535,8 @@ func TestProbesWhileOperating(t *testing.T) {
 	tmClient, ok := throttler.overrideTmClient.(*fakeTMClient)
 	require.True(t, ok)
 	assert.Empty(t, tmClient.AppNames())
+	// Only the metrics in use are probed: the default metric, and those checked by some app.
+	throttler.appCheckedMetrics.Set(throttlerapp.OnlineDDLName.String(), base.MetricNames{base.HistoryListLengthMetricName}, cache.DefaultExpiration)
 
 	t.Run("aggregated", func(t *testing.T) {
 		assert.Equal(t, 0, throttler.aggregatedMetrics.ItemCount())
@@ -408,21 +545,26 @@ func TestProbesWhileOperating(t *testing.T) {
 	defer cancel()
 	runThrottler(t, ctx, throttler, time.Minute, func(t *testing.T, ctx context.Context) {
 		t.Run("aggregated", func(t *testing.T) {
-			assert.Equal(t, 2, throttler.aggregatedMetrics.ItemCount()) // flushed upon Disable()
+			// "self" and "shard" clusters, each with lag and history_list_length metrics
+			assert.Equal(t, 4, throttler.aggregatedMetrics.ItemCount()) // flushed upon Disable()
 			aggr := throttler.aggregatedMetricsSnapshot()
-			assert.Equal(t, 2, len(aggr)) // "self" and "shard" clusters
-			for clusterName, metricResult := range aggr {
+			assert.Equal(t, 4, len(aggr))
+			for aggregatedMetricName, metricResult := range aggr {
 				val, err := metricResult.Get()
 				assert.NoError(t, err)
-				switch clusterName {
-				case "mysql/self":
+				switch {
+				case strings.HasPrefix(aggregatedMetricName, "mysql/self"):
 					assert.Equal(t, float64(1), val)
-				case "mysql/shard":
+				case strings.HasPrefix(aggregatedMetricName, "mysql/shard"):
 					assert.Equal(t, float64(0), val)
 				default:
-					assert.Failf(t, "unknown clusterName", "%v", clusterName)
+					assert.Failf(t, "unknown aggregatedMetricName", "%v", aggregatedMetricName)
 				}
 			}
+			assert.Contains(t, aggr, "mysql/self")
+			assert.Contains(t, aggr, "mysql/shard")
+			assert.Contains(t, aggr, "mysql/shard/history_list_length")
+			assert.NotContains(t, aggr, "mysql/shard/threads_running")
 			assert.NotEmpty(t, tmClient.AppNames())
 			// The throttler here emulates a PRIMARY tablet, and therefore should probe the replicas using
 			// the "vitess" app name.
@@ -478,7 +620,8 @@ func TestProbesPostDisable(t *testing.T) {
 	})
 
 	t.Run("metrics", func(t *testing.T) {
-		assert.Equal(t, 3, len(throttler.mysqlInventory.TabletMetrics)) // 1 self tablet + 2 shard tablets
+		assert.Equal(t, 4, len(throttler.mysqlInventory.TabletMetrics))                     // lag, threads_running, history_list_length, loadavg
+		assert.Equal(t, 3, len(throttler.mysqlInventory.TabletMetrics[base.LagMetricName])) // 1 self tablet + 2 shard tablets
 	})
 
 	t.Run("aggregated", func(t *testing.T) {
@@ -488,6 +631,98 @@ func TestProbesPostDisable(t *testing.T) {
 	})
 }
 
+func TestCheckMetrics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	throttler := newTestThrottler()
+	throttler.StoreMetricsThreshold(5)
+	throttler.applyMetricThresholds(&topodatapb.ThrottlerConfig{
+		Threshold:        5,
+		MetricThresholds: map[string]float64{"history_list_length": 0.5},
+	})
+	throttler.applyAppCheckedMetrics(&topodatapb.ThrottlerConfig{
+		AppCheckedMetrics: map[string]*topodatapb.ThrottlerConfig_MetricNames{
+			throttlerapp.OnlineDDLName.String():    {Names: []string{"lag", "history_list_length"}},
+			throttlerapp.VReplicationName.String(): {Names: []string{"lag"}},
+		},
+	})
+
+	runThrottler(t, ctx, throttler, time.Minute, func(t *testing.T, ctx context.Context) {
+		flags := &CheckFlags{SkipRequestHeartbeats: true}
+		t.Run("online-ddl", func(t *testing.T) {
+			checkResult := throttler.CheckByType(ctx, throttlerapp.OnlineDDLName.String(), "", flags, ThrottleCheckSelf)
+			assert.Equal(t, http.StatusTooManyRequests, checkResult.StatusCode)
+			assert.Equal(t, "history_list_length", checkResult.MetricName)
+			assert.Equal(t, float64(0.5), checkResult.Threshold)
+			require.Len(t, checkResult.Metrics, 2)
+			assert.Equal(t, http.StatusOK, checkResult.Metrics["lag"].StatusCode)
+			assert.Equal(t, float64(5), checkResult.Metrics["lag"].Threshold)
+			assert.Equal(t, http.StatusTooManyRequests, checkResult.Metrics["history_list_length"].StatusCode)
+		})
+		t.Run("vreplication", func(t *testing.T) {
+			checkResult := throttler.CheckByType(ctx, "vcopier:wf:vreplication", "", flags, ThrottleCheckSelf)
+			assert.Equal(t, http.StatusOK, checkResult.StatusCode)
+			assert.Equal(t, "lag", checkResult.MetricName)
+			assert.Len(t, checkResult.Metrics, 1)
+		})
+		t.Run("vitess", func(t *testing.T) {
+			checkResult := throttler.CheckByType(ctx, throttlerapp.VitessName.String(), "", flags, ThrottleCheckSelf)
+			// The result is that of the default metric, while all metrics in use are reported
+			assert.Equal(t, http.StatusOK, checkResult.StatusCode)
+			assert.Equal(t, "lag", checkResult.MetricName)
+			assert.Len(t, checkResult.Metrics, 2)
+			assert.Equal(t, http.StatusTooManyRequests, checkResult.Metrics["history_list_length"].StatusCode)
+		})
+		cancel() // end test early
+	})
+}
+
+func TestCheckDryRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	throttler := newTestThrottler()
+	throttler.StoreMetricsThreshold(5)
+	throttler.applyMetricThresholds(&topodatapb.ThrottlerConfig{
+		Threshold:        5,
+		MetricThresholds: map[string]float64{"history_list_length": 0.5},
+	})
+	throttler.applyAppCheckedMetrics(&topodatapb.ThrottlerConfig{
+		AppCheckedMetrics: map[string]*topodatapb.ThrottlerConfig_MetricNames{
+			throttlerapp.OnlineDDLName.String(): {Names: []string{"lag", "history_list_length"}},
+		},
+	})
+	throttler.ThrottleApp(throttlerapp.OnlineDDLName.String(), time.Now().Add(time.Hour), 0, false, true)
+
+	runThrottler(t, ctx, throttler, time.Minute, func(t *testing.T, ctx context.Context) {
+		flags := &CheckFlags{SkipRequestHeartbeats: true}
+		t.Run("online-ddl", func(t *testing.T) {
+			checkResult := throttler.CheckByType(ctx, throttlerapp.OnlineDDLName.String(), "", flags, ThrottleCheckSelf)
+			assert.Equal(t, http.StatusOK, checkResult.StatusCode)
+			assert.Equal(t, http.StatusTooManyRequests, checkResult.DryRunStatusCode)
+			assert.Equal(t, "history_list_length", checkResult.MetricName)
+			assert.NoError(t, checkResult.Error)
+
+			recentApp, ok := throttler.RecentAppsMap()[throttlerapp.OnlineDDLName.String()+"/"]
+			require.True(t, ok)
+			assert.Equal(t, http.StatusTooManyRequests, recentApp.StatusCode)
+			assert.True(t, recentApp.DryRun)
+		})
+		t.Run("vreplication", func(t *testing.T) {
+			checkResult := throttler.CheckByType(ctx, throttlerapp.VReplicationName.String(), "", flags, ThrottleCheckSelf)
+			assert.Equal(t, http.StatusOK, checkResult.StatusCode)
+			assert.Zero(t, checkResult.DryRunStatusCode)
+
+			recentApp, ok := throttler.RecentAppsMap()[throttlerapp.VReplicationName.String()+"/"]
+			require.True(t, ok)
+			assert.Equal(t, http.StatusOK, recentApp.StatusCode)
+			assert.False(t, recentApp.DryRun)
+		})
+		cancel() // end test early
+	})
+}
+
 func TestDormant(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
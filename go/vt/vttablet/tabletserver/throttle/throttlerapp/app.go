@@ -44,6 +44,7 @@ const (
 	// DefaultName is the app name used by vitess when app doesn't indicate its name
 	DefaultName             Name = "default"
 	VitessName              Name = "vitess"
+	AllName                 Name = "all"
 	ThrottlerStimulatorName Name = "throttler-stimulator"
 
 	TableGCName   Name = "tablegc"